@@ -14,6 +14,7 @@ type TTRPCSystemService interface {
 	OnlineCPU(context.Context, *OnlineCPURequest) (*emptypb.Empty, error)
 	OfflineMemory(context.Context, *OfflineMemoryRequest) (*emptypb.Empty, error)
 	OnlineMemory(context.Context, *OnlineMemoryRequest) (*emptypb.Empty, error)
+	CPUUtilization(context.Context, *emptypb.Empty) (*CPUUtilizationResponse, error)
 }
 
 func RegisterTTRPCSystemService(srv *ttrpc.Server, svc TTRPCSystemService) {
@@ -54,6 +55,13 @@ func RegisterTTRPCSystemService(srv *ttrpc.Server, svc TTRPCSystemService) {
 				}
 				return svc.OnlineMemory(ctx, &req)
 			},
+			"CPUUtilization": func(ctx context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+				var req emptypb.Empty
+				if err := unmarshal(&req); err != nil {
+					return nil, err
+				}
+				return svc.CPUUtilization(ctx, &req)
+			},
 		},
 	})
 }
@@ -107,3 +115,11 @@ func (c *ttrpcsystemClient) OnlineMemory(ctx context.Context, req *OnlineMemoryR
 	}
 	return &resp, nil
 }
+
+func (c *ttrpcsystemClient) CPUUtilization(ctx context.Context, req *emptypb.Empty) (*CPUUtilizationResponse, error) {
+	var resp CPUUtilizationResponse
+	if err := c.client.Call(ctx, "containerd.vminitd.services.system.v1.System", "CPUUtilization", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
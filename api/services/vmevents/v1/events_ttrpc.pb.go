@@ -4,17 +4,15 @@ package vmevents
 
 import (
 	context "context"
-	types "github.com/containerd/containerd/api/types"
 	ttrpc "github.com/containerd/ttrpc"
-	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 type TTRPCEventsService interface {
-	Stream(context.Context, *emptypb.Empty, TTRPCEvents_StreamServer) error
+	Stream(context.Context, *StreamRequest, TTRPCEvents_StreamServer) error
 }
 
 type TTRPCEvents_StreamServer interface {
-	Send(*types.Envelope) error
+	Send(*StreamEvent) error
 	ttrpc.StreamServer
 }
 
@@ -22,7 +20,7 @@ type ttrpceventsStreamServer struct {
 	ttrpc.StreamServer
 }
 
-func (x *ttrpceventsStreamServer) Send(m *types.Envelope) error {
+func (x *ttrpceventsStreamServer) Send(m *StreamEvent) error {
 	return x.StreamServer.SendMsg(m)
 }
 
@@ -31,7 +29,7 @@ func RegisterTTRPCEventsService(srv *ttrpc.Server, svc TTRPCEventsService) {
 		Streams: map[string]ttrpc.Stream{
 			"Stream": {
 				Handler: func(ctx context.Context, stream ttrpc.StreamServer) (interface{}, error) {
-					m := new(emptypb.Empty)
+					m := new(StreamRequest)
 					if err := stream.RecvMsg(m); err != nil {
 						return nil, err
 					}
@@ -45,7 +43,7 @@ func RegisterTTRPCEventsService(srv *ttrpc.Server, svc TTRPCEventsService) {
 }
 
 type TTRPCEventsClient interface {
-	Stream(context.Context, *emptypb.Empty) (TTRPCEvents_StreamClient, error)
+	Stream(context.Context, *StreamRequest) (TTRPCEvents_StreamClient, error)
 }
 
 type ttrpceventsClient struct {
@@ -58,7 +56,7 @@ func NewTTRPCEventsClient(client *ttrpc.Client) TTRPCEventsClient {
 	}
 }
 
-func (c *ttrpceventsClient) Stream(ctx context.Context, req *emptypb.Empty) (TTRPCEvents_StreamClient, error) {
+func (c *ttrpceventsClient) Stream(ctx context.Context, req *StreamRequest) (TTRPCEvents_StreamClient, error) {
 	stream, err := c.client.NewStream(ctx, &ttrpc.StreamDesc{
 		StreamingClient: false,
 		StreamingServer: true,
@@ -71,7 +69,7 @@ func (c *ttrpceventsClient) Stream(ctx context.Context, req *emptypb.Empty) (TTR
 }
 
 type TTRPCEvents_StreamClient interface {
-	Recv() (*types.Envelope, error)
+	Recv() (*StreamEvent, error)
 	ttrpc.ClientStream
 }
 
@@ -79,8 +77,8 @@ type ttrpceventsStreamClient struct {
 	ttrpc.ClientStream
 }
 
-func (x *ttrpceventsStreamClient) Recv() (*types.Envelope, error) {
-	m := new(types.Envelope)
+func (x *ttrpceventsStreamClient) Recv() (*StreamEvent, error) {
+	m := new(StreamEvent)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
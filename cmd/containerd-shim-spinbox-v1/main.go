@@ -17,7 +17,7 @@ import (
 
 func main() {
 	// Load configuration first - fail fast if config is missing or invalid
-	_, err := config.Get()
+	cfg, err := config.Get()
 	if err != nil {
 		// Use structured logging for the error (consistent with vminitd)
 		log.L.WithError(err).Error("failed to load spinbox configuration")
@@ -28,6 +28,16 @@ func main() {
 		os.Exit(1)
 	}
 
+	for _, w := range cfg.Warnings() {
+		log.L.Warn(w)
+	}
+
+	// Catch unwritable directories now rather than at the first container create
+	if err := cfg.PreflightCheck(); err != nil {
+		log.L.WithError(err).Error("spinbox preflight check failed")
+		os.Exit(1)
+	}
+
 	// Log level is controlled by containerd configuration, not the shim
 	ctx := context.Background()
 	shim.Run(ctx, manager.NewShimManager("io.containerd.spinbox.v1"))
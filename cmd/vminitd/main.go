@@ -83,6 +83,7 @@ func main() {
 
 func run(ctx context.Context, cfg *config.ServiceConfig) error {
 	t1 := time.Now()
+	system.SetBootTime(t1)
 
 	ctx, cfg.Shutdown = shutdown.WithShutdown(ctx)
 
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"runtime"
 	"sync"
 	"time"
 )
@@ -17,6 +18,20 @@ const (
 
 	// ConfigEnvVar is the environment variable to override config file location
 	ConfigEnvVar = "SPINBOX_CONFIG"
+
+	// KernelPathEnvVar and InitrdPathEnvVar point VMs of the host architecture
+	// at an explicit kernel or initrd instead of the ones in share_dir
+	KernelPathEnvVar = "SPINBOX_KERNEL_PATH"
+	InitrdPathEnvVar = "SPINBOX_INITRD_PATH"
+
+	// KernelNameEnvVar and InitrdNameEnvVar replace the file names of the
+	// host architecture's kernel and initrd within share_dir/kernel
+	KernelNameEnvVar = "SPINBOX_KERNEL_NAME"
+	InitrdNameEnvVar = "SPINBOX_INITRD_NAME"
+
+	// VMStateDirEnvVar relocates per-VM state (sockets, QEMU state) from the
+	// bundle to <dir>/<namespace>/<container-id>
+	VMStateDirEnvVar = "SPINBOX_VM_STATE_DIR"
 )
 
 // Config is the root configuration structure
@@ -26,6 +41,7 @@ type Config struct {
 	Timeouts   TimeoutsConfig   `json:"timeouts"`
 	CPUHotplug CPUHotplugConfig `json:"cpu_hotplug"`
 	MemHotplug MemHotplugConfig `json:"memory_hotplug"`
+	Security   SecurityConfig   `json:"security"`
 }
 
 // PathsConfig defines filesystem paths for spinbox components
@@ -35,6 +51,8 @@ type PathsConfig struct {
 	LogDir        string `json:"log_dir"`         // Logs directory
 	QEMUPath      string `json:"qemu_path"`       // QEMU binary location (auto-discovered if empty)
 	QEMUSharePath string `json:"qemu_share_path"` // QEMU firmware/BIOS directory (auto-discovered if empty)
+	CNIConfDir    string `json:"cni_conf_dir"`    // CNI network config directory (auto-discovered if empty)
+	CNIBinDir     string `json:"cni_bin_dir"`     // CNI plugin directory (auto-discovered if empty)
 }
 
 // RuntimeConfig defines runtime behavior settings
@@ -42,12 +60,66 @@ type RuntimeConfig struct {
 	VMM string `json:"vmm"` // VMM backend (currently only "qemu" supported)
 }
 
+// hostArch is the architecture of the host, as a GOARCH value. It is a
+// variable so tests can resolve names for other architectures.
+var hostArch = runtime.GOARCH
+
+// NormalizeArch maps a Go (GOARCH) or kernel (uname -m) architecture name to
+// the suffix used by spinbox kernel images.
+func NormalizeArch(arch string) (string, error) {
+	switch arch {
+	case "amd64", "x86_64":
+		return "x86_64", nil
+	case "arm64", "aarch64":
+		return "aarch64", nil
+	default:
+		return "", fmt.Errorf("unsupported architecture %q", arch)
+	}
+}
+
+// KernelName returns the file name of the kernel image for arch within
+// share_dir/kernel (e.g., "spinbox-kernel-x86_64" for amd64 and
+// "spinbox-kernel-aarch64" for arm64). KernelNameEnvVar overrides it.
+func KernelName(arch string) string {
+	if name := os.Getenv(KernelNameEnvVar); name != "" {
+		return name
+	}
+	a, err := NormalizeArch(arch)
+	if err != nil {
+		// No spinbox kernel is built for this arch; keep the name
+		// predictable so the missing file is reported clearly
+		a = arch
+	}
+	return "spinbox-kernel-" + a
+}
+
+// InitrdName returns the file name of the arch-neutral initrd within
+// share_dir/kernel. InitrdNameEnvVar overrides it.
+func InitrdName() string {
+	if name := os.Getenv(InitrdNameEnvVar); name != "" {
+		return name
+	}
+	return "spinbox-initrd"
+}
+
+// SecurityConfig defines container security policy settings
+type SecurityConfig struct {
+	// PrivilegedPolicy is "allow" (default), "reject" or "downgrade"
+	PrivilegedPolicy string `json:"privileged_policy"`
+	// PrivilegedCapabilities are granted to downgraded privileged containers
+	// (default: the containerd default capability set)
+	PrivilegedCapabilities []string `json:"privileged_capabilities"`
+	// MaxMounts is the most mounts a container spec may have (default: 256)
+	MaxMounts int `json:"max_mounts"`
+}
+
 // TimeoutsConfig defines timeout durations for various lifecycle operations.
 // All values are duration strings (e.g., "5s", "2m", "500ms").
 type TimeoutsConfig struct {
-	VMStart         string `json:"vm_start"`          // VM boot timeout (default: 10s)
+	VMStart         string `json:"vm_start"`          // VM boot timeout (default: 30s)
 	DeviceDetection string `json:"device_detection"`  // Guest device detection timeout (default: 5s)
 	ShutdownGrace   string `json:"shutdown_grace"`    // Grace period before SIGKILL (default: 2s)
+	ShutdownACPI    string `json:"shutdown_acpi"`     // Guest shutdown time before QEMU quits (default: 500ms)
 	EventReconnect  string `json:"event_reconnect"`   // Event stream reconnection timeout (default: 2s)
 	TaskClientRetry string `json:"task_client_retry"` // Vsock dial retry timeout (default: 1s)
 	IOWait          string `json:"io_wait"`           // I/O forwarder completion timeout (default: 30s)
@@ -65,6 +137,8 @@ func (t *TimeoutsConfig) Duration(name string) time.Duration {
 		s = t.DeviceDetection
 	case "shutdown_grace":
 		s = t.ShutdownGrace
+	case "shutdown_acpi":
+		s = t.ShutdownACPI
 	case "event_reconnect":
 		s = t.EventReconnect
 	case "task_client_retry":
@@ -125,10 +199,15 @@ var defaultConfig = Config{
 	Runtime: RuntimeConfig{
 		VMM: "qemu",
 	},
+	Security: SecurityConfig{
+		PrivilegedPolicy: "allow",
+		MaxMounts:        256,
+	},
 	Timeouts: TimeoutsConfig{
-		VMStart:         "10s",
+		VMStart:         "30s",
 		DeviceDetection: "5s",
 		ShutdownGrace:   "2s",
+		ShutdownACPI:    "500ms",
 		EventReconnect:  "2s",
 		TaskClientRetry: "1s",
 		IOWait:          "30s",
@@ -240,10 +319,15 @@ func (c *Config) applyDefaults() {
 	// Runtime
 	setDefault(&c.Runtime.VMM, d.Runtime.VMM)
 
+	// Security
+	setDefault(&c.Security.PrivilegedPolicy, d.Security.PrivilegedPolicy)
+	setDefault(&c.Security.MaxMounts, d.Security.MaxMounts)
+
 	// Timeouts
 	setDefault(&c.Timeouts.VMStart, d.Timeouts.VMStart)
 	setDefault(&c.Timeouts.DeviceDetection, d.Timeouts.DeviceDetection)
 	setDefault(&c.Timeouts.ShutdownGrace, d.Timeouts.ShutdownGrace)
+	setDefault(&c.Timeouts.ShutdownACPI, d.Timeouts.ShutdownACPI)
 	setDefault(&c.Timeouts.EventReconnect, d.Timeouts.EventReconnect)
 	setDefault(&c.Timeouts.TaskClientRetry, d.Timeouts.TaskClientRetry)
 	setDefault(&c.Timeouts.IOWait, d.Timeouts.IOWait)
@@ -44,8 +44,8 @@ func createTestConfigEnv(t *testing.T, baseDir string) testConfigEnv {
 	}
 
 	// Create dummy kernel and initrd files
-	kernelPath := filepath.Join(env.shareDir, "kernel", "spinbox-kernel-x86_64")
-	initrdPath := filepath.Join(env.shareDir, "kernel", "spinbox-initrd")
+	kernelPath := filepath.Join(env.shareDir, "kernel", KernelName(hostArch))
+	initrdPath := filepath.Join(env.shareDir, "kernel", InitrdName())
 	if err := os.WriteFile(kernelPath, []byte("dummy"), 0644); err != nil {
 		t.Fatalf("failed to create dummy kernel: %v", err)
 	}
@@ -160,8 +160,8 @@ func TestLoadFrom_ValidConfig(t *testing.T) {
 	}
 
 	// Create dummy kernel and initrd
-	kernelPath := filepath.Join(kernelDir, "spinbox-kernel-x86_64")
-	initrdPath := filepath.Join(kernelDir, "spinbox-initrd")
+	kernelPath := filepath.Join(kernelDir, KernelName(hostArch))
+	initrdPath := filepath.Join(kernelDir, InitrdName())
 	if err := os.WriteFile(kernelPath, []byte("dummy"), 0600); err != nil {
 		t.Fatal(err)
 	}
@@ -334,8 +334,8 @@ func TestValidate_Comprehensive(t *testing.T) {
 		}
 
 		// Create dummy kernel and initrd
-		kernelPath := filepath.Join(kernelDir, "spinbox-kernel-x86_64")
-		initrdPath := filepath.Join(kernelDir, "spinbox-initrd")
+		kernelPath := filepath.Join(kernelDir, KernelName(hostArch))
+		initrdPath := filepath.Join(kernelDir, InitrdName())
 		if err := os.WriteFile(kernelPath, []byte("dummy"), 0600); err != nil {
 			t.Fatal(err)
 		}
@@ -605,6 +605,59 @@ func TestValidate_Comprehensive(t *testing.T) {
 				c.MemHotplug.IncrementSizeMB = 256
 			},
 		},
+		// Security validation
+		{
+			name:    "Invalid privileged policy",
+			wantErr: true,
+			setupFunc: func(c *Config) {
+				c.Security.PrivilegedPolicy = "deny"
+			},
+		},
+		{
+			name:    "Invalid privileged capability",
+			wantErr: true,
+			setupFunc: func(c *Config) {
+				c.Security.PrivilegedPolicy = "downgrade"
+				c.Security.PrivilegedCapabilities = []string{"net_admin"}
+			},
+		},
+		{
+			name:    "Valid downgrade policy",
+			wantErr: false,
+			setupFunc: func(c *Config) {
+				c.Security.PrivilegedPolicy = "downgrade"
+				c.Security.PrivilegedCapabilities = []string{"CAP_NET_ADMIN"}
+			},
+		},
+		{
+			name:    "Invalid max mounts",
+			wantErr: true,
+			setupFunc: func(c *Config) {
+				c.Security.MaxMounts = -1
+			},
+		},
+		// Timeouts validation
+		{
+			name:    "Invalid shutdown_acpi",
+			wantErr: true,
+			setupFunc: func(c *Config) {
+				c.Timeouts.ShutdownACPI = "soon"
+			},
+		},
+		{
+			name:    "shutdown_grace above stage limit",
+			wantErr: true,
+			setupFunc: func(c *Config) {
+				c.Timeouts.ShutdownGrace = "10m"
+			},
+		},
+		{
+			name:    "Valid longer shutdown_acpi",
+			wantErr: false,
+			setupFunc: func(c *Config) {
+				c.Timeouts.ShutdownACPI = "30s"
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -686,3 +739,146 @@ func TestReset(t *testing.T) {
 		t.Fatal("test setup error: directories should be different")
 	}
 }
+
+func TestValidate_BootArtifactOverrides(t *testing.T) {
+	env := createTestConfigEnv(t, t.TempDir())
+	cfg, err := LoadFrom(env.configFile)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+
+	// Kernel and initrd outside share_dir
+	localDir := t.TempDir()
+	kernel := filepath.Join(localDir, "bzImage")
+	initrd := filepath.Join(localDir, "initrd")
+	for _, p := range []string{kernel, initrd} {
+		if err := os.WriteFile(p, []byte("dummy"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.RemoveAll(filepath.Join(env.shareDir, "kernel")); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for missing kernel in share_dir")
+	}
+
+	t.Setenv(KernelPathEnvVar, kernel)
+	t.Setenv(InitrdPathEnvVar, initrd)
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected overrides to satisfy validation, got %v", err)
+	}
+
+	t.Setenv(KernelPathEnvVar, filepath.Join(localDir, "missing"))
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for missing kernel override")
+	}
+}
+
+func TestValidate_KernelNames(t *testing.T) {
+	setHostArch := func(t *testing.T, arch string) {
+		t.Helper()
+		old := hostArch
+		hostArch = arch
+		t.Cleanup(func() { hostArch = old })
+	}
+	// useKernelFiles leaves only the given kernel and initrd in share_dir/kernel.
+	useKernelFiles := func(t *testing.T, env testConfigEnv, kernel, initrd string) {
+		t.Helper()
+		kernelDir := filepath.Join(env.shareDir, "kernel")
+		if err := os.RemoveAll(kernelDir); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(kernelDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{kernel, initrd} {
+			if err := os.WriteFile(filepath.Join(kernelDir, name), []byte("dummy"), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	t.Run("arm64 kernel", func(t *testing.T) {
+		setHostArch(t, "arm64")
+		env := createTestConfigEnv(t, t.TempDir())
+		useKernelFiles(t, env, "spinbox-kernel-aarch64", "spinbox-initrd")
+
+		if _, err := LoadFrom(env.configFile); err != nil {
+			t.Fatalf("LoadFrom failed on arm64: %v", err)
+		}
+
+		setHostArch(t, "amd64")
+		if _, err := LoadFrom(env.configFile); err == nil {
+			t.Fatal("expected error for missing x86_64 kernel")
+		}
+	})
+
+	t.Run("name overrides", func(t *testing.T) {
+		env := createTestConfigEnv(t, t.TempDir())
+		useKernelFiles(t, env, "vmlinux-custom", "initrd-custom")
+
+		if _, err := LoadFrom(env.configFile); err == nil {
+			t.Fatal("expected error for missing default kernel name")
+		}
+
+		t.Setenv(KernelNameEnvVar, "vmlinux-custom")
+		t.Setenv(InitrdNameEnvVar, "initrd-custom")
+		if _, err := LoadFrom(env.configFile); err != nil {
+			t.Fatalf("expected name overrides to satisfy validation, got %v", err)
+		}
+	})
+}
+
+func TestLoadFrom_CNIDirs(t *testing.T) {
+	cniConfDir := t.TempDir()
+	cniBinDir := t.TempDir()
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	tests := []struct {
+		name    string
+		confDir string
+		binDir  string
+		wantErr string
+	}{
+		{name: "unset"},
+		{name: "conf dir only", confDir: cniConfDir},
+		{name: "conf and bin dirs", confDir: cniConfDir, binDir: cniBinDir},
+		{name: "missing conf dir", confDir: missing, wantErr: "cni_conf_dir"},
+		{name: "missing bin dir", confDir: cniConfDir, binDir: missing, wantErr: "cni_bin_dir"},
+		{name: "bin dir without conf dir", binDir: cniBinDir, wantErr: "cni_bin_dir requires cni_conf_dir"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := createTestConfigEnv(t, t.TempDir())
+			cfg := DefaultConfig()
+			cfg.Paths.ShareDir = env.shareDir
+			cfg.Paths.StateDir = env.stateDir
+			cfg.Paths.LogDir = env.logDir
+			cfg.Paths.CNIConfDir = tt.confDir
+			cfg.Paths.CNIBinDir = tt.binDir
+			data, err := json.Marshal(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(env.configFile, data, 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			loaded, err := LoadFrom(env.configFile)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadFrom() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadFrom() unexpected error: %v", err)
+			}
+			if loaded.Paths.CNIConfDir != tt.confDir || loaded.Paths.CNIBinDir != tt.binDir {
+				t.Errorf("CNI dirs = %q, %q, want %q, %q", loaded.Paths.CNIConfDir, loaded.Paths.CNIBinDir, tt.confDir, tt.binDir)
+			}
+		})
+	}
+}
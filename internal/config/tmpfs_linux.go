@@ -0,0 +1,25 @@
+//go:build linux
+
+package config
+
+import (
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+// onTmpfs reports whether path is on a tmpfs mount. The nearest existing
+// ancestor is checked when path does not exist yet.
+func onTmpfs(path string) bool {
+	var st unix.Statfs_t
+	for {
+		if err := unix.Statfs(path, &st); err == nil {
+			return st.Type == unix.TMPFS_MAGIC
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return false
+		}
+		path = parent
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package config
+
+// onTmpfs reports whether path is on a tmpfs mount. tmpfs is only detected
+// on Linux.
+func onTmpfs(string) bool {
+	return false
+}
@@ -1,14 +1,26 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"golang.org/x/sys/unix"
 )
 
+// Thresholds below which Warnings reports a setting
+const (
+	minOOMSafetyMarginMB = 128
+	minVMStartTimeout    = 10 * time.Second
+)
+
+// maxShutdownStage caps the configurable VM shutdown waits, so that the
+// whole shutdown sequence stays within the QEMU instance's limit.
+const maxShutdownStage = 2 * time.Minute
+
 // Validate validates the entire configuration.
 func (c *Config) Validate() error {
 	if err := c.validatePaths(); err != nil {
@@ -26,6 +38,9 @@ func (c *Config) Validate() error {
 	if err := c.validateMemHotplug(); err != nil {
 		return fmt.Errorf("memory_hotplug: %w", err)
 	}
+	if err := c.validateSecurity(); err != nil {
+		return fmt.Errorf("security: %w", err)
+	}
 	return nil
 }
 
@@ -37,9 +52,15 @@ func (c *Config) validatePaths() error {
 		return err
 	}
 
-	// Check kernel and initrd exist
-	kernelPath := filepath.Join(c.Paths.ShareDir, "kernel", "spinbox-kernel-x86_64")
-	initrdPath := filepath.Join(c.Paths.ShareDir, "kernel", "spinbox-initrd")
+	// Check kernel and initrd exist, unless overridden through the environment
+	kernelPath := filepath.Join(c.Paths.ShareDir, "kernel", KernelName(hostArch))
+	initrdPath := filepath.Join(c.Paths.ShareDir, "kernel", InitrdName())
+	if p := os.Getenv(KernelPathEnvVar); p != "" {
+		kernelPath = p
+	}
+	if p := os.Getenv(InitrdPathEnvVar); p != "" {
+		initrdPath = p
+	}
 
 	if _, err := os.Stat(kernelPath); err != nil {
 		if os.IsNotExist(err) {
@@ -57,14 +78,14 @@ func (c *Config) validatePaths() error {
 	if c.Paths.StateDir == "" {
 		return fmt.Errorf("state_dir cannot be empty")
 	}
-	if err := ensureDirWritable(c.Paths.StateDir, "state_dir"); err != nil {
+	if err := validateDirLocation(c.Paths.StateDir, "state_dir"); err != nil {
 		return err
 	}
 
 	if c.Paths.LogDir == "" {
 		return fmt.Errorf("log_dir cannot be empty")
 	}
-	if err := ensureDirWritable(c.Paths.LogDir, "log_dir"); err != nil {
+	if err := validateDirLocation(c.Paths.LogDir, "log_dir"); err != nil {
 		return err
 	}
 
@@ -78,6 +99,19 @@ func (c *Config) validatePaths() error {
 			return err
 		}
 	}
+	if c.Paths.CNIConfDir != "" {
+		if err := validateDirExists(c.Paths.CNIConfDir, "cni_conf_dir"); err != nil {
+			return err
+		}
+	}
+	if c.Paths.CNIBinDir != "" {
+		if c.Paths.CNIConfDir == "" {
+			return fmt.Errorf("cni_bin_dir requires cni_conf_dir to be set")
+		}
+		if err := validateDirExists(c.Paths.CNIBinDir, "cni_bin_dir"); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -88,11 +122,29 @@ func (c *Config) validateRuntime() error {
 	return nil
 }
 
+func (c *Config) validateSecurity() error {
+	switch c.Security.PrivilegedPolicy {
+	case "allow", "reject", "downgrade":
+	default:
+		return fmt.Errorf("privileged_policy must be \"allow\", \"reject\" or \"downgrade\", got %q", c.Security.PrivilegedPolicy)
+	}
+	for _, capName := range c.Security.PrivilegedCapabilities {
+		if !strings.HasPrefix(capName, "CAP_") || strings.ToUpper(capName) != capName {
+			return fmt.Errorf("privileged_capabilities: invalid capability %q (expected e.g. CAP_NET_ADMIN)", capName)
+		}
+	}
+	if c.Security.MaxMounts <= 0 {
+		return fmt.Errorf("max_mounts must be positive, got %d", c.Security.MaxMounts)
+	}
+	return nil
+}
+
 func (c *Config) validateTimeouts() error {
 	fields := map[string]string{
 		"vm_start":          c.Timeouts.VMStart,
 		"device_detection":  c.Timeouts.DeviceDetection,
 		"shutdown_grace":    c.Timeouts.ShutdownGrace,
+		"shutdown_acpi":     c.Timeouts.ShutdownACPI,
 		"event_reconnect":   c.Timeouts.EventReconnect,
 		"task_client_retry": c.Timeouts.TaskClientRetry,
 		"io_wait":           c.Timeouts.IOWait,
@@ -111,6 +163,16 @@ func (c *Config) validateTimeouts() error {
 			return fmt.Errorf("%s: too large (%s), max is 1h", name, d)
 		}
 	}
+
+	// Shutdown stages run back to back while the container is torn down
+	for name, val := range map[string]string{
+		"shutdown_grace": c.Timeouts.ShutdownGrace,
+		"shutdown_acpi":  c.Timeouts.ShutdownACPI,
+	} {
+		if d, _ := time.ParseDuration(val); d > maxShutdownStage {
+			return fmt.Errorf("%s: too large (%s), max is %s", name, d, maxShutdownStage)
+		}
+	}
 	return nil
 }
 
@@ -176,18 +238,113 @@ func (c *Config) validateMemHotplug() error {
 	return nil
 }
 
+// Warnings returns non-fatal problems with a valid configuration, such as
+// settings that work but are likely to cause trouble in production. The shim
+// logs them at startup.
+func (c *Config) Warnings() []string {
+	var warnings []string
+	if c.Paths.StateDir != "" && onTmpfs(c.Paths.StateDir) {
+		warnings = append(warnings, fmt.Sprintf("state_dir %s is on tmpfs; VM state is lost on reboot", c.Paths.StateDir))
+	}
+	if c.MemHotplug.OOMSafetyMarginMB > 0 && c.MemHotplug.OOMSafetyMarginMB < minOOMSafetyMarginMB {
+		warnings = append(warnings, fmt.Sprintf("memory_hotplug.oom_safety_margin_mb %d is below %d; guests may OOM while memory is being added",
+			c.MemHotplug.OOMSafetyMarginMB, minOOMSafetyMarginMB))
+	}
+	if d, err := time.ParseDuration(c.Timeouts.VMStart); err == nil && d < minVMStartTimeout {
+		warnings = append(warnings, fmt.Sprintf("timeouts.vm_start %s is below %s; VMs may fail to boot on a loaded host", d, minVMStartTimeout))
+	}
+	return warnings
+}
+
+// PreflightCheck verifies that every directory the shim writes to exists (or
+// can be created) and is writable: state_dir, log_dir and, when set, the
+// VMStateDirEnvVar directory. Validate only checks where these directories
+// are; PreflightCheck does not stop at the first problem, and the returned
+// error lists every unusable directory.
+func (c *Config) PreflightCheck() error {
+	dirs := []struct{ path, name string }{
+		{c.Paths.StateDir, "state_dir"},
+		{c.Paths.LogDir, "log_dir"},
+	}
+	if dir := os.Getenv(VMStateDirEnvVar); dir != "" {
+		dirs = append(dirs, struct{ path, name string }{dir, VMStateDirEnvVar})
+	}
+
+	var errs []error
+	for _, d := range dirs {
+		if err := ensureDirWritable(d.path, d.name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // Helper functions
 
+// canonicalizePath cleans path and resolves its symlinks. When path does not
+// exist yet, its nearest existing ancestor is resolved and the missing
+// components are appended, so the result is where the path will be created.
 func canonicalizePath(path string) (string, error) {
 	cleaned := filepath.Clean(path)
-	resolved, err := filepath.EvalSymlinks(cleaned)
-	if err == nil {
-		return resolved, nil
+	var missing []string
+	for dir := cleaned; ; {
+		resolved, err := filepath.EvalSymlinks(dir)
+		if err == nil {
+			return filepath.Join(append([]string{resolved}, missing...)...), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to resolve path %s: %w", path, err)
+		}
+		// A dangling symlink still decides where the path will be created
+		if fi, lerr := os.Lstat(dir); lerr == nil && fi.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(dir)
+			if err != nil {
+				return "", fmt.Errorf("failed to resolve path %s: %w", path, err)
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(dir), target)
+			}
+			dir = filepath.Clean(target)
+			continue
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return cleaned, nil
+		}
+		missing = append([]string{filepath.Base(dir)}, missing...)
+		dir = parent
 	}
-	if os.IsNotExist(err) {
-		return cleaned, nil
+}
+
+// isWithin reports whether path is root or a location below it. Both must
+// be clean.
+func isWithin(path, root string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// protectedDirs are system locations that writable spinbox directories must
+// not resolve into.
+var protectedDirs = []string{"/boot", "/dev", "/etc", "/proc", "/sys"}
+
+// validateDirLocation rejects a writable directory (e.g., state_dir) that
+// resolves, directly or through a symlink, to the filesystem root or into
+// one of protectedDirs. Symlinks to any other location, such as a
+// directory on another disk, are allowed.
+func validateDirLocation(path, name string) error {
+	canonical, err := canonicalizePath(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if canonical == "/" {
+		return fmt.Errorf("%s: %s resolves to the filesystem root", name, path)
 	}
-	return "", fmt.Errorf("failed to resolve path %s: %w", path, err)
+	for _, dir := range protectedDirs {
+		if isWithin(canonical, dir) {
+			return fmt.Errorf("%s: %s resolves to %s, inside protected directory %s", name, path, canonical, dir)
+		}
+	}
+	return nil
 }
 
 func validateDirExists(path, name string) error {
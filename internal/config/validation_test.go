@@ -3,6 +3,7 @@
 package config
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -47,6 +48,20 @@ func TestCanonicalizePath(t *testing.T) {
 				return nonExistent, tmpDir
 			},
 		},
+		{
+			name: "resolves the nearest existing ancestor",
+			setup: func(t *testing.T, tmpDir string) (string, string) {
+				realDir := filepath.Join(tmpDir, "realdir")
+				if err := os.MkdirAll(realDir, 0750); err != nil {
+					t.Fatal(err)
+				}
+				symlinkPath := filepath.Join(tmpDir, "linkdir")
+				if err := os.Symlink(realDir, symlinkPath); err != nil {
+					t.Fatal(err)
+				}
+				return filepath.Join(symlinkPath, "new", "dir"), filepath.Join(realDir, "new", "dir")
+			},
+		},
 		{
 			name: "reveals symlink escape attempt",
 			setup: func(t *testing.T, tmpDir string) (string, string) {
@@ -92,6 +107,51 @@ func TestCanonicalizePath(t *testing.T) {
 	}
 }
 
+func TestValidateDirLocation(t *testing.T) {
+	tmpDir := t.TempDir()
+	disk := filepath.Join(tmpDir, "disk")
+	if err := os.MkdirAll(disk, 0750); err != nil {
+		t.Fatal(err)
+	}
+	links := map[string]string{
+		"other-disk": disk,
+		"etc":        "/etc",
+		"dangling":   "/etc/spinbox-does-not-exist",
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(tmpDir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "plain directory", path: disk},
+		{name: "symlink to another disk", path: filepath.Join(tmpDir, "other-disk")},
+		{name: "missing directory below symlinked parent", path: filepath.Join(tmpDir, "other-disk", "spinbox", "state")},
+		{name: "symlink into /etc", path: filepath.Join(tmpDir, "etc"), wantErr: true},
+		{name: "missing directory below symlink into /etc", path: filepath.Join(tmpDir, "etc", "spinbox"), wantErr: true},
+		{name: "dangling symlink into /etc", path: filepath.Join(tmpDir, "dangling"), wantErr: true},
+		{name: "proc", path: "/proc/self", wantErr: true},
+		{name: "filesystem root", path: "/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDirLocation(tt.path, "state_dir")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateDirLocation(%s) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "state_dir") {
+				t.Errorf("error %q does not name the option", err)
+			}
+		})
+	}
+}
+
 func TestValidateDirExists(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -331,3 +391,130 @@ func TestValidateExecutable(t *testing.T) {
 		})
 	}
 }
+
+func TestPreflightCheck(t *testing.T) {
+	tmpDir := t.TempDir()
+	// A regular file cannot be used as, or hold, a directory. Unlike
+	// permission bits this also holds when the tests run as root.
+	blocker := filepath.Join(tmpDir, "file")
+	if err := os.WriteFile(blocker, []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("all writable", func(t *testing.T) {
+		t.Setenv(VMStateDirEnvVar, filepath.Join(tmpDir, "vm-state"))
+		cfg := &Config{Paths: PathsConfig{
+			StateDir: filepath.Join(tmpDir, "state"),
+			LogDir:   filepath.Join(tmpDir, "log"),
+		}}
+		if err := cfg.PreflightCheck(); err != nil {
+			t.Fatalf("PreflightCheck() unexpected error: %v", err)
+		}
+	})
+
+	t.Run("reports every unusable directory", func(t *testing.T) {
+		vmStateDir := filepath.Join(blocker, "vm-state")
+		t.Setenv(VMStateDirEnvVar, vmStateDir)
+		cfg := &Config{Paths: PathsConfig{
+			StateDir: blocker,
+			LogDir:   filepath.Join(tmpDir, "log"),
+		}}
+
+		err := cfg.PreflightCheck()
+		if err == nil {
+			t.Fatal("PreflightCheck() expected error, got nil")
+		}
+		for _, want := range []string{"state_dir", blocker, VMStateDirEnvVar, vmStateDir} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("error %q does not mention %q", err, want)
+			}
+		}
+		if strings.Contains(err.Error(), "log_dir") {
+			t.Errorf("error %q mentions the writable log_dir", err)
+		}
+	})
+
+	t.Run("loaded config reports every unusable directory", func(t *testing.T) {
+		t.Setenv(VMStateDirEnvVar, "")
+		env := createTestConfigEnv(t, t.TempDir())
+		cfg, err := LoadFrom(env.configFile)
+		if err != nil {
+			t.Fatalf("LoadFrom failed: %v", err)
+		}
+		logBlocker := filepath.Join(tmpDir, "log-file")
+		if err := os.WriteFile(logBlocker, []byte("x"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		cfg.Paths.StateDir = blocker
+		cfg.Paths.LogDir = logBlocker
+		data, err := json.Marshal(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(env.configFile, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		// Loading only validates where the directories are
+		cfg, err = LoadFrom(env.configFile)
+		if err != nil {
+			t.Fatalf("LoadFrom failed: %v", err)
+		}
+		err = cfg.PreflightCheck()
+		if err == nil {
+			t.Fatal("PreflightCheck() expected error, got nil")
+		}
+		for _, want := range []string{"state_dir", "log_dir"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("error %q does not mention %q", err, want)
+			}
+		}
+	})
+}
+
+func TestWarnings(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Paths.StateDir = t.TempDir()
+		if onTmpfs(cfg.Paths.StateDir) {
+			t.Skip("temp dir is on tmpfs")
+		}
+		if w := cfg.Warnings(); len(w) != 0 {
+			t.Errorf("Warnings() = %v, want none", w)
+		}
+	})
+
+	t.Run("borderline settings warn but validate", func(t *testing.T) {
+		env := createTestConfigEnv(t, t.TempDir())
+		cfg, err := LoadFrom(env.configFile)
+		if err != nil {
+			t.Fatalf("LoadFrom failed: %v", err)
+		}
+		cfg.MemHotplug.OOMSafetyMarginMB = 64
+		cfg.Timeouts.VMStart = "3s"
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("Validate() unexpected error: %v", err)
+		}
+
+		warnings := strings.Join(cfg.Warnings(), "\n")
+		for _, want := range []string{"oom_safety_margin_mb 64", "vm_start 3s"} {
+			if !strings.Contains(warnings, want) {
+				t.Errorf("warnings %q do not mention %q", warnings, want)
+			}
+		}
+	})
+
+	t.Run("state dir on tmpfs", func(t *testing.T) {
+		if !onTmpfs("/dev/shm") {
+			t.Skip("/dev/shm is not tmpfs")
+		}
+		cfg := DefaultConfig()
+		// Need not exist: the nearest existing ancestor is checked
+		cfg.Paths.StateDir = "/dev/shm/spinbox-test/state"
+
+		warnings := cfg.Warnings()
+		if len(warnings) != 1 || !strings.Contains(warnings[0], "tmpfs") {
+			t.Errorf("Warnings() = %v, want a tmpfs warning", warnings)
+		}
+	})
+}
@@ -8,8 +8,15 @@
 package services
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -29,6 +36,11 @@ const (
 	rootfsDir       = "rootfs"
 	bundleDirPerms  = 0750 // rwxr-x---: owner + group readable
 	bundleFilePerms = 0600 // rw-------: owner only
+
+	// dirArchiveSuffix marks a bundle file holding a tar archive of a
+	// directory bind mount (must match transform.DirArchiveSuffix). The
+	// archive "<name>.dir.tar" is extracted to "<name>" instead of written.
+	dirArchiveSuffix = ".dir.tar"
 )
 
 func init() {
@@ -102,6 +114,17 @@ func (s *service) Create(ctx context.Context, r *api.CreateRequest) (_ *api.Crea
 			return nil, errgrpc.ToGRPCf(errdefs.ErrInvalidArgument,
 				"invalid bundle filename: %q", filename)
 		}
+
+		if dir, ok := strings.CutSuffix(filename, dirArchiveSuffix); ok {
+			if dir == "" || dir == "." || dir == ".." || dir == rootfsDir {
+				return nil, errgrpc.ToGRPCf(errdefs.ErrInvalidArgument,
+					"invalid bundle directory archive: %q", filename)
+			}
+			if _, dup := r.Files[dir]; dup {
+				return nil, errgrpc.ToGRPCf(errdefs.ErrInvalidArgument,
+					"bundle file %q conflicts with directory archive %q", dir, filename)
+			}
+		}
 	}
 	if err := os.Mkdir(d, bundleDirPerms); err != nil {
 		return nil, errgrpc.ToGRPC(err)
@@ -121,6 +144,12 @@ func (s *service) Create(ctx context.Context, r *api.CreateRequest) (_ *api.Crea
 	}
 
 	for f, b := range r.Files {
+		if dir, ok := strings.CutSuffix(f, dirArchiveSuffix); ok {
+			if err := extractDirArchive(d, dir, b); err != nil {
+				return nil, errgrpc.ToGRPC(fmt.Errorf("failed to extract %q: %w", f, err))
+			}
+			continue
+		}
 		if err := os.WriteFile(filepath.Join(d, f), b, bundleFilePerms); err != nil {
 			return nil, errgrpc.ToGRPC(err)
 		}
@@ -130,3 +159,62 @@ func (s *service) Create(ctx context.Context, r *api.CreateRequest) (_ *api.Crea
 		Bundle: d,
 	}, nil
 }
+
+// extractDirArchive unpacks a directory archive into dir under the bundle
+// directory d. Only directories and regular files are accepted; all paths
+// are resolved through os.Root so entries cannot escape the target.
+func extractDirArchive(d, dir string, data []byte) error {
+	bundleRoot, err := os.OpenRoot(d)
+	if err != nil {
+		return err
+	}
+	defer bundleRoot.Close()
+	if err := bundleRoot.Mkdir(dir, bundleDirPerms); err != nil {
+		return err
+	}
+	root, err := bundleRoot.OpenRoot(dir)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := path.Clean(hdr.Name)
+		perm := fs.FileMode(hdr.Mode).Perm()
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := root.MkdirAll(name, perm); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := root.MkdirAll(path.Dir(name), bundleDirPerms); err != nil {
+				return err
+			}
+			if err := writeArchiveFile(root, name, perm, tr); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported entry type %q for %q", hdr.Typeflag, hdr.Name)
+		}
+	}
+}
+
+func writeArchiveFile(root *os.Root, name string, perm fs.FileMode, r io.Reader) error {
+	f, err := root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"os"
 	"path/filepath"
@@ -371,6 +373,114 @@ func TestServiceCreate_CleanupOnFileWriteFailure(t *testing.T) {
 	// but that's OK - in real usage, Create() creates the directory itself
 }
 
+func buildTar(t *testing.T, entries []tar.Header, data map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, hdr := range entries {
+		hdr.Size = int64(len(data[hdr.Name]))
+		if err := tw.WriteHeader(&hdr); err != nil {
+			t.Fatalf("WriteHeader(%q) error = %v", hdr.Name, err)
+		}
+		if _, err := tw.Write([]byte(data[hdr.Name])); err != nil {
+			t.Fatalf("Write(%q) error = %v", hdr.Name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestServiceCreate_DirArchive(t *testing.T) {
+	t.Run("extracts directory archive", func(t *testing.T) {
+		svc := &service{bundleRoot: t.TempDir()}
+		archive := buildTar(t, []tar.Header{
+			{Name: "a.conf", Typeflag: tar.TypeReg, Mode: 0644},
+			{Name: "sub/", Typeflag: tar.TypeDir, Mode: 0755},
+			{Name: "sub/b.conf", Typeflag: tar.TypeReg, Mode: 0600},
+		}, map[string]string{"a.conf": "a=1", "sub/b.conf": "b=2"})
+
+		resp, err := svc.Create(context.Background(), &api.CreateRequest{
+			ID: "test-bundle",
+			Files: map[string][]byte{
+				"config.json":               []byte(`{}`),
+				"conf.d" + dirArchiveSuffix: archive,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		for name, want := range map[string]string{"a.conf": "a=1", "sub/b.conf": "b=2"} {
+			data, err := os.ReadFile(filepath.Join(resp.Bundle, "conf.d", name))
+			if err != nil {
+				t.Fatalf("ReadFile(%q) error = %v", name, err)
+			}
+			if string(data) != want {
+				t.Errorf("%s = %q, want %q", name, data, want)
+			}
+		}
+		info, err := os.Stat(filepath.Join(resp.Bundle, "conf.d", "a.conf"))
+		if err != nil {
+			t.Fatalf("Stat() error = %v", err)
+		}
+		if info.Mode().Perm() != 0644 {
+			t.Errorf("a.conf mode = %v, want 0644", info.Mode().Perm())
+		}
+		if _, err := os.Stat(filepath.Join(resp.Bundle, "conf.d"+dirArchiveSuffix)); !os.IsNotExist(err) {
+			t.Errorf("archive was written to the bundle: %v", err)
+		}
+	})
+
+	for _, tc := range []struct {
+		name  string
+		files map[string][]byte
+	}{
+		{
+			name: "entry escaping the directory",
+			files: map[string][]byte{"conf.d" + dirArchiveSuffix: buildTar(t, []tar.Header{
+				{Name: "../../escape", Typeflag: tar.TypeReg, Mode: 0644},
+			}, nil)},
+		},
+		{
+			name: "symlink entry",
+			files: map[string][]byte{"conf.d" + dirArchiveSuffix: buildTar(t, []tar.Header{
+				{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "/etc/shadow"},
+			}, nil)},
+		},
+		{
+			name:  "archive without a directory name",
+			files: map[string][]byte{dirArchiveSuffix: buildTar(t, nil, nil)},
+		},
+		{
+			name:  "archive shadowing rootfs",
+			files: map[string][]byte{rootfsDir + dirArchiveSuffix: buildTar(t, nil, nil)},
+		},
+		{
+			name: "archive conflicting with a file",
+			files: map[string][]byte{
+				"conf.d":                    []byte("x"),
+				"conf.d" + dirArchiveSuffix: buildTar(t, nil, nil),
+			},
+		},
+	} {
+		t.Run("rejects "+tc.name, func(t *testing.T) {
+			bundleRoot := t.TempDir()
+			svc := &service{bundleRoot: bundleRoot}
+			if _, err := svc.Create(context.Background(), &api.CreateRequest{ID: "test-bundle", Files: tc.files}); err == nil {
+				t.Fatal("Create() succeeded, want error")
+			}
+			if _, err := os.Stat(filepath.Join(bundleRoot, "test-bundle")); !os.IsNotExist(err) {
+				t.Errorf("bundle directory not cleaned up: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(bundleRoot, "escape")); !os.IsNotExist(err) {
+				t.Errorf("archive entry escaped the bundle: %v", err)
+			}
+		})
+	}
+}
+
 func TestServiceRegisterTTRPC(t *testing.T) {
 	svc := &service{
 		bundleRoot: t.TempDir(),
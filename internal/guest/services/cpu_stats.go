@@ -0,0 +1,190 @@
+//go:build linux
+
+package services
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/containerd/errdefs/pkg/errgrpc"
+	"github.com/containerd/log"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+
+	api "github.com/spin-stack/spinbox/api/services/system/v1"
+)
+
+// procStatPath is the kernel CPU statistics file.
+const procStatPath = "/proc/stat"
+
+// cpuTimes holds the cumulative time counters of one CPU from /proc/stat,
+// in USER_HZ ticks.
+type cpuTimes struct {
+	User    uint64
+	Nice    uint64
+	System  uint64
+	Idle    uint64
+	IOWait  uint64
+	IRQ     uint64
+	SoftIRQ uint64
+	Steal   uint64
+}
+
+func (t cpuTimes) total() uint64 {
+	return t.User + t.Nice + t.System + t.Idle + t.IOWait + t.IRQ + t.SoftIRQ + t.Steal
+}
+
+// sub returns the counters elapsed since prev. ok is false if any counter
+// went backwards, which means prev does not describe the same CPU.
+func (t cpuTimes) sub(prev cpuTimes) (cpuTimes, bool) {
+	cur := []uint64{t.User, t.Nice, t.System, t.Idle, t.IOWait, t.IRQ, t.SoftIRQ, t.Steal}
+	old := []uint64{prev.User, prev.Nice, prev.System, prev.Idle, prev.IOWait, prev.IRQ, prev.SoftIRQ, prev.Steal}
+	for i := range cur {
+		if cur[i] < old[i] {
+			return cpuTimes{}, false
+		}
+	}
+	return cpuTimes{
+		User:    t.User - prev.User,
+		Nice:    t.Nice - prev.Nice,
+		System:  t.System - prev.System,
+		Idle:    t.Idle - prev.Idle,
+		IOWait:  t.IOWait - prev.IOWait,
+		IRQ:     t.IRQ - prev.IRQ,
+		SoftIRQ: t.SoftIRQ - prev.SoftIRQ,
+		Steal:   t.Steal - prev.Steal,
+	}, true
+}
+
+// cpuUtilization is the utilization of one CPU over an interval.
+// Percentages are of the interval's total time (0-100).
+type cpuUtilization struct {
+	CPU    int
+	Busy   float64 // everything except idle and iowait
+	User   float64 // user + nice
+	System float64 // system + irq + softirq
+	IOWait float64
+	Steal  float64
+}
+
+// parseProcStat reads the per-CPU lines ("cpuN ...") from /proc/stat content.
+// The aggregate "cpu" line is skipped. Offline CPUs are absent.
+func parseProcStat(r io.Reader) (map[int]cpuTimes, error) {
+	stats := make(map[int]cpuTimes)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		idStr, ok := strings.CutPrefix(fields[0], "cpu")
+		if !ok || idStr == "" {
+			continue
+		}
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpu line %q: %w", fields[0], err)
+		}
+		// Kernels since 2.6.11 report at least 8 counters
+		if len(fields) < 9 {
+			return nil, fmt.Errorf("cpu%d: expected at least 8 counters, got %d", id, len(fields)-1)
+		}
+
+		var v [8]uint64
+		for i := range v {
+			v[i], err = strconv.ParseUint(fields[i+1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("cpu%d: invalid counter %q: %w", id, fields[i+1], err)
+			}
+		}
+		stats[id] = cpuTimes{
+			User: v[0], Nice: v[1], System: v[2], Idle: v[3],
+			IOWait: v[4], IRQ: v[5], SoftIRQ: v[6], Steal: v[7],
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// computeCPUUtilization returns per-CPU utilization between two snapshots,
+// ordered by CPU number. A CPU without a usable previous sample (first call,
+// newly onlined CPU) reports its utilization since boot.
+func computeCPUUtilization(prev, cur map[int]cpuTimes) []cpuUtilization {
+	result := make([]cpuUtilization, 0, len(cur))
+	for _, id := range slices.Sorted(maps.Keys(cur)) {
+		times := cur[id]
+		if old, ok := prev[id]; ok {
+			if delta, ok := times.sub(old); ok {
+				times = delta
+			}
+		}
+
+		u := cpuUtilization{CPU: id}
+		if total := times.total(); total > 0 {
+			pct := func(v uint64) float64 { return float64(v) * 100 / float64(total) }
+			u.Busy = pct(total - times.Idle - times.IOWait)
+			u.User = pct(times.User + times.Nice)
+			u.System = pct(times.System + times.IRQ + times.SoftIRQ)
+			u.IOWait = pct(times.IOWait)
+			u.Steal = pct(times.Steal)
+		}
+		result = append(result, u)
+	}
+	return result
+}
+
+// CPUUtilization reports per-CPU utilization since the previous call.
+// The first call reports utilization since boot. This surfaces imbalance
+// across vCPUs that aggregate cgroup statistics hide.
+func (s *systemService) CPUUtilization(ctx context.Context, _ *emptypb.Empty) (*api.CPUUtilizationResponse, error) {
+	cur, err := readProcStat(ctx, procStatPath)
+	if err != nil {
+		return nil, errgrpc.ToGRPC(err)
+	}
+
+	s.cpuMu.Lock()
+	prev := s.prevCPUTimes
+	s.prevCPUTimes = cur
+	s.cpuMu.Unlock()
+
+	utilization := computeCPUUtilization(prev, cur)
+	resp := &api.CPUUtilizationResponse{Cpus: make([]*api.CPUUtilization, 0, len(utilization))}
+	for _, u := range utilization {
+		resp.Cpus = append(resp.Cpus, &api.CPUUtilization{
+			Cpu:    uint32(u.CPU),
+			Busy:   u.Busy,
+			User:   u.User,
+			System: u.System,
+			Iowait: u.IOWait,
+			Steal:  u.Steal,
+		})
+	}
+	return resp, nil
+}
+
+// readProcStat reads the per-CPU counters from the /proc/stat file at path.
+func readProcStat(ctx context.Context, path string) (map[int]cpuTimes, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to close %s", path)
+		}
+	}()
+
+	stats, err := parseProcStat(f)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return stats, nil
+}
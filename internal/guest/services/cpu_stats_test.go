@@ -0,0 +1,130 @@
+//go:build linux
+
+package services
+
+import (
+	"strings"
+	"testing"
+
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+const procStatFirst = `cpu  300 0 150 1550 0 0 0 0 0 0
+cpu0 100 0 50 850 0 0 0 0 0 0
+cpu1 200 0 100 700 0 0 0 0 0 0
+intr 12345 0 0
+ctxt 6789
+`
+
+const procStatSecond = `cpu  500 0 250 2200 50 0 0 0 0 0
+cpu0 180 0 70 900 50 0 0 0 0 0
+cpu1 320 0 180 1300 0 0 0 0 0 0
+intr 23456 0 0
+ctxt 7890
+`
+
+func TestParseProcStat(t *testing.T) {
+	stats, err := parseProcStat(strings.NewReader(procStatFirst))
+	if err != nil {
+		t.Fatalf("parseProcStat() error = %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("parseProcStat() returned %d CPUs, want 2", len(stats))
+	}
+	want := cpuTimes{User: 200, System: 100, Idle: 700}
+	if stats[1] != want {
+		t.Errorf("cpu1 = %+v, want %+v", stats[1], want)
+	}
+
+	for _, bad := range []string{
+		"cpu0 1 2 3\n",
+		"cpu0 1 2 3 4 5 6 7 x\n",
+		"cpux 1 2 3 4 5 6 7 8\n",
+	} {
+		if _, err := parseProcStat(strings.NewReader(bad)); err == nil {
+			t.Errorf("parseProcStat(%q) expected error", bad)
+		}
+	}
+}
+
+func TestComputeCPUUtilization(t *testing.T) {
+	first, err := parseProcStat(strings.NewReader(procStatFirst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := parseProcStat(strings.NewReader(procStatSecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("first call reports since boot", func(t *testing.T) {
+		got := computeCPUUtilization(nil, first)
+		if len(got) != 2 {
+			t.Fatalf("got %d CPUs, want 2", len(got))
+		}
+		// cpu0: 150 busy of 1000
+		if got[0].CPU != 0 || got[0].Busy != 15 {
+			t.Errorf("cpu0 = %+v, want Busy 15", got[0])
+		}
+		// cpu1: 300 busy of 1000
+		if got[1].CPU != 1 || got[1].Busy != 30 {
+			t.Errorf("cpu1 = %+v, want Busy 30", got[1])
+		}
+	})
+
+	t.Run("delta between samples", func(t *testing.T) {
+		got := computeCPUUtilization(first, second)
+		if len(got) != 2 {
+			t.Fatalf("got %d CPUs, want 2", len(got))
+		}
+		// cpu0 delta: user 80, system 20, idle 50, iowait 50 -> total 200
+		want0 := cpuUtilization{CPU: 0, Busy: 50, User: 40, System: 10, IOWait: 25}
+		if got[0] != want0 {
+			t.Errorf("cpu0 = %+v, want %+v", got[0], want0)
+		}
+		// cpu1 delta: user 120, system 80, idle 600 -> total 800
+		want1 := cpuUtilization{CPU: 1, Busy: 25, User: 15, System: 10}
+		if got[1] != want1 {
+			t.Errorf("cpu1 = %+v, want %+v", got[1], want1)
+		}
+	})
+
+	t.Run("offline and idle CPUs", func(t *testing.T) {
+		cur := map[int]cpuTimes{0: first[0], 2: {}}
+		got := computeCPUUtilization(first, cur)
+		if len(got) != 2 || got[0].CPU != 0 || got[1].CPU != 2 {
+			t.Fatalf("got %+v, want CPUs 0 and 2", got)
+		}
+		if got[0] != (cpuUtilization{CPU: 0}) || got[1] != (cpuUtilization{CPU: 2}) {
+			t.Errorf("got %+v, want zero utilization", got)
+		}
+	})
+
+	t.Run("counters going backwards fall back to since boot", func(t *testing.T) {
+		got := computeCPUUtilization(second, first)
+		if got[0].Busy != 15 {
+			t.Errorf("cpu0 Busy = %v, want 15", got[0].Busy)
+		}
+	})
+}
+
+func TestSystemService_CPUUtilization(t *testing.T) {
+	s := &systemService{}
+	for range 2 {
+		resp, err := s.CPUUtilization(t.Context(), &emptypb.Empty{})
+		if err != nil {
+			t.Fatalf("CPUUtilization() error = %v", err)
+		}
+		if len(resp.Cpus) == 0 {
+			t.Fatal("CPUUtilization() returned no CPUs")
+		}
+		for i, u := range resp.Cpus {
+			if i > 0 && u.Cpu <= resp.Cpus[i-1].Cpu {
+				t.Errorf("CPUs not ordered: cpu%d after cpu%d", u.Cpu, resp.Cpus[i-1].Cpu)
+			}
+			if u.Busy < 0 || u.Busy > 100 {
+				t.Errorf("cpu%d Busy = %v, want 0-100", u.Cpu, u.Busy)
+			}
+		}
+	}
+}
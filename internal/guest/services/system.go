@@ -3,12 +3,14 @@
 package services
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 	"unsafe"
 
@@ -23,6 +25,8 @@ import (
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
 
 	api "github.com/spin-stack/spinbox/api/services/system/v1"
+	"github.com/spin-stack/spinbox/internal/guest/vminit/system"
+	"github.com/spin-stack/spinbox/internal/version"
 )
 
 const (
@@ -39,7 +43,22 @@ const (
 	featuresFilePerms = 0600
 )
 
-type systemService struct{}
+const (
+	// Default locations read by Info; overridable for tests
+	procVersionPath = "/proc/version"
+	cgroupRootPath  = "/sys/fs/cgroup"
+)
+
+type systemService struct {
+	// Previous /proc/stat sample for CPUUtilization deltas
+	cpuMu        sync.Mutex
+	prevCPUTimes map[int]cpuTimes
+
+	// procVersion and cgroupRoot override procVersionPath and
+	// cgroupRootPath when set
+	procVersion string
+	cgroupRoot  string
+}
 
 var _ api.TTRPCSystemService = &systemService{}
 
@@ -205,16 +224,76 @@ func waitForSysfsFilePoll(ctx context.Context, path string, timeout time.Duratio
 }
 
 func (s *systemService) Info(ctx context.Context, _ *emptypb.Empty) (*api.InfoResponse, error) {
-	v, err := os.ReadFile("/proc/version")
+	procVersion := cmp.Or(s.procVersion, procVersionPath)
+	v, err := os.ReadFile(procVersion)
 	if err != nil && !os.IsNotExist(err) {
 		return nil, errgrpc.ToGRPC(err)
 	}
+	cgroupVersion, controllers, err := cgroupInfo(cmp.Or(s.cgroupRoot, cgroupRootPath))
+	if err != nil {
+		return nil, errgrpc.ToGRPC(err)
+	}
 	return &api.InfoResponse{
-		Version:       "dev",
-		KernelVersion: string(v),
+		Version:           version.Version,
+		KernelVersion:     string(v),
+		Revision:          version.Revision,
+		BuildDate:         version.BuildDate,
+		CgroupVersion:     cgroupVersion,
+		CgroupControllers: controllers,
+		UptimeNs:          uptime().Nanoseconds(),
+		InitDurationNs:    system.InitDuration().Nanoseconds(),
+		Containers:        containerUsage(ctx),
 	}, nil
 }
 
+// containerUsage converts the usage reported by the task service.
+func containerUsage(ctx context.Context) []*api.ContainerUsage {
+	usage := system.Usage(ctx)
+	if len(usage) == 0 {
+		return nil
+	}
+	out := make([]*api.ContainerUsage, 0, len(usage))
+	for _, u := range usage {
+		out = append(out, &api.ContainerUsage{
+			ContainerID:      u.ID,
+			RunningExecs:     uint32(u.RunningExecs),
+			CpuUsageUsec:     u.CPUUsageUsec,
+			MemoryUsageBytes: u.MemoryUsageBytes,
+		})
+	}
+	return out
+}
+
+// uptime returns the time since vminitd recorded its boot time, or zero if
+// it has not.
+func uptime() time.Duration {
+	boot := system.BootTime()
+	if boot.IsZero() {
+		return 0
+	}
+	return time.Since(boot)
+}
+
+// cgroupInfo reports the cgroup hierarchy version mounted at root and, for
+// cgroup v2, the controllers available in the root cgroup. The version is 0
+// when no hierarchy is mounted.
+func cgroupInfo(root string) (uint32, []string, error) {
+	data, err := os.ReadFile(filepath.Join(root, "cgroup.controllers"))
+	if err == nil {
+		return 2, strings.Fields(string(data)), nil
+	}
+	if !os.IsNotExist(err) {
+		return 0, nil, fmt.Errorf("read cgroup controllers: %w", err)
+	}
+	if _, err := os.Stat(root); err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil, nil
+		}
+		return 0, nil, fmt.Errorf("stat cgroup root: %w", err)
+	}
+	return 1, nil, nil
+}
+
 func (s *systemService) OfflineCPU(ctx context.Context, req *api.OfflineCPURequest) (*emptypb.Empty, error) {
 	cpuID := req.GetCpuID()
 	if cpuID == 0 {
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
@@ -14,6 +15,8 @@ import (
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
 
 	api "github.com/spin-stack/spinbox/api/services/system/v1"
+	"github.com/spin-stack/spinbox/internal/guest/vminit/system"
+	"github.com/spin-stack/spinbox/internal/version"
 )
 
 // Test helper functions
@@ -186,6 +189,104 @@ func TestSystemServiceInfo(t *testing.T) {
 		// On Linux it should contain version info
 		_ = resp.KernelVersion
 	})
+
+	t.Run("reports build information", func(t *testing.T) {
+		oldVersion, oldRevision, oldDate := version.Version, version.Revision, version.BuildDate
+		t.Cleanup(func() {
+			version.Version, version.Revision, version.BuildDate = oldVersion, oldRevision, oldDate
+		})
+		version.Version = "v1.2.3"
+		version.Revision = "0123456789abcdef"
+		version.BuildDate = "2026-01-02T03:04:05Z"
+
+		svc := &systemService{}
+		resp, err := svc.Info(context.Background(), &emptypb.Empty{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Version != "v1.2.3" {
+			t.Errorf("version = %q, want %q", resp.Version, "v1.2.3")
+		}
+		if resp.Revision != "0123456789abcdef" {
+			t.Errorf("revision = %q, want %q", resp.Revision, "0123456789abcdef")
+		}
+		if resp.BuildDate != "2026-01-02T03:04:05Z" {
+			t.Errorf("build date = %q, want %q", resp.BuildDate, "2026-01-02T03:04:05Z")
+		}
+	})
+
+	t.Run("missing /proc/version", func(t *testing.T) {
+		dir := t.TempDir()
+		svc := &systemService{
+			procVersion: filepath.Join(dir, "version"),
+			cgroupRoot:  filepath.Join(dir, "cgroup"),
+		}
+
+		resp, err := svc.Info(context.Background(), &emptypb.Empty{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.KernelVersion != "" {
+			t.Errorf("kernel version = %q, want empty", resp.KernelVersion)
+		}
+		if resp.CgroupVersion != 0 {
+			t.Errorf("cgroup version = %d, want 0", resp.CgroupVersion)
+		}
+	})
+
+	t.Run("cgroup v2", func(t *testing.T) {
+		root := t.TempDir()
+		if err := os.WriteFile(filepath.Join(root, "cgroup.controllers"), []byte("cpuset cpu io memory pids\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		svc := &systemService{cgroupRoot: root}
+
+		resp, err := svc.Info(context.Background(), &emptypb.Empty{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.CgroupVersion != 2 {
+			t.Errorf("cgroup version = %d, want 2", resp.CgroupVersion)
+		}
+		want := []string{"cpuset", "cpu", "io", "memory", "pids"}
+		if !slices.Equal(resp.CgroupControllers, want) {
+			t.Errorf("cgroup controllers = %v, want %v", resp.CgroupControllers, want)
+		}
+	})
+
+	t.Run("cgroup v1", func(t *testing.T) {
+		svc := &systemService{cgroupRoot: t.TempDir()}
+
+		resp, err := svc.Info(context.Background(), &emptypb.Empty{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.CgroupVersion != 1 {
+			t.Errorf("cgroup version = %d, want 1", resp.CgroupVersion)
+		}
+		if len(resp.CgroupControllers) != 0 {
+			t.Errorf("cgroup controllers = %v, want none", resp.CgroupControllers)
+		}
+	})
+}
+
+func TestSystemServiceInfoBootTiming(t *testing.T) {
+	oldBoot := system.BootTime()
+	t.Cleanup(func() { system.SetBootTime(oldBoot) })
+
+	boot := time.Now().Add(-90 * time.Second)
+	system.SetBootTime(boot)
+
+	svc := &systemService{}
+	resp, err := svc.Info(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := time.Duration(resp.UptimeNs)
+	if got < 90*time.Second || got > 95*time.Second {
+		t.Errorf("uptime = %v, want about 90s", got)
+	}
 }
 
 func TestSystemServiceOfflineCPU(t *testing.T) {
@@ -555,3 +656,37 @@ func BenchmarkWriteSysfsValue(b *testing.B) {
 		_ = writeSysfsValue(tmpFile, "1")
 	}
 }
+
+func TestSystemServiceInfoContainers(t *testing.T) {
+	t.Cleanup(func() { system.SetUsageReporter(nil) })
+
+	svc := &systemService{}
+	resp, err := svc.Info(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Containers) != 0 {
+		t.Errorf("containers = %v, want none without a reporter", resp.Containers)
+	}
+
+	system.SetUsageReporter(func(context.Context) []system.ContainerUsage {
+		return []system.ContainerUsage{
+			{ID: "a", RunningExecs: 2, CPUUsageUsec: 1500, MemoryUsageBytes: 4096},
+			{ID: "b"},
+		}
+	})
+	resp, err = svc.Info(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Containers) != 2 {
+		t.Fatalf("got %d containers, want 2", len(resp.Containers))
+	}
+	a := resp.Containers[0]
+	if a.ContainerID != "a" || a.RunningExecs != 2 || a.CpuUsageUsec != 1500 || a.MemoryUsageBytes != 4096 {
+		t.Errorf("container a = %v", a)
+	}
+	if resp.Containers[1].ContainerID != "b" {
+		t.Errorf("second container = %q, want b", resp.Containers[1].ContainerID)
+	}
+}
@@ -0,0 +1,285 @@
+//go:build linux
+
+package devices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/containerd/log"
+	"github.com/moby/sys/mountinfo"
+	"golang.org/x/sys/unix"
+)
+
+// ResizePollInterval is how often ResizeWatcher reads block device sizes.
+const ResizePollInterval = 2 * time.Second
+
+// Online resize ioctls from linux/ext4.h and xfs_fs.h, which x/sys/unix
+// does not define. The initrd ships no resize2fs or xfs_growfs, so the
+// filesystems are grown through the kernel directly.
+const (
+	ext4IocResizeFS    = 0x40086610 // _IOW('f', 16, __u64)
+	xfsIocFSGeometryV1 = 0x80705864 // _IOR('X', 100, struct xfs_fsop_geom_v1)
+	xfsIocFSGrowFSData = 0x4010586e // _IOW('X', 110, struct xfs_growfs_data)
+)
+
+// growers maps filesystem types that can grow while mounted to the function
+// that grows the filesystem mounted at mountpoint to fill a device of size
+// bytes. Other filesystems are left alone.
+var growers = map[string]func(mountpoint string, size uint64) error{
+	"ext4": growExt4,
+	"ext3": growExt4,
+	"xfs":  growXFS,
+}
+
+// ResizeWatcher grows the filesystems on data disks that are resized while
+// the VM is running. The host grows the image and QEMU notifies the guest
+// kernel, which updates the device capacity; the filesystem on it keeps its
+// old size until it is grown explicitly.
+//
+// Only filesystems mounted read-write directly on a whole virtio block
+// device are grown. Partitioned disks and filesystems without online growth
+// are skipped.
+type ResizeWatcher struct {
+	sysBlock  string // /sys/block
+	mountInfo string // /proc/self/mountinfo
+	interval  time.Duration
+	grow      func(fstype, mountpoint string, size uint64) error
+
+	sizes map[string]uint64 // last seen size of each device, in sectors
+}
+
+var startResizeWatcher sync.Once
+
+// StartResizeWatcher runs a ResizeWatcher for the running system in the
+// background. Only the first call starts one; callers invoke it once they
+// have mounted a data disk, so VMs without one never poll /sys/block.
+func StartResizeWatcher(ctx context.Context) {
+	startResizeWatcher.Do(func() {
+		go NewResizeWatcher().Run(context.WithoutCancel(ctx))
+	})
+}
+
+// IsDataDisk reports whether a mount of source with options is a read-write
+// filesystem on a virtio block device, which ResizeWatcher can grow.
+func IsDataDisk(source string, options []string) bool {
+	return strings.HasPrefix(source, "/dev/vd") && !slices.Contains(options, "ro")
+}
+
+// NewResizeWatcher returns a ResizeWatcher for the running system.
+func NewResizeWatcher() *ResizeWatcher {
+	return &ResizeWatcher{
+		sysBlock:  "/sys/block",
+		mountInfo: "/proc/self/mountinfo",
+		interval:  ResizePollInterval,
+		grow:      growFilesystem,
+		sizes:     make(map[string]uint64),
+	}
+}
+
+// Run polls block device sizes until ctx is cancelled.
+func (w *ResizeWatcher) Run(ctx context.Context) {
+	t := time.NewTicker(w.interval)
+	defer t.Stop()
+
+	w.check(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			w.check(ctx)
+		}
+	}
+}
+
+// check reads the size of every virtio block device and grows the
+// filesystems on those that grew since the last check. The first size seen
+// for a device is only recorded.
+func (w *ResizeWatcher) check(ctx context.Context) {
+	entries, err := os.ReadDir(w.sysBlock)
+	if err != nil {
+		log.G(ctx).WithError(err).Debug("failed to list block devices")
+		return
+	}
+	for _, entry := range entries {
+		dev := entry.Name()
+		if !strings.HasPrefix(dev, "vd") {
+			continue
+		}
+		size, err := w.deviceSize(dev)
+		if err != nil {
+			log.G(ctx).WithError(err).WithField("device", dev).Debug("failed to read block device size")
+			continue
+		}
+		prev, seen := w.sizes[dev]
+		w.sizes[dev] = size
+		if !seen || size <= prev {
+			continue
+		}
+
+		log.G(ctx).WithFields(log.Fields{
+			"device":      dev,
+			"old_sectors": prev,
+			"new_sectors": size,
+		}).Info("block device grew")
+		w.rescan(ctx, dev)
+		if err := w.growFilesystems(ctx, dev, size*512); err != nil {
+			log.G(ctx).WithError(err).WithField("device", dev).Warn("failed to grow filesystem")
+		}
+	}
+}
+
+// deviceSize returns the size of dev in 512-byte sectors.
+func (w *ResizeWatcher) deviceSize(dev string) (uint64, error) {
+	b, err := os.ReadFile(filepath.Join(w.sysBlock, dev, "size"))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+}
+
+// rescan asks the driver to re-read the device capacity where it supports
+// it. virtio-blk updates the capacity on its own and has no rescan
+// attribute, so this is a no-op there.
+func (w *ResizeWatcher) rescan(ctx context.Context, dev string) {
+	p := filepath.Join(w.sysBlock, dev, "device", "rescan")
+	if _, err := os.Stat(p); err != nil {
+		return
+	}
+	if err := os.WriteFile(p, []byte("1"), 0o200); err != nil {
+		log.G(ctx).WithError(err).WithField("device", dev).Debug("failed to rescan block device")
+	}
+}
+
+// growFilesystems grows every read-write filesystem mounted from dev to size
+// bytes.
+func (w *ResizeWatcher) growFilesystems(ctx context.Context, dev string, size uint64) error {
+	f, err := os.Open(w.mountInfo)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	device := "/dev/" + dev
+	mounts, err := mountinfo.GetMountsFromReader(f, func(m *mountinfo.Info) (bool, bool) {
+		return m.Source != device, false
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read mounts: %w", err)
+	}
+
+	// A filesystem mounted in several places only needs growing once
+	grown := false
+	var errs []error
+	for _, m := range mounts {
+		logger := log.G(ctx).WithFields(log.Fields{
+			"device":     device,
+			"mountpoint": m.Mountpoint,
+			"fstype":     m.FSType,
+		})
+		if slices.Contains(strings.Split(m.Options, ","), "ro") {
+			logger.Debug("skipping read-only mount")
+			continue
+		}
+		if _, ok := growers[m.FSType]; !ok {
+			logger.Info("filesystem does not support online growth, skipping")
+			continue
+		}
+		if grown {
+			continue
+		}
+		if err := w.grow(m.FSType, m.Mountpoint, size); err != nil {
+			errs = append(errs, fmt.Errorf("grow %s at %s: %w", m.FSType, m.Mountpoint, err))
+			continue
+		}
+		grown = true
+		logger.Info("grew filesystem")
+	}
+	return errors.Join(errs...)
+}
+
+func growFilesystem(fstype, mountpoint string, size uint64) error {
+	return growers[fstype](mountpoint, size)
+}
+
+// growExt4 resizes an ext4 filesystem with EXT4_IOC_RESIZE_FS, which takes
+// the new size in filesystem blocks.
+func growExt4(mountpoint string, size uint64) error {
+	var st unix.Statfs_t
+	if err := unix.Statfs(mountpoint, &st); err != nil {
+		return err
+	}
+	blocks := size / uint64(st.Bsize)
+	// #nosec G103 -- unsafe.Pointer is required to pass the ioctl argument
+	return ioctlMountpoint(mountpoint, ext4IocResizeFS, unsafe.Pointer(&blocks))
+}
+
+// xfsGeometryV1 is struct xfs_fsop_geom_v1.
+type xfsGeometryV1 struct {
+	Blocksize    uint32
+	Rtextsize    uint32
+	Agblocks     uint32
+	Agcount      uint32
+	Logblocks    uint32
+	Sectsize     uint32
+	Inodesize    uint32
+	Imaxpct      uint32
+	Datablocks   uint64
+	Rtblocks     uint64
+	Rtextents    uint64
+	Logstart     uint64
+	UUID         [16]byte
+	Sunit        uint32
+	Swidth       uint32
+	Version      int32
+	Flags        uint32
+	Logsectsize  uint32
+	Rtsectsize   uint32
+	Dirblocksize uint32
+}
+
+// xfsGrowFSData is struct xfs_growfs_data.
+type xfsGrowFSData struct {
+	Newblocks uint64
+	Imaxpct   uint32
+	_         uint32
+}
+
+// growXFS grows the data section of an XFS filesystem with
+// XFS_IOC_FSGROWFSDATA. The inode space limit is passed through unchanged.
+func growXFS(mountpoint string, size uint64) error {
+	var geo xfsGeometryV1
+	// #nosec G103 -- unsafe.Pointer is required to pass the ioctl argument
+	if err := ioctlMountpoint(mountpoint, xfsIocFSGeometryV1, unsafe.Pointer(&geo)); err != nil {
+		return fmt.Errorf("read geometry: %w", err)
+	}
+	req := xfsGrowFSData{Newblocks: size / uint64(geo.Blocksize), Imaxpct: geo.Imaxpct}
+	if req.Newblocks <= geo.Datablocks {
+		return nil
+	}
+	// #nosec G103 -- unsafe.Pointer is required to pass the ioctl argument
+	return ioctlMountpoint(mountpoint, xfsIocFSGrowFSData, unsafe.Pointer(&req))
+}
+
+// ioctlMountpoint issues ioctl req on the filesystem mounted at mountpoint.
+func ioctlMountpoint(mountpoint string, req uintptr, arg unsafe.Pointer) error {
+	f, err := os.Open(mountpoint)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), req, uintptr(arg)); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+//go:build linux
+
+package devices
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBlockSystem is a fake /sys/block and mountinfo for a ResizeWatcher.
+type fakeBlockSystem struct {
+	t     *testing.T
+	w     *ResizeWatcher
+	grown []growCall
+	fail  error
+}
+
+// growCall records one filesystem growth.
+type growCall struct {
+	fstype     string
+	mountpoint string
+	size       uint64
+}
+
+func newFakeBlockSystem(t *testing.T, mounts ...string) *fakeBlockSystem {
+	t.Helper()
+	dir := t.TempDir()
+	f := &fakeBlockSystem{t: t}
+	f.w = &ResizeWatcher{
+		sysBlock:  filepath.Join(dir, "block"),
+		mountInfo: filepath.Join(dir, "mountinfo"),
+		grow: func(fstype, mountpoint string, size uint64) error {
+			f.grown = append(f.grown, growCall{fstype, mountpoint, size})
+			return f.fail
+		},
+		sizes: make(map[string]uint64),
+	}
+	require.NoError(t, os.MkdirAll(f.w.sysBlock, 0o755))
+	require.NoError(t, os.WriteFile(f.w.mountInfo, []byte(strings.Join(mounts, "\n")+"\n"), 0o600))
+	return f
+}
+
+// setSize creates dev if needed and sets its size in sectors.
+func (f *fakeBlockSystem) setSize(dev, sectors string) {
+	f.t.Helper()
+	dir := filepath.Join(f.w.sysBlock, dev)
+	require.NoError(f.t, os.MkdirAll(dir, 0o755))
+	require.NoError(f.t, os.WriteFile(filepath.Join(dir, "size"), []byte(sectors+"\n"), 0o600))
+}
+
+func TestResizeWatcher(t *testing.T) {
+	const (
+		ext4Mount = "36 25 253:16 / /tmp rw,nosuid,nodev,noexec - ext4 /dev/vdb rw"
+		xfsMount  = "37 25 253:32 / /data rw,relatime - xfs /dev/vdc rw,attr2"
+		roMount   = "38 25 253:48 / /ro ro,relatime - ext4 /dev/vdd ro"
+		vfatMount = "39 25 253:64 / /boot rw - vfat /dev/vde rw"
+		bindMount = "40 25 253:16 /sub /mnt/tmp rw - ext4 /dev/vdb rw"
+	)
+	ctx := context.Background()
+
+	t.Run("grows mounted filesystems when the device grows", func(t *testing.T) {
+		f := newFakeBlockSystem(t, ext4Mount, xfsMount, bindMount)
+		f.setSize("vdb", "2097152")
+		f.setSize("vdc", "2097152")
+
+		// The first sizes are only recorded
+		f.w.check(ctx)
+		assert.Empty(t, f.grown)
+
+		f.setSize("vdb", "4194304")
+		f.w.check(ctx)
+		assert.Equal(t, []growCall{{"ext4", "/tmp", 2 << 30}}, f.grown, "a filesystem mounted twice is grown once")
+
+		f.setSize("vdc", "4194304")
+		f.w.check(ctx)
+		assert.Equal(t, []growCall{{"ext4", "/tmp", 2 << 30}, {"xfs", "/data", 2 << 30}}, f.grown)
+
+		// No change, no resize
+		f.w.check(ctx)
+		assert.Len(t, f.grown, 2)
+	})
+
+	t.Run("rescans devices that support it", func(t *testing.T) {
+		f := newFakeBlockSystem(t, ext4Mount)
+		f.setSize("vdb", "100")
+		rescan := filepath.Join(f.w.sysBlock, "vdb", "device", "rescan")
+		require.NoError(t, os.MkdirAll(filepath.Dir(rescan), 0o755))
+		require.NoError(t, os.WriteFile(rescan, nil, 0o600))
+		f.w.check(ctx)
+
+		f.setSize("vdb", "200")
+		f.w.check(ctx)
+		b, err := os.ReadFile(rescan)
+		require.NoError(t, err)
+		assert.Equal(t, "1", string(b))
+		assert.Len(t, f.grown, 1)
+	})
+
+	t.Run("skips filesystems that cannot grow online", func(t *testing.T) {
+		f := newFakeBlockSystem(t, roMount, vfatMount)
+		f.setSize("vdd", "100")
+		f.setSize("vde", "100")
+		f.setSize("vdf", "100") // not mounted
+		f.w.check(ctx)
+
+		f.setSize("vdd", "200")
+		f.setSize("vde", "200")
+		f.setSize("vdf", "200")
+		f.w.check(ctx)
+		assert.Empty(t, f.grown)
+	})
+
+	t.Run("shrinking and non-virtio devices are ignored", func(t *testing.T) {
+		f := newFakeBlockSystem(t, ext4Mount, "41 25 7:0 / /loop rw - ext4 /dev/loop0 rw")
+		f.setSize("vdb", "200")
+		f.setSize("loop0", "100")
+		f.w.check(ctx)
+
+		f.setSize("vdb", "100")
+		f.setSize("loop0", "200")
+		f.w.check(ctx)
+		assert.Empty(t, f.grown)
+	})
+
+	t.Run("failed resize is retried on the next growth", func(t *testing.T) {
+		f := newFakeBlockSystem(t, ext4Mount)
+		f.fail = errors.New("inappropriate ioctl for device")
+		f.setSize("vdb", "100")
+		f.w.check(ctx)
+
+		f.setSize("vdb", "200")
+		f.w.check(ctx)
+		err := f.w.growFilesystems(ctx, "vdb", 200*512)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "grow ext4 at /tmp")
+
+		f.fail = nil
+		f.setSize("vdb", "300")
+		f.w.check(ctx)
+		assert.Len(t, f.grown, 3)
+	})
+}
+
+func TestXFSIoctlLayout(t *testing.T) {
+	// The ioctl numbers encode the argument sizes.
+	assert.Equal(t, uintptr(112), unsafe.Sizeof(xfsGeometryV1{}))
+	assert.Equal(t, uintptr(16), unsafe.Sizeof(xfsGrowFSData{}))
+}
+
+func TestIsDataDisk(t *testing.T) {
+	assert.True(t, IsDataDisk("/dev/vdb", nil))
+	assert.True(t, IsDataDisk("/dev/vdc", []string{"rw", "noatime"}))
+	assert.False(t, IsDataDisk("/dev/vdb", []string{"ro"}))
+	assert.False(t, IsDataDisk("/dev/loop0", nil))
+	assert.False(t, IsDataDisk("overlay", nil))
+}
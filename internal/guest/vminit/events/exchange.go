@@ -2,12 +2,27 @@
 package events
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/v2/core/events"
 	"github.com/containerd/containerd/v2/core/events/exchange"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
 	"github.com/containerd/containerd/v2/plugins"
 	"github.com/containerd/plugin"
 	"github.com/containerd/plugin/registry"
+	"github.com/containerd/typeurl/v2"
+	goevents "github.com/docker/go-events"
 )
 
+// DefaultHistorySize is the number of recent events an Exchange keeps for
+// replay when created without WithHistorySize.
+const DefaultHistorySize = 1024
+
 func init() {
 	registry.Register(&plugin.Registration{
 		Type: plugins.EventPlugin,
@@ -18,10 +33,201 @@ func init() {
 	})
 }
 
-// Exchange is an alias to containerd's event exchange implementation.
-type Exchange = exchange.Exchange
+// SequencedEnvelope is an event envelope with the sequence number the
+// Exchange assigned when it was published. Sequence numbers start at 1 and
+// increase by one for every event.
+type SequencedEnvelope struct {
+	Seq uint64
+	*events.Envelope
+}
+
+// ExchangeOpt configures an Exchange.
+type ExchangeOpt func(*Exchange)
+
+// WithHistorySize sets how many recent events are kept for SubscribeFrom.
+// A size of zero disables replay.
+func WithHistorySize(n int) ExchangeOpt {
+	return func(e *Exchange) {
+		e.history = make([]*SequencedEnvelope, max(n, 0))
+	}
+}
+
+// Exchange is containerd's event exchange extended with a bounded history of
+// recently published events, so a subscriber that reconnects after a dropped
+// stream can replay the events it missed.
+type Exchange struct {
+	*exchange.Exchange
+
+	mu      sync.Mutex
+	seq     uint64               // sequence of the last published event
+	history []*SequencedEnvelope // ring buffer indexed by seq % len(history)
+	replay  map[*replaySubscriber]struct{}
+}
 
 // NewExchange returns a new event Exchange.
-func NewExchange() *Exchange {
-	return exchange.NewExchange()
+func NewExchange(opts ...ExchangeOpt) *Exchange {
+	e := &Exchange{
+		Exchange: exchange.NewExchange(),
+		history:  make([]*SequencedEnvelope, DefaultHistorySize),
+		replay:   make(map[*replaySubscriber]struct{}),
+	}
+	for _, o := range opts {
+		o(e)
+	}
+	return e
+}
+
+// Publish packages and sends an event, assigning it the next sequence number.
+func (e *Exchange) Publish(ctx context.Context, topic string, event events.Event) error {
+	namespace, err := namespaces.NamespaceRequired(ctx)
+	if err != nil {
+		return fmt.Errorf("failed publishing event: %w", err)
+	}
+	encoded, err := typeurl.MarshalAny(event)
+	if err != nil {
+		return err
+	}
+	return e.Forward(ctx, &events.Envelope{
+		Timestamp: time.Now().UTC(),
+		Namespace: namespace,
+		Topic:     topic,
+		Event:     encoded,
+	})
+}
+
+var (
+	// ErrEmptyTopic is returned by PublishWithNamespace for an empty topic.
+	ErrEmptyTopic = errors.New("event topic must not be empty")
+	// ErrInvalidTopic is returned by PublishWithNamespace for a topic that
+	// does not start with "/".
+	ErrInvalidTopic = errors.New("event topic must start with \"/\"")
+	// ErrEmptyNamespace is returned by PublishWithNamespace for an empty
+	// namespace.
+	ErrEmptyNamespace = errors.New("event namespace must not be empty")
+)
+
+// PublishWithNamespace publishes an event in namespace ns, overriding any
+// namespace already set on ctx. The topic must be non-empty and start with
+// "/".
+func (e *Exchange) PublishWithNamespace(ctx context.Context, ns, topic string, event events.Event) error {
+	if ns == "" {
+		return ErrEmptyNamespace
+	}
+	if topic == "" {
+		return ErrEmptyTopic
+	}
+	if !strings.HasPrefix(topic, "/") {
+		return fmt.Errorf("topic %q: %w", topic, ErrInvalidTopic)
+	}
+	return e.Publish(namespaces.WithNamespace(ctx, ns), topic, event)
+}
+
+// Forward distributes an envelope on the exchange, assigning it the next
+// sequence number.
+func (e *Exchange) Forward(ctx context.Context, envelope *events.Envelope) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// Holding mu across the broadcast keeps live delivery in sequence order
+	// and lets SubscribeFrom switch from replay to live without gaps.
+	if err := e.Exchange.Forward(ctx, envelope); err != nil {
+		return err
+	}
+	e.seq++
+	sev := &SequencedEnvelope{Seq: e.seq, Envelope: envelope}
+	if len(e.history) > 0 {
+		e.history[e.seq%uint64(len(e.history))] = sev
+	}
+	for s := range e.replay {
+		_ = s.queue.Write(sev)
+	}
+	return nil
+}
+
+// SubscribeFrom replays the retained events with a sequence number of at
+// least seq and then streams new events as they are published. Cancel ctx to
+// end the subscription. Events are sent on the first channel; the result of
+// the subscription is sent on the error channel, which is then closed.
+//
+// If events from seq onwards were already evicted from the history, replay
+// starts at the oldest retained event and the returned count is the number of
+// events that can no longer be delivered.
+func (e *Exchange) SubscribeFrom(ctx context.Context, seq uint64) (<-chan *SequencedEnvelope, <-chan error, uint64) {
+	var (
+		evch    = make(chan *SequencedEnvelope)
+		errq    = make(chan error, 1)
+		channel = goevents.NewChannel(0)
+		s       = &replaySubscriber{queue: goevents.NewQueue(channel)}
+		missed  uint64
+	)
+
+	e.mu.Lock()
+	seq = max(seq, 1)
+	oldest := e.oldestRetained()
+	if seq < oldest {
+		missed = oldest - seq
+		seq = oldest
+	}
+	for i := seq; i <= e.seq; i++ {
+		_ = s.queue.Write(e.history[i%uint64(len(e.history))])
+	}
+	e.replay[s] = struct{}{}
+	e.mu.Unlock()
+
+	go func() {
+		defer func() {
+			e.mu.Lock()
+			delete(e.replay, s)
+			e.mu.Unlock()
+			_ = channel.Close()
+			_ = s.queue.Close()
+			close(errq)
+		}()
+
+		for {
+			select {
+			case ev := <-channel.C:
+				sev, ok := ev.(*SequencedEnvelope)
+				if !ok {
+					errq <- fmt.Errorf("invalid event %#v on replay subscription", ev)
+					return
+				}
+				select {
+				case evch <- sev:
+				case <-ctx.Done():
+					errq <- subscriptionErr(ctx)
+					return
+				}
+			case <-ctx.Done():
+				errq <- subscriptionErr(ctx)
+				return
+			}
+		}
+	}()
+
+	return evch, errq, missed
+}
+
+// oldestRetained returns the sequence number of the oldest event still in the
+// history, or the next sequence number if none is retained. Callers must
+// hold mu.
+func (e *Exchange) oldestRetained() uint64 {
+	size := uint64(len(e.history))
+	if e.seq < size {
+		return 1
+	}
+	return e.seq - size + 1
+}
+
+type replaySubscriber struct {
+	queue *goevents.Queue
+}
+
+// subscriptionErr mirrors containerd's Subscribe: cancellation ends a
+// subscription cleanly, any other context error is reported.
+func subscriptionErr(ctx context.Context) error {
+	if err := ctx.Err(); !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
 }
@@ -2,6 +2,9 @@ package events
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"testing"
 	"time"
 
@@ -51,3 +54,197 @@ func TestExchange(t *testing.T) {
 		}
 	})
 }
+
+func TestExchangePublishWithNamespace(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		ns      string
+		topic   string
+		wantErr error
+	}{
+		{name: "empty namespace", topic: "/tasks/start", wantErr: ErrEmptyNamespace},
+		{name: "empty topic", ns: "default", wantErr: ErrEmptyTopic},
+		{name: "relative topic", ns: "default", topic: "tasks/start", wantErr: ErrInvalidTopic},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ex := NewExchange()
+			err := ex.PublishWithNamespace(context.Background(), tc.ns, tc.topic, &emptypb.Empty{})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("PublishWithNamespace() error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+
+	t.Run("valid publish", func(t *testing.T) {
+		ex := NewExchange()
+		// The explicit namespace wins over the one on the context
+		ctx := namespaces.WithNamespace(context.Background(), "other")
+		evCh, errCh := ex.Subscribe(ctx)
+
+		if err := ex.PublishWithNamespace(ctx, "k8s.io", "/tasks/start", &emptypb.Empty{}); err != nil {
+			t.Fatalf("PublishWithNamespace() failed: %v", err)
+		}
+
+		select {
+		case env := <-evCh:
+			if env.Namespace != "k8s.io" || env.Topic != "/tasks/start" {
+				t.Fatalf("got %s %s, want k8s.io /tasks/start", env.Namespace, env.Topic)
+			}
+		case err := <-errCh:
+			t.Fatalf("unexpected error: %v", err)
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for event")
+		}
+	})
+}
+
+func publishTopics(t *testing.T, ctx context.Context, ex *Exchange, from, to int) {
+	t.Helper()
+	for i := from; i <= to; i++ {
+		if err := ex.Publish(ctx, fmt.Sprintf("/test/%d", i), &emptypb.Empty{}); err != nil {
+			t.Fatalf("Publish() failed: %v", err)
+		}
+	}
+}
+
+func receiveSeqs(t *testing.T, ch <-chan *SequencedEnvelope, n int) []uint64 {
+	t.Helper()
+	var seqs []uint64
+	for range n {
+		select {
+		case env := <-ch:
+			if want := fmt.Sprintf("/test/%d", env.Seq); env.Topic != want {
+				t.Fatalf("event %d has topic %q, want %q", env.Seq, env.Topic, want)
+			}
+			seqs = append(seqs, env.Seq)
+		case <-time.After(time.Second):
+			t.Fatalf("timeout waiting for events, got %v", seqs)
+		}
+	}
+	return seqs
+}
+
+func seqRange(from, to uint64) []uint64 {
+	var seqs []uint64
+	for i := from; i <= to; i++ {
+		seqs = append(seqs, i)
+	}
+	return seqs
+}
+
+func TestExchangeSubscribeFrom(t *testing.T) {
+	ns := namespaces.WithNamespace(context.Background(), "default")
+
+	t.Run("replays from mid-point then streams live", func(t *testing.T) {
+		ex := NewExchange(WithHistorySize(10))
+		publishTopics(t, ns, ex, 1, 8)
+
+		ctx, cancel := context.WithCancel(ns)
+		defer cancel()
+		ch, _, missed := ex.SubscribeFrom(ctx, 5)
+		if missed != 0 {
+			t.Fatalf("missed = %d, want 0", missed)
+		}
+		publishTopics(t, ns, ex, 9, 12)
+
+		if got, want := receiveSeqs(t, ch, 8), seqRange(5, 12); !slices.Equal(got, want) {
+			t.Fatalf("seqs = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("reports evicted events as missed", func(t *testing.T) {
+		ex := NewExchange(WithHistorySize(4))
+		publishTopics(t, ns, ex, 1, 10)
+
+		ctx, cancel := context.WithCancel(ns)
+		defer cancel()
+		ch, _, missed := ex.SubscribeFrom(ctx, 3)
+		if missed != 4 {
+			t.Fatalf("missed = %d, want 4", missed)
+		}
+		if got, want := receiveSeqs(t, ch, 4), seqRange(7, 10); !slices.Equal(got, want) {
+			t.Fatalf("seqs = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("subscribing from zero replays all retained events", func(t *testing.T) {
+		ex := NewExchange(WithHistorySize(10))
+		publishTopics(t, ns, ex, 1, 3)
+
+		ctx, cancel := context.WithCancel(ns)
+		defer cancel()
+		ch, _, missed := ex.SubscribeFrom(ctx, 0)
+		if missed != 0 {
+			t.Fatalf("missed = %d, want 0", missed)
+		}
+		if got, want := receiveSeqs(t, ch, 3), seqRange(1, 3); !slices.Equal(got, want) {
+			t.Fatalf("seqs = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("future sequence waits for new events", func(t *testing.T) {
+		ex := NewExchange()
+		publishTopics(t, ns, ex, 1, 2)
+
+		ctx, cancel := context.WithCancel(ns)
+		defer cancel()
+		ch, _, _ := ex.SubscribeFrom(ctx, 3)
+		publishTopics(t, ns, ex, 3, 3)
+
+		if got, want := receiveSeqs(t, ch, 1), seqRange(3, 3); !slices.Equal(got, want) {
+			t.Fatalf("seqs = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("disabled history misses everything published", func(t *testing.T) {
+		ex := NewExchange(WithHistorySize(0))
+		publishTopics(t, ns, ex, 1, 5)
+
+		ctx, cancel := context.WithCancel(ns)
+		defer cancel()
+		ch, _, missed := ex.SubscribeFrom(ctx, 1)
+		if missed != 5 {
+			t.Fatalf("missed = %d, want 5", missed)
+		}
+		publishTopics(t, ns, ex, 6, 6)
+		if got, want := receiveSeqs(t, ch, 1), seqRange(6, 6); !slices.Equal(got, want) {
+			t.Fatalf("seqs = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("cancel ends subscription", func(t *testing.T) {
+		ex := NewExchange()
+		ctx, cancel := context.WithCancel(ns)
+		_, errs, _ := ex.SubscribeFrom(ctx, 0)
+		cancel()
+
+		select {
+		case err := <-errs:
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for subscription to end")
+		}
+
+		// Publishing after the subscriber left must not block
+		publishTopics(t, ns, ex, 1, 3)
+	})
+
+	t.Run("plain subscribers still receive events", func(t *testing.T) {
+		ex := NewExchange()
+		ctx, cancel := context.WithCancel(ns)
+		defer cancel()
+		evCh, _ := ex.Subscribe(ctx)
+		publishTopics(t, ns, ex, 1, 1)
+
+		select {
+		case env := <-evCh:
+			if env.Topic != "/test/1" {
+				t.Fatalf("topic = %q, want %q", env.Topic, "/test/1")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for event")
+		}
+	})
+}
@@ -2,10 +2,15 @@ package events
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"net"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/containerd/containerd/api/types"
-	"github.com/containerd/containerd/v2/core/events"
 	"github.com/containerd/containerd/v2/pkg/protobuf"
 	cplugins "github.com/containerd/containerd/v2/plugins"
 	"github.com/containerd/log"
@@ -13,7 +18,6 @@ import (
 	"github.com/containerd/plugin/registry"
 	"github.com/containerd/ttrpc"
 	"github.com/containerd/typeurl/v2"
-	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/spin-stack/spinbox/api/services/vmevents/v1"
 )
@@ -31,18 +35,54 @@ func init() {
 			if err != nil {
 				return nil, err
 			}
-			exchange, ok := p.(Subscriber)
-			if !ok {
-				return nil, plugin.ErrSkipPlugin
-			}
-			return NewService(exchange), nil
+			return NewService(subscriberFor(ic.Context, p)), nil
 		},
 	})
 }
 
-// Subscriber provides access to the event stream.
+// ErrEventsDisabled is reported to vmevents streams when the guest has no
+// usable event exchange.
+var ErrEventsDisabled = errors.New("guest events are disabled: event exchange unavailable")
+
+// subscriberFor returns the exchange plugin as a Subscriber. If it is not
+// one, a disabledSubscriber is returned so Stream fails fast with
+// ErrEventsDisabled instead of the vmevents service being skipped.
+func subscriberFor(ctx context.Context, p interface{}) Subscriber {
+	if sub, ok := p.(Subscriber); ok {
+		return sub
+	}
+	log.G(ctx).WithField("exchange", fmt.Sprintf("%T", p)).Warn("event exchange does not support subscriptions, guest events are disabled")
+	return disabledSubscriber{}
+}
+
+// disabledSubscriber is the Subscriber used when no event exchange is
+// available. Subscriptions end immediately with ErrEventsDisabled.
+type disabledSubscriber struct{}
+
+func (disabledSubscriber) SubscribeFrom(ctx context.Context, _ uint64) (<-chan *SequencedEnvelope, <-chan error, uint64) {
+	log.G(ctx).Warn("vmevents subscription rejected, guest events are disabled")
+	errs := make(chan error, 1)
+	errs <- ErrEventsDisabled
+	close(errs)
+	// A nil events channel is never ready, so Stream always sees the error.
+	return nil, errs, 0
+}
+
+// sendRetryDelays are the waits before each retry of a failed Send. A short
+// vsock hiccup should not tear down the stream and drop the event.
+var sendRetryDelays = []time.Duration{
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	250 * time.Millisecond,
+}
+
+// Subscriber provides access to the sequenced event stream. Exchange
+// implements it.
 type Subscriber interface {
-	Subscribe(ctx context.Context, topics ...string) (<-chan *events.Envelope, <-chan error)
+	// SubscribeFrom replays retained events from sequence number seq and
+	// then streams new ones. It also returns how many events from seq on
+	// were already dropped from the history.
+	SubscribeFrom(ctx context.Context, seq uint64) (<-chan *SequencedEnvelope, <-chan error, uint64)
 }
 
 type service struct {
@@ -61,9 +101,19 @@ func (s *service) RegisterTTRPC(server *ttrpc.Server) error {
 	return nil
 }
 
-func (s *service) Stream(ctx context.Context, _ *emptypb.Empty, ss vmevents.TTRPCEvents_StreamServer) error {
-	log.G(ctx).Info("vmevents stream opened")
-	events, errs := s.sub.Subscribe(ctx)
+// Stream sends the events after req.AfterSeq, replaying those the exchange
+// still retains, so a host that reconnects resumes where it left off. Events
+// that were dropped from the history are reported in the Missed field of
+// the next event sent.
+func (s *service) Stream(ctx context.Context, req *vmevents.StreamRequest, ss vmevents.TTRPCEvents_StreamServer) error {
+	log.G(ctx).WithFields(log.Fields{
+		"topics":    req.GetTopics(),
+		"after_seq": req.GetAfterSeq(),
+	}).Info("vmevents stream opened")
+	events, errs, missed := s.sub.SubscribeFrom(ctx, req.GetAfterSeq()+1)
+	if missed > 0 {
+		log.G(ctx).WithField("missed", missed).Warn("vmevents stream resumed after events were dropped from history")
+	}
 
 	// Add debug logging to track stream lifecycle
 	defer func() {
@@ -78,16 +128,22 @@ func (s *service) Stream(ctx context.Context, _ *emptypb.Empty, ss vmevents.TTRP
 				log.G(ctx).WithField("events_sent", eventCount).Warn("vmevents stream events channel closed")
 				return io.EOF
 			}
-			if event == nil {
+			if event == nil || event.Envelope == nil {
 				log.G(ctx).Warn("vmevents stream received nil event")
 				continue
 			}
+			if !matchTopic(req.GetTopics(), event.Topic) {
+				continue
+			}
 			log.G(ctx).WithFields(log.Fields{
 				"topic":     event.Topic,
 				"namespace": event.Namespace,
+				"seq":       event.Seq,
 				"event_num": eventCount,
 			}).Debug("vmevents sending event")
-			if err := ss.Send(toProto(event)); err != nil {
+			sev := toProto(event)
+			sev.Missed, missed = missed, 0
+			if err := sendWithRetry(ctx, ss, sev); err != nil {
 				log.G(ctx).WithError(err).WithFields(log.Fields{
 					"topic":       event.Topic,
 					"namespace":   event.Namespace,
@@ -116,11 +172,64 @@ func (s *service) Stream(ctx context.Context, _ *emptypb.Empty, ss vmevents.TTRP
 	}
 }
 
-func toProto(env *events.Envelope) *types.Envelope {
-	return &types.Envelope{
-		Timestamp: protobuf.ToTimestamp(env.Timestamp),
-		Namespace: env.Namespace,
-		Topic:     env.Topic,
-		Event:     typeurl.MarshalProto(env.Event),
+// sendWithRetry sends env, retrying transient failures with sendRetryDelays.
+// Retrying in place keeps the event and its position in the stream.
+func sendWithRetry(ctx context.Context, ss vmevents.TTRPCEvents_StreamServer, env *vmevents.StreamEvent) error {
+	err := ss.Send(env)
+	for attempt, delay := range sendRetryDelays {
+		if err == nil || !isTransientSendError(err) {
+			return err
+		}
+		log.G(ctx).WithError(err).WithFields(log.Fields{
+			"topic":   env.GetEnvelope().GetTopic(),
+			"attempt": attempt + 1,
+		}).Debug("vmevents send failed, retrying")
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+		err = ss.Send(env)
+	}
+	return err
+}
+
+// isTransientSendError reports whether a Send failure may succeed on retry.
+// io.EOF and a closed connection mean the host is gone and are terminal.
+func isTransientSendError(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, ttrpc.ErrClosed) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EINTR) || errors.Is(err, syscall.ENOBUFS)
+}
+
+// matchTopic reports whether topic starts with one of prefixes. No prefixes,
+// or an empty one, matches every topic.
+func matchTopic(prefixes []string, topic string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(topic, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func toProto(env *SequencedEnvelope) *vmevents.StreamEvent {
+	return &vmevents.StreamEvent{
+		Envelope: &types.Envelope{
+			Timestamp: protobuf.ToTimestamp(env.Timestamp),
+			Namespace: env.Namespace,
+			Topic:     env.Topic,
+			Event:     typeurl.MarshalProto(env.Event),
+		},
+		Seq: env.Seq,
 	}
 }
@@ -0,0 +1,335 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/api/types"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
+	"github.com/containerd/ttrpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/spin-stack/spinbox/api/services/vmevents/v1"
+)
+
+// notifySubscriber signals once the stream has subscribed, so events are not
+// published before the subscription exists.
+type notifySubscriber struct {
+	Subscriber
+	subscribed chan struct{}
+}
+
+func (n *notifySubscriber) SubscribeFrom(ctx context.Context, seq uint64) (<-chan *SequencedEnvelope, <-chan error, uint64) {
+	defer close(n.subscribed)
+	return n.Subscriber.SubscribeFrom(ctx, seq)
+}
+
+type fakeStreamServer struct {
+	ttrpc.StreamServer
+	sent chan *vmevents.StreamEvent
+}
+
+func (f *fakeStreamServer) Send(ev *vmevents.StreamEvent) error {
+	f.sent <- ev
+	return nil
+}
+
+func TestServiceStreamTopicFilter(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		topics  []string
+		publish []string
+		want    []string
+	}{
+		{
+			name:    "no topics streams everything",
+			publish: []string{"/containers/create", "/tasks/start", "/images/update"},
+			want:    []string{"/containers/create", "/tasks/start", "/images/update"},
+		},
+		{
+			name:    "single prefix",
+			topics:  []string{"/tasks/"},
+			publish: []string{"/containers/create", "/tasks/start", "/images/update", "/tasks/exit", "/taskset"},
+			want:    []string{"/tasks/start", "/tasks/exit"},
+		},
+		{
+			name:    "multiple prefixes",
+			topics:  []string{"/tasks/", "/containers/"},
+			publish: []string{"/containers/create", "/tasks/start", "/images/update", "/snapshot/prepare"},
+			want:    []string{"/containers/create", "/tasks/start"},
+		},
+		{
+			name:    "prefix is matched literally",
+			topics:  []string{"/a.b/"},
+			publish: []string{"/axb/event", "/a.b/event", "/x/a.b/event"},
+			want:    []string{"/a.b/event"},
+		},
+		{
+			name:    "empty prefix streams everything",
+			topics:  []string{"/tasks/", ""},
+			publish: []string{"/containers/create", "/tasks/start"},
+			want:    []string{"/containers/create", "/tasks/start"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ex := NewExchange()
+			sub := &notifySubscriber{Subscriber: ex, subscribed: make(chan struct{})}
+			svc := NewService(sub)
+
+			ctx, cancel := context.WithCancel(namespaces.WithNamespace(context.Background(), "default"))
+			defer cancel()
+
+			ss := &fakeStreamServer{sent: make(chan *vmevents.StreamEvent, len(tc.publish))}
+			done := make(chan error, 1)
+			go func() {
+				done <- svc.Stream(ctx, &vmevents.StreamRequest{Topics: tc.topics}, ss)
+			}()
+
+			select {
+			case <-sub.subscribed:
+			case <-time.After(time.Second):
+				t.Fatal("timeout waiting for subscription")
+			}
+
+			for _, topic := range tc.publish {
+				if err := ex.Publish(ctx, topic, &emptypb.Empty{}); err != nil {
+					t.Fatalf("Publish(%q) failed: %v", topic, err)
+				}
+			}
+
+			var got []string
+			for range tc.want {
+				select {
+				case ev := <-ss.sent:
+					got = append(got, ev.Envelope.Topic)
+				case <-time.After(time.Second):
+					t.Fatalf("timeout waiting for events, got %v, want %v", got, tc.want)
+				}
+			}
+
+			// Give any unexpected event a chance to arrive
+			select {
+			case ev := <-ss.sent:
+				t.Fatalf("unexpected event %q", ev.Envelope.Topic)
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			for i := range tc.want {
+				if got[i] != tc.want[i] {
+					t.Fatalf("topics = %v, want %v", got, tc.want)
+				}
+			}
+
+			cancel()
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("Stream did not return after cancellation")
+			}
+		})
+	}
+}
+
+func streamEvent(topic string) *vmevents.StreamEvent {
+	return &vmevents.StreamEvent{Envelope: &types.Envelope{Topic: topic}}
+}
+
+// flakyStreamServer fails the first len(errs) Send calls with errs in order.
+type flakyStreamServer struct {
+	ttrpc.StreamServer
+	errs  []error
+	calls int
+	sent  []string
+}
+
+func (f *flakyStreamServer) Send(ev *vmevents.StreamEvent) error {
+	f.calls++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return err
+	}
+	f.sent = append(f.sent, ev.Envelope.Topic)
+	return nil
+}
+
+func TestSendWithRetry(t *testing.T) {
+	ctx := context.Background()
+	timeoutErr := &net.OpError{Op: "write", Net: "vsock", Err: os.ErrDeadlineExceeded}
+
+	t.Run("retries transient errors without dropping the event", func(t *testing.T) {
+		ss := &flakyStreamServer{errs: []error{syscall.EAGAIN, timeoutErr}}
+		for _, topic := range []string{"/tasks/start", "/tasks/exit"} {
+			if err := sendWithRetry(ctx, ss, streamEvent(topic)); err != nil {
+				t.Fatalf("sendWithRetry(%q) failed: %v", topic, err)
+			}
+		}
+		if ss.calls != 4 {
+			t.Fatalf("Send called %d times, want 4", ss.calls)
+		}
+		if want := []string{"/tasks/start", "/tasks/exit"}; !slices.Equal(ss.sent, want) {
+			t.Fatalf("sent = %v, want %v", ss.sent, want)
+		}
+	})
+
+	t.Run("EOF is terminal", func(t *testing.T) {
+		ss := &flakyStreamServer{errs: []error{io.EOF}}
+		err := sendWithRetry(ctx, ss, streamEvent("/tasks/start"))
+		if !errors.Is(err, io.EOF) {
+			t.Fatalf("err = %v, want io.EOF", err)
+		}
+		if ss.calls != 1 {
+			t.Fatalf("Send called %d times, want 1", ss.calls)
+		}
+	})
+
+	t.Run("closed connection is terminal", func(t *testing.T) {
+		ss := &flakyStreamServer{errs: []error{ttrpc.ErrClosed}}
+		if err := sendWithRetry(ctx, ss, streamEvent("/tasks/start")); !errors.Is(err, ttrpc.ErrClosed) {
+			t.Fatalf("err = %v, want ttrpc.ErrClosed", err)
+		}
+		if ss.calls != 1 {
+			t.Fatalf("Send called %d times, want 1", ss.calls)
+		}
+	})
+
+	t.Run("gives up when retries are exhausted", func(t *testing.T) {
+		errs := make([]error, len(sendRetryDelays)+1)
+		for i := range errs {
+			errs[i] = syscall.EAGAIN
+		}
+		ss := &flakyStreamServer{errs: errs}
+		if err := sendWithRetry(ctx, ss, streamEvent("/tasks/start")); !errors.Is(err, syscall.EAGAIN) {
+			t.Fatalf("err = %v, want EAGAIN", err)
+		}
+		if ss.calls != len(sendRetryDelays)+1 {
+			t.Fatalf("Send called %d times, want %d", ss.calls, len(sendRetryDelays)+1)
+		}
+		if len(ss.sent) != 0 {
+			t.Fatalf("sent = %v, want none", ss.sent)
+		}
+	})
+
+	t.Run("stops retrying when the stream is cancelled", func(t *testing.T) {
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+		ss := &flakyStreamServer{errs: []error{syscall.EAGAIN}}
+		if err := sendWithRetry(cctx, ss, streamEvent("/tasks/start")); !errors.Is(err, syscall.EAGAIN) {
+			t.Fatalf("err = %v, want EAGAIN", err)
+		}
+		if ss.calls != 1 {
+			t.Fatalf("Send called %d times, want 1", ss.calls)
+		}
+	})
+}
+
+func TestServiceStreamResume(t *testing.T) {
+	ctx, cancel := context.WithCancel(namespaces.WithNamespace(context.Background(), "default"))
+	defer cancel()
+
+	stream := func(ex *Exchange, afterSeq uint64, n int) []*vmevents.StreamEvent {
+		t.Helper()
+		sctx, scancel := context.WithCancel(ctx)
+		defer scancel()
+
+		ss := &fakeStreamServer{sent: make(chan *vmevents.StreamEvent, n)}
+		done := make(chan error, 1)
+		go func() {
+			done <- NewService(ex).Stream(sctx, &vmevents.StreamRequest{Topics: []string{"/tasks/"}, AfterSeq: afterSeq}, ss)
+		}()
+
+		var got []*vmevents.StreamEvent
+		for range n {
+			select {
+			case ev := <-ss.sent:
+				got = append(got, ev)
+			case <-time.After(time.Second):
+				t.Fatalf("timeout waiting for events, got %d of %d", len(got), n)
+			}
+		}
+		scancel()
+		<-done
+		return got
+	}
+
+	t.Run("replays events after the last sequence number", func(t *testing.T) {
+		ex := NewExchange()
+		for _, topic := range []string{"/tasks/create", "/images/update", "/tasks/start", "/tasks/exit"} {
+			if err := ex.Publish(ctx, topic, &emptypb.Empty{}); err != nil {
+				t.Fatalf("Publish(%q) failed: %v", topic, err)
+			}
+		}
+
+		got := stream(ex, 1, 2)
+		if got[0].Envelope.Topic != "/tasks/start" || got[0].Seq != 3 {
+			t.Fatalf("first event = %s seq %d, want /tasks/start seq 3", got[0].Envelope.Topic, got[0].Seq)
+		}
+		if got[1].Envelope.Topic != "/tasks/exit" || got[1].Seq != 4 {
+			t.Fatalf("second event = %s seq %d, want /tasks/exit seq 4", got[1].Envelope.Topic, got[1].Seq)
+		}
+		if got[0].Missed != 0 || got[1].Missed != 0 {
+			t.Fatalf("missed = %d, %d, want none", got[0].Missed, got[1].Missed)
+		}
+	})
+
+	t.Run("reports events dropped from history", func(t *testing.T) {
+		ex := NewExchange(WithHistorySize(2))
+		for _, topic := range []string{"/tasks/create", "/tasks/start", "/tasks/exec-added", "/tasks/exit"} {
+			if err := ex.Publish(ctx, topic, &emptypb.Empty{}); err != nil {
+				t.Fatalf("Publish(%q) failed: %v", topic, err)
+			}
+		}
+
+		got := stream(ex, 1, 2)
+		if got[0].Seq != 3 || got[0].Missed != 1 {
+			t.Fatalf("first event seq %d missed %d, want seq 3 missed 1", got[0].Seq, got[0].Missed)
+		}
+		if got[1].Missed != 0 {
+			t.Fatalf("second event missed %d, want 0", got[1].Missed)
+		}
+	})
+}
+
+func TestSubscriberForFallback(t *testing.T) {
+	ctx := namespaces.WithNamespace(context.Background(), "default")
+
+	ex := NewExchange()
+	if got := subscriberFor(ctx, ex); got != Subscriber(ex) {
+		t.Fatalf("subscriberFor(exchange) = %T, want the exchange", got)
+	}
+
+	for _, tc := range []struct {
+		name string
+		p    interface{}
+	}{
+		{"nil", nil},
+		{"unexpected type", struct{}{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sub := subscriberFor(ctx, tc.p)
+			if _, ok := sub.(disabledSubscriber); !ok {
+				t.Fatalf("subscriberFor(%v) = %T, want disabledSubscriber", tc.p, sub)
+			}
+
+			done := make(chan error, 1)
+			go func() {
+				done <- NewService(sub).Stream(ctx, &vmevents.StreamRequest{}, &fakeStreamServer{sent: make(chan *vmevents.StreamEvent, 1)})
+			}()
+			select {
+			case err := <-done:
+				if !errors.Is(err, ErrEventsDisabled) {
+					t.Fatalf("Stream() error = %v, want ErrEventsDisabled", err)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("Stream did not return with events disabled")
+			}
+		})
+	}
+}
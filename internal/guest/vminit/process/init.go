@@ -374,15 +374,15 @@ func (p *Init) kill(ctx context.Context, signal uint32, all bool) error {
 	return checkKillError(err)
 }
 
-// KillAll processes belonging to the init process
-func (p *Init) KillAll(ctx context.Context) error {
+// KillAll sends signal to all processes belonging to the init process
+func (p *Init) KillAll(ctx context.Context, signal unix.Signal) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	if p.runtime == nil {
 		return errors.New("runtime not initialized")
 	}
-	err := p.runtime.Kill(ctx, p.id, int(unix.SIGKILL), &runc.KillOpts{
+	err := p.runtime.Kill(ctx, p.id, int(signal), &runc.KillOpts{
 		All: true,
 	})
 	return p.runtimeError(err, "OCI runtime killall failed")
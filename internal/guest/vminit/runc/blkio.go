@@ -0,0 +1,173 @@
+//go:build linux
+
+package runc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	cgroupsv2 "github.com/containerd/cgroups/v3/cgroup2"
+	"github.com/containerd/log"
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+const (
+	// cgroupRoot is where the unified cgroup v2 hierarchy is mounted
+	cgroupRoot = "/sys/fs/cgroup"
+
+	// blkio (cgroup v1) weight range used by the OCI spec
+	blkioWeightMin = 10
+	blkioWeightMax = 1000
+)
+
+// blockDevice identifies a block device by its major and minor numbers.
+type blockDevice struct {
+	major, minor int64
+}
+
+func (d blockDevice) String() string {
+	return fmt.Sprintf("%d:%d", d.major, d.minor)
+}
+
+// blockIOConfig is the cgroup v2 io controller content for a BlockIO spec.
+// Each entry is one line to write to the named control file.
+type blockIOConfig struct {
+	weight []string // io.weight
+	max    []string // io.max
+}
+
+// guestBlockDeviceExists reports whether the guest kernel knows dev. The spec
+// is written against the host's devices; limits for devices that do not exist
+// inside the VM cannot be applied.
+func guestBlockDeviceExists(dev blockDevice) bool {
+	_, err := os.Stat(filepath.Join("/sys/dev/block", dev.String()))
+	return err == nil
+}
+
+// convertBlkioWeight maps a blkio weight (10-1000) onto the io.weight range
+// (1-10000) the same way runc does.
+func convertBlkioWeight(w uint16) uint64 {
+	return 1 + (uint64(w)-blkioWeightMin)*9999/(blkioWeightMax-blkioWeightMin)
+}
+
+func validBlkioWeight(w *uint16) bool {
+	return w != nil && *w >= blkioWeightMin && *w <= blkioWeightMax
+}
+
+// buildBlockIOConfig translates bio into io.weight and io.max lines. Devices
+// for which exists returns false, out-of-range weights and fields without a
+// cgroup v2 equivalent are skipped and described in the returned warnings.
+func buildBlockIOConfig(bio *specs.LinuxBlockIO, exists func(blockDevice) bool) (blockIOConfig, []string) {
+	var cfg blockIOConfig
+	var warnings []string
+	if bio == nil {
+		return cfg, nil
+	}
+
+	if bio.Weight != nil {
+		if validBlkioWeight(bio.Weight) {
+			cfg.weight = append(cfg.weight, fmt.Sprintf("default %d", convertBlkioWeight(*bio.Weight)))
+		} else {
+			warnings = append(warnings, fmt.Sprintf("weight %d outside %d-%d", *bio.Weight, blkioWeightMin, blkioWeightMax))
+		}
+	}
+	if bio.LeafWeight != nil {
+		warnings = append(warnings, "leafWeight has no cgroup v2 equivalent")
+	}
+
+	for _, wd := range bio.WeightDevice {
+		dev := blockDevice{wd.Major, wd.Minor}
+		if wd.LeafWeight != nil {
+			warnings = append(warnings, fmt.Sprintf("device %s: leafWeight has no cgroup v2 equivalent", dev))
+		}
+		if wd.Weight == nil {
+			continue
+		}
+		if !validBlkioWeight(wd.Weight) {
+			warnings = append(warnings, fmt.Sprintf("device %s: weight %d outside %d-%d", dev, *wd.Weight, blkioWeightMin, blkioWeightMax))
+			continue
+		}
+		if !exists(dev) {
+			warnings = append(warnings, fmt.Sprintf("device %s: not present in guest", dev))
+			continue
+		}
+		cfg.weight = append(cfg.weight, fmt.Sprintf("%s %d", dev, convertBlkioWeight(*wd.Weight)))
+	}
+
+	// io.max takes all limits of a device on one line
+	limits := make(map[blockDevice][]string)
+	var order []blockDevice
+	addLimits := func(key string, devices []specs.LinuxThrottleDevice) {
+		for _, td := range devices {
+			dev := blockDevice{td.Major, td.Minor}
+			if !exists(dev) {
+				warnings = append(warnings, fmt.Sprintf("device %s: %s limit skipped, not present in guest", dev, key))
+				continue
+			}
+			if _, ok := limits[dev]; !ok {
+				order = append(order, dev)
+			}
+			// A zero rate means unlimited, as in blkio
+			rate := "max"
+			if td.Rate > 0 {
+				rate = strconv.FormatUint(td.Rate, 10)
+			}
+			limits[dev] = append(limits[dev], key+"="+rate)
+		}
+	}
+	addLimits("rbps", bio.ThrottleReadBpsDevice)
+	addLimits("wbps", bio.ThrottleWriteBpsDevice)
+	addLimits("riops", bio.ThrottleReadIOPSDevice)
+	addLimits("wiops", bio.ThrottleWriteIOPSDevice)
+
+	slices.SortFunc(order, func(a, b blockDevice) int {
+		if a.major != b.major {
+			return int(a.major - b.major)
+		}
+		return int(a.minor - b.minor)
+	})
+	for _, dev := range order {
+		cfg.max = append(cfg.max, dev.String()+" "+strings.Join(limits[dev], " "))
+	}
+
+	return cfg, warnings
+}
+
+// applyBlockIO applies the spec's block I/O limits to the cgroup v2 group of
+// pid. RelaxOCISpec drops spec resources so runc never sees them; this
+// restores the block I/O part on the guest cgroup.
+func applyBlockIO(ctx context.Context, pid int, bio *specs.LinuxBlockIO) error {
+	cfg, warnings := buildBlockIOConfig(bio, guestBlockDeviceExists)
+	for _, w := range warnings {
+		log.G(ctx).WithField("pid", pid).Warnf("blockIO: %s", w)
+	}
+	if len(cfg.weight) == 0 && len(cfg.max) == 0 {
+		return nil
+	}
+
+	group, err := cgroupsv2.PidGroupPath(pid)
+	if err != nil {
+		return fmt.Errorf("find cgroup of pid %d: %w", pid, err)
+	}
+	dir := filepath.Join(cgroupRoot, group)
+
+	var errs []error
+	// The kernel parses one entry per write
+	for _, line := range cfg.weight {
+		if err := os.WriteFile(filepath.Join(dir, "io.weight"), []byte(line), 0); err != nil {
+			errs = append(errs, fmt.Errorf("write io.weight %q: %w", line, err))
+		}
+	}
+	for _, line := range cfg.max {
+		if err := os.WriteFile(filepath.Join(dir, "io.max"), []byte(line), 0); err != nil {
+			errs = append(errs, fmt.Errorf("write io.max %q: %w", line, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,73 @@
+//go:build linux
+
+package runc
+
+import (
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildBlockIOConfig(t *testing.T) {
+	u16 := func(v uint16) *uint16 { return &v }
+	throttle := func(major, minor int64, rate uint64) specs.LinuxThrottleDevice {
+		td := specs.LinuxThrottleDevice{Rate: rate}
+		td.Major, td.Minor = major, minor
+		return td
+	}
+	weightDev := func(major, minor int64, weight uint16) specs.LinuxWeightDevice {
+		wd := specs.LinuxWeightDevice{Weight: u16(weight)}
+		wd.Major, wd.Minor = major, minor
+		return wd
+	}
+	// Only virtio disks 254:0 and 254:16 exist in the guest
+	exists := func(d blockDevice) bool {
+		return d.major == 254 && (d.minor == 0 || d.minor == 16)
+	}
+
+	t.Run("nil config", func(t *testing.T) {
+		cfg, warnings := buildBlockIOConfig(nil, exists)
+		assert.Empty(t, cfg.weight)
+		assert.Empty(t, cfg.max)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("weights", func(t *testing.T) {
+		cfg, warnings := buildBlockIOConfig(&specs.LinuxBlockIO{
+			Weight:       u16(500),
+			WeightDevice: []specs.LinuxWeightDevice{weightDev(254, 16, 1000)},
+		}, exists)
+		assert.Equal(t, []string{"default 4950", "254:16 10000"}, cfg.weight)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("throttles merged per device", func(t *testing.T) {
+		cfg, warnings := buildBlockIOConfig(&specs.LinuxBlockIO{
+			ThrottleReadBpsDevice:   []specs.LinuxThrottleDevice{throttle(254, 16, 1048576), throttle(254, 0, 2097152)},
+			ThrottleWriteBpsDevice:  []specs.LinuxThrottleDevice{throttle(254, 16, 0)},
+			ThrottleReadIOPSDevice:  []specs.LinuxThrottleDevice{throttle(254, 0, 100)},
+			ThrottleWriteIOPSDevice: []specs.LinuxThrottleDevice{throttle(254, 16, 50)},
+		}, exists)
+		assert.Equal(t, []string{
+			"254:0 rbps=2097152 riops=100",
+			"254:16 rbps=1048576 wbps=max wiops=50",
+		}, cfg.max)
+		assert.Empty(t, cfg.weight)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("unsupported fields are skipped with warnings", func(t *testing.T) {
+		wd := weightDev(254, 0, 300)
+		wd.LeafWeight = u16(200)
+		cfg, warnings := buildBlockIOConfig(&specs.LinuxBlockIO{
+			Weight:                u16(5),
+			LeafWeight:            u16(100),
+			WeightDevice:          []specs.LinuxWeightDevice{wd, weightDev(8, 0, 500)},
+			ThrottleReadBpsDevice: []specs.LinuxThrottleDevice{throttle(8, 0, 1024)},
+		}, exists)
+		assert.Equal(t, []string{"254:0 2930"}, cfg.weight)
+		assert.Empty(t, cfg.max)
+		assert.Len(t, warnings, 5)
+	})
+}
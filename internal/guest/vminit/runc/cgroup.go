@@ -4,9 +4,16 @@ package runc
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 
 	cgroupsv2 "github.com/containerd/cgroups/v3/cgroup2"
 	"github.com/containerd/cgroups/v3/cgroup2/stats"
+	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
 	"github.com/moby/sys/userns"
 )
@@ -21,57 +28,170 @@ type CgroupManager interface {
 	// Stats returns cgroup v2 statistics
 	Stats(ctx context.Context) (*stats.Metrics, error)
 
-	// EnableControllers enables all available cgroup controllers
-	EnableControllers(ctx context.Context) error
+	// EnableControllers enables all available cgroup controllers and returns
+	// the controllers effectively available to the cgroup afterwards. The
+	// list is returned even on error, as some controllers may still have been
+	// enabled (e.g. when only part of the hierarchy is delegated).
+	EnableControllers(ctx context.Context) ([]string, error)
+
+	// PSI returns memory, CPU and I/O pressure stall information.
+	// Returns ErrPSINotSupported if the kernel does not provide it.
+	PSI(ctx context.Context) (*PressureStats, error)
+
+	// SetMemoryLimit writes memory.max. A negative limit removes the limit.
+	SetMemoryLimit(ctx context.Context, bytes int64) error
+
+	// SetCPUMax writes cpu.max: quota microseconds of CPU time per period.
+	// A negative quota removes the limit.
+	SetCPUMax(ctx context.Context, quota, period int64) error
+}
+
+// cpu.max period bounds enforced by the kernel, in microseconds.
+const (
+	minCPUPeriod = 1000
+	maxCPUPeriod = 1000000
+)
+
+// cgroupV2 is the subset of *cgroupsv2.Manager used by cgroupManager.
+type cgroupV2 interface {
+	Stat() (*stats.Metrics, error)
+	RootControllers() ([]string, error)
+	Controllers() ([]string, error)
+	ToggleControllers(controllers []string, t cgroupsv2.ControllerToggle) error
 }
 
 // cgroupManager implements CgroupManager for cgroup v2
 type cgroupManager struct {
-	manager *cgroupsv2.Manager
+	manager cgroupV2
+	dir     string // absolute path of the cgroup directory
 }
 
-// NewCgroupManager creates a new cgroup v2 manager
-func NewCgroupManager(mgr *cgroupsv2.Manager) CgroupManager {
-	return &cgroupManager{manager: mgr}
+// NewCgroupManager creates a new cgroup v2 manager for mgr, which was loaded
+// from group, the cgroup's path relative to the hierarchy root (e.g. as
+// returned by cgroupsv2.PidGroupPath).
+func NewCgroupManager(mgr *cgroupsv2.Manager, group string) CgroupManager {
+	return &cgroupManager{manager: mgr, dir: filepath.Join(cgroupRoot, group)}
 }
 
 func (m *cgroupManager) Stats(ctx context.Context) (*stats.Metrics, error) {
 	return m.manager.Stat()
 }
 
-func (m *cgroupManager) EnableControllers(ctx context.Context) error {
+func (m *cgroupManager) EnableControllers(ctx context.Context) ([]string, error) {
 	allControllers, err := m.manager.RootControllers()
 	if err != nil {
 		log.G(ctx).WithError(err).Error("failed to get root controllers")
-		return err
+		return nil, err
 	}
 
-	if err := m.manager.ToggleControllers(allControllers, cgroupsv2.Enable); err != nil {
+	toggleErr := m.manager.ToggleControllers(allControllers, cgroupsv2.Enable)
+	if toggleErr != nil {
 		if userns.RunningInUserNS() {
-			log.G(ctx).WithError(err).Debugf("failed to enable controllers (%v)", allControllers)
+			log.G(ctx).WithError(toggleErr).Debugf("failed to enable controllers (%v)", allControllers)
 		} else {
-			log.G(ctx).WithError(err).Errorf("failed to enable controllers (%v)", allControllers)
+			log.G(ctx).WithError(toggleErr).Errorf("failed to enable controllers (%v)", allControllers)
+		}
+	}
+
+	// cgroup.controllers lists what the parents actually delegated
+	enabled, err := m.manager.Controllers()
+	if err != nil {
+		log.G(ctx).WithError(err).Error("failed to read enabled controllers")
+		return nil, errors.Join(toggleErr, err)
+	}
+
+	var missing []string
+	for _, c := range allControllers {
+		if !slices.Contains(enabled, c) {
+			missing = append(missing, c)
+		}
+	}
+	if len(missing) > 0 {
+		log.G(ctx).WithFields(log.Fields{
+			"enabled": enabled,
+			"missing": missing,
+		}).Warn("some cgroup controllers could not be enabled")
+	}
+
+	return enabled, toggleErr
+}
+
+func (m *cgroupManager) SetMemoryLimit(ctx context.Context, bytes int64) error {
+	value := "max"
+	if bytes >= 0 {
+		value = strconv.FormatInt(bytes, 10)
+	}
+	return m.writeFile(ctx, "memory.max", value)
+}
+
+func (m *cgroupManager) SetCPUMax(ctx context.Context, quota, period int64) error {
+	if period < minCPUPeriod || period > maxCPUPeriod {
+		return fmt.Errorf("cpu period %d out of range [%d, %d]: %w", period, minCPUPeriod, maxCPUPeriod, errdefs.ErrInvalidArgument)
+	}
+	value := "max"
+	if quota >= 0 {
+		if quota < minCPUPeriod {
+			return fmt.Errorf("cpu quota %d below minimum %d: %w", quota, minCPUPeriod, errdefs.ErrInvalidArgument)
 		}
-		return err
+		value = strconv.FormatInt(quota, 10)
 	}
+	return m.writeFile(ctx, "cpu.max", fmt.Sprintf("%s %d", value, period))
+}
 
+// writeFile writes a cgroup interface file of the managed cgroup.
+func (m *cgroupManager) writeFile(ctx context.Context, name, value string) error {
+	if m.dir == "" {
+		return errors.New("cgroup path not known")
+	}
+	// Interface files are never created, only written
+	f, err := os.OpenFile(filepath.Join(m.dir, name), os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	_, err = f.WriteString(value)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	log.G(ctx).WithFields(log.Fields{"file": name, "value": value, "cgroup": m.dir}).Debug("updated cgroup limit")
 	return nil
 }
 
+// ErrCgroupV1Unsupported is returned by LoadProcessCgroup when the cgroup
+// hierarchy is not in unified (v2) mode.
+var ErrCgroupV1Unsupported = errors.New("cgroup v1 (legacy or hybrid mode) is not supported, cgroup v2 unified mode is required")
+
 // LoadProcessCgroup loads the cgroup for a given PID and returns a CgroupManager.
-// Only cgroup v2 (unified mode) is supported.
+// Only cgroup v2 (unified mode) is supported; otherwise ErrCgroupV1Unsupported
+// is returned.
 func LoadProcessCgroup(ctx context.Context, pid int) (CgroupManager, error) {
+	return loadProcessCgroupAt(ctx, cgroupRoot, pid)
+}
+
+// loadProcessCgroupAt is LoadProcessCgroup with the cgroup hierarchy mounted
+// at root.
+func loadProcessCgroupAt(ctx context.Context, root string, pid int) (CgroupManager, error) {
+	// cgroup.controllers only exists at the root of a unified hierarchy
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%s: %w", root, ErrCgroupV1Unsupported)
+		}
+		return nil, err
+	}
+
 	g, err := cgroupsv2.PidGroupPath(pid)
 	if err != nil {
 		log.G(ctx).WithError(err).Errorf("loading cgroup2 for %d", pid)
 		return nil, err
 	}
 
-	mgr, err := cgroupsv2.Load(g)
+	mgr, err := cgroupsv2.Load(g, cgroupsv2.WithMountpoint(root))
 	if err != nil {
 		log.G(ctx).WithError(err).Errorf("loading cgroup2 for %d", pid)
 		return nil, err
 	}
 
-	return NewCgroupManager(mgr), nil
+	return &cgroupManager{manager: mgr, dir: filepath.Join(root, g)}, nil
 }
@@ -5,9 +5,12 @@ package runc
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 
+	cgroupsv2 "github.com/containerd/cgroups/v3/cgroup2"
 	"github.com/containerd/cgroups/v3/cgroup2/stats"
+	"github.com/containerd/errdefs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -21,12 +24,13 @@ func isCgroupV2Available() bool {
 func TestNewCgroupManager(t *testing.T) {
 	// NewCgroupManager wraps the v2 manager
 	// We can't create a real manager without cgroup2 setup, but we test nil handling
-	mgr := NewCgroupManager(nil)
+	mgr := NewCgroupManager(nil, "/system.slice/app.scope")
 	require.NotNil(t, mgr)
 
 	cm, ok := mgr.(*cgroupManager)
 	require.True(t, ok)
 	assert.Nil(t, cm.manager) // Underlying manager is nil
+	assert.Equal(t, "/sys/fs/cgroup/system.slice/app.scope", cm.dir)
 }
 
 func TestCgroupManager_Stats_NilManager(t *testing.T) {
@@ -72,6 +76,17 @@ func TestLoadProcessCgroup(t *testing.T) {
 
 		require.NotNil(t, mgr)
 	})
+
+	t.Run("cgroup v1 hierarchy", func(t *testing.T) {
+		// A legacy/hybrid root has per-controller directories but no
+		// cgroup.controllers file
+		root := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "memory"), 0o750))
+
+		_, err := loadProcessCgroupAt(context.Background(), root, os.Getpid())
+		require.ErrorIs(t, err, ErrCgroupV1Unsupported)
+		assert.Contains(t, err.Error(), root)
+	})
 }
 
 // MockCgroupManager provides a test implementation of CgroupManager
@@ -79,6 +94,9 @@ type MockCgroupManager struct {
 	StatsResult           *stats.Metrics
 	StatsError            error
 	EnableControllersErr  error
+	EnabledControllers    []string
+	PSIResult             *PressureStats
+	PSIError              error
 	StatsCalls            int
 	EnableControllerCalls int
 }
@@ -88,9 +106,21 @@ func (m *MockCgroupManager) Stats(ctx context.Context) (*stats.Metrics, error) {
 	return m.StatsResult, m.StatsError
 }
 
-func (m *MockCgroupManager) EnableControllers(ctx context.Context) error {
+func (m *MockCgroupManager) EnableControllers(ctx context.Context) ([]string, error) {
 	m.EnableControllerCalls++
-	return m.EnableControllersErr
+	return m.EnabledControllers, m.EnableControllersErr
+}
+
+func (m *MockCgroupManager) PSI(ctx context.Context) (*PressureStats, error) {
+	return m.PSIResult, m.PSIError
+}
+
+func (m *MockCgroupManager) SetMemoryLimit(ctx context.Context, bytes int64) error {
+	return nil
+}
+
+func (m *MockCgroupManager) SetCPUMax(ctx context.Context, quota, period int64) error {
+	return nil
 }
 
 func TestMockCgroupManager(t *testing.T) {
@@ -132,9 +162,125 @@ func TestMockCgroupManager(t *testing.T) {
 		mock := &MockCgroupManager{}
 
 		ctx := context.Background()
-		err := mock.EnableControllers(ctx)
+		_, err := mock.EnableControllers(ctx)
 
 		require.NoError(t, err)
 		assert.Equal(t, 1, mock.EnableControllerCalls)
 	})
 }
+
+// newTestCgroupDir creates a directory with empty cgroup interface files.
+func newTestCgroupDir(t *testing.T, files ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, f := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, f), nil, 0o600))
+	}
+	return dir
+}
+
+func readCgroupFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestCgroupManager_SetMemoryLimit(t *testing.T) {
+	ctx := context.Background()
+	dir := newTestCgroupDir(t, "memory.max")
+	m := &cgroupManager{dir: dir}
+
+	require.NoError(t, m.SetMemoryLimit(ctx, 512*1024*1024))
+	assert.Equal(t, "536870912", readCgroupFile(t, dir, "memory.max"))
+
+	require.NoError(t, m.SetMemoryLimit(ctx, -1))
+	assert.Equal(t, "max", readCgroupFile(t, dir, "memory.max"))
+
+	// Interface files are not created
+	err := (&cgroupManager{dir: t.TempDir()}).SetMemoryLimit(ctx, 1024)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCgroupManager_SetCPUMax(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("writes quota and period", func(t *testing.T) {
+		dir := newTestCgroupDir(t, "cpu.max")
+		m := &cgroupManager{dir: dir}
+
+		require.NoError(t, m.SetCPUMax(ctx, 200000, 100000))
+		assert.Equal(t, "200000 100000", readCgroupFile(t, dir, "cpu.max"))
+
+		require.NoError(t, m.SetCPUMax(ctx, -1, 100000))
+		assert.Equal(t, "max 100000", readCgroupFile(t, dir, "cpu.max"))
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		dir := newTestCgroupDir(t, "cpu.max")
+		m := &cgroupManager{dir: dir}
+
+		for _, tc := range []struct{ quota, period int64 }{
+			{quota: 100000, period: 0},
+			{quota: 100000, period: 999},
+			{quota: 100000, period: 1000001},
+			{quota: 999, period: 100000},
+			{quota: 0, period: 100000},
+		} {
+			err := m.SetCPUMax(ctx, tc.quota, tc.period)
+			require.ErrorIs(t, err, errdefs.ErrInvalidArgument, "quota=%d period=%d", tc.quota, tc.period)
+		}
+		assert.Empty(t, readCgroupFile(t, dir, "cpu.max"))
+	})
+
+	t.Run("unknown cgroup path", func(t *testing.T) {
+		require.Error(t, (&cgroupManager{}).SetCPUMax(ctx, -1, 100000))
+	})
+}
+
+// fakeCgroupV2 reports a fixed set of root and delegated controllers.
+type fakeCgroupV2 struct {
+	root      []string
+	delegated []string
+	toggleErr error
+	toggled   []string
+}
+
+func (f *fakeCgroupV2) Stat() (*stats.Metrics, error) { return &stats.Metrics{}, nil }
+
+func (f *fakeCgroupV2) RootControllers() ([]string, error) { return f.root, nil }
+
+func (f *fakeCgroupV2) Controllers() ([]string, error) { return f.delegated, nil }
+
+func (f *fakeCgroupV2) ToggleControllers(controllers []string, _ cgroupsv2.ControllerToggle) error {
+	f.toggled = controllers
+	return f.toggleErr
+}
+
+func TestCgroupManager_EnableControllers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("all controllers enabled", func(t *testing.T) {
+		all := []string{"cpuset", "cpu", "io", "memory", "pids"}
+		fake := &fakeCgroupV2{root: all, delegated: all}
+		m := &cgroupManager{manager: fake}
+
+		enabled, err := m.EnableControllers(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, all, enabled)
+		assert.Equal(t, all, fake.toggled)
+	})
+
+	t.Run("returns the delegated subset", func(t *testing.T) {
+		fake := &fakeCgroupV2{
+			root:      []string{"cpuset", "cpu", "io", "memory", "pids"},
+			delegated: []string{"cpu", "pids"},
+			toggleErr: os.ErrPermission,
+		}
+		m := &cgroupManager{manager: fake}
+
+		enabled, err := m.EnableControllers(ctx)
+		require.ErrorIs(t, err, os.ErrPermission)
+		assert.Equal(t, []string{"cpu", "pids"}, enabled)
+	})
+}
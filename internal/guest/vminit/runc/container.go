@@ -5,6 +5,7 @@ package runc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -17,7 +18,9 @@ import (
 	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
 	"github.com/containerd/typeurl/v2"
+	"github.com/opencontainers/runtime-spec/specs-go"
 
+	"github.com/spin-stack/spinbox/internal/guest/vminit/devices"
 	"github.com/spin-stack/spinbox/internal/guest/vminit/process"
 	"github.com/spin-stack/spinbox/internal/guest/vminit/stream"
 	"github.com/spin-stack/spinbox/internal/host/mountutil"
@@ -118,10 +121,32 @@ func NewContainer(ctx context.Context, platform stdio.Platform, r *task.CreateTa
 			return nil, err
 		}
 		log.G(ctx).WithField("rootfs", rootfs).Info("rootfs components mounted")
+		for _, m := range r.Rootfs {
+			if devices.IsDataDisk(m.Source, m.Options) {
+				devices.StartResizeWatcher(ctx)
+				break
+			}
+		}
+	}
+
+	// RelaxOCISpec drops spec resources; keep block I/O limits to apply
+	// to the container cgroup once it exists
+	var blockIO *specs.LinuxBlockIO
+	if spec, err := readSpec(r.Bundle); err != nil {
+		log.G(ctx).WithError(err).Warn("failed to read spec, block I/O limits will not be applied")
+	} else if spec.Linux != nil && spec.Linux.Resources != nil {
+		blockIO = spec.Linux.Resources.BlockIO
 	}
 
 	// Relax OCI spec restrictions - VM provides the security boundary
 	if err := RelaxOCISpec(ctx, r.Bundle); err != nil {
+		// runc would reject the kept profile, fail early with a clear error
+		if errors.Is(err, ErrIncompatibleSeccomp) {
+			if mountCleanup != nil {
+				_ = mountCleanup(context.WithoutCancel(ctx))
+			}
+			return nil, err
+		}
 		log.G(ctx).WithError(err).Warn("failed to relax OCI spec")
 	}
 
@@ -153,6 +178,11 @@ func NewContainer(ctx context.Context, platform stdio.Platform, r *task.CreateTa
 		if cg, err := loadProcessCgroup(ctx, pid); err == nil {
 			container.cgroup = cg
 		}
+		// The init process has not started yet, so limits apply from its
+		// first instruction
+		if err := applyBlockIO(ctx, pid, blockIO); err != nil {
+			log.G(ctx).WithError(err).Warn("failed to apply block I/O limits")
+		}
 	}
 	return container, nil
 }
@@ -445,6 +475,9 @@ func (c *Container) Update(ctx context.Context, r *task.UpdateTaskRequest) error
 	if !ok {
 		return fmt.Errorf("expected init process, got %T", p)
 	}
+	// The runtime gets the full resource set: it derives memory.swap.max
+	// from the memory limit and re-applies its recorded limits on every
+	// update, so limits written around it would be lost or inconsistent
 	return initProc.Update(ctx, r.Resources)
 }
 
@@ -0,0 +1,132 @@
+//go:build linux
+
+package runc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// ErrPSINotSupported is returned by PSI when the kernel does not expose
+// pressure stall information (CONFIG_PSI disabled or booted with psi=0).
+var ErrPSINotSupported = errors.New("pressure stall information not supported")
+
+// PressureValues holds one line of a cgroup pressure file. Averages are the
+// percentage of time stalled over the last 10, 60 and 300 seconds; Total is
+// the accumulated stall time in microseconds.
+type PressureValues struct {
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+	Total  uint64
+}
+
+// Pressure holds the "some" (at least one task stalled) and "full" (all
+// non-idle tasks stalled) values of a resource.
+type Pressure struct {
+	Some PressureValues
+	Full PressureValues
+}
+
+// PressureStats is the pressure stall information of a cgroup.
+type PressureStats struct {
+	Memory Pressure
+	CPU    Pressure
+	IO     Pressure
+}
+
+func (m *cgroupManager) PSI(ctx context.Context) (*PressureStats, error) {
+	if m.dir == "" {
+		return nil, errors.New("cgroup path not known")
+	}
+	var ps PressureStats
+	for _, r := range []struct {
+		file string
+		dst  *Pressure
+	}{
+		{"memory.pressure", &ps.Memory},
+		{"cpu.pressure", &ps.CPU},
+		{"io.pressure", &ps.IO},
+	} {
+		p, err := readPressureFile(filepath.Join(m.dir, r.file))
+		if err != nil {
+			return nil, err
+		}
+		*r.dst = p
+	}
+	return &ps, nil
+}
+
+// readPressureFile reads a cgroup v2 pressure file. The files are missing
+// when the kernel lacks PSI, and reads fail with EOPNOTSUPP when PSI is
+// disabled at boot; both are reported as ErrPSINotSupported.
+func readPressureFile(path string) (Pressure, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, unix.EOPNOTSUPP) {
+		return Pressure{}, ErrPSINotSupported
+	}
+	if err != nil {
+		return Pressure{}, err
+	}
+	p, err := parsePressure(data)
+	if err != nil {
+		return Pressure{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// parsePressure parses the content of a pressure file:
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//
+// The "full" line is absent for CPU on kernels before 5.13.
+func parsePressure(data []byte) (Pressure, error) {
+	var p Pressure
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var dst *PressureValues
+		switch fields[0] {
+		case "some":
+			dst = &p.Some
+		case "full":
+			dst = &p.Full
+		default:
+			return Pressure{}, fmt.Errorf("unexpected line %q", scanner.Text())
+		}
+		for _, f := range fields[1:] {
+			key, value, ok := strings.Cut(f, "=")
+			if !ok {
+				return Pressure{}, fmt.Errorf("malformed field %q", f)
+			}
+			var err error
+			switch key {
+			case "avg10":
+				dst.Avg10, err = strconv.ParseFloat(value, 64)
+			case "avg60":
+				dst.Avg60, err = strconv.ParseFloat(value, 64)
+			case "avg300":
+				dst.Avg300, err = strconv.ParseFloat(value, 64)
+			case "total":
+				dst.Total, err = strconv.ParseUint(value, 10, 64)
+			}
+			if err != nil {
+				return Pressure{}, fmt.Errorf("field %q: %w", f, err)
+			}
+		}
+	}
+	return p, scanner.Err()
+}
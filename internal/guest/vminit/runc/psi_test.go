@@ -0,0 +1,96 @@
+//go:build linux
+
+package runc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePressureFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+}
+
+func TestCgroupManagerPSI(t *testing.T) {
+	t.Run("parses pressure files", func(t *testing.T) {
+		dir := t.TempDir()
+		writePressureFiles(t, dir, map[string]string{
+			"memory.pressure": "some avg10=1.50 avg60=0.75 avg300=0.25 total=123456\n" +
+				"full avg10=0.50 avg60=0.10 avg300=0.00 total=6543\n",
+			"cpu.pressure": "some avg10=12.00 avg60=8.40 avg300=3.10 total=9999999\n" +
+				"full avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+			// Kernels before 5.13 report no "full" line for CPU
+			"io.pressure": "some avg10=0.00 avg60=0.02 avg300=0.00 total=42\n",
+		})
+
+		m := &cgroupManager{dir: dir}
+		ps, err := m.PSI(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, Pressure{
+			Some: PressureValues{Avg10: 1.5, Avg60: 0.75, Avg300: 0.25, Total: 123456},
+			Full: PressureValues{Avg10: 0.5, Avg60: 0.1, Avg300: 0, Total: 6543},
+		}, ps.Memory)
+		assert.Equal(t, PressureValues{Avg10: 12, Avg60: 8.4, Avg300: 3.1, Total: 9999999}, ps.CPU.Some)
+		assert.Equal(t, PressureValues{}, ps.CPU.Full)
+		assert.Equal(t, Pressure{Some: PressureValues{Avg60: 0.02, Total: 42}}, ps.IO)
+	})
+
+	t.Run("missing files mean PSI is not supported", func(t *testing.T) {
+		m := &cgroupManager{dir: t.TempDir()}
+		_, err := m.PSI(context.Background())
+		assert.ErrorIs(t, err, ErrPSINotSupported)
+	})
+
+	t.Run("malformed file", func(t *testing.T) {
+		dir := t.TempDir()
+		writePressureFiles(t, dir, map[string]string{
+			"memory.pressure": "some avg10=abc avg60=0.00 avg300=0.00 total=0\n",
+			"cpu.pressure":    "",
+			"io.pressure":     "",
+		})
+
+		m := &cgroupManager{dir: dir}
+		_, err := m.PSI(context.Background())
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrPSINotSupported)
+		assert.Contains(t, err.Error(), "memory.pressure")
+	})
+
+	t.Run("unknown cgroup path", func(t *testing.T) {
+		m := &cgroupManager{}
+		_, err := m.PSI(context.Background())
+		assert.Error(t, err)
+	})
+}
+
+func TestParsePressure(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "empty", input: ""},
+		{name: "unknown line", input: "partial avg10=0.00\n", wantErr: true},
+		{name: "field without value", input: "some avg10\n", wantErr: true},
+		{name: "bad total", input: "some total=-1\n", wantErr: true},
+		{name: "unknown fields are ignored", input: "some avg10=1.00 future=7\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parsePressure([]byte(tc.input))
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
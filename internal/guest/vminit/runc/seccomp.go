@@ -0,0 +1,85 @@
+//go:build linux
+
+package runc
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// AnnotationSecurityMode selects how much in-container hardening
+// RelaxOCISpec keeps (must match shim transform package). With "seccomp" the
+// seccomp profile is left in place; with "preserve" readonly/masked paths
+// are kept as well. Any other value relaxes them, since the VM is the
+// security boundary.
+const AnnotationSecurityMode = "io.spin.security.mode"
+
+// AnnotationRestrictDevices keeps the spec's device cgroup rules instead of
+// allowing all devices (must match shim transform package). The shim sets it
+// on privileged containers downgraded by policy.
+const AnnotationRestrictDevices = "io.spin.devices.restrict"
+
+const (
+	// securityModeSeccomp keeps the seccomp profile only.
+	securityModeSeccomp = "seccomp"
+	// securityModePreserve keeps readonly/masked paths and seccomp.
+	securityModePreserve = "preserve"
+)
+
+// ErrIncompatibleSeccomp is returned when a kept seccomp profile cannot be
+// applied on the guest architecture.
+var ErrIncompatibleSeccomp = errors.New("seccomp profile incompatible with guest architecture")
+
+// seccompArches maps a GOARCH to the seccomp architectures the guest kernel
+// can filter: the native architecture and its compat ABIs.
+var seccompArches = map[string][]specs.Arch{
+	"amd64": {specs.ArchX86_64, specs.ArchX86, specs.ArchX32},
+	"arm64": {specs.ArchAARCH64, specs.ArchARM},
+}
+
+// preserveSecurity reports whether the spec asks to keep readonly/masked
+// paths and seccomp.
+func preserveSecurity(spec *specs.Spec) bool {
+	return spec.Annotations[AnnotationSecurityMode] == securityModePreserve
+}
+
+// keepSeccomp reports whether the spec asks to keep its seccomp profile.
+func keepSeccomp(spec *specs.Spec) bool {
+	return spec.Annotations[AnnotationSecurityMode] == securityModeSeccomp || preserveSecurity(spec)
+}
+
+// validateSeccomp checks a kept seccomp profile against the guest architecture.
+// Architecture entries the guest cannot filter are dropped so runc does not
+// reject the profile. Returns ErrIncompatibleSeccomp if the profile lists
+// architectures but none of them are supported by the guest.
+func validateSeccomp(seccomp *specs.LinuxSeccomp, goarch string) (dropped []specs.Arch, err error) {
+	// An empty list means the native architecture only
+	if seccomp == nil || len(seccomp.Architectures) == 0 {
+		return nil, nil
+	}
+
+	supported, ok := seccompArches[goarch]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported guest architecture %q", ErrIncompatibleSeccomp, goarch)
+	}
+
+	kept := make([]specs.Arch, 0, len(seccomp.Architectures))
+	for _, arch := range seccomp.Architectures {
+		if slices.Contains(supported, arch) {
+			kept = append(kept, arch)
+		} else {
+			dropped = append(dropped, arch)
+		}
+	}
+
+	if len(kept) == 0 {
+		return dropped, fmt.Errorf("%w: profile architectures %v not supported on %s",
+			ErrIncompatibleSeccomp, seccomp.Architectures, goarch)
+	}
+
+	seccomp.Architectures = kept
+	return dropped, nil
+}
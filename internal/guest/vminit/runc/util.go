@@ -3,13 +3,21 @@
 package runc
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/containerd/log"
 	"github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/unix"
 )
 
 // ShouldKillAllOnExit reads the bundle's OCI spec and returns true if
@@ -31,6 +39,99 @@ func ShouldKillAllOnExit(ctx context.Context, bundlePath string) bool {
 	return true
 }
 
+const (
+	// AnnotationKillSignal sets the signal sent to the remaining container
+	// processes when init exits (e.g., "SIGTERM", "TERM" or "15").
+	AnnotationKillSignal = "io.spin.kill.signal"
+
+	// AnnotationKillGrace sets how long to wait after AnnotationKillSignal
+	// before sending SIGKILL (e.g., "10s").
+	AnnotationKillGrace = "io.spin.kill.grace"
+
+	// AnnotationInitExitMaxDelay bounds how long the init exit of a container
+	// waits for its running execs before they are abandoned (e.g., "30s").
+	// Without it the init exit waits for every exec, however long.
+	AnnotationInitExitMaxDelay = "io.spin.init-exit.max-delay"
+)
+
+// InitExitMaxDelay reads AnnotationInitExitMaxDelay from the bundle. It
+// returns zero, meaning no bound, when the annotation is missing or invalid;
+// invalid values are logged.
+func InitExitMaxDelay(ctx context.Context, bundlePath string) time.Duration {
+	spec, err := readSpec(bundlePath)
+	if err != nil {
+		log.G(ctx).WithError(err).Error("initExitMaxDelay: failed to read config.json")
+		return 0
+	}
+	v, ok := spec.Annotations[AnnotationInitExitMaxDelay]
+	if !ok {
+		return 0
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		log.G(ctx).WithField("value", v).Warnf("ignoring invalid %s annotation", AnnotationInitExitMaxDelay)
+		return 0
+	}
+	return d
+}
+
+// ExitKillPolicy describes how the remaining container processes are killed
+// when init exits: Signal first, then SIGKILL once Grace has passed. The
+// default is an immediate SIGKILL.
+type ExitKillPolicy struct {
+	Signal unix.Signal
+	Grace  time.Duration
+}
+
+// KillPolicy reads the bundle's kill annotations. Missing or invalid values
+// fall back to SIGKILL with no grace period; invalid values are logged.
+func KillPolicy(ctx context.Context, bundlePath string) ExitKillPolicy {
+	policy := ExitKillPolicy{Signal: unix.SIGKILL}
+	spec, err := readSpec(bundlePath)
+	if err != nil {
+		log.G(ctx).WithError(err).Error("killPolicy: failed to read config.json")
+		return policy
+	}
+
+	if v, ok := spec.Annotations[AnnotationKillSignal]; ok {
+		sig, err := parseSignal(v)
+		if err != nil {
+			log.G(ctx).WithError(err).Warnf("ignoring invalid %s annotation", AnnotationKillSignal)
+		} else {
+			policy.Signal = sig
+		}
+	}
+	if v, ok := spec.Annotations[AnnotationKillGrace]; ok {
+		grace, err := time.ParseDuration(v)
+		if err != nil || grace < 0 {
+			log.G(ctx).WithField("value", v).Warnf("ignoring invalid %s annotation", AnnotationKillGrace)
+		} else {
+			policy.Grace = grace
+		}
+	}
+	return policy
+}
+
+// parseSignal accepts a signal name with or without the SIG prefix, or a
+// signal number.
+func parseSignal(s string) (unix.Signal, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n <= 0 || unix.SignalName(unix.Signal(n)) == "" {
+			return 0, fmt.Errorf("unknown signal number %d", n)
+		}
+		return unix.Signal(n), nil
+	}
+	name := strings.ToUpper(s)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	sig := unix.SignalNum(name)
+	if sig == 0 {
+		return 0, fmt.Errorf("unknown signal %q", s)
+	}
+	return sig, nil
+}
+
 func readSpec(p string) (*specs.Spec, error) {
 	const configFileName = "config.json"
 	f, err := os.Open(filepath.Join(p, configFileName))
@@ -49,29 +150,43 @@ func readSpec(p string) (*specs.Spec, error) {
 	return &s, nil
 }
 
+// writeSpec writes spec to the bundle's config.json. The file is left
+// untouched when it already holds the same encoding, so rewriting an
+// unchanged spec (e.g. on container restart) is a no-op.
 func writeSpec(p string, spec *specs.Spec) error {
 	const configFileName = "config.json"
-	f, err := os.Create(filepath.Join(p, configFileName))
-	if err != nil {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(spec); err != nil {
 		return err
 	}
-	defer func() {
-		if err := f.Close(); err != nil {
-			log.L.WithError(err).Warn("failed to close config.json")
-		}
-	}()
-	enc := json.NewEncoder(f)
-	enc.SetIndent("", "  ")
-	return enc.Encode(spec)
+
+	path := filepath.Join(p, configFileName)
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, buf.Bytes()) {
+		return nil
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o666)
 }
 
+// resolvConfFileName is the bundle file holding a container-specific
+// resolv.conf passed by the shim (transform.ResolvConfFileName).
+const resolvConfFileName = ".spinbox-resolv.conf"
+
 // RelaxOCISpec modifies the OCI spec for VM-isolated containers.
 // Since the container runs inside a VM, the VM provides the security boundary.
 // This function:
 //   - Bind-mounts /dev from the VM (gives access to all devices)
-//   - Allows all device access in cgroups
-//   - Removes readonly/masked paths and seccomp
-//   - Adds /etc/resolv.conf for DNS
+//   - Allows all device access in cgroups, unless AnnotationRestrictDevices
+//     is set
+//   - Removes readonly/masked paths and seccomp, unless AnnotationSecurityMode
+//     keeps them
+//   - Adds /etc/resolv.conf for DNS, preferring a resolv.conf in the bundle
+//     over the VM's own
+//
+// If AnnotationSecurityMode is "seccomp" or "preserve", the seccomp profile
+// is kept after validating it against the guest architecture. An
+// incompatible profile returns an error wrapping ErrIncompatibleSeccomp.
 func RelaxOCISpec(ctx context.Context, bundlePath string) error {
 	spec, err := readSpec(bundlePath)
 	if err != nil {
@@ -82,23 +197,47 @@ func RelaxOCISpec(ctx context.Context, bundlePath string) error {
 		spec.Linux = &specs.Linux{}
 	}
 
-	// Allow access to all devices via cgroups
-	spec.Linux.Resources = &specs.LinuxResources{
-		Devices: []specs.LinuxDeviceCgroup{{Allow: true, Access: "rwm"}},
+	// Allow access to all devices via cgroups, unless the shim restricted
+	// them (downgraded privileged container)
+	devices := []specs.LinuxDeviceCgroup{{Allow: true, Access: "rwm"}}
+	if spec.Annotations[AnnotationRestrictDevices] == "true" && spec.Linux.Resources != nil {
+		devices = spec.Linux.Resources.Devices
 	}
+	spec.Linux.Resources = &specs.LinuxResources{Devices: devices}
 
 	// Remove container isolation - VM provides it
-	spec.Linux.ReadonlyPaths = nil
-	spec.Linux.MaskedPaths = nil
-	spec.Linux.Seccomp = nil
+	if !preserveSecurity(spec) {
+		spec.Linux.ReadonlyPaths = nil
+		spec.Linux.MaskedPaths = nil
+	}
+	if keepSeccomp(spec) {
+		dropped, err := validateSeccomp(spec.Linux.Seccomp, runtime.GOARCH)
+		if err != nil {
+			return err
+		}
+		if len(dropped) > 0 {
+			log.G(ctx).WithField("architectures", dropped).Warn("dropped seccomp architectures unsupported by guest")
+		}
+	} else {
+		spec.Linux.Seccomp = nil
+	}
 
 	// Replace /dev with bind mount from VM's /dev
 	// This gives access to all devices (fuse, tun, etc.) automatically
 	// Also skip /dev/* mounts since they're included in the bind mount
-	var newMounts []specs.Mount
+	// Each mount added here appears at most once, so relaxing an already
+	// relaxed spec leaves it unchanged.
+	var (
+		newMounts []specs.Mount
+		hasDev    bool
+	)
 	for _, m := range spec.Mounts {
 		switch m.Destination {
 		case "/dev":
+			if hasDev {
+				continue
+			}
+			hasDev = true
 			// Replace tmpfs with bind mount
 			newMounts = append(newMounts, specs.Mount{
 				Destination: "/dev",
@@ -114,18 +253,13 @@ func RelaxOCISpec(ctx context.Context, bundlePath string) error {
 	}
 
 	// Add /etc/resolv.conf if not present
-	hasResolv := false
-	for _, m := range newMounts {
-		if m.Destination == "/etc/resolv.conf" {
-			hasResolv = true
-			break
-		}
-	}
-	if !hasResolv {
+	if !slices.ContainsFunc(newMounts, func(m specs.Mount) bool {
+		return m.Destination == "/etc/resolv.conf"
+	}) {
 		newMounts = append(newMounts, specs.Mount{
 			Destination: "/etc/resolv.conf",
 			Type:        "bind",
-			Source:      "/etc/resolv.conf",
+			Source:      resolvConfSource(bundlePath),
 			Options:     []string{"rbind", "ro"},
 		})
 	}
@@ -134,3 +268,14 @@ func RelaxOCISpec(ctx context.Context, bundlePath string) error {
 
 	return writeSpec(bundlePath, spec)
 }
+
+// resolvConfSource returns the resolv.conf to mount into a container: the
+// bundle's own copy if the shim provided one, otherwise the VM-wide file
+// written by configureDNS.
+func resolvConfSource(bundlePath string) string {
+	p := filepath.Join(bundlePath, resolvConfFileName)
+	if _, err := os.Stat(p); err == nil {
+		return p
+	}
+	return "/etc/resolv.conf"
+}
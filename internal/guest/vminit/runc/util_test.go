@@ -4,11 +4,16 @@ package runc
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/unix"
 )
 
 const devPath = "/dev"
@@ -188,6 +193,101 @@ func TestShouldKillAllOnExit(t *testing.T) {
 	}
 }
 
+func TestKillPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        ExitKillPolicy
+	}{
+		{
+			name: "defaults when absent",
+			want: ExitKillPolicy{Signal: unix.SIGKILL},
+		},
+		{
+			name: "signal name and grace",
+			annotations: map[string]string{
+				AnnotationKillSignal: "SIGTERM",
+				AnnotationKillGrace:  "10s",
+			},
+			want: ExitKillPolicy{Signal: unix.SIGTERM, Grace: 10 * time.Second},
+		},
+		{
+			name:        "short lowercase signal name",
+			annotations: map[string]string{AnnotationKillSignal: "int"},
+			want:        ExitKillPolicy{Signal: unix.SIGINT},
+		},
+		{
+			name:        "signal number",
+			annotations: map[string]string{AnnotationKillSignal: "15"},
+			want:        ExitKillPolicy{Signal: unix.SIGTERM},
+		},
+		{
+			name: "invalid values fall back to defaults",
+			annotations: map[string]string{
+				AnnotationKillSignal: "SIGBOGUS",
+				AnnotationKillGrace:  "soon",
+			},
+			want: ExitKillPolicy{Signal: unix.SIGKILL},
+		},
+		{
+			name: "negative grace and out of range signal are ignored",
+			annotations: map[string]string{
+				AnnotationKillSignal: "999",
+				AnnotationKillGrace:  "-1s",
+			},
+			want: ExitKillPolicy{Signal: unix.SIGKILL},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundleDir := t.TempDir()
+			spec := &specs.Spec{Version: "1.0.0", Annotations: tt.annotations}
+			if err := writeSpec(bundleDir, spec); err != nil {
+				t.Fatalf("failed to write spec: %v", err)
+			}
+
+			if got := KillPolicy(context.Background(), bundleDir); got != tt.want {
+				t.Errorf("KillPolicy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("missing spec uses defaults", func(t *testing.T) {
+		got := KillPolicy(context.Background(), t.TempDir())
+		if want := (ExitKillPolicy{Signal: unix.SIGKILL}); got != want {
+			t.Errorf("KillPolicy() = %+v, want %+v", got, want)
+		}
+	})
+}
+
+func TestInitExitMaxDelay(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        time.Duration
+	}{
+		{name: "unbounded when absent", want: 0},
+		{name: "duration", annotations: map[string]string{AnnotationInitExitMaxDelay: "30s"}, want: 30 * time.Second},
+		{name: "invalid is unbounded", annotations: map[string]string{AnnotationInitExitMaxDelay: "soon"}, want: 0},
+		{name: "negative is unbounded", annotations: map[string]string{AnnotationInitExitMaxDelay: "-1s"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundleDir := t.TempDir()
+			spec := &specs.Spec{Version: "1.0.0", Annotations: tt.annotations}
+			if err := writeSpec(bundleDir, spec); err != nil {
+				t.Fatalf("failed to write spec: %v", err)
+			}
+
+			if got := InitExitMaxDelay(context.Background(), bundleDir); got != tt.want {
+				t.Errorf("InitExitMaxDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestRelaxOCISpec(t *testing.T) {
 	t.Run("replaces /dev with bind mount and relaxes restrictions", func(t *testing.T) {
 		bundleDir := t.TempDir()
@@ -264,6 +364,266 @@ func TestRelaxOCISpec(t *testing.T) {
 		}
 	})
 
+	t.Run("keeps compatible seccomp profile when requested", func(t *testing.T) {
+		bundleDir := t.TempDir()
+
+		native := seccompArches[runtime.GOARCH][0]
+		spec := &specs.Spec{
+			Version:     "1.0.0",
+			Annotations: map[string]string{AnnotationSecurityMode: "seccomp"},
+			Linux: &specs.Linux{
+				Seccomp: &specs.LinuxSeccomp{
+					DefaultAction: specs.ActErrno,
+					Architectures: []specs.Arch{native, specs.ArchS390X},
+					Syscalls: []specs.LinuxSyscall{
+						{Names: []string{"read", "write"}, Action: specs.ActAllow},
+					},
+				},
+			},
+		}
+		if err := writeSpec(bundleDir, spec); err != nil {
+			t.Fatalf("failed to write spec: %v", err)
+		}
+
+		if err := RelaxOCISpec(context.Background(), bundleDir); err != nil {
+			t.Fatalf("RelaxOCISpec failed: %v", err)
+		}
+
+		updated, err := readSpec(bundleDir)
+		if err != nil {
+			t.Fatalf("failed to read updated spec: %v", err)
+		}
+		if updated.Linux.Seccomp == nil {
+			t.Fatal("Seccomp cleared despite keep annotation")
+		}
+		if !slices.Equal(updated.Linux.Seccomp.Architectures, []specs.Arch{native}) {
+			t.Errorf("Architectures = %v, want [%s]", updated.Linux.Seccomp.Architectures, native)
+		}
+		if len(updated.Linux.Seccomp.Syscalls) != 1 {
+			t.Errorf("Syscalls = %v, want rules preserved", updated.Linux.Seccomp.Syscalls)
+		}
+	})
+
+	t.Run("preserve mode keeps hardening but opens devices", func(t *testing.T) {
+		bundleDir := t.TempDir()
+
+		spec := &specs.Spec{
+			Version:     "1.0.0",
+			Annotations: map[string]string{AnnotationSecurityMode: "preserve"},
+			Linux: &specs.Linux{
+				ReadonlyPaths: []string{"/proc/bus"},
+				MaskedPaths:   []string{"/proc/kcore"},
+				Seccomp:       &specs.LinuxSeccomp{DefaultAction: specs.ActErrno},
+				Resources: &specs.LinuxResources{
+					Devices: []specs.LinuxDeviceCgroup{{Allow: false, Access: "rwm"}},
+				},
+			},
+		}
+		if err := writeSpec(bundleDir, spec); err != nil {
+			t.Fatalf("failed to write spec: %v", err)
+		}
+
+		if err := RelaxOCISpec(context.Background(), bundleDir); err != nil {
+			t.Fatalf("RelaxOCISpec failed: %v", err)
+		}
+
+		updated, err := readSpec(bundleDir)
+		if err != nil {
+			t.Fatalf("failed to read updated spec: %v", err)
+		}
+		if !slices.Equal(updated.Linux.ReadonlyPaths, []string{"/proc/bus"}) {
+			t.Errorf("ReadonlyPaths = %v, want [/proc/bus]", updated.Linux.ReadonlyPaths)
+		}
+		if !slices.Equal(updated.Linux.MaskedPaths, []string{"/proc/kcore"}) {
+			t.Errorf("MaskedPaths = %v, want [/proc/kcore]", updated.Linux.MaskedPaths)
+		}
+		if updated.Linux.Seccomp == nil || updated.Linux.Seccomp.DefaultAction != specs.ActErrno {
+			t.Errorf("Seccomp = %+v, want profile preserved", updated.Linux.Seccomp)
+		}
+		if len(updated.Linux.Resources.Devices) != 1 || !updated.Linux.Resources.Devices[0].Allow {
+			t.Errorf("Devices = %+v, want allow-all", updated.Linux.Resources.Devices)
+		}
+		hasResolv := false
+		for _, m := range updated.Mounts {
+			if m.Destination == "/etc/resolv.conf" {
+				hasResolv = true
+			}
+		}
+		if !hasResolv {
+			t.Error("resolv.conf mount not added")
+		}
+	})
+
+	t.Run("restricted devices are kept", func(t *testing.T) {
+		bundleDir := t.TempDir()
+
+		deny := []specs.LinuxDeviceCgroup{{Allow: false, Access: "rwm"}}
+		spec := &specs.Spec{
+			Version:     "1.0.0",
+			Annotations: map[string]string{AnnotationRestrictDevices: "true"},
+			Linux: &specs.Linux{
+				Resources: &specs.LinuxResources{Devices: deny},
+			},
+		}
+		if err := writeSpec(bundleDir, spec); err != nil {
+			t.Fatalf("failed to write spec: %v", err)
+		}
+
+		if err := RelaxOCISpec(context.Background(), bundleDir); err != nil {
+			t.Fatalf("RelaxOCISpec failed: %v", err)
+		}
+
+		updated, err := readSpec(bundleDir)
+		if err != nil {
+			t.Fatalf("failed to read updated spec: %v", err)
+		}
+		if !slices.Equal(updated.Linux.Resources.Devices, deny) {
+			t.Errorf("Devices = %+v, want %+v", updated.Linux.Resources.Devices, deny)
+		}
+	})
+
+	t.Run("rejects incompatible seccomp profile", func(t *testing.T) {
+		bundleDir := t.TempDir()
+
+		spec := &specs.Spec{
+			Version:     "1.0.0",
+			Annotations: map[string]string{AnnotationSecurityMode: "seccomp"},
+			Linux: &specs.Linux{
+				Seccomp: &specs.LinuxSeccomp{
+					DefaultAction: specs.ActErrno,
+					Architectures: []specs.Arch{specs.ArchS390X, specs.ArchPPC64LE},
+				},
+			},
+		}
+		if err := writeSpec(bundleDir, spec); err != nil {
+			t.Fatalf("failed to write spec: %v", err)
+		}
+
+		err := RelaxOCISpec(context.Background(), bundleDir)
+		if !errors.Is(err, ErrIncompatibleSeccomp) {
+			t.Fatalf("RelaxOCISpec error = %v, want ErrIncompatibleSeccomp", err)
+		}
+	})
+
+	t.Run("mounts bundle resolv.conf when present", func(t *testing.T) {
+		for _, tc := range []struct {
+			name       string
+			bundleFile bool
+			want       func(bundleDir string) string
+		}{
+			{
+				name:       "bundle file",
+				bundleFile: true,
+				want:       func(bundleDir string) string { return filepath.Join(bundleDir, resolvConfFileName) },
+			},
+			{
+				name: "VM fallback",
+				want: func(string) string { return "/etc/resolv.conf" },
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				bundleDir := t.TempDir()
+				if err := writeSpec(bundleDir, &specs.Spec{Version: "1.0.0"}); err != nil {
+					t.Fatalf("failed to write spec: %v", err)
+				}
+				if tc.bundleFile {
+					if err := os.WriteFile(filepath.Join(bundleDir, resolvConfFileName), []byte("nameserver 10.0.0.53\n"), 0600); err != nil {
+						t.Fatalf("failed to write resolv.conf: %v", err)
+					}
+				}
+
+				if err := RelaxOCISpec(context.Background(), bundleDir); err != nil {
+					t.Fatalf("RelaxOCISpec failed: %v", err)
+				}
+
+				updated, err := readSpec(bundleDir)
+				if err != nil {
+					t.Fatalf("failed to read updated spec: %v", err)
+				}
+				var resolv *specs.Mount
+				for i, m := range updated.Mounts {
+					if m.Destination == "/etc/resolv.conf" {
+						resolv = &updated.Mounts[i]
+					}
+				}
+				if resolv == nil {
+					t.Fatal("resolv.conf mount not added")
+				}
+				if want := tc.want(bundleDir); resolv.Source != want {
+					t.Errorf("resolv.conf source = %q, want %q", resolv.Source, want)
+				}
+			})
+		}
+	})
+
+	t.Run("is idempotent", func(t *testing.T) {
+		bundleDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(bundleDir, resolvConfFileName), []byte("nameserver 10.0.0.53\n"), 0600); err != nil {
+			t.Fatalf("failed to write resolv.conf: %v", err)
+		}
+
+		native := seccompArches[runtime.GOARCH][0]
+		spec := &specs.Spec{
+			Version:     "1.0.0",
+			Annotations: map[string]string{AnnotationSecurityMode: "seccomp"},
+			Linux: &specs.Linux{
+				ReadonlyPaths: []string{"/proc/bus"},
+				MaskedPaths:   []string{"/proc/kcore"},
+				Seccomp: &specs.LinuxSeccomp{
+					DefaultAction: specs.ActErrno,
+					Architectures: []specs.Arch{native, specs.ArchS390X},
+				},
+				Resources: &specs.LinuxResources{
+					Devices: []specs.LinuxDeviceCgroup{{Allow: false, Access: "rwm"}},
+				},
+			},
+			Mounts: []specs.Mount{
+				{Destination: "/proc", Type: "proc", Source: "proc"},
+				{Destination: devPath, Type: "tmpfs", Source: "tmpfs"},
+				{Destination: "/dev/pts", Type: "devpts", Source: "devpts"},
+				{Destination: devPath, Type: "tmpfs", Source: "tmpfs"},
+			},
+		}
+		if err := writeSpec(bundleDir, spec); err != nil {
+			t.Fatalf("failed to write spec: %v", err)
+		}
+
+		configPath := filepath.Join(bundleDir, "config.json")
+		if err := RelaxOCISpec(context.Background(), bundleDir); err != nil {
+			t.Fatalf("first RelaxOCISpec failed: %v", err)
+		}
+		first, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatalf("failed to read spec: %v", err)
+		}
+
+		if err := RelaxOCISpec(context.Background(), bundleDir); err != nil {
+			t.Fatalf("second RelaxOCISpec failed: %v", err)
+		}
+		second, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatalf("failed to read spec: %v", err)
+		}
+		if string(first) != string(second) {
+			t.Fatalf("spec changed on second run:\nfirst:\n%s\nsecond:\n%s", first, second)
+		}
+
+		updated, err := readSpec(bundleDir)
+		if err != nil {
+			t.Fatalf("failed to read updated spec: %v", err)
+		}
+		counts := make(map[string]int)
+		for _, m := range updated.Mounts {
+			counts[m.Destination]++
+		}
+		if counts[devPath] != 1 || counts["/etc/resolv.conf"] != 1 || counts["/dev/pts"] != 0 {
+			t.Errorf("mount counts = %v, want one /dev and one /etc/resolv.conf", counts)
+		}
+		if len(updated.Linux.Resources.Devices) != 1 {
+			t.Errorf("Devices = %+v, want a single allow-all rule", updated.Linux.Resources.Devices)
+		}
+	})
+
 	t.Run("error on missing spec file", func(t *testing.T) {
 		bundleDir := t.TempDir()
 		err := RelaxOCISpec(context.Background(), bundleDir)
@@ -0,0 +1,44 @@
+//go:build linux
+
+package system
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	bootMu       sync.RWMutex
+	bootTime     time.Time
+	initDuration time.Duration
+)
+
+// SetBootTime records when vminitd started. It is called once from main
+// before Initialize.
+func SetBootTime(t time.Time) {
+	bootMu.Lock()
+	defer bootMu.Unlock()
+	bootTime = t
+}
+
+// BootTime returns the time recorded by SetBootTime, or the zero time if it
+// was never called.
+func BootTime() time.Time {
+	bootMu.RLock()
+	defer bootMu.RUnlock()
+	return bootTime
+}
+
+// InitDuration returns how long the last successful Initialize took, or zero
+// if Initialize has not completed.
+func InitDuration() time.Duration {
+	bootMu.RLock()
+	defer bootMu.RUnlock()
+	return initDuration
+}
+
+func setInitDuration(d time.Duration) {
+	bootMu.Lock()
+	defer bootMu.Unlock()
+	initDuration = d
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/containerd/containerd/v2/core/mount"
 	"github.com/containerd/log"
@@ -19,6 +20,8 @@ import (
 // Initialize performs all system initialization tasks for the VM guest.
 // This includes mounting filesystems, configuring cgroups, and setting up DNS.
 func Initialize(ctx context.Context) error {
+	start := time.Now()
+
 	if err := mountFilesystems(); err != nil {
 		return err
 	}
@@ -41,6 +44,11 @@ func Initialize(ctx context.Context) error {
 	// Not fatal if devices don't appear - they might appear later or not be needed
 	devices.WaitForBlockDevices(ctx)
 
+	// Mount /tmp once block devices are available, since it may be backed by a data disk
+	if err := mountTmp(ctx); err != nil {
+		return err
+	}
+
 	if err := setupCgroupControl(); err != nil {
 		return err
 	}
@@ -55,15 +63,32 @@ func Initialize(ctx context.Context) error {
 		log.G(ctx).WithError(err).Warn("failed to configure DNS, continuing anyway")
 	}
 
+	// Configure the eth0 MTU from kernel command line
+	if err := configureMTU(ctx); err != nil {
+		log.G(ctx).WithError(err).Warn("failed to configure MTU, continuing anyway")
+	}
+
+	// Configure eth0 IPv6 from kernel command line
+	if err := configureIPv6(ctx); err != nil {
+		log.G(ctx).WithError(err).Warn("failed to configure IPv6, continuing anyway")
+	}
+
+	// Install static routes from kernel command line
+	if err := configureRoutes(ctx); err != nil {
+		log.G(ctx).WithError(err).Warn("failed to configure static routes, continuing anyway")
+	}
+
 	// Configure route to metadata service for supervisor agent
 	if err := configureMetadataRoute(ctx); err != nil {
 		log.G(ctx).WithError(err).Warn("failed to configure metadata route, continuing anyway")
 	}
 
+	setInitDuration(time.Since(start))
 	return nil
 }
 
 // mountFilesystems mounts all required filesystems for the VM guest.
+// /tmp is mounted later by mountTmp.
 func mountFilesystems() error {
 	// Create /lib if it doesn't exist (needed for modules)
 	// #nosec G301 -- /lib must be world-readable inside the VM.
@@ -96,12 +121,6 @@ func mountFilesystems() error {
 			Target:  "/run",
 			Options: []string{"nosuid", "noexec", "nodev"},
 		},
-		{
-			Type:    "tmpfs",
-			Source:  "tmpfs",
-			Target:  "/tmp",
-			Options: []string{"nosuid", "noexec", "nodev"},
-		},
 		{
 			Type:    "devtmpfs",
 			Source:  "devtmpfs",
@@ -0,0 +1,228 @@
+//go:build linux
+
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/containerd/log"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// ParamMTU is the kernel cmdline parameter carrying the eth0 MTU
+// (must match the qemu kernel cmdline builder). The kernel ip= parameter
+// has no MTU field.
+const ParamMTU = "spin.mtu"
+
+// ParamRoutes is the kernel cmdline parameter carrying the static routes
+// for eth0 beyond the default route: comma-separated <dst-cidr>[@<gateway>]
+// entries (must match the qemu kernel cmdline builder).
+const ParamRoutes = "spin.routes"
+
+// ParamIPv6 is the kernel cmdline parameter carrying the eth0 IPv6
+// configuration as <addr/prefix>[@<gateway>] (must match the qemu kernel
+// cmdline builder). The kernel ip= parameter is IPv4-only.
+const ParamIPv6 = "spin.ipv6"
+
+// Bounds accepted for ParamMTU: the IPv4 minimum and the largest jumbo frame.
+const (
+	minMTU = 68
+	maxMTU = 65535
+)
+
+// parseMTU extracts the eth0 MTU from the kernel command line.
+// Returns zero if the parameter is absent.
+func parseMTU(cmdline string) (int, error) {
+	var mtu int
+	for param := range strings.FieldsSeq(cmdline) {
+		value, ok := strings.CutPrefix(param, ParamMTU+"=")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < minMTU || n > maxMTU {
+			return 0, fmt.Errorf("invalid %s=%s: must be between %d and %d", ParamMTU, value, minMTU, maxMTU)
+		}
+		mtu = n
+	}
+	return mtu, nil
+}
+
+// configureMTU sets the eth0 MTU selected on the kernel command line, so the
+// guest matches networks with a smaller MTU, such as VXLAN overlays.
+func configureMTU(ctx context.Context) error {
+	cmdline, err := os.ReadFile("/proc/cmdline")
+	if err != nil {
+		return fmt.Errorf("failed to read /proc/cmdline: %w", err)
+	}
+	mtu, err := parseMTU(string(cmdline))
+	if err != nil || mtu == 0 {
+		return err
+	}
+
+	if err := setLinkMTU("eth0", mtu); err != nil {
+		return fmt.Errorf("set eth0 MTU to %d: %w", mtu, err)
+	}
+	log.G(ctx).WithField("mtu", mtu).Info("configured eth0 MTU")
+	return nil
+}
+
+// ipv6Config is the eth0 IPv6 configuration from the kernel command line.
+type ipv6Config struct {
+	Addr *net.IPNet
+	GW   net.IP // nil without a default route
+}
+
+// parseIPv6 extracts the eth0 IPv6 configuration from the kernel command
+// line. Returns nil if the parameter is absent.
+func parseIPv6(cmdline string) (*ipv6Config, error) {
+	var cfg *ipv6Config
+	for param := range strings.FieldsSeq(cmdline) {
+		value, ok := strings.CutPrefix(param, ParamIPv6+"=")
+		if !ok {
+			continue
+		}
+		addr, gw, hasGW := strings.Cut(value, "@")
+		ip, ipnet, err := net.ParseCIDR(addr)
+		if err != nil || ip.To4() != nil {
+			return nil, fmt.Errorf("invalid %s=%s: want an IPv6 address with prefix length", ParamIPv6, value)
+		}
+		ipnet.IP = ip
+		cfg = &ipv6Config{Addr: ipnet}
+		if hasGW {
+			if cfg.GW = net.ParseIP(gw); cfg.GW == nil || cfg.GW.To4() != nil {
+				return nil, fmt.Errorf("invalid %s=%s: bad IPv6 gateway", ParamIPv6, value)
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// configureIPv6 assigns the eth0 IPv6 address and default route selected on
+// the kernel command line. eth0 is brought up first, since an IPv6-only
+// network has no ip= parameter for the kernel to do so.
+func configureIPv6(ctx context.Context) error {
+	cmdline, err := os.ReadFile("/proc/cmdline")
+	if err != nil {
+		return fmt.Errorf("failed to read /proc/cmdline: %w", err)
+	}
+	cfg, err := parseIPv6(string(cmdline))
+	if err != nil || cfg == nil {
+		return err
+	}
+
+	link, err := netlink.LinkByName("eth0")
+	if err != nil {
+		return fmt.Errorf("lookup eth0: %w", err)
+	}
+	if err := netlink.LinkSetUp(link); err != nil {
+		return fmt.Errorf("set eth0 up: %w", err)
+	}
+	// Skip duplicate address detection: the address is allocated by IPAM,
+	// and a tentative address cannot be used as a route source at boot
+	if err := netlink.AddrReplace(link, &netlink.Addr{IPNet: cfg.Addr, Flags: unix.IFA_F_NODAD}); err != nil {
+		return fmt.Errorf("add address %s: %w", cfg.Addr, err)
+	}
+	if cfg.GW != nil {
+		_, defaultDst, _ := net.ParseCIDR("::/0")
+		if err := netlink.RouteReplace(&netlink.Route{LinkIndex: link.Attrs().Index, Dst: defaultDst, Gw: cfg.GW}); err != nil {
+			return fmt.Errorf("add default route via %s: %w", cfg.GW, err)
+		}
+	}
+
+	log.G(ctx).WithFields(log.Fields{
+		"address": cfg.Addr.String(),
+		"gateway": cfg.GW,
+	}).Info("configured eth0 IPv6")
+	return nil
+}
+
+// route is a static route from the kernel command line.
+type route struct {
+	Dst *net.IPNet
+	GW  net.IP // nil for an on-link route
+}
+
+// parseRoutes extracts the eth0 static routes from the kernel command line.
+func parseRoutes(cmdline string) ([]route, error) {
+	var routes []route
+	for param := range strings.FieldsSeq(cmdline) {
+		value, ok := strings.CutPrefix(param, ParamRoutes+"=")
+		if !ok {
+			continue
+		}
+		routes = routes[:0]
+		for entry := range strings.SplitSeq(value, ",") {
+			dst, gw, hasGW := strings.Cut(entry, "@")
+			_, ipnet, err := net.ParseCIDR(dst)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s entry %q: %w", ParamRoutes, entry, err)
+			}
+			r := route{Dst: ipnet}
+			if hasGW {
+				if r.GW = net.ParseIP(gw); r.GW == nil {
+					return nil, fmt.Errorf("invalid %s entry %q: bad gateway", ParamRoutes, entry)
+				}
+			}
+			routes = append(routes, r)
+		}
+	}
+	return routes, nil
+}
+
+// configureRoutes installs the eth0 static routes selected on the kernel
+// command line. The default route is configured by the kernel from ip=.
+func configureRoutes(ctx context.Context) error {
+	cmdline, err := os.ReadFile("/proc/cmdline")
+	if err != nil {
+		return fmt.Errorf("failed to read /proc/cmdline: %w", err)
+	}
+	routes, err := parseRoutes(string(cmdline))
+	if err != nil || len(routes) == 0 {
+		return err
+	}
+
+	link, err := netlink.LinkByName("eth0")
+	if err != nil {
+		return fmt.Errorf("lookup eth0: %w", err)
+	}
+	var errs []error
+	for _, r := range routes {
+		nr := &netlink.Route{LinkIndex: link.Attrs().Index, Dst: r.Dst, Gw: r.GW}
+		if r.GW == nil {
+			nr.Scope = netlink.SCOPE_LINK
+		}
+		if err := netlink.RouteReplace(nr); err != nil {
+			errs = append(errs, fmt.Errorf("add route %s: %w", r.Dst, err))
+			continue
+		}
+		log.G(ctx).WithFields(log.Fields{
+			"dst":     r.Dst.String(),
+			"gateway": r.GW,
+		}).Debug("installed static route")
+	}
+	return errors.Join(errs...)
+}
+
+// setLinkMTU sets the MTU of the named interface.
+func setLinkMTU(name string, mtu int) error {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return fmt.Errorf("open socket: %w", err)
+	}
+	defer unix.Close(fd)
+
+	ifr, err := unix.NewIfreq(name)
+	if err != nil {
+		return err
+	}
+	ifr.SetUint32(uint32(mtu)) // #nosec G115 -- bounded by parseMTU
+	return unix.IoctlIfreq(fd, unix.SIOCSIFMTU, ifr)
+}
@@ -0,0 +1,125 @@
+//go:build linux
+
+package system
+
+import (
+	"net"
+	"testing"
+)
+
+func TestParseMTU(t *testing.T) {
+	tests := []struct {
+		name      string
+		cmdline   string
+		expected  int
+		expectErr bool
+	}{
+		{
+			name:    "absent",
+			cmdline: "console=ttyS0 ip=10.0.0.2::10.0.0.1:255.255.255.0::eth0:none",
+		},
+		{
+			name:     "overlay MTU",
+			cmdline:  "console=ttyS0 ip=10.0.0.2::10.0.0.1:255.255.255.0::eth0:none spin.mtu=1450",
+			expected: 1450,
+		},
+		{
+			name:     "last value wins",
+			cmdline:  "spin.mtu=1500 spin.mtu=9000",
+			expected: 9000,
+		},
+		{
+			name:      "not a number",
+			cmdline:   "spin.mtu=large",
+			expectErr: true,
+		},
+		{
+			name:      "below IPv4 minimum",
+			cmdline:   "spin.mtu=67",
+			expectErr: true,
+		},
+		{
+			name:      "too large",
+			cmdline:   "spin.mtu=65536",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mtu, err := parseMTU(tt.cmdline)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("parseMTU(%q) = %d, want error", tt.cmdline, mtu)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseMTU(%q) failed: %v", tt.cmdline, err)
+			}
+			if mtu != tt.expected {
+				t.Fatalf("parseMTU(%q) = %d, want %d", tt.cmdline, mtu, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := parseRoutes("console=ttyS0 spin.routes=10.96.0.0/12@10.88.0.254,192.168.100.0/24")
+	if err != nil {
+		t.Fatalf("parseRoutes failed: %v", err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("got %d routes, want 2", len(routes))
+	}
+	if routes[0].Dst.String() != "10.96.0.0/12" || !routes[0].GW.Equal(net.ParseIP("10.88.0.254")) {
+		t.Errorf("routes[0] = %v via %v", routes[0].Dst, routes[0].GW)
+	}
+	if routes[1].Dst.String() != "192.168.100.0/24" || routes[1].GW != nil {
+		t.Errorf("routes[1] = %v via %v, want on-link", routes[1].Dst, routes[1].GW)
+	}
+
+	if routes, err := parseRoutes("console=ttyS0"); err != nil || len(routes) != 0 {
+		t.Errorf("parseRoutes without param = %v, %v", routes, err)
+	}
+
+	for _, cmdline := range []string{
+		"spin.routes=10.96.0.0",
+		"spin.routes=10.96.0.0/12@gateway",
+		"spin.routes=",
+	} {
+		if _, err := parseRoutes(cmdline); err == nil {
+			t.Errorf("parseRoutes(%q) succeeded, want error", cmdline)
+		}
+	}
+}
+
+func TestParseIPv6(t *testing.T) {
+	cfg, err := parseIPv6("console=ttyS0 spin.ipv6=fd00::5/64@fd00::1")
+	if err != nil {
+		t.Fatalf("parseIPv6 failed: %v", err)
+	}
+	if cfg.Addr.String() != "fd00::5/64" || !cfg.GW.Equal(net.ParseIP("fd00::1")) {
+		t.Errorf("parseIPv6 = %v via %v, want fd00::5/64 via fd00::1", cfg.Addr, cfg.GW)
+	}
+
+	cfg, err = parseIPv6("spin.ipv6=fd00::5/64")
+	if err != nil || cfg.GW != nil {
+		t.Errorf("parseIPv6 without gateway = %+v, %v", cfg, err)
+	}
+
+	if cfg, err := parseIPv6("console=ttyS0"); err != nil || cfg != nil {
+		t.Errorf("parseIPv6 without param = %+v, %v", cfg, err)
+	}
+
+	for _, cmdline := range []string{
+		"spin.ipv6=fd00::5",
+		"spin.ipv6=10.0.0.5/24",
+		"spin.ipv6=fd00::5/64@10.0.0.1",
+		"spin.ipv6=fd00::5/64@gateway",
+	} {
+		if _, err := parseIPv6(cmdline); err == nil {
+			t.Errorf("parseIPv6(%q) succeeded, want error", cmdline)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+//go:build linux
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containerd/containerd/v2/core/mount"
+	"github.com/containerd/log"
+
+	"github.com/spin-stack/spinbox/internal/guest/vminit/devices"
+)
+
+// ParamTmp is the kernel cmdline parameter selecting the /tmp backing
+// (must match shim/resources package).
+//
+// Accepted values:
+//   - tmpfs (default): /tmp is a tmpfs sharing guest memory with the workload
+//   - disk:<device>[:<fstype>]: /tmp is the filesystem on a data disk
+//     (e.g., disk:/dev/vdb or disk:/dev/vdb:xfs; fstype defaults to ext4)
+const ParamTmp = "spin.tmp"
+
+const (
+	tmpBackingTmpfs = "tmpfs"
+	tmpBackingDisk  = "disk"
+
+	defaultTmpDiskFSType = "ext4"
+)
+
+// tmpConfig describes how /tmp is backed in the guest.
+type tmpConfig struct {
+	Backing string // tmpBackingTmpfs or tmpBackingDisk
+	Device  string // block device for disk backing
+	FSType  string // filesystem type for disk backing
+}
+
+// parseTmpConfig extracts the /tmp backing from the kernel command line.
+// Returns the tmpfs backing if the parameter is absent.
+func parseTmpConfig(cmdline string) (tmpConfig, error) {
+	cfg := tmpConfig{Backing: tmpBackingTmpfs}
+
+	for param := range strings.FieldsSeq(cmdline) {
+		value, ok := strings.CutPrefix(param, ParamTmp+"=")
+		if !ok {
+			continue
+		}
+
+		backing, rest, _ := strings.Cut(value, ":")
+		switch backing {
+		case tmpBackingTmpfs:
+			cfg = tmpConfig{Backing: tmpBackingTmpfs}
+		case tmpBackingDisk:
+			device, fsType, _ := strings.Cut(rest, ":")
+			if !strings.HasPrefix(device, "/dev/") {
+				return tmpConfig{}, fmt.Errorf("invalid %s=%s: disk backing requires a /dev/ device", ParamTmp, value)
+			}
+			if fsType == "" {
+				fsType = defaultTmpDiskFSType
+			}
+			cfg = tmpConfig{Backing: tmpBackingDisk, Device: device, FSType: fsType}
+		default:
+			return tmpConfig{}, fmt.Errorf("invalid %s=%s: unknown backing %q", ParamTmp, value, backing)
+		}
+	}
+
+	return cfg, nil
+}
+
+// tmpMount returns the mount for /tmp with the given backing.
+func tmpMount(cfg tmpConfig) mount.Mount {
+	if cfg.Backing == tmpBackingDisk {
+		return mount.Mount{
+			Type:    cfg.FSType,
+			Source:  cfg.Device,
+			Target:  "/tmp",
+			Options: []string{"nosuid", "noexec", "nodev"},
+		}
+	}
+
+	return mount.Mount{
+		Type:    "tmpfs",
+		Source:  "tmpfs",
+		Target:  "/tmp",
+		Options: []string{"nosuid", "noexec", "nodev"},
+	}
+}
+
+// mountTmp mounts /tmp using the backing selected on the kernel command line.
+// Must be called after block devices have been probed. If the data disk
+// cannot be mounted, /tmp falls back to tmpfs so the VM still boots.
+func mountTmp(ctx context.Context) error {
+	cmdline, err := os.ReadFile("/proc/cmdline")
+	if err != nil {
+		return fmt.Errorf("failed to read /proc/cmdline: %w", err)
+	}
+
+	cfg, err := parseTmpConfig(string(cmdline))
+	if err != nil {
+		log.G(ctx).WithError(err).Warn("invalid /tmp backing, using tmpfs")
+		cfg = tmpConfig{Backing: tmpBackingTmpfs}
+	}
+
+	if cfg.Backing == tmpBackingDisk {
+		if err := mount.All([]mount.Mount{tmpMount(cfg)}, "/"); err != nil {
+			log.G(ctx).WithError(err).WithField("device", cfg.Device).
+				Warn("failed to mount /tmp data disk, using tmpfs")
+			cfg = tmpConfig{Backing: tmpBackingTmpfs}
+		} else {
+			// A fresh filesystem root is 0755; /tmp must be world-writable with the sticky bit.
+			// #nosec G302 -- /tmp requires mode 1777.
+			if err := os.Chmod("/tmp", 0o777|os.ModeSticky); err != nil {
+				return fmt.Errorf("failed to set /tmp permissions: %w", err)
+			}
+			log.G(ctx).WithFields(log.Fields{
+				"device": cfg.Device,
+				"fstype": cfg.FSType,
+			}).Info("mounted /tmp on data disk")
+			devices.StartResizeWatcher(ctx)
+			return nil
+		}
+	}
+
+	return mount.All([]mount.Mount{tmpMount(cfg)}, "/")
+}
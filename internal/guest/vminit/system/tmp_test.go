@@ -0,0 +1,93 @@
+//go:build linux
+
+package system
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseTmpConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		cmdline   string
+		expected  tmpConfig
+		expectErr bool
+	}{
+		{
+			name:     "absent defaults to tmpfs",
+			cmdline:  "console=ttyS0 ip=10.0.0.2::10.0.0.1:255.255.255.0::eth0:none",
+			expected: tmpConfig{Backing: tmpBackingTmpfs},
+		},
+		{
+			name:     "explicit tmpfs",
+			cmdline:  "console=ttyS0 spin.tmp=tmpfs",
+			expected: tmpConfig{Backing: tmpBackingTmpfs},
+		},
+		{
+			name:     "disk with default fstype",
+			cmdline:  "console=ttyS0 spin.tmp=disk:/dev/vdb",
+			expected: tmpConfig{Backing: tmpBackingDisk, Device: "/dev/vdb", FSType: "ext4"},
+		},
+		{
+			name:     "disk with fstype",
+			cmdline:  "spin.tmp=disk:/dev/vdc:xfs quiet",
+			expected: tmpConfig{Backing: tmpBackingDisk, Device: "/dev/vdc", FSType: "xfs"},
+		},
+		{
+			name:      "disk without device",
+			cmdline:   "spin.tmp=disk",
+			expectErr: true,
+		},
+		{
+			name:      "disk with relative device",
+			cmdline:   "spin.tmp=disk:vdb",
+			expectErr: true,
+		},
+		{
+			name:      "unknown backing",
+			cmdline:   "spin.tmp=ramdisk",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := parseTmpConfig(tt.cmdline)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("parseTmpConfig() expected error, got %+v", cfg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTmpConfig() unexpected error: %v", err)
+			}
+			if cfg != tt.expected {
+				t.Errorf("parseTmpConfig() = %+v, want %+v", cfg, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTmpMount(t *testing.T) {
+	t.Run("tmpfs", func(t *testing.T) {
+		m := tmpMount(tmpConfig{Backing: tmpBackingTmpfs})
+		if m.Type != "tmpfs" || m.Source != "tmpfs" || m.Target != "/tmp" {
+			t.Errorf("tmpMount() = %+v, want tmpfs on /tmp", m)
+		}
+		if !slices.Equal(m.Options, []string{"nosuid", "noexec", "nodev"}) {
+			t.Errorf("tmpMount() options = %v", m.Options)
+		}
+	})
+
+	t.Run("disk", func(t *testing.T) {
+		m := tmpMount(tmpConfig{Backing: tmpBackingDisk, Device: "/dev/vdb", FSType: "ext4"})
+		if m.Type != "ext4" || m.Source != "/dev/vdb" || m.Target != "/tmp" {
+			t.Errorf("tmpMount() = %+v, want ext4 /dev/vdb on /tmp", m)
+		}
+		if !slices.Equal(m.Options, []string{"nosuid", "noexec", "nodev"}) {
+			t.Errorf("tmpMount() options = %v", m.Options)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+//go:build linux
+
+package system
+
+import (
+	"context"
+	"sync"
+)
+
+// ContainerUsage is the exec count and resource usage of one container.
+type ContainerUsage struct {
+	ID               string
+	RunningExecs     int
+	CPUUsageUsec     uint64
+	MemoryUsageBytes uint64
+}
+
+// UsageReporter returns the usage of every container in the VM.
+type UsageReporter func(ctx context.Context) []ContainerUsage
+
+var (
+	usageMu       sync.RWMutex
+	usageReporter UsageReporter
+)
+
+// SetUsageReporter registers the function Usage calls. The task service
+// registers itself when it is created.
+func SetUsageReporter(r UsageReporter) {
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	usageReporter = r
+}
+
+// Usage returns the usage reported by the registered UsageReporter, or nil
+// if none is registered.
+func Usage(ctx context.Context) []ContainerUsage {
+	usageMu.RLock()
+	r := usageReporter
+	usageMu.RUnlock()
+	if r == nil {
+		return nil
+	}
+	return r(ctx)
+}
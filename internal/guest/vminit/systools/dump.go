@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -15,33 +16,32 @@ import (
 	"github.com/containerd/log"
 )
 
-// DumpInfo dumps information about the system
-func DumpInfo(ctx context.Context) {
-	if err := filepath.Walk("/", func(path string, info os.FileInfo, err error) error {
-		if path == "/proc" || path == "/sys" {
-			path = fmt.Sprintf("%s (skipping)", path)
-			err = filepath.SkipDir
-		}
+// infoFiles are the files whose contents DumpInfo logs.
+var infoFiles = []string{
+	"/proc/cmdline",
+	"/proc/mounts",
+	"/etc/resolv.conf",
+	"/etc/hosts",
+	"/sys/fs/cgroup/cgroup.controllers",
+	"/sys/fs/cgroup/cgroup.subtree_control",
+}
 
-		if info != nil {
-			log.G(ctx).WithFields(
-				log.Fields{
-					"mode": info.Mode(),
-					"size": info.Size(),
-				}).Debug(path)
-		}
+// infoDirs are the directories whose entries DumpInfo logs, one level deep.
+var infoDirs = []string{
+	"/",
+	"/dev",
+	"/run",
+	"/sbin",
+}
 
-		return err
-	}); err != nil {
-		log.G(ctx).WithError(err).Warn("failed to walk filesystem")
-	}
+// maxInfoFileSize caps how much of each file in infoFiles is logged.
+const maxInfoFileSize = 4096
+
+// DumpInfo dumps information about the system. It only looks at a fixed set
+// of files and directories so it stays cheap enough to run on every boot.
+func DumpInfo(ctx context.Context) {
+	dumpInfo(ctx, infoFiles, infoDirs)
 
-	b, err := os.ReadFile("/proc/cmdline")
-	if err != nil {
-		log.G(ctx).WithError(err).Error("failed to read kernel command line")
-	} else {
-		log.G(ctx).WithField("cmdline", string(b)).Debug("read kernel command line")
-	}
 	log.G(ctx).WithField("ncpu", runtime.NumCPU()).Debug("runtime CPU count")
 
 	if b, err := exec.CommandContext(ctx, "/sbin/crun", "--version").Output(); err != nil {
@@ -52,27 +52,143 @@ func DumpInfo(ctx context.Context) {
 	DumpPids(ctx)
 }
 
-// DumpFile writes a file's contents to stderr for debugging.
-func DumpFile(ctx context.Context, name string) {
+// dumpInfo logs the contents of files and the entries of dirs.
+func dumpInfo(ctx context.Context, files, dirs []string) {
+	for _, name := range files {
+		data, err := readHead(name, maxInfoFileSize)
+		if err != nil {
+			log.G(ctx).WithError(err).WithField("f", name).Warn("failed to read file")
+			continue
+		}
+		log.G(ctx).WithField("f", name).Debug(strings.TrimSpace(string(data)))
+	}
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			log.G(ctx).WithError(err).WithField("dir", dir).Warn("failed to read directory")
+			continue
+		}
+		for _, e := range entries {
+			info, err := e.Info()
+			if err != nil {
+				continue
+			}
+			log.G(ctx).WithFields(log.Fields{
+				"mode": info.Mode(),
+				"size": info.Size(),
+			}).Debug(filepath.Join(dir, e.Name()))
+		}
+	}
+}
+
+// readHead reads at most n bytes from the start of the file name.
+func readHead(name string, n int64) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return io.ReadAll(io.LimitReader(f, n))
+}
+
+// DefaultDumpFileMaxBytes is how much of a file DumpFile writes by default.
+const DefaultDumpFileMaxBytes = 64 * 1024
+
+// DumpOpt configures DumpFile.
+type DumpOpt func(*dumpConfig)
+
+type dumpConfig struct {
+	maxBytes int64
+}
+
+// WithMaxBytes sets how much of the file DumpFile writes. Longer files are
+// truncated and end with a marker giving their full size. n <= 0 means no
+// limit.
+func WithMaxBytes(n int64) DumpOpt {
+	return func(c *dumpConfig) {
+		c.maxBytes = n
+	}
+}
+
+// DumpFile writes a file's contents to stderr for debugging. Files larger
+// than DefaultDumpFileMaxBytes (or WithMaxBytes) are truncated. Nothing is
+// written unless debug logging is enabled.
+func DumpFile(ctx context.Context, name string, opts ...DumpOpt) {
 	if !log.G(ctx).Logger.IsLevelEnabled(log.DebugLevel) {
 		return
 	}
 
-	data, err := os.ReadFile(name)
+	if err := DumpFileToWriter(ctx, os.Stderr, name, opts...); err != nil {
+		log.G(ctx).WithError(err).WithField("f", name).Warn("failed to dump file")
+	}
+}
+
+// DumpFileToWriter writes a file's contents to w like DumpFile, regardless
+// of the log level. JSON files are pretty-printed unless truncated.
+func DumpFileToWriter(ctx context.Context, w io.Writer, name string, opts ...DumpOpt) error {
+	cfg := dumpConfig{maxBytes: DefaultDumpFileMaxBytes}
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	data, total, err := readDumpFile(name, cfg.maxBytes)
 	if err != nil {
-		log.G(ctx).WithError(err).WithField("f", name).Warn("failed to read file")
-		return
+		return fmt.Errorf("read %s: %w", name, err)
+	}
+
+	log.G(ctx).WithField("f", name).Debug("dumping file")
+
+	if _, err := fmt.Fprintln(w, string(formatDump(name, data, total))); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// readDumpFile reads up to maxBytes of the file name (all of it if maxBytes
+// <= 0) and returns the data read along with the file's total size.
+func readDumpFile(name string, maxBytes int64) ([]byte, int64, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, 0, err
 	}
+	defer func() { _ = f.Close() }()
 
-	log.G(ctx).WithField("f", name).Debug("dumping file to stderr")
+	var r io.Reader = f
+	if maxBytes > 0 {
+		r = io.LimitReader(f, maxBytes)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := int64(len(data))
+	if maxBytes > 0 && total == maxBytes {
+		// The file may continue; count the rest without keeping it
+		rest, err := io.Copy(io.Discard, f)
+		if err != nil {
+			return nil, 0, err
+		}
+		total += rest
+	}
+	return data, total, nil
+}
+
+// formatDump renders data, the first part of a file of total bytes, for
+// DumpFile. Complete JSON files are pretty-printed; truncated files are
+// written as is, followed by a truncation marker.
+func formatDump(name string, data []byte, total int64) []byte {
+	if int64(len(data)) < total {
+		return fmt.Appendf(data[:len(data):len(data)], "\n...[truncated, %d bytes total]", total)
+	}
 
 	// Pretty-print JSON files
 	if strings.HasSuffix(name, ".json") {
 		var formatted bytes.Buffer
 		if json.Indent(&formatted, data, "", "  ") == nil {
-			data = formatted.Bytes()
+			return formatted.Bytes()
 		}
 	}
-
-	fmt.Fprintln(os.Stderr, string(data))
+	return data
 }
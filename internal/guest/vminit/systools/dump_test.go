@@ -1,85 +1,275 @@
 package systools
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/containerd/log"
+	"github.com/sirupsen/logrus"
 )
 
 func TestDumpFile(t *testing.T) {
+	// DumpFile only writes when debug logging is enabled
+	dir := t.TempDir()
+	path := filepath.Join(dir, "debug.txt")
+	if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	DumpFile(context.Background(), path)
+	DumpFile(context.Background(), "/nonexistent/file.txt")
+}
+
+func TestDumpFileToWriter(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{
+			name:    "plain text file",
+			file:    "test.txt",
+			content: "Hello, World!\nLine 2\n",
+			want:    "Hello, World!\nLine 2\n\n",
+		},
+		{
+			name:    "JSON file",
+			file:    "test.json",
+			content: `{"key": "value", "number": 123}`,
+			want:    "{\n  \"key\": \"value\",\n  \"number\": 123\n}\n",
+		},
+		{
+			name:    "invalid JSON file",
+			file:    "bad.json",
+			content: `{invalid json`,
+			want:    "{invalid json\n",
+		},
+		{
+			name:    "JSON content without .json suffix",
+			file:    "config",
+			content: `{"key":"value"}`,
+			want:    "{\"key\":\"value\"}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			if err := DumpFileToWriter(context.Background(), &buf, path); err != nil {
+				t.Fatalf("DumpFileToWriter() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("non-existent file", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := DumpFileToWriter(context.Background(), &buf, "/nonexistent/file.txt")
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("DumpFileToWriter() error = %v, want not exist", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("wrote %q for a missing file", buf.String())
+		}
+	})
+
+	t.Run("max bytes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "large.json")
+		if err := os.WriteFile(path, []byte(`{"key":"`+strings.Repeat("x", 100)+`"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := DumpFileToWriter(context.Background(), &buf, path, WithMaxBytes(8)); err != nil {
+			t.Fatal(err)
+		}
+		want := `{"key":"` + "\n...[truncated, 110 bytes total]\n"
+		if got := buf.String(); got != want {
+			t.Errorf("output = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestDumpFileTruncation(t *testing.T) {
+	const limit = 64
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	marker := "...[truncated,"
+
 	tests := []struct {
-		name  string
-		setup func(t *testing.T, tmpDir string) string
+		name      string
+		path      string
+		want      string // exact output, when set
+		truncated bool
 	}{
 		{
-			name: "plain text file",
-			setup: func(t *testing.T, tmpDir string) string {
-				path := filepath.Join(tmpDir, "test.txt")
-				if err := os.WriteFile(path, []byte("Hello, World!\nLine 2\n"), 0644); err != nil {
-					t.Fatal(err)
-				}
-				return path
-			},
+			name: "below cap",
+			path: write("small.txt", "short\n"),
+			want: "short\n",
 		},
 		{
-			name: "JSON file",
-			setup: func(t *testing.T, tmpDir string) string {
-				path := filepath.Join(tmpDir, "test.json")
-				if err := os.WriteFile(path, []byte(`{"key": "value", "number": 123}`), 0644); err != nil {
-					t.Fatal(err)
-				}
-				return path
-			},
+			name: "exactly at cap",
+			path: write("exact.txt", strings.Repeat("a", limit)),
+			want: strings.Repeat("a", limit),
 		},
 		{
-			name: "invalid JSON file",
-			setup: func(t *testing.T, tmpDir string) string {
-				path := filepath.Join(tmpDir, "bad.json")
-				if err := os.WriteFile(path, []byte(`{invalid json`), 0644); err != nil {
-					t.Fatal(err)
-				}
-				return path
-			},
+			name:      "above cap",
+			path:      write("large.txt", strings.Repeat("b", 3*limit)),
+			want:      strings.Repeat("b", limit) + fmt.Sprintf("\n...[truncated, %d bytes total]", 3*limit),
+			truncated: true,
 		},
 		{
-			name: "non-existent file",
-			setup: func(t *testing.T, _ string) string {
-				return "/nonexistent/file.txt"
-			},
+			name: "JSON below cap is pretty-printed",
+			path: write("small.json", `{"a":1}`),
+			want: "{\n  \"a\": 1\n}",
 		},
 		{
-			name: "debug level disabled",
-			setup: func(t *testing.T, tmpDir string) string {
-				path := filepath.Join(tmpDir, "debug.txt")
-				if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
-					t.Fatal(err)
-				}
-				return path
-			},
+			name:      "JSON above cap is truncated as plain text",
+			path:      write("large.json", `{"key":"`+strings.Repeat("c", 2*limit)+`"}`),
+			truncated: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tmpDir := t.TempDir()
-			path := tt.setup(t, tmpDir)
+			data, total, err := readDumpFile(tt.path, limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if int64(len(data)) > limit {
+				t.Fatalf("read %d bytes, want at most %d", len(data), limit)
+			}
+			info, err := os.Stat(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != info.Size() {
+				t.Errorf("total = %d, want %d", total, info.Size())
+			}
 
-			// DumpFile should not panic for any input
-			DumpFile(context.Background(), path)
+			out := string(formatDump(tt.path, data, total))
+			if got := strings.Contains(out, marker); got != tt.truncated {
+				t.Errorf("truncation marker present = %v, want %v:\n%s", got, tt.truncated, out)
+			}
+			if tt.want != "" && out != tt.want {
+				t.Errorf("output = %q, want %q", out, tt.want)
+			}
+			if tt.truncated && !strings.HasPrefix(out, string(data)) {
+				t.Errorf("truncated output does not start with the raw file content:\n%s", out)
+			}
 		})
 	}
+
+	// No limit reads the whole file
+	data, total, err := readDumpFile(filepath.Join(dir, "large.txt"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 3*limit || total != 3*limit {
+		t.Errorf("unlimited read = %d bytes of %d, want %d", len(data), total, 3*limit)
+	}
+}
+
+// captureLogs returns a context whose debug logs are written to the
+// returned buffer.
+func captureLogs(t testing.TB) (context.Context, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	l := logrus.New()
+	l.SetOutput(&buf)
+	l.SetLevel(logrus.DebugLevel)
+	return log.WithLogger(context.Background(), logrus.NewEntry(l)), &buf
 }
 
 func TestDumpInfo(t *testing.T) {
-	// DumpInfo walks the filesystem and calls various system commands.
-	// Skip by default as it walks "/" which is very slow (~14s).
-	// Covers: /proc/cmdline access, /sbin/crun --version, filesystem traversal.
-	t.Skip("skipping DumpInfo test (walks entire filesystem, ~14s)")
+	dir := t.TempDir()
+	cmdline := filepath.Join(dir, "cmdline")
+	if err := os.WriteFile(cmdline, []byte("console=hvc0 spin.debug=1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	large := filepath.Join(dir, "large")
+	if err := os.WriteFile(large, []byte(strings.Repeat("x", 2*maxInfoFileSize)+"TAIL"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(dir, "sub")
+	if err := os.MkdirAll(filepath.Join(sub, "nested"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "nested", "deep"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, buf := captureLogs(t)
+	dumpInfo(ctx,
+		[]string{cmdline, large, filepath.Join(dir, "missing")},
+		[]string{sub, filepath.Join(dir, "missing-dir")},
+	)
+	out := buf.String()
 
-	DumpInfo(context.Background())
+	for _, want := range []string{
+		"console=hvc0 spin.debug=1",
+		filepath.Join(sub, "nested"),
+		"failed to read file",
+		"failed to read directory",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "TAIL") {
+		t.Error("file content beyond maxInfoFileSize was logged")
+	}
+	if strings.Contains(out, "deep") {
+		t.Error("directories must only be listed one level deep")
+	}
+}
+
+func TestDumpInfoTargets(t *testing.T) {
+	for _, p := range append(append([]string{}, infoFiles...), infoDirs...) {
+		if !filepath.IsAbs(p) {
+			t.Errorf("info target %q is not absolute", p)
+		}
+	}
+	if !slices.Contains(infoFiles, "/proc/cmdline") {
+		t.Error("DumpInfo must log the kernel command line")
+	}
+}
+
+func BenchmarkDumpInfo(b *testing.B) {
+	ctx, buf := captureLogs(b)
+
+	var slowest time.Duration
+	for b.Loop() {
+		buf.Reset()
+		start := time.Now()
+		DumpInfo(ctx)
+		slowest = max(slowest, time.Since(start))
+	}
+	if slowest > 100*time.Millisecond {
+		b.Errorf("DumpInfo took %s, want under 100ms", slowest)
+	}
 }
 
 // Benchmark DumpFile performance
@@ -4,6 +4,7 @@ package task
 
 import (
 	"context"
+	"time"
 
 	eventstypes "github.com/containerd/containerd/api/events"
 	"github.com/containerd/containerd/v2/core/events"
@@ -12,6 +13,7 @@ import (
 	"github.com/containerd/containerd/v2/pkg/protobuf"
 	runcC "github.com/containerd/go-runc"
 	"github.com/containerd/log"
+	"golang.org/x/sys/unix"
 
 	"github.com/spin-stack/spinbox/internal/guest/vminit/process"
 	"github.com/spin-stack/spinbox/internal/guest/vminit/runc"
@@ -29,10 +31,14 @@ import (
 // process has been published. Note that s.lifecycleMu must not be held when
 // calling handleStarted.
 //
+// The returned bind closure ties the subscription to a container that was not
+// passed as c, so deleting that container cancels it. It must be called as
+// soon as the container is known when c is nil.
+//
 // The returned cleanup closure releases resources used to handle early exits.
 // It must be called before the caller of preStart returns, otherwise severe
 // memory leaks will occur.
-func (s *service) preStart(c *runc.Container) (func(*runc.Container, process.Process), func()) {
+func (s *service) preStart(c *runc.Container) (func(*runc.Container, process.Process), func(*runc.Container), func()) {
 	sub := s.exitTracker.Subscribe(c)
 
 	handleStarted := func(c *runc.Container, p process.Process) {
@@ -54,17 +60,17 @@ func (s *service) preStart(c *runc.Container) (func(*runc.Container, process.Pro
 		sub.Cancel()
 	}
 
-	return handleStarted, cleanup
+	return handleStarted, sub.Bind, cleanup
 }
 
 func (s *service) processExits() {
 	for e := range s.ec {
 		// While unlikely, it is not impossible for a container process to exit
 		// and have its PID be recycled for a new container process before we
-		// have a chance to process the first exit. As we have no way to tell
-		// for sure which of the processes the exit event corresponds to (until
-		// pidfd support is implemented) there is no way for us to handle the
-		// exit correctly in that case.
+		// have a chance to process the first exit. The exit tracker compares
+		// the reaper timestamp against each process's start timestamp to drop
+		// exits that predate the new process; exits without a timestamp are
+		// still matched by PID alone (until pidfd support is implemented).
 
 		// Notify exit tracker and get container processes that exited
 		cps := s.exitTracker.NotifyExit(e)
@@ -89,34 +95,77 @@ func (s *service) send(evt interface{}) {
 // - for a given container, the init process exit MUST be the last exit published
 // This is achieved by:
 // - killing all running container processes (if the container has a shared pid
-// namespace, otherwise all other processes have been reaped already).
+// namespace, otherwise all other processes have been reaped already), as set
+// by the container's kill annotations (runc.KillPolicy).
 // - waiting for the container's running exec counter to reach 0.
 // - finally, publishing the init exit.
 func (s *service) handleInitExit(e runcC.Exit, c *runc.Container, p *process.Init) {
 	// kill all running container processes
 	if runc.ShouldKillAllOnExit(s.context, c.Bundle) {
-		if err := p.KillAll(s.context); err != nil {
-			log.G(s.context).WithError(err).WithField("id", p.ID()).
-				Error("failed to kill init's children")
-		}
+		s.killRemaining(c, p, runc.KillPolicy(s.context, c.Bundle))
 	}
 
 	// Check if we need to delay init exit until all execs complete
-	shouldDelay, waitChan := s.exitTracker.ShouldDelayInitExit(c)
+	shouldDelay, wait := s.exitTracker.ShouldDelayInitExit(c, runc.InitExitMaxDelay(s.context, c.Bundle))
 	if !shouldDelay {
 		// No execs running, publish immediately
 		s.handleProcessExit(e, c, p)
 		return
 	}
 
-	// Execs still running - wait for them to complete
+	// Execs still running - wait for them to complete, or until the
+	// container's opt-in deadline so a wedged exec cannot hang the task.
 	go func() {
-		<-waitChan
-		// All running execs have exited now, publish the init exit
+		if wait.Deadline.IsZero() {
+			<-wait.Done
+		} else {
+			timer := time.NewTimer(time.Until(wait.Deadline))
+			defer timer.Stop()
+			select {
+			case <-wait.Done:
+			case <-timer.C:
+				s.exitTracker.ForceReleaseInitExit(c)
+			}
+		}
+		// All running execs have exited (or were abandoned), publish the init exit
 		s.handleProcessExit(e, c, p)
 	}()
 }
 
+// killRemaining sends policy.Signal to the processes left behind by init and
+// SIGKILL once the grace period has passed. The grace period runs in the
+// background so exits keep being processed; execs that exit within it are
+// published as usual.
+func (s *service) killRemaining(c *runc.Container, p *process.Init, policy runc.ExitKillPolicy) {
+	logger := log.G(s.context).WithField("id", p.ID())
+	if err := p.KillAll(s.context, policy.Signal); err != nil {
+		logger.WithError(err).WithField("signal", policy.Signal).Error("failed to kill init's children")
+	}
+	if policy.Signal == unix.SIGKILL {
+		return
+	}
+
+	killAll := func() {
+		// KillAll targets the container ID. The container may have been
+		// deleted and its ID reused once the grace period ends, so only kill
+		// while c is still the registered container. Holding the read lock
+		// keeps a new container from being registered under the ID meanwhile.
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		if s.containers[c.ID] != c {
+			return
+		}
+		if err := p.KillAll(s.context, unix.SIGKILL); err != nil {
+			logger.WithError(err).Debug("failed to kill init's children after grace period")
+		}
+	}
+	if policy.Grace <= 0 {
+		killAll()
+		return
+	}
+	time.AfterFunc(policy.Grace, killAll)
+}
+
 func (s *service) handleProcessExit(e runcC.Exit, c *runc.Container, p process.Process) {
 	p.SetExited(e.Status)
 
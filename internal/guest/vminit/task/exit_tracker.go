@@ -3,10 +3,13 @@
 package task
 
 import (
+	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	runcC "github.com/containerd/go-runc"
+	"github.com/containerd/log"
 
 	"github.com/spin-stack/spinbox/internal/guest/vminit/process"
 	"github.com/spin-stack/spinbox/internal/guest/vminit/runc"
@@ -52,6 +55,15 @@ import (
 //
 // Operations naturally sequence: detect exit → remove process → stash if init.
 // Each step is independent and doesn't need to see a consistent view of the others.
+//
+// # PID Reuse
+//
+// Every started process is assigned a start timestamp taken when its
+// subscription was created (which is guaranteed to precede the process
+// start). An exit whose reaper timestamp predates a process's start
+// timestamp cannot belong to that process, so it is treated as a stale exit
+// from a previous holder of the PID and is not delivered to it. Exits
+// without a timestamp are matched by PID alone.
 type exitTracker struct {
 	detector    *earlyExitDetector
 	coordinator *exitCoordinator
@@ -66,17 +78,28 @@ func newExitTracker() *exitTracker {
 	}
 }
 
+// initExitWait describes how a delayed init exit should wait for execs.
+type initExitWait struct {
+	// Done is closed when the last running exec exits.
+	Done <-chan struct{}
+	// Deadline is when the caller should give up and call
+	// ForceReleaseInitExit. Zero means no deadline.
+	Deadline time.Time
+}
+
 // Subscribe registers interest in process exits that occur before Start completes.
 // Returns a subscription that must be completed via HandleStart or cancelled via Cancel.
 //
 // If restarting an existing container (c != nil), removes its init process from
-// the running map so early exits during restart are properly detected.
+// the running map so early exits during restart are properly detected. The
+// subscription is also bound to c so Cleanup can cancel it if the container is
+// deleted before the start completes.
 func (t *exitTracker) Subscribe(c *runc.Container) *subscription {
-	sub := t.detector.subscribe()
+	sub := t.detector.subscribe(c)
 
 	// When restarting, remove the old init process from running map
 	if c != nil {
-		t.processes.removeByContainer(c.Pid(), c)
+		t.processes.removeInit(c)
 	}
 
 	return &subscription{
@@ -87,12 +110,13 @@ func (t *exitTracker) Subscribe(c *runc.Container) *subscription {
 
 // NotifyExit handles a process exit event.
 // Returns the container processes that exited (may be >1 due to PID reuse).
+// Processes that started after the exit was reaped are left running.
 func (t *exitTracker) NotifyExit(e runcC.Exit) []containerProcess {
 	// Notify early exit detector (broadcasts to all active subscriptions)
 	t.detector.notifyExit(e)
 
 	// Find and remove running processes with this PID
-	cps := t.processes.removeByPID(e.Pid)
+	cps := t.processes.removeByExit(e)
 
 	// Stash init exits for later (need to wait for execs to complete)
 	for _, cp := range cps {
@@ -107,11 +131,40 @@ func (t *exitTracker) NotifyExit(e runcC.Exit) []containerProcess {
 // ShouldDelayInitExit checks if an init process exit should be delayed
 // until all exec processes exit.
 //
+// maxDelay bounds the wait; zero means wait until the execs exit, however long
+// that takes.
+//
 // Returns:
-//   - (false, nil): No delay needed, safe to publish init exit immediately
-//   - (true, chan): Delay needed, wait on channel for signal when execs complete
-func (t *exitTracker) ShouldDelayInitExit(c *runc.Container) (bool, <-chan struct{}) {
-	return t.coordinator.shouldDelayInitExit(c)
+//   - (false, zero): No delay needed, safe to publish init exit immediately
+//   - (true, wait): Delay needed, wait on wait.Done for signal when execs
+//     complete, or until wait.Deadline if it is set
+func (t *exitTracker) ShouldDelayInitExit(c *runc.Container, maxDelay time.Duration) (bool, initExitWait) {
+	delay, ch := t.coordinator.shouldDelayInitExit(c)
+	if !delay {
+		return false, initExitWait{}
+	}
+	wait := initExitWait{Done: ch}
+	if maxDelay > 0 {
+		wait.Deadline = time.Now().Add(maxDelay)
+	}
+	return true, wait
+}
+
+// ForceReleaseInitExit abandons the running execs of a container so that a
+// delayed init exit can be published. It clears the exec counter, closes any
+// waiter and returns how many execs were abandoned.
+//
+// This trades exit ordering for liveness: exits of abandoned execs that arrive
+// later may be published after the init exit.
+func (t *exitTracker) ForceReleaseInitExit(c *runc.Container) int {
+	abandoned := t.coordinator.forceRelease(c)
+	if abandoned > 0 {
+		log.L.WithFields(log.Fields{
+			"id":        c.ID,
+			"abandoned": abandoned,
+		}).Warn("releasing init exit with execs still running")
+	}
+	return abandoned
 }
 
 // NotifyExecExit decrements the exec counter for a container.
@@ -137,9 +190,44 @@ func (t *exitTracker) DecrementExecCount(c *runc.Container) {
 	t.coordinator.notifyExecExit(c)
 }
 
-// Cleanup removes all tracking state for a container.
+// RunningPids returns the PIDs of all tracked running processes that belong
+// to the container, in ascending order.
+func (t *exitTracker) RunningPids(c *runc.Container) []int {
+	return t.processes.pidsForContainer(c)
+}
+
+// exitTrackerSnapshot is a point-in-time copy of the tracker's state, used to
+// diagnose containers whose exits are never published.
+type exitTrackerSnapshot struct {
+	// ActiveSubscriptions is the number of starts still waiting on HandleStart or Cancel.
+	ActiveSubscriptions int
+	// RunningPIDs is the number of distinct PIDs with at least one running process.
+	RunningPIDs int
+	// RunningExecs maps container ID to its count of running exec processes.
+	RunningExecs map[string]int
+	// StashedInitExits is the number of init exits waiting to be published.
+	StashedInitExits int
+	// PendingExecWaiters is the number of init exits blocked on running execs.
+	PendingExecWaiters int
+}
+
+// Snapshot returns a copy of the tracker's current state for diagnostics.
+// Each sub-component is read under its own mutex, so counts from different
+// components may reflect slightly different instants.
+func (t *exitTracker) Snapshot() exitTrackerSnapshot {
+	snap := exitTrackerSnapshot{
+		ActiveSubscriptions: t.detector.count(),
+		RunningPIDs:         t.processes.count(),
+	}
+	snap.RunningExecs, snap.StashedInitExits, snap.PendingExecWaiters = t.coordinator.snapshot()
+	return snap
+}
+
+// Cleanup removes all tracking state for a container, including subscriptions
+// bound to it that were never completed or cancelled.
 // Should be called when a container is deleted.
 func (t *exitTracker) Cleanup(c *runc.Container) {
+	t.detector.cancelContainer(c)
 	t.coordinator.cleanup(c)
 	t.processes.cleanupContainer(c)
 }
@@ -170,6 +258,7 @@ func (s *subscription) HandleStart(c *runc.Container, p process.Process, pid int
 	s.tracker.processes.add(pid, containerProcess{
 		Container: c,
 		Process:   p,
+		StartedAt: s.sub.startedAt,
 	})
 
 	// Track exec processes for init exit ordering
@@ -180,6 +269,14 @@ func (s *subscription) HandleStart(c *runc.Container, p process.Process, pid int
 	return nil
 }
 
+// Bind records c as the container the subscription belongs to, so Cleanup
+// cancels it if c is deleted before the start completes. Used when c was not
+// known at Subscribe time (creating a container) or must not be passed there
+// (starting an exec, which is not a restart).
+func (s *subscription) Bind(c *runc.Container) {
+	s.sub.bind(c)
+}
+
 // Cancel cancels the subscription without completing a start.
 // Must be called if HandleStart is not called, to prevent memory leaks.
 func (s *subscription) Cancel() {
@@ -201,15 +298,18 @@ func newEarlyExitDetector() *earlyExitDetector {
 }
 
 // subscribe creates a new subscription that will collect exit events.
-func (d *earlyExitDetector) subscribe() *earlyExitSubscription {
+// c is the container being started, or nil if not yet known.
+func (d *earlyExitDetector) subscribe(c *runc.Container) *earlyExitSubscription {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
 	subID := atomic.AddUint64(&d.nextSubID, 1)
 	sub := &earlyExitSubscription{
-		id:       subID,
-		detector: d,
-		exits:    make(map[int][]runcC.Exit),
+		id:        subID,
+		detector:  d,
+		container: c,
+		startedAt: time.Now(),
+		exits:     make(map[int][]runcC.Exit),
 	}
 	d.subscriptions[subID] = sub
 
@@ -217,15 +317,38 @@ func (d *earlyExitDetector) subscribe() *earlyExitSubscription {
 }
 
 // notifyExit broadcasts an exit event to all active subscriptions.
+// Subscriptions created after the exit was reaped do not receive it.
 func (d *earlyExitDetector) notifyExit(e runcC.Exit) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
 	for _, sub := range d.subscriptions {
+		if exitPredates(e, sub.startedAt) {
+			continue
+		}
 		sub.exits[e.Pid] = append(sub.exits[e.Pid], e)
 	}
 }
 
+// count returns the number of active subscriptions.
+func (d *earlyExitDetector) count() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.subscriptions)
+}
+
+// cancelContainer removes all subscriptions bound to the container.
+func (d *earlyExitDetector) cancelContainer(c *runc.Container) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for id, sub := range d.subscriptions {
+		if sub.container == c {
+			delete(d.subscriptions, id)
+		}
+	}
+}
+
 // remove removes a subscription from the detector.
 func (d *earlyExitDetector) remove(id uint64) {
 	d.mu.Lock()
@@ -235,9 +358,18 @@ func (d *earlyExitDetector) remove(id uint64) {
 
 // earlyExitSubscription collects exit events during the start window.
 type earlyExitSubscription struct {
-	id       uint64
-	detector *earlyExitDetector
-	exits    map[int][]runcC.Exit // PID -> exits collected during start window
+	id        uint64
+	detector  *earlyExitDetector
+	container *runc.Container      // container being started, nil if unknown
+	startedAt time.Time            // taken before the process is started
+	exits     map[int][]runcC.Exit // PID -> exits collected during start window
+}
+
+// bind sets the subscription's container.
+func (s *earlyExitSubscription) bind(c *runc.Container) {
+	s.detector.mu.Lock()
+	defer s.detector.mu.Unlock()
+	s.container = c
 }
 
 // complete finishes the subscription and returns any early exits for the given PID.
@@ -335,7 +467,10 @@ func (c *exitCoordinator) notifyExecExit(container *runc.Container) {
 		return
 	}
 
-	state.runningExecs--
+	// The counter may already be zero if the execs were force released.
+	if state.runningExecs > 0 {
+		state.runningExecs--
+	}
 	if state.runningExecs > 0 {
 		return
 	}
@@ -347,6 +482,26 @@ func (c *exitCoordinator) notifyExecExit(container *runc.Container) {
 	}
 }
 
+// forceRelease clears the exec counter and signals any waiter.
+// Returns the number of execs that were still counted as running.
+func (c *exitCoordinator) forceRelease(container *runc.Container) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state := c.containers[container]
+	if state == nil {
+		return 0
+	}
+
+	abandoned := state.runningExecs
+	state.runningExecs = 0
+	if state.execWaiter != nil {
+		close(state.execWaiter)
+		state.execWaiter = nil
+	}
+	return abandoned
+}
+
 // getInitExit returns and clears the stashed init exit.
 func (c *exitCoordinator) getInitExit(container *runc.Container) (runcC.Exit, bool) {
 	c.mu.Lock()
@@ -371,6 +526,27 @@ func (c *exitCoordinator) initHasExited(container *runc.Container) bool {
 	return state != nil && state.initExit != nil
 }
 
+// snapshot returns per-container running exec counts along with the number of
+// stashed init exits and pending exec waiters.
+func (c *exitCoordinator) snapshot() (runningExecs map[string]int, initExits, waiters int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	runningExecs = make(map[string]int, len(c.containers))
+	for container, state := range c.containers {
+		if state.runningExecs > 0 {
+			runningExecs[container.ID] = state.runningExecs
+		}
+		if state.initExit != nil {
+			initExits++
+		}
+		if state.execWaiter != nil {
+			waiters++
+		}
+	}
+	return runningExecs, initExits, waiters
+}
+
 // cleanup removes all state for a container.
 func (c *exitCoordinator) cleanup(container *runc.Container) {
 	c.mu.Lock()
@@ -379,6 +555,12 @@ func (c *exitCoordinator) cleanup(container *runc.Container) {
 	delete(c.containers, container)
 }
 
+// exitPredates reports whether e was reaped before startedAt, meaning it
+// belongs to an earlier process that held the same PID.
+func exitPredates(e runcC.Exit, startedAt time.Time) bool {
+	return !e.Timestamp.IsZero() && !startedAt.IsZero() && e.Timestamp.Before(startedAt)
+}
+
 // processRegistry tracks running processes by PID.
 // Multiple processes may share a PID due to PID reuse.
 type processRegistry struct {
@@ -400,39 +582,78 @@ func (r *processRegistry) add(pid int, cp containerProcess) {
 	r.running[pid] = append(r.running[pid], cp)
 }
 
-// removeByPID removes and returns all processes with the given PID.
-func (r *processRegistry) removeByPID(pid int) []containerProcess {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	cps := r.running[pid]
-	delete(r.running, pid)
-	return cps
-}
-
-// removeByContainer removes processes for a specific container from a PID.
-func (r *processRegistry) removeByContainer(pid int, c *runc.Container) {
+// removeByExit removes and returns the processes with the exit's PID that
+// could have produced it. Processes started after the exit was reaped are kept.
+func (r *processRegistry) removeByExit(e runcC.Exit) []containerProcess {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	cps := r.running[pid]
+	cps := r.running[e.Pid]
 	if len(cps) == 0 {
-		return
+		return nil
 	}
 
-	// Filter out processes belonging to this container
-	remaining := cps[:0]
+	var exited, remaining []containerProcess
 	for _, cp := range cps {
-		if cp.Container != c {
+		if exitPredates(e, cp.StartedAt) {
 			remaining = append(remaining, cp)
+		} else {
+			exited = append(exited, cp)
 		}
 	}
 
 	if len(remaining) > 0 {
-		r.running[pid] = remaining
+		r.running[e.Pid] = remaining
 	} else {
-		delete(r.running, pid)
+		delete(r.running, e.Pid)
+	}
+	return exited
+}
+
+// removeInit removes the init process of a container.
+func (r *processRegistry) removeInit(c *runc.Container) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for pid, cps := range r.running {
+		remaining := cps[:0]
+		for _, cp := range cps {
+			if cp.Container != c || !cp.Process.IsInit() {
+				remaining = append(remaining, cp)
+			}
+		}
+
+		if len(remaining) > 0 {
+			r.running[pid] = remaining
+		} else {
+			delete(r.running, pid)
+		}
+	}
+}
+
+// count returns the number of PIDs with running processes.
+func (r *processRegistry) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.running)
+}
+
+// pidsForContainer returns the sorted PIDs of running processes for a container.
+func (r *processRegistry) pidsForContainer(c *runc.Container) []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var pids []int
+	for pid, cps := range r.running {
+		for _, cp := range cps {
+			if cp.Container == c {
+				pids = append(pids, pid)
+				break
+			}
+		}
 	}
+	slices.Sort(pids)
+	return pids
 }
 
 // cleanupContainer removes all processes for a container.
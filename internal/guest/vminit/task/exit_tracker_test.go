@@ -4,6 +4,7 @@ package task
 
 import (
 	"testing"
+	"time"
 
 	runcC "github.com/containerd/go-runc"
 
@@ -78,15 +79,16 @@ func TestExitTracker_InitExitDelayed(t *testing.T) {
 	sub2.HandleStart(container, execProc, 1235)
 
 	// Try to handle init exit - should be delayed because exec is still running
-	shouldDelay, waitChan := tracker.ShouldDelayInitExit(container)
+	shouldDelay, wait := tracker.ShouldDelayInitExit(container, 0)
 
 	if !shouldDelay {
 		t.Error("Expected init exit to be delayed")
 	}
 
-	if waitChan == nil {
+	if wait.Done == nil {
 		t.Fatal("Expected non-nil wait channel")
 	}
+	waitChan := wait.Done
 
 	// Verify channel is not closed yet
 	select {
@@ -113,13 +115,13 @@ func TestExitTracker_InitExitNotDelayed(t *testing.T) {
 	container := testutil.MockContainer("test-container")
 
 	// No execs running - init exit should not be delayed
-	shouldDelay, waitChan := tracker.ShouldDelayInitExit(container)
+	shouldDelay, wait := tracker.ShouldDelayInitExit(container, 0)
 
 	if shouldDelay {
 		t.Error("Expected init exit not to be delayed when no execs running")
 	}
 
-	if waitChan != nil {
+	if wait.Done != nil {
 		t.Error("Expected nil wait channel when not delayed")
 	}
 }
@@ -205,7 +207,7 @@ func TestExitTracker_Cleanup(t *testing.T) {
 	}
 
 	// Verify container state is cleaned up by checking ShouldDelayInitExit returns false
-	shouldDelay, _ := tracker.ShouldDelayInitExit(container)
+	shouldDelay, _ := tracker.ShouldDelayInitExit(container, 0)
 	if shouldDelay {
 		t.Error("Cleanup did not remove container state")
 	}
@@ -234,6 +236,61 @@ func TestExitTracker_PIDReuse(t *testing.T) {
 	}
 }
 
+func TestExitTracker_PIDReuse_StaleExitDropped(t *testing.T) {
+	tracker := newExitTracker()
+	container1 := testutil.MockContainer("container-1")
+	container2 := testutil.MockContainer("container-2")
+	proc1 := &testutil.MockProcess{IDValue: "proc1", PIDValue: 1234}
+	proc2 := &testutil.MockProcess{IDValue: "proc2", PIDValue: 1234}
+
+	sub1 := tracker.Subscribe(nil)
+	sub1.HandleStart(container1, proc1, 1234)
+
+	// proc1 exits, but the exit is not processed until after the PID has
+	// been recycled for proc2.
+	staleExit := runcC.Exit{Pid: 1234, Status: 1, Timestamp: time.Now()}
+
+	sub2 := tracker.Subscribe(nil)
+	sub2.HandleStart(container2, proc2, 1234)
+
+	exited := tracker.NotifyExit(staleExit)
+	if len(exited) != 1 {
+		t.Fatalf("Expected 1 exited process for stale exit, got %d", len(exited))
+	}
+	if exited[0].Process.ID() != "proc1" {
+		t.Errorf("Stale exit delivered to %q, want proc1", exited[0].Process.ID())
+	}
+
+	exited = tracker.NotifyExit(runcC.Exit{Pid: 1234, Status: 2, Timestamp: time.Now()})
+	if len(exited) != 1 {
+		t.Fatalf("Expected 1 exited process for current exit, got %d", len(exited))
+	}
+	if exited[0].Process.ID() != "proc2" {
+		t.Errorf("Current exit delivered to %q, want proc2", exited[0].Process.ID())
+	}
+}
+
+func TestExitTracker_PIDReuse_StaleExitNotEarlyExit(t *testing.T) {
+	tracker := newExitTracker()
+	container := testutil.MockContainer("test-container")
+	proc := &testutil.MockProcess{IDValue: "exec1", PIDValue: 1234}
+
+	// Exit from a previous holder of the PID, reaped before the new start.
+	staleExit := runcC.Exit{Pid: 1234, Status: 137, Timestamp: time.Now()}
+
+	sub := tracker.Subscribe(nil)
+	tracker.NotifyExit(staleExit)
+
+	if earlyExits := sub.HandleStart(container, proc, 1234); len(earlyExits) != 0 {
+		t.Fatalf("Stale exit reported as early exit: %v", earlyExits)
+	}
+
+	exited := tracker.NotifyExit(runcC.Exit{Pid: 1234, Status: 0, Timestamp: time.Now()})
+	if len(exited) != 1 {
+		t.Fatalf("Expected new process to be tracked, got %d exits", len(exited))
+	}
+}
+
 func TestExitTracker_InitHasExited(t *testing.T) {
 	tracker := newExitTracker()
 	container := testutil.MockContainer("test-container")
@@ -267,10 +324,11 @@ func TestExitTracker_DecrementExecCount(t *testing.T) {
 	sub.HandleStart(container, execProc, 1235)
 
 	// Check that init exit would be delayed (meaning exec count > 0)
-	shouldDelay, waitChan := tracker.ShouldDelayInitExit(container)
+	shouldDelay, wait := tracker.ShouldDelayInitExit(container, 0)
 	if !shouldDelay {
 		t.Fatal("Expected init exit to be delayed with exec running")
 	}
+	waitChan := wait.Done
 
 	// Manually decrement (simulating error path)
 	tracker.DecrementExecCount(container)
@@ -284,7 +342,7 @@ func TestExitTracker_DecrementExecCount(t *testing.T) {
 	}
 
 	// Now init exit should not be delayed
-	shouldDelayAfter, _ := tracker.ShouldDelayInitExit(container)
+	shouldDelayAfter, _ := tracker.ShouldDelayInitExit(container, 0)
 	if shouldDelayAfter {
 		t.Error("Expected init exit not to be delayed after decrement")
 	}
@@ -324,3 +382,215 @@ func TestExitTracker_GetInitExit(t *testing.T) {
 		t.Error("GetInitExit should clear the exit after first retrieval")
 	}
 }
+
+func TestExitTracker_ForceReleaseInitExit(t *testing.T) {
+	tracker := newExitTracker()
+	container := testutil.MockContainer("test-container")
+	initProc := &process.Init{}
+
+	sub := tracker.Subscribe(nil)
+	sub.HandleStart(container, initProc, 1234)
+
+	// Two execs start; one of them wedges and never calls NotifyExecExit.
+	for _, exec := range []*testutil.MockProcess{
+		{IDValue: "exec1", PIDValue: 1235},
+		{IDValue: "exec2", PIDValue: 1236},
+	} {
+		sub := tracker.Subscribe(nil)
+		sub.HandleStart(container, exec, exec.PIDValue)
+	}
+	tracker.NotifyExecExit(container)
+
+	shouldDelay, wait := tracker.ShouldDelayInitExit(container, 0)
+	if !shouldDelay {
+		t.Fatal("Expected init exit to be delayed with exec running")
+	}
+	if !wait.Deadline.IsZero() {
+		t.Errorf("Expected no deadline without a max delay, got %v", wait.Deadline)
+	}
+
+	select {
+	case <-wait.Done:
+		t.Fatal("Wait channel should not be closed while exec is running")
+	default:
+	}
+
+	if abandoned := tracker.ForceReleaseInitExit(container); abandoned != 1 {
+		t.Errorf("Expected 1 abandoned exec, got %d", abandoned)
+	}
+
+	select {
+	case <-wait.Done:
+	default:
+		t.Fatal("Wait channel should be closed after forced release")
+	}
+
+	// A late exit from the abandoned exec must not underflow the counter.
+	tracker.NotifyExecExit(container)
+	if delay, _ := tracker.ShouldDelayInitExit(container, 0); delay {
+		t.Error("Expected no delay after forced release and late exec exit")
+	}
+	sub = tracker.Subscribe(nil)
+	sub.HandleStart(container, &testutil.MockProcess{IDValue: "exec3", PIDValue: 1237}, 1237)
+	if delay, _ := tracker.ShouldDelayInitExit(container, 0); !delay {
+		t.Error("Expected new exec to be counted after forced release")
+	}
+}
+
+func TestExitTracker_ShouldDelayInitExit_Deadline(t *testing.T) {
+	tracker := newExitTracker()
+	container := testutil.MockContainer("test-container")
+
+	sub := tracker.Subscribe(nil)
+	sub.HandleStart(container, &testutil.MockProcess{IDValue: "exec1", PIDValue: 1235}, 1235)
+
+	shouldDelay, wait := tracker.ShouldDelayInitExit(container, 20*time.Millisecond)
+	if !shouldDelay {
+		t.Fatal("Expected init exit to be delayed with exec running")
+	}
+
+	// Simulate the caller: the exec never exits, so the timer must fire.
+	timer := time.NewTimer(time.Until(wait.Deadline))
+	defer timer.Stop()
+	select {
+	case <-wait.Done:
+		t.Fatal("Wait channel closed without exec exit")
+	case <-timer.C:
+		tracker.ForceReleaseInitExit(container)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Deadline did not fire")
+	}
+
+	select {
+	case <-wait.Done:
+	default:
+		t.Fatal("Wait channel should be closed after forced release")
+	}
+}
+
+func TestExitTracker_ShouldDelayInitExit_NoDeadline(t *testing.T) {
+	tracker := newExitTracker()
+	container := testutil.MockContainer("test-container")
+
+	sub := tracker.Subscribe(nil)
+	sub.HandleStart(container, &testutil.MockProcess{IDValue: "exec1", PIDValue: 1235}, 1235)
+
+	_, wait := tracker.ShouldDelayInitExit(container, 0)
+	if !wait.Deadline.IsZero() {
+		t.Errorf("Expected zero deadline when max delay is disabled, got %v", wait.Deadline)
+	}
+}
+
+func TestExitTracker_Snapshot(t *testing.T) {
+	tracker := newExitTracker()
+	c1 := testutil.MockContainer("container-1")
+	c2 := testutil.MockContainer("container-2")
+
+	// c1: init plus two execs; init exits and waits on the execs.
+	sub := tracker.Subscribe(nil)
+	sub.HandleStart(c1, &process.Init{}, 100)
+	for _, pid := range []int{101, 102} {
+		sub := tracker.Subscribe(nil)
+		sub.HandleStart(c1, &testutil.MockProcess{IDValue: "exec", PIDValue: pid}, pid)
+	}
+	tracker.NotifyExit(runcC.Exit{Pid: 100})
+	if delay, _ := tracker.ShouldDelayInitExit(c1, 0); !delay {
+		t.Fatal("Expected init exit to be delayed")
+	}
+
+	// c2: one exec running.
+	sub = tracker.Subscribe(nil)
+	sub.HandleStart(c2, &testutil.MockProcess{IDValue: "exec", PIDValue: 200}, 200)
+
+	// Two starts in flight.
+	pending1 := tracker.Subscribe(nil)
+	pending2 := tracker.Subscribe(nil)
+	defer pending1.Cancel()
+	defer pending2.Cancel()
+
+	snap := tracker.Snapshot()
+
+	if snap.ActiveSubscriptions != 2 {
+		t.Errorf("ActiveSubscriptions = %d, want 2", snap.ActiveSubscriptions)
+	}
+	if snap.RunningPIDs != 3 {
+		t.Errorf("RunningPIDs = %d, want 3", snap.RunningPIDs)
+	}
+	if got := snap.RunningExecs["container-1"]; got != 2 {
+		t.Errorf("RunningExecs[container-1] = %d, want 2", got)
+	}
+	if got := snap.RunningExecs["container-2"]; got != 1 {
+		t.Errorf("RunningExecs[container-2] = %d, want 1", got)
+	}
+	if snap.StashedInitExits != 1 {
+		t.Errorf("StashedInitExits = %d, want 1", snap.StashedInitExits)
+	}
+	if snap.PendingExecWaiters != 1 {
+		t.Errorf("PendingExecWaiters = %d, want 1", snap.PendingExecWaiters)
+	}
+
+	// The snapshot is a copy; mutating it must not affect the tracker.
+	snap.RunningExecs["container-1"] = 99
+	if got := tracker.Snapshot().RunningExecs["container-1"]; got != 2 {
+		t.Errorf("tracker state modified through snapshot: got %d, want 2", got)
+	}
+}
+
+func TestExitTracker_CleanupCancelsSubscriptions(t *testing.T) {
+	tracker := newExitTracker()
+	container := testutil.MockContainer("test-container")
+	other := testutil.MockContainer("other-container")
+
+	// Start of container races with Delete: Cleanup runs before HandleStart.
+	tracker.Subscribe(container)
+	tracker.Subscribe(container)
+	otherSub := tracker.Subscribe(other)
+	defer otherSub.Cancel()
+
+	if got := tracker.Snapshot().ActiveSubscriptions; got != 3 {
+		t.Fatalf("ActiveSubscriptions = %d, want 3", got)
+	}
+
+	tracker.Cleanup(container)
+
+	if got := tracker.Snapshot().ActiveSubscriptions; got != 1 {
+		t.Errorf("ActiveSubscriptions after Cleanup = %d, want 1 (other container)", got)
+	}
+}
+
+func TestExitTracker_CleanupCancelsBoundSubscriptions(t *testing.T) {
+	tracker := newExitTracker()
+	container := testutil.MockContainer("test-container")
+
+	// Create and exec starts subscribe before the container is known to
+	// the subscription and bind it afterwards.
+	sub := tracker.Subscribe(nil)
+	sub.Bind(container)
+
+	tracker.Cleanup(container)
+
+	if got := tracker.Snapshot().ActiveSubscriptions; got != 0 {
+		t.Errorf("ActiveSubscriptions after Cleanup = %d, want 0", got)
+	}
+}
+
+func TestExitTracker_SubscribeRestartRemovesInit(t *testing.T) {
+	tracker := newExitTracker()
+	container := testutil.MockContainer("test-container")
+
+	sub := tracker.Subscribe(nil)
+	sub.HandleStart(container, &process.Init{}, 1234)
+	sub = tracker.Subscribe(nil)
+	sub.HandleStart(container, &testutil.MockProcess{IDValue: "exec1", PIDValue: 1235}, 1235)
+
+	// Restarting the container drops the old init but keeps its execs.
+	restart := tracker.Subscribe(container)
+	defer restart.Cancel()
+
+	if exited := tracker.NotifyExit(runcC.Exit{Pid: 1234}); len(exited) != 0 {
+		t.Errorf("Expected old init to be untracked after restart, got %d exits", len(exited))
+	}
+	if exited := tracker.NotifyExit(runcC.Exit{Pid: 1235}); len(exited) != 1 {
+		t.Errorf("Expected exec to remain tracked, got %d exits", len(exited))
+	}
+}
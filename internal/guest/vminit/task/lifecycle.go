@@ -4,7 +4,10 @@ package task
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"path/filepath"
+	"syscall"
 
 	eventstypes "github.com/containerd/containerd/api/events"
 	taskAPI "github.com/containerd/containerd/api/runtime/task/v3"
@@ -15,6 +18,7 @@ import (
 	"github.com/containerd/errdefs/pkg/errgrpc"
 	"github.com/containerd/log"
 	"github.com/containerd/typeurl/v2"
+	"golang.org/x/sys/unix"
 
 	"github.com/spin-stack/spinbox/internal/guest/vminit/runc"
 	"github.com/spin-stack/spinbox/internal/guest/vminit/systools"
@@ -37,7 +41,7 @@ func (s *service) Create(ctx context.Context, r *taskAPI.CreateTaskRequest) (*ta
 	s.mu.RUnlock()
 
 	// Subscribe for early exit detection before creating container
-	handleStarted, cleanup := s.preStart(nil)
+	handleStarted, bind, cleanup := s.preStart(nil)
 	defer cleanup()
 
 	systools.DumpFile(ctx, filepath.Join(r.Bundle, "config.json"))
@@ -48,6 +52,7 @@ func (s *service) Create(ctx context.Context, r *taskAPI.CreateTaskRequest) (*ta
 		return nil, errgrpc.ToGRPC(err)
 	}
 	log.G(ctx).WithField("container_id", container.ID).Info("created container")
+	bind(container)
 
 	// Store container with lock - handle race where another Create happened concurrently
 	s.mu.Lock()
@@ -106,8 +111,9 @@ func (s *service) Start(ctx context.Context, r *taskAPI.StartRequest) (*taskAPI.
 	} else if s.exitTracker.InitHasExited(container) {
 		return nil, errgrpc.ToGRPCf(errdefs.ErrFailedPrecondition, "container %s init process is not running", container.ID)
 	}
-	handleStarted, cleanup := s.preStart(cinit)
+	handleStarted, bind, cleanup := s.preStart(cinit)
 	defer cleanup()
+	bind(container)
 
 	log.G(ctx).WithFields(log.Fields{
 		"container_id": r.ID,
@@ -133,8 +139,13 @@ func (s *service) Start(ctx context.Context, r *taskAPI.StartRequest) (*taskAPI.
 	case "":
 		cg := container.Cgroup()
 		if cg != nil {
-			// Enable all available cgroup v2 controllers
-			_ = cg.EnableControllers(ctx)
+			// Enable all available cgroup v2 controllers; failures and
+			// controllers that could not be enabled are logged by the manager
+			enabled, _ := cg.EnableControllers(ctx)
+			log.G(ctx).WithFields(log.Fields{
+				"container_id": container.ID,
+				"controllers":  enabled,
+			}).Debug("effective cgroup controllers")
 		}
 
 		s.send(&eventstypes.TaskStart{
@@ -258,12 +269,69 @@ func (s *service) Kill(ctx context.Context, r *taskAPI.KillRequest) (*ptypes.Emp
 	if err != nil {
 		return nil, err
 	}
-	if err := container.Kill(ctx, r); err != nil {
+	err = container.Kill(ctx, r)
+	if err != nil && r.All && r.ExecID == "" && errdefs.IsNotFound(err) {
+		// The init process is gone, so the OCI runtime refuses to signal the
+		// container. Execs that outlived it are still tracked; reach them
+		// directly.
+		err = s.signalRemaining(ctx, r.ID, int(r.Signal))
+	}
+	if err != nil {
 		return nil, errgrpc.ToGRPC(err)
 	}
 	return empty, nil
 }
 
+// signalRemaining delivers signal to the container's tracked processes with
+// SignalAll. Processes that have already exited are not an error.
+func (s *service) signalRemaining(ctx context.Context, containerID string, signal int) error {
+	results, err := s.SignalAll(ctx, containerID, signal)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, perr := range results {
+		if perr != nil && !errdefs.IsNotFound(perr) {
+			errs = append(errs, perr)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SignalAll delivers signal to every tracked process of the container (init and
+// execs) and returns the per-PID delivery result. PIDs are those observed by
+// vminitd, so signals are sent from the VM's PID namespace rather than through
+// the OCI runtime. A process that has already exited reports ErrNotFound.
+func (s *service) SignalAll(ctx context.Context, containerID string, signal int) (map[int]error, error) {
+	container, err := s.getContainer(containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	pids := s.exitTracker.RunningPids(container)
+	results := make(map[int]error, len(pids))
+	for _, pid := range pids {
+		if err := unix.Kill(pid, syscall.Signal(signal)); err != nil {
+			if errors.Is(err, unix.ESRCH) {
+				err = fmt.Errorf("process %d already finished: %w", pid, errdefs.ErrNotFound)
+			} else {
+				err = fmt.Errorf("signal process %d: %w", pid, err)
+			}
+			results[pid] = err
+			continue
+		}
+		results[pid] = nil
+	}
+
+	log.G(ctx).WithFields(log.Fields{
+		"id":     containerID,
+		"signal": signal,
+		"pids":   pids,
+	}).Debug("broadcast signal to container processes")
+
+	return results, nil
+}
+
 // Checkpoint the container - not supported in VM-based runtime
 func (s *service) Checkpoint(ctx context.Context, r *taskAPI.CheckpointTaskRequest) (*ptypes.Empty, error) {
 	return nil, errgrpc.ToGRPCf(errdefs.ErrNotImplemented, "checkpoint is not supported")
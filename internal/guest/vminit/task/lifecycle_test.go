@@ -0,0 +1,143 @@
+//go:build linux
+
+package task
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"syscall"
+	"testing"
+
+	"github.com/containerd/errdefs"
+
+	"github.com/spin-stack/spinbox/internal/guest/vminit/runc"
+	"github.com/spin-stack/spinbox/internal/guest/vminit/testutil"
+)
+
+func TestService_SignalAll(t *testing.T) {
+	container := testutil.MockContainer("test-container")
+	other := testutil.MockContainer("other-container")
+	s := &service{
+		containers:  map[string]*runc.Container{container.ID: container, other.ID: other},
+		exitTracker: newExitTracker(),
+	}
+
+	// Start two real processes and track them as the container's processes.
+	var cmds []*exec.Cmd
+	for range 2 {
+		cmd := exec.Command("sleep", "30")
+		if err := cmd.Start(); err != nil {
+			t.Skipf("cannot start helper process: %v", err)
+		}
+		t.Cleanup(func() { _ = cmd.Process.Kill(); _ = cmd.Wait() })
+		cmds = append(cmds, cmd)
+
+		pid := cmd.Process.Pid
+		sub := s.exitTracker.Subscribe(nil)
+		sub.HandleStart(container, &testutil.MockProcess{IDValue: "proc", PIDValue: pid}, pid)
+	}
+
+	// A process in another container must not be signalled.
+	otherCmd := exec.Command("sleep", "30")
+	if err := otherCmd.Start(); err != nil {
+		t.Skipf("cannot start helper process: %v", err)
+	}
+	t.Cleanup(func() { _ = otherCmd.Process.Kill(); _ = otherCmd.Wait() })
+	otherPid := otherCmd.Process.Pid
+	sub := s.exitTracker.Subscribe(nil)
+	sub.HandleStart(other, &testutil.MockProcess{IDValue: "other", PIDValue: otherPid}, otherPid)
+
+	results, err := s.SignalAll(context.Background(), container.ID, int(syscall.SIGUSR1))
+	if err != nil {
+		t.Fatalf("SignalAll() error = %v", err)
+	}
+	if len(results) != len(cmds) {
+		t.Fatalf("SignalAll() returned %d results, want %d", len(results), len(cmds))
+	}
+
+	for _, cmd := range cmds {
+		pid := cmd.Process.Pid
+		if err, ok := results[pid]; !ok || err != nil {
+			t.Errorf("result for pid %d = %v (present=%v), want nil", pid, err, ok)
+		}
+		_ = cmd.Wait()
+		ws, ok := cmd.ProcessState.Sys().(syscall.WaitStatus)
+		if !ok || !ws.Signaled() || ws.Signal() != syscall.SIGUSR1 {
+			t.Errorf("pid %d was not terminated by SIGUSR1: %v", pid, cmd.ProcessState)
+		}
+	}
+
+	if _, ok := results[otherPid]; ok {
+		t.Error("SignalAll signalled a process from another container")
+	}
+}
+
+func TestService_SignalAll_ExitedProcess(t *testing.T) {
+	container := testutil.MockContainer("test-container")
+	s := &service{
+		containers:  map[string]*runc.Container{container.ID: container},
+		exitTracker: newExitTracker(),
+	}
+
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skipf("cannot run helper process: %v", err)
+	}
+	pid := cmd.Process.Pid
+	sub := s.exitTracker.Subscribe(nil)
+	sub.HandleStart(container, &testutil.MockProcess{IDValue: "proc", PIDValue: pid}, pid)
+
+	results, err := s.SignalAll(context.Background(), container.ID, int(syscall.SIGTERM))
+	if err != nil {
+		t.Fatalf("SignalAll() error = %v", err)
+	}
+	if !errors.Is(results[pid], errdefs.ErrNotFound) {
+		t.Errorf("result for exited pid = %v, want ErrNotFound", results[pid])
+	}
+}
+
+func TestService_SignalAll_UnknownContainer(t *testing.T) {
+	s := &service{
+		containers:  map[string]*runc.Container{},
+		exitTracker: newExitTracker(),
+	}
+
+	if _, err := s.SignalAll(context.Background(), "missing", int(syscall.SIGTERM)); err == nil {
+		t.Fatal("expected error for unknown container")
+	}
+}
+
+func TestService_SignalRemaining(t *testing.T) {
+	container := testutil.MockContainer("test-container")
+	s := &service{
+		containers:  map[string]*runc.Container{container.ID: container},
+		exitTracker: newExitTracker(),
+	}
+
+	exited := exec.Command("true")
+	if err := exited.Run(); err != nil {
+		t.Skipf("cannot run helper process: %v", err)
+	}
+	running := exec.Command("sleep", "30")
+	if err := running.Start(); err != nil {
+		t.Skipf("cannot start helper process: %v", err)
+	}
+	t.Cleanup(func() { _ = running.Process.Kill(); _ = running.Wait() })
+
+	for _, cmd := range []*exec.Cmd{exited, running} {
+		pid := cmd.Process.Pid
+		sub := s.exitTracker.Subscribe(nil)
+		sub.HandleStart(container, &testutil.MockProcess{IDValue: "proc", PIDValue: pid}, pid)
+	}
+
+	// The exited process must not turn the broadcast into an error
+	if err := s.signalRemaining(context.Background(), container.ID, int(syscall.SIGTERM)); err != nil {
+		t.Fatalf("signalRemaining() error = %v", err)
+	}
+	_ = running.Wait()
+	ws, ok := running.ProcessState.Sys().(syscall.WaitStatus)
+	if !ok || !ws.Signaled() || ws.Signal() != syscall.SIGTERM {
+		t.Errorf("running process was not terminated by SIGTERM: %v", running.ProcessState)
+	}
+}
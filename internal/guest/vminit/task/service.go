@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/containerd/cgroups/v3"
 	"github.com/containerd/containerd/api/runtime/task/v3"
@@ -27,6 +28,7 @@ import (
 	"github.com/spin-stack/spinbox/internal/guest/vminit/process"
 	"github.com/spin-stack/spinbox/internal/guest/vminit/runc"
 	"github.com/spin-stack/spinbox/internal/guest/vminit/stream"
+	"github.com/spin-stack/spinbox/internal/guest/vminit/system"
 )
 
 var (
@@ -55,6 +57,7 @@ func NewTaskService(ctx context.Context, bundle string, publisher events.Publish
 		exitTracker: newExitTracker(),
 	}
 	go s.processExits()
+	system.SetUsageReporter(s.usageReport)
 	runcC.Monitor = reaper.Default
 	if err := s.initPlatform(); err != nil {
 		return nil, fmt.Errorf("failed to initialized platform behavior: %w", err)
@@ -96,6 +99,10 @@ type service struct {
 type containerProcess struct {
 	Container *runc.Container
 	Process   process.Process
+
+	// StartedAt is a timestamp taken before the process was started. Exits
+	// reaped before it belong to a previous process with the same PID.
+	StartedAt time.Time
 }
 
 func (s *service) RegisterTTRPC(server *ttrpc.Server) error {
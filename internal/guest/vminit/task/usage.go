@@ -0,0 +1,49 @@
+//go:build linux
+
+package task
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/containerd/log"
+
+	"github.com/spin-stack/spinbox/internal/guest/vminit/runc"
+	"github.com/spin-stack/spinbox/internal/guest/vminit/system"
+)
+
+// usageReport returns the running exec count and cgroup CPU and memory
+// usage of every container, sorted by container ID. A container whose
+// cgroup stats cannot be read is still reported with its exec count.
+func (s *service) usageReport(ctx context.Context) []system.ContainerUsage {
+	s.mu.RLock()
+	containers := make([]*runc.Container, 0, len(s.containers))
+	for _, c := range s.containers {
+		containers = append(containers, c)
+	}
+	s.mu.RUnlock()
+
+	execs := s.exitTracker.Snapshot().RunningExecs
+	report := make([]system.ContainerUsage, 0, len(containers))
+	for _, c := range containers {
+		u := system.ContainerUsage{
+			ID:           c.ID,
+			RunningExecs: execs[c.ID],
+		}
+		if cg := c.Cgroup(); cg != nil {
+			metrics, err := cg.Stats(ctx)
+			if err != nil {
+				log.G(ctx).WithError(err).WithField("id", c.ID).Debug("failed to read cgroup stats for usage report")
+			} else {
+				u.CPUUsageUsec = metrics.GetCPU().GetUsageUsec()
+				u.MemoryUsageBytes = metrics.GetMemory().GetUsage()
+			}
+		}
+		report = append(report, u)
+	}
+	slices.SortFunc(report, func(a, b system.ContainerUsage) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return report
+}
@@ -0,0 +1,73 @@
+//go:build linux
+
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/containerd/cgroups/v3/cgroup2/stats"
+
+	"github.com/spin-stack/spinbox/internal/guest/vminit/runc"
+	"github.com/spin-stack/spinbox/internal/guest/vminit/system"
+	"github.com/spin-stack/spinbox/internal/guest/vminit/testutil"
+)
+
+// statsCgroup is a CgroupManager that only implements Stats.
+type statsCgroup struct {
+	runc.CgroupManager
+	metrics *stats.Metrics
+	err     error
+}
+
+func (c *statsCgroup) Stats(context.Context) (*stats.Metrics, error) {
+	return c.metrics, c.err
+}
+
+func TestService_UsageReport(t *testing.T) {
+	busy := testutil.MockContainer("busy")
+	busy.CgroupSet(&statsCgroup{metrics: &stats.Metrics{
+		CPU:    &stats.CPUStat{UsageUsec: 250000},
+		Memory: &stats.MemoryStat{Usage: 64 << 20},
+	}})
+	idle := testutil.MockContainer("idle")
+	idle.CgroupSet(&statsCgroup{metrics: &stats.Metrics{}})
+	broken := testutil.MockContainer("broken")
+	broken.CgroupSet(&statsCgroup{err: errors.New("cgroup removed")})
+	noCgroup := testutil.MockContainer("no-cgroup")
+
+	s := &service{
+		containers: map[string]*runc.Container{
+			busy.ID: busy, idle.ID: idle, broken.ID: broken, noCgroup.ID: noCgroup,
+		},
+		exitTracker: newExitTracker(),
+	}
+
+	// Two execs in busy and one in broken; init processes are not counted.
+	for i, start := range []struct {
+		c      *runc.Container
+		isInit bool
+	}{
+		{busy, true}, {busy, false}, {busy, false}, {broken, false},
+	} {
+		pid := 1000 + i
+		s.exitTracker.Subscribe(start.c).HandleStart(start.c, &testutil.MockProcess{PIDValue: pid, IsInitValue: start.isInit}, pid)
+	}
+
+	got := s.usageReport(context.Background())
+	want := []system.ContainerUsage{
+		{ID: "broken", RunningExecs: 1},
+		{ID: "busy", RunningExecs: 2, CPUUsageUsec: 250000, MemoryUsageBytes: 64 << 20},
+		{ID: "idle"},
+		{ID: "no-cgroup"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("usageReport() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("usageReport()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
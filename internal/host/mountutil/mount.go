@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -99,7 +100,7 @@ func processMkdirOptions(options []string, baseDir string) ([]string, []*mkdirSp
 // applyMkdirSpecs creates directories from specs.
 func applyMkdirSpecs(specs []*mkdirSpec) error {
 	for _, spec := range specs {
-		if err := os.MkdirAll(spec.Path, spec.Mode); err != nil {
+		if err := mkdirAll(spec.Path, spec.Mode); err != nil {
 			return err
 		}
 		if spec.UID != -1 || spec.GID != -1 {
@@ -159,11 +160,20 @@ func cleanupMounts(ctx context.Context, active []mount.ActiveMount) error {
 	return lastErr
 }
 
+// mkdirAll creates mount target directories. It is a variable so tests can
+// slow it down to exercise cancellation.
+var mkdirAll = os.MkdirAll
+
 // All mounts all the provided mounts to the provided rootfs, handling
 // "format/" and "mkdir/" mount type prefixes for template substitution
 // and directory creation.
 // It returns an optional cleanup function that should be called on container
 // delete to unmount any mounted filesystems.
+//
+// ctx is checked before each mount is prepared and again before it is
+// mounted. If it is cancelled, the mounts made so far are unmounted and the
+// returned error wraps ctx.Err(). A mount syscall already in progress is not
+// interrupted.
 func All(ctx context.Context, rootfs, mdir string, mounts []*types.Mount) (cleanup func(context.Context) error, retErr error) {
 	if len(mounts) == 0 {
 		return nil, nil
@@ -172,16 +182,26 @@ func All(ctx context.Context, rootfs, mdir string, mounts []*types.Mount) (clean
 	log.G(ctx).WithField("mounts", mounts).Info("mounting rootfs components")
 	var active []mount.ActiveMount
 
+	// abort unmounts everything mounted so far. Cleanup must run even when
+	// ctx is what caused the abort.
+	abort := func(stage string, err error) error {
+		if cleanupErr := cleanupMounts(context.WithoutCancel(ctx), active); cleanupErr != nil {
+			log.G(ctx).WithError(cleanupErr).Warnf("cleanup failed after %s", stage)
+		}
+		return err
+	}
+
 	for i, m := range mounts {
+		if err := ctx.Err(); err != nil {
+			return nil, abort("cancellation", fmt.Errorf("mounting rootfs component %d: %w", i, err))
+		}
+
 		// Determine target directory
 		target := rootfs
 		if i < len(mounts)-1 {
 			target = filepath.Join(mdir, fmt.Sprintf("%d", i))
-			if err := os.MkdirAll(target, 0750); err != nil {
-				if cleanupErr := cleanupMounts(ctx, active); cleanupErr != nil {
-					log.G(ctx).WithError(cleanupErr).Warn("cleanup failed after MkdirAll error")
-				}
-				return nil, err
+			if err := mkdirAll(target, 0750); err != nil {
+				return nil, abort("MkdirAll error", err)
 			}
 		}
 
@@ -189,10 +209,7 @@ func All(ctx context.Context, rootfs, mdir string, mounts []*types.Mount) (clean
 		if t, ok := strings.CutPrefix(m.Type, "format/"); ok {
 			m.Type = t
 			if err := applyFormatSubstitution(m, active); err != nil {
-				if cleanupErr := cleanupMounts(ctx, active); cleanupErr != nil {
-					log.G(ctx).WithError(cleanupErr).Warn("cleanup failed after format substitution error")
-				}
-				return nil, err
+				return nil, abort("format substitution error", err)
 			}
 		}
 
@@ -201,20 +218,28 @@ func All(ctx context.Context, rootfs, mdir string, mounts []*types.Mount) (clean
 			m.Type = t
 			remaining, specs, err := processMkdirOptions(m.Options, mdir)
 			if err != nil {
-				if cleanupErr := cleanupMounts(ctx, active); cleanupErr != nil {
-					log.G(ctx).WithError(cleanupErr).Warn("cleanup failed after mkdir options error")
-				}
-				return nil, err
+				return nil, abort("mkdir options error", err)
 			}
 			if err := applyMkdirSpecs(specs); err != nil {
-				if cleanupErr := cleanupMounts(ctx, active); cleanupErr != nil {
-					log.G(ctx).WithError(cleanupErr).Warn("cleanup failed after mkdir specs error")
-				}
-				return nil, err
+				return nil, abort("mkdir specs error", err)
 			}
 			m.Options = remaining
 		}
 
+		if m.Type == "overlay" {
+			if opts, removed := dedupeLowerdir(m.Options); removed > 0 {
+				log.G(ctx).WithFields(log.Fields{
+					"index":   i,
+					"removed": removed,
+				}).Debug("removed duplicate overlay lowerdir entries")
+				m.Options = opts
+			}
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, abort("cancellation", fmt.Errorf("mounting rootfs component %d: %w", i, err))
+		}
+
 		// Perform the mount
 		now := time.Now()
 		am := mount.ActiveMount{
@@ -229,16 +254,13 @@ func All(ctx context.Context, rootfs, mdir string, mounts []*types.Mount) (clean
 		}
 
 		if err := am.Mount.Mount(target); err != nil {
-			if cleanupErr := cleanupMounts(ctx, active); cleanupErr != nil {
-				log.G(ctx).WithError(cleanupErr).Warn("cleanup failed after mount error")
-			}
 			log.G(ctx).WithFields(log.Fields{
 				"type":    am.Type,
 				"source":  am.Source,
 				"target":  target,
 				"options": am.Options,
 			}).WithError(err).Error("mount failed")
-			return nil, err
+			return nil, abort("mount error", err)
 		}
 
 		log.G(ctx).WithFields(log.Fields{
@@ -257,6 +279,50 @@ func All(ctx context.Context, rootfs, mdir string, mounts []*types.Mount) (clean
 	return cleanup, nil
 }
 
+// dedupeLowerdir removes consecutive duplicate entries from an overlay
+// lowerdir= option. Overlay resolves lookups in lowerdir order, so dropping
+// an entry that repeats the one before it does not change the merged view.
+// It returns the options, copied if changed, and the number of entries
+// removed. Colons escaped with a backslash are part of an entry.
+func dedupeLowerdir(options []string) ([]string, int) {
+	const prefix = "lowerdir="
+	for i, opt := range options {
+		value, ok := strings.CutPrefix(opt, prefix)
+		if !ok {
+			continue
+		}
+		dirs := splitLowerdir(value)
+		deduped := slices.Compact(slices.Clone(dirs))
+		removed := len(dirs) - len(deduped)
+		if removed == 0 {
+			return options, 0
+		}
+		out := slices.Clone(options)
+		out[i] = prefix + strings.Join(deduped, ":")
+		return out, removed
+	}
+	return options, 0
+}
+
+// splitLowerdir splits a lowerdir value on colons not escaped by a backslash,
+// keeping escapes in the returned entries.
+func splitLowerdir(value string) []string {
+	var (
+		dirs  []string
+		start int
+	)
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ':':
+			dirs = append(dirs, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(dirs, value[start:])
+}
+
 // formatCheck is the marker for format strings that need substitution.
 const formatCheck = "{{"
 
@@ -303,22 +369,38 @@ func replaceSimplePattern(s string, pattern *regexp.Regexp, mounts []mount.Activ
 }
 
 // buildOverlayDirs builds the colon-separated directory list for overlay.
+// Each resolved mount point must be unique and exist on disk so that template
+// bugs surface here with the offending index rather than as a kernel error.
 func buildOverlayDirs(start, end int, mounts []mount.ActiveMount) (string, error) {
-	var dirs []string
+	var indices []int
 	if start > end {
 		if start >= len(mounts) || end < 0 {
 			return "", fmt.Errorf("invalid range: %d-%d, has %d active mounts", start, end, len(mounts))
 		}
 		for i := start; i >= end; i-- {
-			dirs = append(dirs, mounts[i].MountPoint)
+			indices = append(indices, i)
 		}
 	} else {
 		if start < 0 || end >= len(mounts) {
 			return "", fmt.Errorf("invalid range: %d-%d, has %d active mounts", start, end, len(mounts))
 		}
 		for i := start; i <= end; i++ {
-			dirs = append(dirs, mounts[i].MountPoint)
+			indices = append(indices, i)
+		}
+	}
+
+	dirs := make([]string, 0, len(indices))
+	seen := make(map[string]int, len(indices))
+	for _, i := range indices {
+		dir := mounts[i].MountPoint
+		if prev, ok := seen[dir]; ok {
+			return "", fmt.Errorf("duplicate overlay layer at index %d: mount point %q already used by index %d", i, dir, prev)
+		}
+		if _, err := os.Stat(dir); err != nil {
+			return "", fmt.Errorf("overlay layer at index %d: mount point %q: %w", i, dir, err)
 		}
+		seen[dir] = i
+		dirs = append(dirs, dir)
 	}
 	return strings.Join(dirs, ":"), nil
 }
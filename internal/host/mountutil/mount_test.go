@@ -4,8 +4,11 @@ package mountutil
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -310,12 +313,27 @@ func TestParseIndex(t *testing.T) {
 	}
 }
 
+// testMountPoints creates n existing directories and returns their paths.
+func testMountPoints(t *testing.T, n int) []string {
+	t.Helper()
+	base := t.TempDir()
+	dirs := make([]string, n)
+	for i := range dirs {
+		dirs[i] = filepath.Join(base, strconv.Itoa(i))
+		if err := os.Mkdir(dirs[i], 0755); err != nil {
+			t.Fatalf("Mkdir() error = %v", err)
+		}
+	}
+	return dirs
+}
+
 func TestBuildOverlayDirs(t *testing.T) {
+	mp := testMountPoints(t, 4)
 	mounts := []mount.ActiveMount{
-		{MountPoint: "/mnt/0"},
-		{MountPoint: "/mnt/1"},
-		{MountPoint: "/mnt/2"},
-		{MountPoint: "/mnt/3"},
+		{MountPoint: mp[0]},
+		{MountPoint: mp[1]},
+		{MountPoint: mp[2]},
+		{MountPoint: mp[3]},
 	}
 
 	tests := []struct {
@@ -331,28 +349,28 @@ func TestBuildOverlayDirs(t *testing.T) {
 			start:  0,
 			end:    2,
 			mounts: mounts,
-			want:   "/mnt/0:/mnt/1:/mnt/2",
+			want:   strings.Join(mp[0:3], ":"),
 		},
 		{
 			name:   "descending order",
 			start:  2,
 			end:    0,
 			mounts: mounts,
-			want:   "/mnt/2:/mnt/1:/mnt/0",
+			want:   mp[2] + ":" + mp[1] + ":" + mp[0],
 		},
 		{
 			name:   "single element",
 			start:  1,
 			end:    1,
 			mounts: mounts,
-			want:   "/mnt/1",
+			want:   mp[1],
 		},
 		{
 			name:   "full range",
 			start:  0,
 			end:    3,
 			mounts: mounts,
-			want:   "/mnt/0:/mnt/1:/mnt/2:/mnt/3",
+			want:   strings.Join(mp, ":"),
 		},
 		{
 			name:    "start out of bounds (ascending)",
@@ -389,6 +407,27 @@ func TestBuildOverlayDirs(t *testing.T) {
 			mounts:  nil,
 			wantErr: "invalid range",
 		},
+		{
+			name:  "duplicate mount point",
+			start: 0,
+			end:   2,
+			mounts: []mount.ActiveMount{
+				{MountPoint: mp[0]},
+				{MountPoint: mp[1]},
+				{MountPoint: mp[0]},
+			},
+			wantErr: "duplicate overlay layer at index 2",
+		},
+		{
+			name:  "nonexistent mount point",
+			start: 0,
+			end:   1,
+			mounts: []mount.ActiveMount{
+				{MountPoint: mp[0]},
+				{MountPoint: filepath.Join(mp[0], "missing")},
+			},
+			wantErr: "overlay layer at index 1",
+		},
 	}
 
 	for _, tt := range tests {
@@ -416,10 +455,11 @@ func TestBuildOverlayDirs(t *testing.T) {
 }
 
 func TestFormatString(t *testing.T) {
+	mp := testMountPoints(t, 3)
 	mounts := []mount.ActiveMount{
-		{Mount: mount.Mount{Source: "/src/0", Target: "/tgt/0"}, MountPoint: "/mnt/0"},
-		{Mount: mount.Mount{Source: "/src/1", Target: "/tgt/1"}, MountPoint: "/mnt/1"},
-		{Mount: mount.Mount{Source: "/src/2", Target: "/tgt/2"}, MountPoint: "/mnt/2"},
+		{Mount: mount.Mount{Source: "/src/0", Target: "/tgt/0"}, MountPoint: mp[0]},
+		{Mount: mount.Mount{Source: "/src/1", Target: "/tgt/1"}, MountPoint: mp[1]},
+		{Mount: mount.Mount{Source: "/src/2", Target: "/tgt/2"}, MountPoint: mp[2]},
 	}
 
 	tests := []struct {
@@ -451,31 +491,31 @@ func TestFormatString(t *testing.T) {
 			name:   "mount pattern",
 			input:  "prefix-{{ mount 2 }}-suffix",
 			mounts: mounts,
-			want:   "prefix-/mnt/2-suffix",
+			want:   "prefix-" + mp[2] + "-suffix",
 		},
 		{
 			name:   "overlay pattern ascending",
 			input:  "lowerdir={{ overlay 0 2 }}",
 			mounts: mounts,
-			want:   "lowerdir=/mnt/0:/mnt/1:/mnt/2",
+			want:   "lowerdir=" + mp[0] + ":" + mp[1] + ":" + mp[2],
 		},
 		{
 			name:   "overlay pattern descending",
 			input:  "lowerdir={{ overlay 2 0 }}",
 			mounts: mounts,
-			want:   "lowerdir=/mnt/2:/mnt/1:/mnt/0",
+			want:   "lowerdir=" + mp[2] + ":" + mp[1] + ":" + mp[0],
 		},
 		{
 			name:   "multiple patterns",
 			input:  "{{ source 0 }}-{{ mount 1 }}",
 			mounts: mounts,
-			want:   "/src/0-/mnt/1",
+			want:   "/src/0-" + mp[1],
 		},
 		{
 			name:   "flexible whitespace",
 			input:  "{{source 0}}-{{  mount  1  }}",
 			mounts: mounts,
-			want:   "/src/0-/mnt/1",
+			want:   "/src/0-" + mp[1],
 		},
 		{
 			name:    "source out of bounds",
@@ -489,6 +529,24 @@ func TestFormatString(t *testing.T) {
 			mounts:  mounts,
 			wantErr: "invalid range",
 		},
+		{
+			name:  "overlay duplicate index",
+			input: "lowerdir={{ overlay 0 1 }}",
+			mounts: []mount.ActiveMount{
+				{MountPoint: mp[0]},
+				{MountPoint: mp[0]},
+			},
+			wantErr: "duplicate overlay layer at index 1",
+		},
+		{
+			name:  "overlay nonexistent mount point",
+			input: "lowerdir={{ overlay 1 0 }}",
+			mounts: []mount.ActiveMount{
+				{MountPoint: mp[0]},
+				{MountPoint: "/nonexistent/spinbox/mount"},
+			},
+			wantErr: "overlay layer at index 1",
+		},
 		{
 			name:    "unsupported pattern",
 			input:   "{{ unknown 0 }}",
@@ -541,9 +599,10 @@ func TestFormatString(t *testing.T) {
 }
 
 func TestApplyFormatSubstitution(t *testing.T) {
+	mp := testMountPoints(t, 2)
 	mounts := []mount.ActiveMount{
-		{Mount: mount.Mount{Source: "/src/0", Target: "/tgt/0"}, MountPoint: "/mnt/0"},
-		{Mount: mount.Mount{Source: "/src/1", Target: "/tgt/1"}, MountPoint: "/mnt/1"},
+		{Mount: mount.Mount{Source: "/src/0", Target: "/tgt/0"}, MountPoint: mp[0]},
+		{Mount: mount.Mount{Source: "/src/1", Target: "/tgt/1"}, MountPoint: mp[1]},
 	}
 
 	tests := []struct {
@@ -596,7 +655,7 @@ func TestApplyFormatSubstitution(t *testing.T) {
 			},
 			wantSource: "/source",
 			wantTarget: "/target",
-			wantOpts:   []string{"lowerdir=/mnt/0:/mnt/1", "upperdir=/upper"},
+			wantOpts:   []string{"lowerdir=" + mp[0] + ":" + mp[1], "upperdir=/upper"},
 		},
 		{
 			name: "invalid source pattern",
@@ -643,6 +702,59 @@ func TestApplyFormatSubstitution(t *testing.T) {
 	}
 }
 
+func TestDedupeLowerdir(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     []string
+		want        []string
+		wantRemoved int
+	}{
+		{
+			name:    "no lowerdir",
+			options: []string{"ro", "index=off"},
+			want:    []string{"ro", "index=off"},
+		},
+		{
+			name:    "no duplicates",
+			options: []string{"lowerdir=/l/2:/l/1", "upperdir=/u", "workdir=/w"},
+			want:    []string{"lowerdir=/l/2:/l/1", "upperdir=/u", "workdir=/w"},
+		},
+		{
+			name:        "consecutive duplicates",
+			options:     []string{"index=off", "lowerdir=/l/3:/l/3:/l/2:/l/1:/l/1:/l/1", "upperdir=/u"},
+			want:        []string{"index=off", "lowerdir=/l/3:/l/2:/l/1", "upperdir=/u"},
+			wantRemoved: 3,
+		},
+		{
+			name:    "non-consecutive duplicates are kept",
+			options: []string{"lowerdir=/l/2:/l/1:/l/2"},
+			want:    []string{"lowerdir=/l/2:/l/1:/l/2"},
+		},
+		{
+			name:        "escaped colons stay within an entry",
+			options:     []string{`lowerdir=/l/a\:b:/l/a\:b:/l/a`},
+			want:        []string{`lowerdir=/l/a\:b:/l/a`},
+			wantRemoved: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := slices.Clone(tt.options)
+			got, removed := dedupeLowerdir(tt.options)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("dedupeLowerdir() = %v, want %v", got, tt.want)
+			}
+			if removed != tt.wantRemoved {
+				t.Errorf("dedupeLowerdir() removed = %d, want %d", removed, tt.wantRemoved)
+			}
+			if !slices.Equal(tt.options, orig) {
+				t.Errorf("dedupeLowerdir() modified its input: %v", tt.options)
+			}
+		})
+	}
+}
+
 func TestAll_EmptyMounts(t *testing.T) {
 	cleanup, err := All(context.Background(), "/rootfs", "/mdir", nil)
 	if err != nil {
@@ -698,6 +810,101 @@ func TestAll_BindMount(t *testing.T) {
 	}
 }
 
+func TestAll_FormatMkdirOverlayDedupe(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("requires root to perform overlay mounts")
+	}
+
+	ctx := context.Background()
+	rootfs := t.TempDir()
+	source := t.TempDir()
+	mountDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(source, "testfile"), []byte("test"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	// Same layout as the shim generates: a writable tmpfs for upper/work and
+	// a templated overlay whose lowerdir repeats a layer
+	overlay := &types.Mount{
+		Type:   "format/mkdir/overlay",
+		Source: "overlay",
+		Options: []string{
+			"X-containerd.mkdir.path={{ mount 1 }}/upper:0755",
+			"X-containerd.mkdir.path={{ mount 1 }}/work:0755",
+			"lowerdir={{ mount 0 }}:{{ mount 0 }}",
+			"upperdir={{ mount 1 }}/upper",
+			"workdir={{ mount 1 }}/work",
+		},
+	}
+	cleanup, err := All(ctx, rootfs, mountDir, []*types.Mount{
+		{Type: "bind", Source: source, Options: []string{"rbind", "ro"}},
+		{Type: "tmpfs", Source: "tmpfs", Options: []string{"mode=0755"}},
+		overlay,
+	})
+	if err != nil {
+		t.Fatalf("All() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if err := cleanup(ctx); err != nil {
+			t.Errorf("cleanup() error = %v", err)
+		}
+	})
+
+	if overlay.Type != "overlay" {
+		t.Errorf("mount type = %q, want overlay", overlay.Type)
+	}
+	wantLower := "lowerdir=" + filepath.Join(mountDir, "0")
+	if !slices.Contains(overlay.Options, wantLower) {
+		t.Errorf("options = %v, want %q", overlay.Options, wantLower)
+	}
+	if _, err := os.Stat(filepath.Join(rootfs, "testfile")); err != nil {
+		t.Errorf("test file not visible after mount: %v", err)
+	}
+}
+
+func TestAll_Cancelled(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("requires root to perform bind mounts")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rootfs := t.TempDir()
+	source := t.TempDir()
+	mountDir := t.TempDir()
+
+	// Cancel while the target of the second mount is being created, after
+	// the first mount is already in place.
+	calls := 0
+	orig := mkdirAll
+	mkdirAll = func(path string, perm os.FileMode) error {
+		calls++
+		if calls == 2 {
+			cancel()
+		}
+		return orig(path, perm)
+	}
+	t.Cleanup(func() { mkdirAll = orig })
+
+	cleanup, err := All(ctx, rootfs, mountDir, []*types.Mount{
+		{Type: "bind", Source: source, Options: []string{"rbind", "ro"}},
+		{Type: "bind", Source: source, Options: []string{"rbind", "ro"}},
+		{Type: "bind", Source: source, Options: []string{"rbind", "rw"}},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("All() error = %v, want context.Canceled", err)
+	}
+	if cleanup != nil {
+		t.Error("expected nil cleanup on cancellation")
+	}
+	for _, p := range []string{filepath.Join(mountDir, "0"), filepath.Join(mountDir, "1"), rootfs} {
+		if isMountPoint(p) {
+			t.Errorf("%s still mounted after cancellation", p)
+		}
+	}
+}
+
 func isMountPoint(path string) bool {
 	data, err := os.ReadFile("/proc/self/mountinfo")
 	if err != nil {
@@ -5,7 +5,11 @@ package cni
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 
 	"github.com/containerd/log"
@@ -13,6 +17,14 @@ import (
 	current "github.com/containernetworking/cni/pkg/types/100"
 )
 
+// DefaultIfName is the interface name of the first network of a VM.
+const DefaultIfName = "eth0"
+
+// IfName returns the interface name of the network at index i of a VM.
+func IfName(i int) string {
+	return fmt.Sprintf("eth%d", i)
+}
+
 // CNIManager manages CNI plugin execution for VM networking.
 type CNIManager struct {
 	confDir string
@@ -21,9 +33,15 @@ type CNIManager struct {
 	// CNI library instance
 	cniConfig libcni.CNI
 
-	// Cached network configuration (protected by netConfMu)
-	netConf   *libcni.NetworkConfigList
-	netConfMu sync.RWMutex
+	// Plugin executor, timed per invocation
+	exec *timedExec
+
+	// Cached network configuration (protected by netConfMu).
+	// netConf is the default network; namedConfs caches networks
+	// requested by name.
+	netConf    *libcni.NetworkConfigList
+	namedConfs map[string]*libcni.NetworkConfigList
+	netConfMu  sync.RWMutex
 }
 
 // NewCNIManager creates a new CNI manager.
@@ -36,10 +54,12 @@ func NewCNIManager(confDir, binDir string) (*CNIManager, error) {
 		return nil, fmt.Errorf("CNI bin directory cannot be empty")
 	}
 
+	exec := newTimedExec()
 	m := &CNIManager{
 		confDir:   confDir,
 		binDir:    binDir,
-		cniConfig: libcni.NewCNIConfig([]string{binDir}, nil),
+		cniConfig: libcni.NewCNIConfig([]string{binDir}, exec),
+		exec:      exec,
 	}
 
 	// Load and cache the configuration at startup
@@ -56,6 +76,16 @@ func (m *CNIManager) Reload() error {
 	return m.loadAndCacheConfig()
 }
 
+// SetPluginTimingHook sets the function called with the duration of every
+// individual CNI plugin invocation. Pass nil to disable.
+func (m *CNIManager) SetPluginTimingHook(fn PluginTimingFunc) {
+	if fn == nil {
+		m.exec.hook.Store(nil)
+		return
+	}
+	m.exec.hook.Store(&fn)
+}
+
 // getNetworkConfig returns the cached network configuration.
 // Returns an error if no configuration is cached.
 func (m *CNIManager) getNetworkConfig() (*libcni.NetworkConfigList, error) {
@@ -68,6 +98,40 @@ func (m *CNIManager) getNetworkConfig() (*libcni.NetworkConfigList, error) {
 	return m.netConf, nil
 }
 
+// getNamedNetworkConfig returns the configuration of the network called name,
+// loading it from the conf directory on first use. An empty name selects the
+// default network.
+func (m *CNIManager) getNamedNetworkConfig(name string) (*libcni.NetworkConfigList, error) {
+	if name == "" {
+		return m.getNetworkConfig()
+	}
+
+	m.netConfMu.RLock()
+	if m.netConf != nil && m.netConf.Name == name {
+		defer m.netConfMu.RUnlock()
+		return m.netConf, nil
+	}
+	netConf, ok := m.namedConfs[name]
+	m.netConfMu.RUnlock()
+	if ok {
+		return netConf, nil
+	}
+
+	netConf, err := libcni.LoadConfList(m.confDir, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CNI network %q from %s: %w", name, m.confDir, err)
+	}
+
+	m.netConfMu.Lock()
+	if m.namedConfs == nil {
+		m.namedConfs = make(map[string]*libcni.NetworkConfigList)
+	}
+	m.namedConfs[name] = netConf
+	m.netConfMu.Unlock()
+
+	return netConf, nil
+}
+
 // loadAndCacheConfig loads the network configuration from disk and caches it.
 func (m *CNIManager) loadAndCacheConfig() error {
 	netConf, err := m.loadNetworkConfigFromDisk()
@@ -77,33 +141,43 @@ func (m *CNIManager) loadAndCacheConfig() error {
 
 	m.netConfMu.Lock()
 	m.netConf = netConf
+	m.namedConfs = nil
 	m.netConfMu.Unlock()
 
 	return nil
 }
 
-// Setup executes the CNI plugin chain to configure networking for a VM.
-// It returns a CNIResult containing the TAP device name and network configuration.
+// Setup executes the CNI plugin chain of the default network to configure
+// networking for a VM. It returns a CNIResult containing the TAP device name
+// and network configuration.
 //
 // Errors returned are wrapped with classification. Use errors.Is() to check:
 //   - cni.ErrResourceConflict: veth/IP already exists (orphaned from previous run)
 //   - cni.ErrIPAMExhausted: no IPs available in pool
 //   - cni.ErrTAPNotCreated: tc-redirect-tap plugin didn't create TAP device
+//   - cni.ErrInvalidResult: the plugin chain returned a malformed result
 func (m *CNIManager) Setup(ctx context.Context, vmID string, netns string) (*CNIResult, error) {
-	// Get cached network configuration
-	netConfList, err := m.getNetworkConfig()
+	return m.SetupNetwork(ctx, vmID, netns, "", DefaultIfName)
+}
+
+// SetupNetwork executes the CNI plugin chain of the named network, creating
+// interface ifName in netns. An empty network selects the default network.
+// Errors are classified as for Setup.
+func (m *CNIManager) SetupNetwork(ctx context.Context, vmID, netns, network, ifName string) (*CNIResult, error) {
+	netConfList, err := m.getNamedNetworkConfig(network)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get CNI network config: %w", err)
 	}
 
 	// Execute CNI plugin chain
-	result, err := m.execPluginChain(ctx, vmID, netns, netConfList)
+	result, err := m.execPluginChain(ctx, vmID, netns, ifName, netConfList)
 	if err != nil {
 		// Classify the error for callers to handle appropriately
 		return nil, ClassifyError(ctx, "ADD", netConfList.Name, err)
 	}
 	log.G(ctx).WithFields(log.Fields{
 		"net":        netConfList.Name,
+		"ifName":     ifName,
 		"plugins":    len(netConfList.Plugins),
 		"interfaces": len(result.Interfaces),
 	}).Debug("CNI plugin chain completed")
@@ -112,7 +186,7 @@ func (m *CNIManager) Setup(ctx context.Context, vmID string, netns string) (*CNI
 	cniResult, err := ParseCNIResultWithNetNS(result, netns)
 	if err != nil {
 		// Clean up on parse failure - log teardown errors but return parse error
-		if teardownErr := m.Teardown(ctx, vmID, netns); teardownErr != nil {
+		if teardownErr := m.TeardownNetwork(ctx, vmID, netns, network, ifName); teardownErr != nil {
 			log.G(ctx).WithError(teardownErr).WithField("vmID", vmID).
 				Warn("failed to teardown CNI after parse failure")
 		}
@@ -122,11 +196,16 @@ func (m *CNIManager) Setup(ctx context.Context, vmID string, netns string) (*CNI
 	return cniResult, nil
 }
 
-// Teardown executes the CNI plugin chain to clean up networking for a VM.
-// Errors are classified - use errors.Is() to check error categories.
+// Teardown executes the CNI plugin chain of the default network to clean up
+// networking for a VM. Errors are classified - use errors.Is() to check error categories.
 func (m *CNIManager) Teardown(ctx context.Context, vmID string, netns string) error {
-	// Get cached network configuration
-	netConfList, err := m.getNetworkConfig()
+	return m.TeardownNetwork(ctx, vmID, netns, "", DefaultIfName)
+}
+
+// TeardownNetwork executes the CNI DEL of the named network for interface
+// ifName. An empty network selects the default network.
+func (m *CNIManager) TeardownNetwork(ctx context.Context, vmID, netns, network, ifName string) error {
+	netConfList, err := m.getNamedNetworkConfig(network)
 	if err != nil {
 		return fmt.Errorf("failed to get CNI network config: %w", err)
 	}
@@ -135,7 +214,7 @@ func (m *CNIManager) Teardown(ctx context.Context, vmID string, netns string) er
 	rt := &libcni.RuntimeConf{
 		ContainerID: vmID,
 		NetNS:       netns,
-		IfName:      "eth0",
+		IfName:      ifName,
 	}
 
 	// Execute DEL operation
@@ -150,21 +229,11 @@ func (m *CNIManager) Teardown(ctx context.Context, vmID string, netns string) er
 // It auto-discovers the first available .conflist file (sorted lexicographically).
 // This is called internally by loadAndCacheConfig; callers should use getNetworkConfig.
 func (m *CNIManager) loadNetworkConfigFromDisk() (*libcni.NetworkConfigList, error) {
-	// Get all CNI config files from the directory
-	files, err := libcni.ConfFiles(m.confDir, []string{".conflist", ".conf"})
+	confFile, err := firstConfFile(m.confDir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read CNI config files from %s: %w", m.confDir, err)
+		return nil, err
 	}
 
-	if len(files) == 0 {
-		return nil, fmt.Errorf("no CNI configuration files found in %s", m.confDir)
-	}
-
-	// Files are returned sorted lexicographically, use the first one
-	// This follows standard CNI practice where files are named like:
-	// 10-mynet.conflist, 20-othernet.conflist, etc.
-	confFile := files[0]
-
 	// Load the network configuration
 	netConfList, err := libcni.ConfListFromFile(confFile)
 	if err != nil {
@@ -180,13 +249,58 @@ func (m *CNIManager) loadNetworkConfigFromDisk() (*libcni.NetworkConfigList, err
 	return netConfList, nil
 }
 
+// ConfigHash returns a hex-encoded SHA-256 over every CNI configuration file
+// in confDir: the default network and the named networks containers may
+// request. Callers can compare hashes to detect config changes that require
+// networks to be set up again; an edit to any network changes the hash.
+func ConfigHash(confDir string) (string, error) {
+	if _, err := firstConfFile(confDir); err != nil {
+		return "", err
+	}
+	// The extensions libcni.LoadConfList looks up named networks in
+	files, err := libcni.ConfFiles(confDir, []string{".conflist", ".conf", ".json"})
+	if err != nil {
+		return "", fmt.Errorf("failed to read CNI config files from %s: %w", confDir, err)
+	}
+
+	h := sha256.New()
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return "", fmt.Errorf("failed to read CNI config %s: %w", f, err)
+		}
+		// Names are hashed too: they decide which file is the default
+		fmt.Fprintf(h, "%s\x00%d\x00", filepath.Base(f), len(data))
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// firstConfFile returns the CNI config file used from confDir.
+func firstConfFile(confDir string) (string, error) {
+	// Get all CNI config files from the directory
+	files, err := libcni.ConfFiles(confDir, []string{".conflist", ".conf"})
+	if err != nil {
+		return "", fmt.Errorf("failed to read CNI config files from %s: %w", confDir, err)
+	}
+
+	if len(files) == 0 {
+		return "", fmt.Errorf("no CNI configuration files found in %s", confDir)
+	}
+
+	// Files are returned sorted lexicographically, use the first one
+	// This follows standard CNI practice where files are named like:
+	// 10-mynet.conflist, 20-othernet.conflist, etc.
+	return files[0], nil
+}
+
 // execPluginChain executes the CNI plugin chain and returns the result.
-func (m *CNIManager) execPluginChain(ctx context.Context, vmID string, netns string, netConfList *libcni.NetworkConfigList) (*current.Result, error) {
+func (m *CNIManager) execPluginChain(ctx context.Context, vmID, netns, ifName string, netConfList *libcni.NetworkConfigList) (*current.Result, error) {
 	// Create runtime configuration
 	rt := &libcni.RuntimeConf{
 		ContainerID: vmID,
 		NetNS:       netns,
-		IfName:      "eth0",
+		IfName:      ifName,
 	}
 
 	// Execute ADD operation
@@ -153,6 +153,41 @@ func TestCNIManager_LoadNetworkConfig(t *testing.T) {
 	}
 }
 
+func TestConfigHash(t *testing.T) {
+	confDir := t.TempDir()
+	confFile := filepath.Join(confDir, "10-test.conflist")
+
+	_, err := ConfigHash(confDir)
+	require.Error(t, err, "empty config directory should fail")
+
+	require.NoError(t, os.WriteFile(confFile, []byte(`{"cniVersion":"1.0.0","name":"a","plugins":[{"type":"bridge"}]}`), 0600))
+	first, err := ConfigHash(confDir)
+	require.NoError(t, err)
+	assert.Len(t, first, 64)
+
+	again, err := ConfigHash(confDir)
+	require.NoError(t, err)
+	assert.Equal(t, first, again, "unchanged config should hash the same")
+
+	require.NoError(t, os.WriteFile(confFile, []byte(`{"cniVersion":"1.0.0","name":"b","plugins":[{"type":"bridge"}]}`), 0600))
+	changed, err := ConfigHash(confDir)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, changed, "changed config should hash differently")
+
+	// Named networks are only loaded on request, but editing one must still
+	// invalidate setups that use it
+	namedFile := filepath.Join(confDir, "20-named.conflist")
+	require.NoError(t, os.WriteFile(namedFile, []byte(`{"cniVersion":"1.0.0","name":"named","plugins":[{"type":"bridge"}]}`), 0600))
+	withNamed, err := ConfigHash(confDir)
+	require.NoError(t, err)
+	assert.NotEqual(t, changed, withNamed, "added named network should change the hash")
+
+	require.NoError(t, os.WriteFile(namedFile, []byte(`{"cniVersion":"1.0.0","name":"named","plugins":[{"type":"ptp"}]}`), 0600))
+	namedChanged, err := ConfigHash(confDir)
+	require.NoError(t, err)
+	assert.NotEqual(t, withNamed, namedChanged, "edited named network should change the hash")
+}
+
 // Test CNI network name validation helpers
 func TestValidCNINetworkName(t *testing.T) {
 	tests := []struct {
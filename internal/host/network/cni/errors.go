@@ -31,6 +31,10 @@ var (
 
 	// ErrIPAMLeak indicates IPAM cleanup did not release the IP allocation.
 	ErrIPAMLeak = errors.New("IPAM leak detected")
+
+	// ErrCNITimeout indicates a CNI ADD or DEL did not finish within the
+	// operation timeout.
+	ErrCNITimeout = errors.New("CNI operation timed out")
 )
 
 // Error wraps a CNI plugin error with classification.
@@ -0,0 +1,55 @@
+//go:build linux
+
+package cni
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/containernetworking/cni/pkg/invoke"
+)
+
+// PluginTimingFunc receives the duration of a single CNI plugin invocation.
+// plugin is the plugin binary name (e.g. "bridge", "host-local") and op is
+// the CNI command (ADD, DEL, CHECK, ...).
+type PluginTimingFunc func(plugin, op string, d time.Duration)
+
+// timedExec wraps the default plugin executor to time each plugin invocation.
+// libcni runs a whole chain per AddNetworkList/DelNetworkList call, so this is
+// the only layer where individual plugin names are visible.
+type timedExec struct {
+	*invoke.DefaultExec
+	hook atomic.Pointer[PluginTimingFunc]
+}
+
+func newTimedExec() *timedExec {
+	return &timedExec{
+		DefaultExec: &invoke.DefaultExec{
+			RawExec: &invoke.RawExec{Stderr: os.Stderr},
+		},
+	}
+}
+
+// ExecPlugin runs the plugin and reports its duration to the timing hook.
+func (e *timedExec) ExecPlugin(ctx context.Context, pluginPath string, stdinData []byte, environ []string) ([]byte, error) {
+	start := time.Now()
+	out, err := e.DefaultExec.ExecPlugin(ctx, pluginPath, stdinData, environ)
+	if hook := e.hook.Load(); hook != nil {
+		(*hook)(filepath.Base(pluginPath), cniCommand(environ), time.Since(start))
+	}
+	return out, err
+}
+
+// cniCommand returns the CNI_COMMAND value from a plugin environment.
+func cniCommand(environ []string) string {
+	for _, kv := range environ {
+		if op, ok := strings.CutPrefix(kv, "CNI_COMMAND="); ok {
+			return op
+		}
+	}
+	return "UNKNOWN"
+}
@@ -0,0 +1,50 @@
+//go:build linux
+
+package cni
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimedExecReportsPluginTiming(t *testing.T) {
+	plugin := filepath.Join(t.TempDir(), "bridge")
+	require.NoError(t, os.WriteFile(plugin, []byte("#!/bin/sh\necho '{}'\n"), 0o755))
+
+	type call struct {
+		plugin, op string
+		d          time.Duration
+	}
+	var calls []call
+
+	e := newTimedExec()
+
+	// No hook set: must not panic
+	_, err := e.ExecPlugin(context.Background(), plugin, nil, []string{"CNI_COMMAND=ADD"})
+	require.NoError(t, err)
+
+	fn := PluginTimingFunc(func(plugin, op string, d time.Duration) {
+		calls = append(calls, call{plugin, op, d})
+	})
+	e.hook.Store(&fn)
+
+	out, err := e.ExecPlugin(context.Background(), plugin, nil, []string{"CNI_IFNAME=eth0", "CNI_COMMAND=DEL"})
+	require.NoError(t, err)
+	assert.JSONEq(t, "{}", string(out))
+
+	require.Len(t, calls, 1)
+	assert.Equal(t, "bridge", calls[0].plugin)
+	assert.Equal(t, "DEL", calls[0].op)
+	assert.Positive(t, calls[0].d)
+}
+
+func TestCNICommand(t *testing.T) {
+	assert.Equal(t, "ADD", cniCommand([]string{"PATH=/bin", "CNI_COMMAND=ADD"}))
+	assert.Equal(t, "UNKNOWN", cniCommand([]string{"PATH=/bin"}))
+}
@@ -8,6 +8,7 @@ import (
 	"runtime"
 
 	"github.com/containerd/log"
+	cnitypes "github.com/containernetworking/cni/pkg/types"
 	current "github.com/containernetworking/cni/pkg/types/100"
 	"github.com/vishvananda/netlink"
 	"github.com/vishvananda/netns"
@@ -20,16 +21,44 @@ type CNIResult struct {
 	// TAPMAC is the MAC address reported for the TAP device (if provided by CNI).
 	TAPMAC string
 
-	// IPAddress is the IP address allocated to the VM.
+	// IPAddress is the IPv4 address allocated to the VM, nil if the
+	// network is IPv6-only.
 	IPAddress net.IP
 
-	// Netmask is the network mask for the allocated IP address.
+	// Netmask is the network mask for the allocated IPv4 address.
 	Netmask string
 
-	// Gateway is the gateway IP address for the network.
+	// Gateway is the IPv4 gateway address for the network.
 	Gateway net.IP
+
+	// IPv6Address is the IPv6 address allocated to the VM, nil if the
+	// network is IPv4-only.
+	IPv6Address net.IP
+
+	// IPv6PrefixLen is the prefix length of the allocated IPv6 address.
+	IPv6PrefixLen int
+
+	// IPv6Gateway is the IPv6 gateway address for the network.
+	IPv6Gateway net.IP
+
+	// MTU is the MTU of the network's interface, DefaultMTU if the result
+	// does not report one.
+	MTU int
+
+	// Routes are the routes the result specifies in addition to the
+	// default route, which is reported as Gateway.
+	Routes []Route
+}
+
+// Route is a static route to install in the guest.
+type Route struct {
+	Dst net.IPNet
+	GW  net.IP // nil for an on-link route
 }
 
+// DefaultMTU is the interface MTU assumed when a CNI result reports none.
+const DefaultMTU = 1500
+
 // ParseCNIResult parses a CNI result and extracts networking information.
 //
 // This function:
@@ -44,7 +73,7 @@ func ParseCNIResult(result *current.Result) (*CNIResult, error) {
 // TAP device inside the provided network namespace.
 func ParseCNIResultWithNetNS(result *current.Result, netnsPath string) (*CNIResult, error) {
 	if result == nil {
-		return nil, fmt.Errorf("CNI result is nil")
+		return nil, fmt.Errorf("CNI result is nil: %w", ErrInvalidResult)
 	}
 
 	// Extract TAP device
@@ -63,29 +92,102 @@ func ParseCNIResultWithNetNS(result *current.Result, netnsPath string) (*CNIResu
 		tapMAC = resolvedMAC
 	}
 
-	// Parse IP address, netmask, and gateway
-	if len(result.IPs) == 0 {
-		return nil, fmt.Errorf("CNI result contains no IP addresses")
+	// Classify IP configurations by address family; the first of each is used
+	var ip4, ip6 *current.IPConfig
+	for _, ipConfig := range result.IPs {
+		switch {
+		case ipConfig == nil || ipConfig.Address.IP == nil:
+		case ipConfig.Address.IP.To4() != nil:
+			if ip4 == nil {
+				ip4 = ipConfig
+			}
+		case ip6 == nil:
+			ip6 = ipConfig
+		}
 	}
-
-	// Use the first IP configuration
-	ipConfig := result.IPs[0]
-	ipAddress := ipConfig.Address.IP
-	gateway := ipConfig.Gateway
-
-	// Extract netmask from the IPNet
-	var netmask string
-	if ipConfig.Address.Mask != nil {
-		netmask = net.IP(ipConfig.Address.Mask).String()
+	if ip4 == nil && ip6 == nil {
+		return nil, fmt.Errorf("CNI result contains no IP addresses: %w", ErrInvalidResult)
 	}
 
-	return &CNIResult{
+	routes, defaultGW4, defaultGW6 := parseRoutes(result.Routes, ip4 != nil, ip6 != nil)
+	parsed := &CNIResult{
 		TAPDevice: tapDevice,
 		TAPMAC:    tapMAC,
-		IPAddress: ipAddress,
-		Netmask:   netmask,
-		Gateway:   gateway,
-	}, nil
+		Routes:    routes,
+	}
+
+	primary := ip4
+	if ip4 != nil {
+		parsed.IPAddress = ip4.Address.IP
+		// Extract netmask from the IPNet
+		if ip4.Address.Mask != nil {
+			parsed.Netmask = net.IP(ip4.Address.Mask).String()
+		}
+		parsed.Gateway = ip4.Gateway
+		if parsed.Gateway == nil {
+			parsed.Gateway = defaultGW4
+		}
+	} else {
+		primary = ip6
+	}
+	if ip6 != nil {
+		parsed.IPv6Address = ip6.Address.IP
+		parsed.IPv6PrefixLen, _ = ip6.Address.Mask.Size()
+		parsed.IPv6Gateway = ip6.Gateway
+		if parsed.IPv6Gateway == nil {
+			parsed.IPv6Gateway = defaultGW6
+		}
+	}
+	parsed.MTU = resultMTU(result, primary, tapDevice)
+
+	return parsed, nil
+}
+
+// parseRoutes splits CNI routes into the default routes' gateways and the
+// remaining routes. Only routes of an allocated address family are kept,
+// since the guest interface has no address of the other family.
+func parseRoutes(cniRoutes []*cnitypes.Route, ipv4, ipv6 bool) ([]Route, net.IP, net.IP) {
+	var (
+		routes                 []Route
+		defaultGW4, defaultGW6 net.IP
+	)
+	for _, r := range cniRoutes {
+		if r == nil {
+			continue
+		}
+		isV4 := r.Dst.IP.To4() != nil
+		if (isV4 && !ipv4) || (!isV4 && !ipv6) {
+			continue
+		}
+		if ones, _ := r.Dst.Mask.Size(); ones == 0 {
+			if isV4 && defaultGW4 == nil {
+				defaultGW4 = r.GW
+			} else if !isV4 && defaultGW6 == nil {
+				defaultGW6 = r.GW
+			}
+			continue
+		}
+		routes = append(routes, Route{Dst: r.Dst, GW: r.GW})
+	}
+	return routes, defaultGW4, defaultGW6
+}
+
+// resultMTU returns the MTU reported for the interface the IP is assigned
+// to, falling back to the TAP device and then to DefaultMTU. Overlay
+// networks such as VXLAN report an MTU below 1500; the guest must use it to
+// avoid fragmentation.
+func resultMTU(result *current.Result, ipConfig *current.IPConfig, tapDevice string) int {
+	if i := ipConfig.Interface; i != nil && *i >= 0 && *i < len(result.Interfaces) {
+		if mtu := result.Interfaces[*i].Mtu; mtu > 0 {
+			return mtu
+		}
+	}
+	for _, iface := range result.Interfaces {
+		if iface.Name == tapDevice && iface.Mtu > 0 {
+			return iface.Mtu
+		}
+	}
+	return DefaultMTU
 }
 
 func readInterfaceMAC(netnsPath, ifName string) (string, error) {
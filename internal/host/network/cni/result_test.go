@@ -6,6 +6,7 @@ import (
 	"net"
 	"testing"
 
+	"github.com/containernetworking/cni/pkg/types"
 	current "github.com/containernetworking/cni/pkg/types/100"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -128,6 +129,7 @@ func TestParseCNIResult_NoIPs(t *testing.T) {
 	_, err := ParseCNIResult(result)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "no IP addresses")
+	assert.ErrorIs(t, err, ErrInvalidResult)
 }
 
 func TestParseCNIResult_TAPMACRequired(t *testing.T) {
@@ -172,11 +174,12 @@ func TestParseCNIResult_NoTAPDevice(t *testing.T) {
 	_, err := ParseCNIResult(result)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "no TAP device found")
+	assert.ErrorIs(t, err, ErrTAPNotCreated)
 }
 
 func TestParseCNIResult_NilResult(t *testing.T) {
 	_, err := ParseCNIResult(nil)
-	require.Error(t, err)
+	require.ErrorIs(t, err, ErrInvalidResult)
 }
 
 func TestParseCNIResult_GatewayOptional(t *testing.T) {
@@ -201,6 +204,182 @@ func TestParseCNIResult_GatewayOptional(t *testing.T) {
 	assert.Nil(t, cniResult.Gateway)
 }
 
+func TestParseCNIResult_MTU(t *testing.T) {
+	newResult := func(ifaces ...*current.Interface) *current.Result {
+		return &current.Result{
+			CNIVersion: "1.0.0",
+			Interfaces: ifaces,
+			IPs: []*current.IPConfig{
+				{
+					Address: net.IPNet{
+						IP:   net.ParseIP("10.88.0.40"),
+						Mask: net.CIDRMask(16, 32),
+					},
+					Gateway:   net.ParseIP("10.88.0.1"),
+					Interface: intPtr(1),
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		result   *current.Result
+		expected int
+	}{
+		{
+			name: "no MTU defaults to 1500",
+			result: newResult(
+				&current.Interface{Name: "spinbox0"},
+				&current.Interface{Name: "tap0", Mac: "11:22:33:44:55:66", Sandbox: "/var/run/netns/test"},
+			),
+			expected: DefaultMTU,
+		},
+		{
+			name: "MTU of the IP's interface",
+			result: newResult(
+				&current.Interface{Name: "spinbox0", Mtu: 9000},
+				&current.Interface{Name: "tap0", Mac: "11:22:33:44:55:66", Mtu: 1450, Sandbox: "/var/run/netns/test"},
+			),
+			expected: 1450,
+		},
+		{
+			name: "falls back to the TAP device",
+			result: newResult(
+				&current.Interface{Name: "tap0", Mac: "11:22:33:44:55:66", Mtu: 1400, Sandbox: "/var/run/netns/test"},
+				&current.Interface{Name: "eth0", Sandbox: "/var/run/netns/test"},
+			),
+			expected: 1400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cniResult, err := ParseCNIResult(tt.result)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cniResult.MTU)
+		})
+	}
+}
+
+func TestParseCNIResult_Routes(t *testing.T) {
+	cidr := func(s string) net.IPNet {
+		_, n, err := net.ParseCIDR(s)
+		require.NoError(t, err)
+		return *n
+	}
+	newResult := func(gw net.IP, routes ...*types.Route) *current.Result {
+		return &current.Result{
+			CNIVersion: "1.0.0",
+			Interfaces: []*current.Interface{
+				{Name: "tap0", Mac: "11:22:33:44:55:66", Sandbox: "/var/run/netns/test"},
+			},
+			IPs: []*current.IPConfig{
+				{
+					Address: net.IPNet{
+						IP:   net.ParseIP("10.88.0.50"),
+						Mask: net.CIDRMask(16, 32),
+					},
+					Gateway: gw,
+				},
+			},
+			Routes: routes,
+		}
+	}
+
+	t.Run("extra routes are captured", func(t *testing.T) {
+		result := newResult(net.ParseIP("10.88.0.1"),
+			&types.Route{Dst: cidr("0.0.0.0/0"), GW: net.ParseIP("10.88.0.1")},
+			&types.Route{Dst: cidr("10.96.0.0/12"), GW: net.ParseIP("10.88.0.254")},
+			&types.Route{Dst: cidr("192.168.100.0/24")},
+			// No IPv6 address on the interface
+			&types.Route{Dst: cidr("fd00::/8"), GW: net.ParseIP("fd00::1")},
+		)
+
+		cniResult, err := ParseCNIResult(result)
+		require.NoError(t, err)
+		assert.Equal(t, "10.88.0.1", cniResult.Gateway.String())
+		assert.Equal(t, []Route{
+			{Dst: cidr("10.96.0.0/12"), GW: net.ParseIP("10.88.0.254")},
+			{Dst: cidr("192.168.100.0/24")},
+		}, cniResult.Routes)
+	})
+
+	t.Run("default route supplies a missing gateway", func(t *testing.T) {
+		result := newResult(nil, &types.Route{Dst: cidr("0.0.0.0/0"), GW: net.ParseIP("10.88.0.1")})
+
+		cniResult, err := ParseCNIResult(result)
+		require.NoError(t, err)
+		assert.Equal(t, "10.88.0.1", cniResult.Gateway.String())
+		assert.Empty(t, cniResult.Routes)
+	})
+
+	t.Run("IP gateway takes precedence over default route", func(t *testing.T) {
+		result := newResult(net.ParseIP("10.88.0.1"), &types.Route{Dst: cidr("0.0.0.0/0"), GW: net.ParseIP("10.88.0.2")})
+
+		cniResult, err := ParseCNIResult(result)
+		require.NoError(t, err)
+		assert.Equal(t, "10.88.0.1", cniResult.Gateway.String())
+	})
+}
+
+func TestParseCNIResult_AddressFamilies(t *testing.T) {
+	tap := &current.Interface{Name: "tap0", Mac: "11:22:33:44:55:66", Sandbox: "/var/run/netns/test"}
+	v4 := &current.IPConfig{
+		Address: net.IPNet{IP: net.ParseIP("10.88.0.60"), Mask: net.CIDRMask(16, 32)},
+		Gateway: net.ParseIP("10.88.0.1"),
+	}
+	v6 := &current.IPConfig{
+		Address: net.IPNet{IP: net.ParseIP("fd00::60"), Mask: net.CIDRMask(64, 128)},
+		Gateway: net.ParseIP("fd00::1"),
+	}
+
+	t.Run("dual-stack", func(t *testing.T) {
+		// Family order in the result does not matter
+		result := &current.Result{Interfaces: []*current.Interface{tap}, IPs: []*current.IPConfig{v6, v4}}
+
+		cniResult, err := ParseCNIResult(result)
+		require.NoError(t, err)
+		assert.Equal(t, "10.88.0.60", cniResult.IPAddress.String())
+		assert.Equal(t, "255.255.0.0", cniResult.Netmask)
+		assert.Equal(t, "10.88.0.1", cniResult.Gateway.String())
+		assert.Equal(t, "fd00::60", cniResult.IPv6Address.String())
+		assert.Equal(t, 64, cniResult.IPv6PrefixLen)
+		assert.Equal(t, "fd00::1", cniResult.IPv6Gateway.String())
+	})
+
+	t.Run("IPv4-only", func(t *testing.T) {
+		result := &current.Result{Interfaces: []*current.Interface{tap}, IPs: []*current.IPConfig{v4}}
+
+		cniResult, err := ParseCNIResult(result)
+		require.NoError(t, err)
+		assert.Equal(t, "10.88.0.60", cniResult.IPAddress.String())
+		assert.Nil(t, cniResult.IPv6Address)
+		assert.Nil(t, cniResult.IPv6Gateway)
+	})
+
+	t.Run("IPv6-only", func(t *testing.T) {
+		_, defaultDst, err := net.ParseCIDR("::/0")
+		require.NoError(t, err)
+		gwless := &current.IPConfig{Address: v6.Address}
+		result := &current.Result{
+			Interfaces: []*current.Interface{tap},
+			IPs:        []*current.IPConfig{gwless},
+			Routes:     []*types.Route{{Dst: *defaultDst, GW: net.ParseIP("fd00::fe")}},
+		}
+
+		cniResult, err := ParseCNIResult(result)
+		require.NoError(t, err)
+		assert.Nil(t, cniResult.IPAddress)
+		assert.Empty(t, cniResult.Netmask)
+		assert.Nil(t, cniResult.Gateway)
+		assert.Equal(t, "fd00::60", cniResult.IPv6Address.String())
+		assert.Equal(t, "fd00::fe", cniResult.IPv6Gateway.String())
+		assert.Equal(t, DefaultMTU, cniResult.MTU)
+		assert.Empty(t, cniResult.Routes)
+	})
+}
+
 // intPtr returns a pointer to an int
 func intPtr(i int) *int {
 	return &i
@@ -20,9 +20,17 @@ func ExtractTAPDevice(result *current.Result) (string, error) {
 }
 
 // ExtractTAPDeviceInfo extracts the TAP device name and MAC from a CNI result.
+//
+// A nil result or one without interfaces is malformed and returns an error
+// wrapping ErrInvalidResult. A result whose interfaces include no TAP device
+// returns an error wrapping ErrTAPNotCreated, which usually means the
+// tc-redirect-tap plugin is missing from the network's plugin chain.
 func ExtractTAPDeviceInfo(result *current.Result) (string, string, error) {
 	if result == nil {
-		return "", "", fmt.Errorf("CNI result is nil")
+		return "", "", fmt.Errorf("CNI result is nil: %w", ErrInvalidResult)
+	}
+	if len(result.Interfaces) == 0 {
+		return "", "", fmt.Errorf("no TAP device found: CNI result has no interfaces: %w", ErrInvalidResult)
 	}
 
 	log.L.WithField("count", len(result.Interfaces)).Debug("CNI result interface count")
@@ -37,7 +45,7 @@ func ExtractTAPDeviceInfo(result *current.Result) (string, string, error) {
 		return tapDevice, tapMAC, nil
 	}
 
-	return "", "", fmt.Errorf("no TAP device found in CNI result (checked %d interfaces)", len(result.Interfaces))
+	return "", "", fmt.Errorf("no TAP device found in CNI result (checked %d interfaces): %w", len(result.Interfaces), ErrTAPNotCreated)
 }
 
 // detectTCRedirectTAP detects TAP devices created by the tc-redirect-tap CNI plugin.
@@ -143,3 +143,32 @@ func TestExtractTAPDevice(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractTAPDeviceErrorCategories(t *testing.T) {
+	t.Run("no interfaces is a malformed result", func(t *testing.T) {
+		_, err := ExtractTAPDevice(&current.Result{})
+		require.ErrorIs(t, err, ErrInvalidResult)
+		assert.NotErrorIs(t, err, ErrTAPNotCreated)
+	})
+
+	t.Run("only non-TAP interfaces", func(t *testing.T) {
+		_, err := ExtractTAPDevice(&current.Result{
+			Interfaces: []*current.Interface{
+				{Name: "spinbox0"},
+				{Name: "eth0", Sandbox: "/var/run/netns/test"},
+			},
+		})
+		require.ErrorIs(t, err, ErrTAPNotCreated)
+		assert.NotErrorIs(t, err, ErrInvalidResult)
+	})
+
+	t.Run("valid result", func(t *testing.T) {
+		tapDevice, err := ExtractTAPDevice(&current.Result{
+			Interfaces: []*current.Interface{
+				{Name: "tap0", Sandbox: "/var/run/netns/test"},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "tap0", tapDevice)
+	})
+}
@@ -6,12 +6,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/containerd/log"
+	"golang.org/x/sys/unix"
 
 	"github.com/spin-stack/spinbox/internal/host/network/cni"
 )
@@ -83,33 +86,92 @@ func newCNINetworkManager(config NetworkConfig) (*cniNetworkManager, error) {
 	nm := &cniNetworkManager{
 		config:           config,
 		cniManager:       cniMgr,
-		cniResults:       make(map[string]*cni.CNIResult),
+		cniNets:          cniMgr,
+		cniResults:       make(map[string]*cniSetup),
 		inFlight:         make(map[string]*setupInFlight),
 		teardownInFlight: make(map[string]*teardownInFlight),
 		metrics:          &Metrics{},
 		ipamDir:          "/var/lib/cni/networks",
 	}
+	nm.setup = nm.performCNISetup
+	nm.teardown = nm.performCNITeardown
+	nm.reclaim = nm.reclaimOrphan
+	cniMgr.SetPluginTimingHook(nm.metrics.RecordPluginTiming)
+
+	// The config was loaded by NewCNIManager; an unreadable config leaves the
+	// hash empty so the next setup reloads it.
+	nm.loadedConfHash, _ = cni.ConfigHash(config.CNIConfDir)
 
 	return nm, nil
 }
 
+// currentConfHash returns the hash of the CNI config on disk.
+// Returns an empty string if the config cannot be read, which disables
+// change detection rather than failing setup.
+func (nm *cniNetworkManager) currentConfHash(ctx context.Context) string {
+	hash, err := cni.ConfigHash(nm.config.CNIConfDir)
+	if err != nil {
+		log.G(ctx).WithError(err).WithField("confDir", nm.config.CNIConfDir).
+			Debug("could not hash CNI config, skipping change detection")
+		return ""
+	}
+	return hash
+}
+
+// syncConfig reloads the CNI config if it differs from the one loaded.
+func (nm *cniNetworkManager) syncConfig(ctx context.Context, confHash string) error {
+	nm.confMu.Lock()
+	defer nm.confMu.Unlock()
+
+	if confHash == "" || confHash == nm.loadedConfHash {
+		return nil
+	}
+
+	if err := nm.cniManager.Reload(); err != nil {
+		return fmt.Errorf("reload CNI config: %w", err)
+	}
+	nm.loadedConfHash = confHash
+
+	log.G(ctx).WithField("confDir", nm.config.CNIConfDir).Info("CNI configuration reloaded")
+	return nil
+}
+
+// cachedSetup returns the cached setup for a container if it was performed
+// with the given config and networks.
+func (nm *cniNetworkManager) cachedSetup(id, confHash string, networks []string) (*cniSetup, bool) {
+	nm.cniMu.RLock()
+	defer nm.cniMu.RUnlock()
+
+	entry, exists := nm.cniResults[id]
+	if !exists {
+		return nil, false
+	}
+	return entry, entry.confHash == confHash && slices.Equal(entry.networks, networks)
+}
+
 // ensureNetworkResourcesCNI allocates and configures network resources using CNI plugins.
 // If multiple goroutines call this concurrently for the same container ID, only one will
 // perform the actual CNI setup. The others will block until setup completes, then return
 // the same result or error.
+//
+// Each network in env.Networks gets its own CNI ADD and TAP device, in order;
+// an empty list sets up the default network only.
+//
+// A previous setup for the same container is reused as long as the CNI config
+// on disk and the requested networks are unchanged. Otherwise the old setup is
+// torn down and CNI ADD runs again with the reloaded config.
 func (nm *cniNetworkManager) ensureNetworkResourcesCNI(ctx context.Context, env *Environment) error {
-	// Fast path: check if already configured
-	nm.cniMu.RLock()
-	if result, exists := nm.cniResults[env.ID]; exists {
-		nm.cniMu.RUnlock()
+	confHash := nm.currentConfHash(ctx)
+
+	// Fast path: check if already configured with the current config
+	if entry, valid := nm.cachedSetup(env.ID, confHash, env.Networks); valid {
 		log.G(ctx).WithFields(log.Fields{
 			"vmID": env.ID,
-			"tap":  result.TAPDevice,
+			"tap":  entry.results[0].TAPDevice,
 		}).Debug("CNI resources already allocated")
-		nm.updateEnvironment(env, result)
+		nm.updateEnvironment(env, entry.results)
 		return nil
 	}
-	nm.cniMu.RUnlock()
 
 	// Check if another goroutine is already setting up this container
 	nm.inflightMu.Lock()
@@ -118,19 +180,15 @@ func (nm *cniNetworkManager) ensureNetworkResourcesCNI(ctx context.Context, env
 		nm.inflightMu.Unlock()
 		log.G(ctx).WithField("vmID", env.ID).Debug("waiting for in-flight CNI setup to complete")
 
-		// Wait with context cancellation support
-		select {
-		case <-inflight.done:
-			// Setup completed
-		case <-ctx.Done():
-			return ctx.Err()
+		if err := nm.waitInFlight(ctx, env.ID, inflight); err != nil {
+			return err
 		}
 
 		// Check the result
 		if inflight.err != nil {
 			return inflight.err
 		}
-		nm.updateEnvironment(env, inflight.result)
+		nm.updateEnvironment(env, inflight.results)
 		return nil
 	}
 
@@ -141,17 +199,46 @@ func (nm *cniNetworkManager) ensureNetworkResourcesCNI(ctx context.Context, env
 	nm.inFlight[env.ID] = inflight
 	nm.inflightMu.Unlock()
 
-	// Ensure we clean up the in-flight tracker and wake waiters when done
+	// Ensure we clean up the in-flight tracker and wake waiters when done.
+	// This also runs if setup panics, so later callers retry instead of waiting
+	// on a tracker that never completes.
 	defer func() {
+		if inflight.err == nil && inflight.results == nil {
+			inflight.err = fmt.Errorf("CNI setup for %s did not complete", env.ID)
+		}
 		close(inflight.done)
 		nm.inflightMu.Lock()
 		delete(nm.inFlight, env.ID)
 		nm.inflightMu.Unlock()
 	}()
 
+	// Another worker may have completed setup while we were acquiring the tracker
+	if entry, valid := nm.cachedSetup(env.ID, confHash, env.Networks); valid {
+		inflight.results = entry.results
+		nm.updateEnvironment(env, entry.results)
+		return nil
+	} else if entry != nil {
+		// Set up with an older config or other networks - release it before
+		// setting up again. Teardown must run before the reload so CNI DEL
+		// uses the old config.
+		log.G(ctx).WithField("vmID", env.ID).Info("CNI configuration changed, re-running network setup")
+		teardownStart := time.Now()
+		cleanup := nm.teardown(ctx, env)
+		nm.metrics.RecordTeardown(!cleanup.HasError(), time.Since(teardownStart))
+		if err := cleanup.Err(); err != nil {
+			log.G(ctx).WithError(err).WithField("vmID", env.ID).
+				Warn("stale CNI setup cleanup completed with errors")
+		}
+	}
+
+	if err := nm.syncConfig(ctx, confHash); err != nil {
+		inflight.err = err
+		return err
+	}
+
 	// Perform the actual CNI setup (without holding locks)
 	start := time.Now()
-	result, err := nm.performCNISetup(ctx, env.ID)
+	results, err := nm.setup(ctx, env.ID, env.Networks)
 	duration := time.Since(start)
 
 	if err != nil {
@@ -165,26 +252,53 @@ func (nm *cniNetworkManager) ensureNetworkResourcesCNI(ctx context.Context, env
 
 	// Store result
 	nm.cniMu.Lock()
-	nm.cniResults[env.ID] = result
+	nm.cniResults[env.ID] = &cniSetup{
+		results:  results,
+		networks: slices.Clone(env.Networks),
+		confHash: confHash,
+	}
 	nm.cniMu.Unlock()
 
-	inflight.result = result
-	nm.updateEnvironment(env, result)
+	inflight.results = results
+	nm.updateEnvironment(env, results)
 
 	log.G(ctx).WithFields(log.Fields{
 		"vmID":     env.ID,
-		"tap":      result.TAPDevice,
-		"ip":       result.IPAddress,
-		"gateway":  result.Gateway,
+		"tap":      results[0].TAPDevice,
+		"ip":       results[0].IPAddress,
+		"gateway":  results[0].Gateway,
+		"networks": len(results),
 		"duration": duration,
 	}).Info("CNI network configured")
 
 	return nil
 }
 
+// waitInFlight waits for another caller's setup to complete.
+// The wait ends early when ctx is done or the configured setup wait timeout
+// elapses; the in-flight setup itself is not affected.
+func (nm *cniNetworkManager) waitInFlight(ctx context.Context, id string, inflight *setupInFlight) error {
+	var timeout <-chan time.Time
+	if nm.config.SetupWaitTimeout > 0 {
+		timer := time.NewTimer(nm.config.SetupWaitTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-inflight.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timeout:
+		return fmt.Errorf("timed out after %s waiting for in-flight CNI setup of %s: %w",
+			nm.config.SetupWaitTimeout, id, context.DeadlineExceeded)
+	}
+}
+
 // performCNISetup executes the actual CNI plugin chain setup.
 // This is extracted to a separate function to keep the synchronization logic clear.
-func (nm *cniNetworkManager) performCNISetup(ctx context.Context, containerID string) (*cni.CNIResult, error) {
+func (nm *cniNetworkManager) performCNISetup(ctx context.Context, containerID string, networks []string) ([]*cni.CNIResult, error) {
 	// Create network namespace for CNI execution
 	netnsStart := time.Now()
 	netnsPath, err := cni.CreateNetNS(containerID)
@@ -199,9 +313,9 @@ func (nm *cniNetworkManager) performCNISetup(ctx context.Context, containerID st
 		"netnsLatency": netnsLatency,
 	}).Debug("network namespace created")
 
-	// Execute CNI plugin chain
+	// Execute CNI plugin chains
 	cniStart := time.Now()
-	result, err := nm.cniManager.Setup(ctx, containerID, netnsPath)
+	results, err := nm.setupNetworks(ctx, containerID, netnsPath, networks)
 	cniLatency := time.Since(cniStart)
 
 	if err != nil {
@@ -218,7 +332,7 @@ func (nm *cniNetworkManager) performCNISetup(ctx context.Context, containerID st
 				"cniLatency":  cniLatency,
 			}).Warn("CNI setup failed due to resource conflict, attempting cleanup")
 
-			nm.attemptOrphanCleanup(ctx, containerID)
+			nm.attemptOrphanCleanup(ctx, containerID, networks)
 
 			return nil, fmt.Errorf("setup CNI network (resource conflict - orphaned resources from previous run?): %w", err)
 		}
@@ -229,15 +343,108 @@ func (nm *cniNetworkManager) performCNISetup(ctx context.Context, containerID st
 	log.G(ctx).WithFields(log.Fields{
 		"containerID": containerID,
 		"cniLatency":  cniLatency,
-		"tapDevice":   result.TAPDevice,
+		"tapDevice":   results[0].TAPDevice,
 	}).Debug("CNI plugin chain completed")
 
-	return result, nil
+	return results, nil
+}
+
+// networkNames returns the networks to set up, in interface order.
+// The default network is represented by an empty name.
+func networkNames(networks []string) []string {
+	if len(networks) == 0 {
+		return []string{""}
+	}
+	return networks
+}
+
+// setupNetworks runs CNI ADD for each network in netnsPath, assigning
+// interfaces eth0, eth1, ... in order. If a network fails, the networks
+// already set up are torn down before returning the error.
+func (nm *cniNetworkManager) setupNetworks(ctx context.Context, containerID, netnsPath string, networks []string) ([]*cni.CNIResult, error) {
+	names := networkNames(networks)
+	results := make([]*cni.CNIResult, 0, len(names))
+	for i, name := range names {
+		var result *cni.CNIResult
+		err := nm.runCNIOp(ctx, "ADD", func(ctx context.Context) error {
+			var err error
+			result, err = nm.cniNets.SetupNetwork(ctx, containerID, netnsPath, name, cni.IfName(i))
+			return err
+		})
+		if err != nil {
+			rollback := names[:i]
+			if errors.Is(err, cni.ErrCNITimeout) {
+				// The plugin chain may have stopped halfway through
+				rollback = names[:i+1]
+			}
+			if teardownErr := nm.teardownNetworks(ctx, containerID, netnsPath, rollback); teardownErr != nil {
+				log.G(ctx).WithError(teardownErr).WithField("containerID", containerID).
+					Warn("failed to teardown networks after CNI setup failure")
+			}
+			if name != "" {
+				return nil, fmt.Errorf("network %s: %w", name, err)
+			}
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// teardownNetworks runs CNI DEL for each network in netnsPath, in reverse
+// setup order. All networks are attempted; errors are accumulated.
+func (nm *cniNetworkManager) teardownNetworks(ctx context.Context, containerID, netnsPath string, networks []string) error {
+	names := networkNames(networks)
+	var errs []error
+	for i := len(names) - 1; i >= 0; i-- {
+		err := nm.runCNIOp(ctx, "DEL", func(ctx context.Context) error {
+			return nm.cniNets.TeardownNetwork(ctx, containerID, netnsPath, names[i], cni.IfName(i))
+		})
+		if err != nil {
+			if names[i] != "" {
+				err = fmt.Errorf("network %s: %w", names[i], err)
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// operationTimeout returns the timeout applied to each CNI ADD and DEL,
+// or zero if operations are not bounded.
+func (nm *cniNetworkManager) operationTimeout() time.Duration {
+	switch {
+	case nm.config.OperationTimeout == 0:
+		return DefaultOperationTimeout
+	case nm.config.OperationTimeout < 0:
+		return 0
+	}
+	return nm.config.OperationTimeout
+}
+
+// runCNIOp runs a CNI ADD or DEL bounded by the operation timeout.
+// Exceeding the timeout returns an error wrapping cni.ErrCNITimeout;
+// cancellation of ctx itself is returned unchanged.
+func (nm *cniNetworkManager) runCNIOp(ctx context.Context, op string, fn func(ctx context.Context) error) error {
+	timeout := nm.operationTimeout()
+	if timeout == 0 {
+		return fn(ctx)
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fn(opCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(opCtx.Err(), context.DeadlineExceeded) {
+		nm.metrics.RecordCNITimeout()
+		return fmt.Errorf("CNI %s after %s: %w: %w", op, timeout, cni.ErrCNITimeout, err)
+	}
+	return err
 }
 
 // attemptOrphanCleanup tries to clean up orphaned CNI resources from a previous run.
 // Uses a unique temporary netns to avoid racing with other processes.
-func (nm *cniNetworkManager) attemptOrphanCleanup(ctx context.Context, containerID string) {
+func (nm *cniNetworkManager) attemptOrphanCleanup(ctx context.Context, containerID string, networks []string) {
 	cleanupID := fmt.Sprintf("%s-cleanup-%d", containerID, time.Now().UnixNano())
 	cleanupNetns, err := cni.CreateNetNS(cleanupID)
 	if err != nil {
@@ -246,7 +453,7 @@ func (nm *cniNetworkManager) attemptOrphanCleanup(ctx context.Context, container
 		return
 	}
 
-	if teardownErr := nm.cniManager.Teardown(ctx, containerID, cleanupNetns); teardownErr != nil {
+	if teardownErr := nm.teardownNetworks(ctx, containerID, cleanupNetns, networks); teardownErr != nil {
 		log.G(ctx).WithError(teardownErr).WithField("containerID", containerID).
 			Warn("failed to teardown orphaned CNI resources")
 	}
@@ -257,14 +464,91 @@ func (nm *cniNetworkManager) attemptOrphanCleanup(ctx context.Context, container
 	}
 }
 
-// updateEnvironment updates the environment with network information from a CNI result.
-func (nm *cniNetworkManager) updateEnvironment(env *Environment, result *cni.CNIResult) {
-	env.NetworkInfo = &NetworkInfo{
-		TapName: result.TAPDevice,
-		MAC:     result.TAPMAC,
-		IP:      result.IPAddress,
-		Netmask: result.Netmask,
-		Gateway: result.Gateway,
+// reconcileOrphans reclaims the CNI resources of containers that still hold
+// IPAM reservations but are neither in running nor known to this manager,
+// such as containers whose shim was killed before teardown. The network
+// directories holding a container's reservations name the networks to tear
+// down. Returns the number of containers reclaimed.
+func (nm *cniNetworkManager) reconcileOrphans(ctx context.Context, running []string) (int, error) {
+	known := make(map[string]struct{}, len(running))
+	for _, id := range running {
+		known[id] = struct{}{}
+	}
+	nm.cniMu.RLock()
+	for id := range nm.cniResults {
+		known[id] = struct{}{}
+	}
+	nm.cniMu.RUnlock()
+	nm.inflightMu.Lock()
+	for id := range nm.inFlight {
+		known[id] = struct{}{}
+	}
+	nm.inflightMu.Unlock()
+
+	orphans := make(map[string][]string)
+	for _, r := range nm.scanIPAMReservations(ctx, func(owner string) bool {
+		_, ok := known[owner]
+		return owner != "" && !ok
+	}) {
+		if !slices.Contains(orphans[r.owner], r.network) {
+			orphans[r.owner] = append(orphans[r.owner], r.network)
+		}
+	}
+
+	var (
+		reclaimed int
+		errs      []error
+	)
+	for _, id := range slices.Sorted(maps.Keys(orphans)) {
+		networks := orphans[id]
+		slices.Sort(networks)
+		if err := nm.reclaim(ctx, id, networks); err != nil {
+			errs = append(errs, fmt.Errorf("reclaim orphaned container %s: %w", id, err))
+			continue
+		}
+		reclaimed++
+		nm.metrics.RecordOrphanReclaimed()
+		log.G(ctx).WithFields(log.Fields{
+			"vmID":     id,
+			"networks": networks,
+		}).Info("Reclaimed orphaned network resources")
+	}
+	return reclaimed, errors.Join(errs...)
+}
+
+// reclaimOrphan tears down an orphaned container's networks and removes any
+// reservation the plugins left behind.
+func (nm *cniNetworkManager) reclaimOrphan(ctx context.Context, containerID string, networks []string) error {
+	nm.attemptOrphanCleanup(ctx, containerID, networks)
+	return nm.remediateIPAMLeaks(ctx, containerID)
+}
+
+// updateEnvironment updates the environment with network information from the
+// CNI results, one per network.
+func (nm *cniNetworkManager) updateEnvironment(env *Environment, results []*cni.CNIResult) {
+	env.NetworkInfos = make([]*NetworkInfo, 0, len(results))
+	for _, result := range results {
+		var routes []Route
+		for _, r := range result.Routes {
+			routes = append(routes, Route{Dst: r.Dst, GW: r.GW})
+		}
+		env.NetworkInfos = append(env.NetworkInfos, &NetworkInfo{
+			TapName: result.TAPDevice,
+			MAC:     result.TAPMAC,
+			IP:      result.IPAddress,
+			Netmask: result.Netmask,
+			Gateway: result.Gateway,
+			MTU:     result.MTU,
+			Routes:  routes,
+
+			IPv6:          result.IPv6Address,
+			IPv6PrefixLen: result.IPv6PrefixLen,
+			IPv6Gateway:   result.IPv6Gateway,
+		})
+	}
+	env.NetworkInfo = nil
+	if len(env.NetworkInfos) > 0 {
+		env.NetworkInfo = env.NetworkInfos[0]
 	}
 }
 
@@ -306,7 +590,7 @@ func (nm *cniNetworkManager) releaseNetworkResourcesCNI(ctx context.Context, env
 
 	// Perform actual teardown
 	start := time.Now()
-	inflight.result = nm.performCNITeardown(ctx, env)
+	inflight.result = nm.teardown(ctx, env)
 	duration := time.Since(start)
 
 	nm.metrics.RecordTeardown(!inflight.result.HasError(), duration)
@@ -321,10 +605,13 @@ func (nm *cniNetworkManager) performCNITeardown(ctx context.Context, env *Enviro
 
 	// Get CNI result for this VM
 	nm.cniMu.RLock()
-	cniResult, exists := nm.cniResults[env.ID]
+	entry, exists := nm.cniResults[env.ID]
 	nm.cniMu.RUnlock()
 
-	if !exists {
+	networks := env.Networks
+	if exists {
+		networks = entry.networks
+	} else {
 		log.G(ctx).WithField("vmID", env.ID).
 			Warn("No CNI result found for VM, attempting cleanup anyway")
 		// Even if we don't have the result in memory, try to clean up
@@ -352,9 +639,9 @@ func (nm *cniNetworkManager) performCNITeardown(ctx context.Context, env *Enviro
 		}
 	}
 
-	// Execute CNI DEL operation
+	// Execute CNI DEL operation for every network
 	// This will clean up veth pairs, IP allocations, firewall rules, etc.
-	if err := nm.cniManager.Teardown(ctx, env.ID, netnsPath); err != nil {
+	if err := nm.teardownNetworks(ctx, env.ID, netnsPath, networks); err != nil {
 		if netnsPath == "" {
 			// Expected to have some errors without netns, but IPAM cleanup might still work
 			log.G(ctx).WithError(err).WithField("vmID", env.ID).
@@ -370,12 +657,7 @@ func (nm *cniNetworkManager) performCNITeardown(ctx context.Context, env *Enviro
 	}
 
 	// Verify IPAM cleanup
-	if verifyErr := nm.verifyIPAMCleanup(ctx, env.ID); verifyErr != nil {
-		log.G(ctx).WithError(verifyErr).WithField("vmID", env.ID).
-			Warn("IPAM cleanup verification failed - IP may be leaked")
-		result.IPAMVerify = verifyErr
-		nm.metrics.RecordIPAMLeak()
-	}
+	result.IPAMVerify = nm.checkIPAMCleanup(ctx, env.ID)
 
 	// Clean up netns (whether it's the original or temporary)
 	if err := cni.DeleteNetNS(env.ID); err != nil {
@@ -394,7 +676,7 @@ func (nm *cniNetworkManager) performCNITeardown(ctx context.Context, env *Enviro
 	if exists {
 		fields := log.Fields{
 			"vmID": env.ID,
-			"tap":  cniResult.TAPDevice,
+			"tap":  entry.results[0].TAPDevice,
 		}
 		if err := result.Err(); err != nil {
 			log.G(ctx).WithFields(fields).WithError(err).
@@ -413,25 +695,48 @@ func (nm *cniNetworkManager) performCNITeardown(ctx context.Context, env *Enviro
 
 	// Clear environment network info
 	env.NetworkInfo = nil
+	env.NetworkInfos = nil
 
 	return result
 }
 
-// verifyIPAMCleanup checks if the IP allocation was properly released.
-// Returns an error if the container ID still has an allocated IP.
-func (nm *cniNetworkManager) verifyIPAMCleanup(ctx context.Context, containerID string) error {
+// ipamReservation is a host-local IPAM reservation file.
+type ipamReservation struct {
+	network string
+	ip      string
+	path    string
+	owner   string // container ID recorded in the file
+}
+
+// ipamReservationOwner returns the container ID recorded in a host-local
+// reservation file. The file holds the container ID on its first line,
+// followed by the interface name on newer plugin versions.
+func ipamReservationOwner(content []byte) string {
+	first, _, _ := strings.Cut(string(content), "\n")
+	return strings.TrimSpace(first)
+}
+
+// findIPAMReservations returns the reservation files still owned by containerID.
+// Unreadable directories are skipped: verification is best-effort.
+func (nm *cniNetworkManager) findIPAMReservations(ctx context.Context, containerID string) []ipamReservation {
+	return nm.scanIPAMReservations(ctx, func(owner string) bool { return owner == containerID })
+}
+
+// scanIPAMReservations returns the reservation files whose owner matches.
+// Unreadable directories and files are skipped.
+func (nm *cniNetworkManager) scanIPAMReservations(ctx context.Context, match func(owner string) bool) []ipamReservation {
 	entries, err := os.ReadDir(nm.ipamDir)
 	if err != nil {
-		if os.IsNotExist(err) {
-			// No IPAM state directory - nothing to verify
-			return nil
+		if !os.IsNotExist(err) {
+			// Can't read directory - log but don't fail
+			log.G(ctx).WithError(err).WithField("ipamDir", nm.ipamDir).
+				Debug("Could not read IPAM directory for verification")
 		}
-		// Can't read directory - log but don't fail
-		log.G(ctx).WithError(err).WithField("ipamDir", nm.ipamDir).
-			Debug("Could not read IPAM directory for verification")
+		// No IPAM state directory - nothing to verify
 		return nil
 	}
 
+	var found []ipamReservation
 	for _, netDir := range entries {
 		if !netDir.IsDir() {
 			continue
@@ -445,20 +750,105 @@ func (nm *cniNetworkManager) verifyIPAMCleanup(ctx context.Context, containerID
 			if ipFile.IsDir() {
 				continue
 			}
-			// Skip special files like "last_reserved_ip"
-			if strings.HasPrefix(ipFile.Name(), "last_") || strings.HasPrefix(ipFile.Name(), ".") {
+			// Skip special files like "last_reserved_ip" and "lock"
+			name := ipFile.Name()
+			if strings.HasPrefix(name, "last_") || strings.HasPrefix(name, ".") || name == "lock" {
 				continue
 			}
-			content, err := os.ReadFile(filepath.Join(netPath, ipFile.Name()))
+			path := filepath.Join(netPath, name)
+			content, err := os.ReadFile(path)
 			if err != nil {
 				continue
 			}
-			// host-local IPAM stores container ID in the file
-			if strings.TrimSpace(string(content)) == containerID {
-				return fmt.Errorf("%w: IP %s in network %s still allocated to %s",
-					cni.ErrIPAMLeak, ipFile.Name(), netDir.Name(), containerID)
+			if owner := ipamReservationOwner(content); match(owner) {
+				found = append(found, ipamReservation{network: netDir.Name(), ip: name, path: path, owner: owner})
 			}
 		}
 	}
+	return found
+}
+
+// verifyIPAMCleanup checks if the IP allocation was properly released.
+// Returns an error if the container ID still has an allocated IP.
+func (nm *cniNetworkManager) verifyIPAMCleanup(ctx context.Context, containerID string) error {
+	leaks := nm.findIPAMReservations(ctx, containerID)
+	if len(leaks) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: IP %s in network %s still allocated to %s",
+		cni.ErrIPAMLeak, leaks[0].ip, leaks[0].network, containerID)
+}
+
+// checkIPAMCleanup verifies the IP allocation was released and applies the
+// configured IPAMLeakPolicy to any leak. Returns nil if no reservation is
+// left for the container.
+func (nm *cniNetworkManager) checkIPAMCleanup(ctx context.Context, containerID string) error {
+	verifyErr := nm.verifyIPAMCleanup(ctx, containerID)
+	if verifyErr == nil {
+		return nil
+	}
+	log.G(ctx).WithError(verifyErr).WithField("vmID", containerID).
+		Warn("IPAM cleanup verification failed - IP may be leaked")
+	nm.metrics.RecordIPAMLeak()
+
+	if nm.config.IPAMLeakPolicy != IPAMLeakRemediateMatching {
+		return verifyErr
+	}
+	if err := nm.remediateIPAMLeaks(ctx, containerID); err != nil {
+		return errors.Join(verifyErr, err)
+	}
 	return nil
 }
+
+// remediateIPAMLeaks removes the reservation files still owned by the released
+// container. Each file is re-read under the host-local network lock right before
+// removal, so an IP reallocated to another container in the meantime is left alone.
+// Returns an error if any reservation could not be removed.
+func (nm *cniNetworkManager) remediateIPAMLeaks(ctx context.Context, containerID string) error {
+	var errs []error
+	for _, leak := range nm.findIPAMReservations(ctx, containerID) {
+		removed, err := removeIPAMReservation(leak, containerID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("remove reservation %s in network %s: %w", leak.ip, leak.network, err))
+			continue
+		}
+		if removed {
+			log.G(ctx).WithFields(log.Fields{
+				"vmID":    containerID,
+				"network": leak.network,
+				"ip":      leak.ip,
+			}).Warn("Removed leaked IPAM reservation")
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// removeIPAMReservation removes a reservation file if it still belongs to
+// containerID. Reports whether the file was removed.
+func removeIPAMReservation(leak ipamReservation, containerID string) (bool, error) {
+	// host-local serializes allocations with flock on <network>/lock
+	lockPath := filepath.Join(filepath.Dir(leak.path), "lock")
+	if lock, err := os.Open(lockPath); err == nil {
+		defer lock.Close()
+		if err := unix.Flock(int(lock.Fd()), unix.LOCK_EX); err != nil {
+			return false, fmt.Errorf("lock %s: %w", lockPath, err)
+		}
+		defer func() { _ = unix.Flock(int(lock.Fd()), unix.LOCK_UN) }()
+	}
+
+	content, err := os.ReadFile(leak.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if ipamReservationOwner(content) != containerID {
+		// Released and reallocated since the scan
+		return false, nil
+	}
+	if err := os.Remove(leak.path); err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	return true, nil
+}
@@ -5,9 +5,14 @@ package network
 import (
 	"context"
 	"errors"
+	"net"
 	"os"
 	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/spin-stack/spinbox/internal/host/network/cni"
 	"github.com/stretchr/testify/assert"
@@ -147,6 +152,155 @@ func TestVerifyIPAMCleanup(t *testing.T) {
 	})
 }
 
+func TestCheckIPAMCleanupPolicy(t *testing.T) {
+	// writeReservation creates a host-local style reservation file
+	writeReservation := func(t *testing.T, ipamDir, ip, content string) string {
+		t.Helper()
+		networkDir := filepath.Join(ipamDir, "test-network")
+		require.NoError(t, os.MkdirAll(networkDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(networkDir, "lock"), nil, 0644))
+		path := filepath.Join(networkDir, ip)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	t.Run("report only leaves reservation", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		leaked := writeReservation(t, tmpDir, "10.88.0.5", "released-id\r\neth0")
+
+		nm := &cniNetworkManager{
+			config:  NetworkConfig{IPAMLeakPolicy: IPAMLeakReportOnly},
+			ipamDir: tmpDir,
+			metrics: &Metrics{},
+		}
+
+		err := nm.checkIPAMCleanup(context.Background(), "released-id")
+		require.ErrorIs(t, err, cni.ErrIPAMLeak)
+		assert.FileExists(t, leaked)
+		assert.Equal(t, int64(1), nm.metrics.IPAMLeaksDetected.Load())
+	})
+
+	t.Run("remediate matching removes only released container", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		leaked := writeReservation(t, tmpDir, "10.88.0.5", "released-id\r\neth0")
+		other := writeReservation(t, tmpDir, "10.88.0.6", "other-id\r\neth0")
+		// Prefix of the released ID must not match
+		prefixed := writeReservation(t, tmpDir, "10.88.0.7", "released-id-2")
+
+		nm := &cniNetworkManager{
+			config:  NetworkConfig{IPAMLeakPolicy: IPAMLeakRemediateMatching},
+			ipamDir: tmpDir,
+			metrics: &Metrics{},
+		}
+
+		err := nm.checkIPAMCleanup(context.Background(), "released-id")
+		require.NoError(t, err)
+		assert.NoFileExists(t, leaked)
+		assert.FileExists(t, other)
+		assert.FileExists(t, prefixed)
+		assert.FileExists(t, filepath.Join(tmpDir, "test-network", "lock"))
+		// The leak is still counted
+		assert.Equal(t, int64(1), nm.metrics.IPAMLeaksDetected.Load())
+
+		// Nothing left to verify
+		assert.NoError(t, nm.verifyIPAMCleanup(context.Background(), "released-id"))
+	})
+
+	t.Run("reallocated reservation is left alone", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		path := writeReservation(t, tmpDir, "10.88.0.5", "new-owner")
+
+		removed, err := removeIPAMReservation(ipamReservation{
+			network: "test-network",
+			ip:      "10.88.0.5",
+			path:    path,
+		}, "released-id")
+		require.NoError(t, err)
+		assert.False(t, removed)
+		assert.FileExists(t, path)
+	})
+}
+
+func TestReconcileOrphans(t *testing.T) {
+	writeReservation := func(t *testing.T, ipamDir, network, ip, owner string) string {
+		t.Helper()
+		networkDir := filepath.Join(ipamDir, network)
+		require.NoError(t, os.MkdirAll(networkDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(networkDir, "lock"), nil, 0644))
+		path := filepath.Join(networkDir, ip)
+		require.NoError(t, os.WriteFile(path, []byte(owner+"\neth0"), 0644))
+		return path
+	}
+
+	t.Run("reclaims only containers that are not running", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeReservation(t, tmpDir, "net-a", "10.88.0.5", "stale-id")
+		writeReservation(t, tmpDir, "net-b", "10.89.0.5", "stale-id")
+		writeReservation(t, tmpDir, "net-a", "10.88.0.6", "running-id")
+		writeReservation(t, tmpDir, "net-a", "10.88.0.7", "cached-id")
+
+		nm := &cniNetworkManager{
+			ipamDir:    tmpDir,
+			metrics:    &Metrics{},
+			cniResults: map[string]*cniSetup{"cached-id": {}},
+			inFlight:   make(map[string]*setupInFlight),
+		}
+		reclaimed := map[string][]string{}
+		nm.reclaim = func(_ context.Context, id string, networks []string) error {
+			reclaimed[id] = networks
+			return nil
+		}
+
+		n, err := nm.reconcileOrphans(context.Background(), []string{"running-id"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, map[string][]string{"stale-id": {"net-a", "net-b"}}, reclaimed)
+		assert.Equal(t, int64(1), nm.metrics.OrphansReclaimed.Load())
+	})
+
+	t.Run("removes leftover reservations", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		stale := writeReservation(t, tmpDir, "net-a", "10.88.0.5", "stale-id")
+		running := writeReservation(t, tmpDir, "net-a", "10.88.0.6", "running-id")
+
+		nm := &cniNetworkManager{
+			ipamDir:  tmpDir,
+			metrics:  &Metrics{},
+			inFlight: make(map[string]*setupInFlight),
+		}
+		// CNI DEL is unavailable; the reservation is removed regardless
+		nm.reclaim = func(ctx context.Context, id string, _ []string) error {
+			return nm.remediateIPAMLeaks(ctx, id)
+		}
+
+		n, err := nm.reconcileOrphans(context.Background(), []string{"running-id"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.NoFileExists(t, stale)
+		assert.FileExists(t, running)
+	})
+
+	t.Run("failed reclaim is reported and not counted", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeReservation(t, tmpDir, "net-a", "10.88.0.5", "stale-id")
+
+		nm := &cniNetworkManager{
+			ipamDir:  tmpDir,
+			metrics:  &Metrics{},
+			inFlight: make(map[string]*setupInFlight),
+		}
+		nm.reclaim = func(context.Context, string, []string) error {
+			return errors.New("teardown failed")
+		}
+
+		n, err := nm.reconcileOrphans(context.Background(), nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "stale-id")
+		assert.Zero(t, n)
+		assert.Zero(t, nm.metrics.OrphansReclaimed.Load())
+	})
+}
+
 func TestCleanupResultErr(t *testing.T) {
 	result := &CleanupResult{
 		CNITeardown: errors.New("test"),
@@ -170,3 +324,429 @@ func TestIPAMLeakErrorWrapping(t *testing.T) {
 
 	assert.ErrorIs(t, result.IPAMVerify, cni.ErrIPAMLeak)
 }
+
+// newTestCNIManager returns a manager backed by a config in a temp dir whose
+// CNI ADD/DEL steps are counted instead of executed.
+func newTestCNIManager(t *testing.T) (nm *cniNetworkManager, confFile string, adds, dels *int) {
+	t.Helper()
+
+	confDir := t.TempDir()
+	confFile = filepath.Join(confDir, "10-test.conflist")
+	writeTestConflist(t, confFile, "10.88.0.0/16")
+
+	nm, err := newCNINetworkManager(NetworkConfig{CNIConfDir: confDir, CNIBinDir: t.TempDir()})
+	require.NoError(t, err)
+
+	adds, dels = new(int), new(int)
+	nm.setup = func(_ context.Context, containerID string, _ []string) ([]*cni.CNIResult, error) {
+		*adds++
+		return []*cni.CNIResult{{
+			TAPDevice: "tap-" + containerID,
+			IPAddress: net.ParseIP("10.88.0.5"),
+			Gateway:   net.ParseIP("10.88.0.1"),
+		}}, nil
+	}
+	nm.teardown = func(_ context.Context, env *Environment) CleanupResult {
+		*dels++
+		nm.cniMu.Lock()
+		delete(nm.cniResults, env.ID)
+		nm.cniMu.Unlock()
+		env.NetworkInfo = nil
+		return CleanupResult{InMemoryClear: true}
+	}
+	return nm, confFile, adds, dels
+}
+
+func writeTestConflist(t *testing.T, path, subnet string) {
+	t.Helper()
+	conf := `{"cniVersion":"1.0.0","name":"test","plugins":[{"type":"bridge","ipam":{"type":"host-local","subnet":"` + subnet + `"}}]}`
+	require.NoError(t, os.WriteFile(path, []byte(conf), 0600))
+}
+
+func TestEnsureNetworkResources_CachedSetup(t *testing.T) {
+	ctx := context.Background()
+	nm, confFile, adds, dels := newTestCNIManager(t)
+
+	env := &Environment{ID: "test-container"}
+	require.NoError(t, nm.EnsureNetworkResources(ctx, env))
+	require.NoError(t, nm.EnsureNetworkResources(ctx, env))
+	assert.Equal(t, 1, *adds, "CNI ADD should run once for an unchanged config")
+	assert.Equal(t, 0, *dels)
+	require.NotNil(t, env.NetworkInfo)
+	assert.Equal(t, "tap-test-container", env.NetworkInfo.TapName)
+
+	// Changing the config invalidates the cached setup.
+	writeTestConflist(t, confFile, "10.89.0.0/16")
+	require.NoError(t, nm.EnsureNetworkResources(ctx, env))
+	assert.Equal(t, 2, *adds, "CNI ADD should run again after a config change")
+	assert.Equal(t, 1, *dels, "stale setup should be torn down before re-running ADD")
+	assert.Equal(t, nm.currentConfHash(ctx), nm.loadedConfHash, "config should be reloaded")
+
+	require.NoError(t, nm.EnsureNetworkResources(ctx, env))
+	assert.Equal(t, 2, *adds)
+}
+
+func TestEnsureNetworkResources_ReleaseInvalidatesCache(t *testing.T) {
+	ctx := context.Background()
+	nm, _, adds, _ := newTestCNIManager(t)
+
+	env := &Environment{ID: "test-container"}
+	require.NoError(t, nm.EnsureNetworkResources(ctx, env))
+	require.NoError(t, nm.ReleaseNetworkResources(ctx, env))
+	assert.Nil(t, env.NetworkInfo)
+
+	require.NoError(t, nm.EnsureNetworkResources(ctx, env))
+	assert.Equal(t, 2, *adds, "CNI ADD should run again after release")
+}
+
+func TestEnsureNetworkResources_WaitersHonorContext(t *testing.T) {
+	nm, _, _, _ := newTestCNIManager(t)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	nm.setup = func(_ context.Context, containerID string, _ []string) ([]*cni.CNIResult, error) {
+		close(started)
+		<-release
+		return []*cni.CNIResult{{TAPDevice: "tap-" + containerID}}, nil
+	}
+
+	leaderErr := make(chan error, 1)
+	go func() {
+		leaderErr <- nm.EnsureNetworkResources(context.Background(), &Environment{ID: "slow"})
+	}()
+	<-started
+
+	// Each waiter gives up at its own deadline while the leader is still busy.
+	const waiters = 3
+	errs := make(chan error, waiters)
+	for i := range waiters {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(i+1)*10*time.Millisecond)
+			defer cancel()
+			errs <- nm.EnsureNetworkResources(ctx, &Environment{ID: "slow"})
+		}()
+	}
+	for range waiters {
+		assert.ErrorIs(t, <-errs, context.DeadlineExceeded)
+	}
+
+	// The leader still completes its work.
+	close(release)
+	require.NoError(t, <-leaderErr)
+	env := &Environment{ID: "slow"}
+	require.NoError(t, nm.EnsureNetworkResources(context.Background(), env))
+	assert.Equal(t, "tap-slow", env.NetworkInfo.TapName)
+}
+
+func TestEnsureNetworkResources_SetupWaitTimeout(t *testing.T) {
+	nm, _, _, _ := newTestCNIManager(t)
+	nm.config.SetupWaitTimeout = 20 * time.Millisecond
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	nm.setup = func(context.Context, string, []string) ([]*cni.CNIResult, error) {
+		close(started)
+		<-release
+		return []*cni.CNIResult{{}}, nil
+	}
+
+	go func() { _ = nm.EnsureNetworkResources(context.Background(), &Environment{ID: "hung"}) }()
+	<-started
+
+	err := nm.EnsureNetworkResources(context.Background(), &Environment{ID: "hung"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "in-flight CNI setup")
+}
+
+func TestEnsureNetworkResources_FailingLeader(t *testing.T) {
+	nm, _, _, _ := newTestCNIManager(t)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	setupErr := errors.New("bridge plugin failed")
+	nm.setup = func(context.Context, string, []string) ([]*cni.CNIResult, error) {
+		close(started)
+		<-release
+		return nil, setupErr
+	}
+
+	leaderErr := make(chan error, 1)
+	go func() {
+		leaderErr <- nm.EnsureNetworkResources(context.Background(), &Environment{ID: "failing"})
+	}()
+	<-started
+
+	waiterErr := make(chan error, 1)
+	go func() {
+		waiterErr <- nm.EnsureNetworkResources(context.Background(), &Environment{ID: "failing"})
+	}()
+
+	// Give the waiter time to block on the leader before it fails.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	assert.ErrorIs(t, <-leaderErr, setupErr)
+	assert.ErrorIs(t, <-waiterErr, setupErr)
+}
+
+func TestEnsureNetworkResources_PanickingLeader(t *testing.T) {
+	nm, _, _, _ := newTestCNIManager(t)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls atomic.Int32
+	nm.setup = func(context.Context, string, []string) ([]*cni.CNIResult, error) {
+		if calls.Add(1) > 1 {
+			return nil, errors.New("waiter ran setup instead of waiting")
+		}
+		close(started)
+		<-release
+		panic("plugin crashed")
+	}
+
+	leaderDone := make(chan any, 1)
+	go func() {
+		defer func() { leaderDone <- recover() }()
+		_ = nm.EnsureNetworkResources(context.Background(), &Environment{ID: "panicky"})
+	}()
+	<-started
+
+	waiterErr := make(chan error, 1)
+	go func() {
+		waiterErr <- nm.EnsureNetworkResources(context.Background(), &Environment{ID: "panicky"})
+	}()
+	// Give the waiter time to block on the leader before it panics.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	assert.Equal(t, "plugin crashed", <-leaderDone)
+	err := <-waiterErr
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "did not complete")
+
+	// The in-flight entry is gone, so the next caller retries setup.
+	nm.setup = func(_ context.Context, containerID string, _ []string) ([]*cni.CNIResult, error) {
+		return []*cni.CNIResult{{TAPDevice: "tap-" + containerID}}, nil
+	}
+	env := &Environment{ID: "panicky"}
+	require.NoError(t, nm.EnsureNetworkResources(context.Background(), env))
+	assert.Equal(t, "tap-panicky", env.NetworkInfo.TapName)
+}
+
+// fakeCNINetworks records CNI ADD/DEL calls per network.
+type fakeCNINetworks struct {
+	mu      sync.Mutex
+	calls   []string
+	failOn  string // network whose ADD fails
+	lastOct int
+}
+
+func (f *fakeCNINetworks) SetupNetwork(_ context.Context, vmID, _, network, ifName string) (*cni.CNIResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "ADD "+network+" "+ifName)
+	if f.failOn != "" && network == f.failOn {
+		return nil, errors.New("plugin failed")
+	}
+	f.lastOct++
+	return &cni.CNIResult{
+		TAPDevice: "tap-" + network,
+		TAPMAC:    "02:00:00:00:00:0" + strconv.Itoa(f.lastOct),
+		IPAddress: net.ParseIP("10.0." + strconv.Itoa(f.lastOct) + ".2"),
+		Gateway:   net.ParseIP("10.0." + strconv.Itoa(f.lastOct) + ".1"),
+	}, nil
+}
+
+func (f *fakeCNINetworks) TeardownNetwork(_ context.Context, vmID, _, network, ifName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "DEL "+network+" "+ifName)
+	return nil
+}
+
+func TestMultipleNetworks(t *testing.T) {
+	ctx := context.Background()
+
+	newManager := func(t *testing.T) (*cniNetworkManager, *fakeCNINetworks) {
+		nm, _, _, _ := newTestCNIManager(t)
+		fake := &fakeCNINetworks{}
+		nm.cniNets = fake
+		nm.setup = func(ctx context.Context, containerID string, networks []string) ([]*cni.CNIResult, error) {
+			return nm.setupNetworks(ctx, containerID, "/fake/netns", networks)
+		}
+		nm.teardown = func(ctx context.Context, env *Environment) CleanupResult {
+			nm.cniMu.Lock()
+			entry := nm.cniResults[env.ID]
+			delete(nm.cniResults, env.ID)
+			nm.cniMu.Unlock()
+			err := nm.teardownNetworks(ctx, env.ID, "/fake/netns", entry.networks)
+			env.NetworkInfo, env.NetworkInfos = nil, nil
+			return CleanupResult{CNITeardown: err, InMemoryClear: true}
+		}
+		return nm, fake
+	}
+
+	t.Run("sets up and tears down every network", func(t *testing.T) {
+		nm, fake := newManager(t)
+
+		env := &Environment{ID: "multi", Networks: []string{"mgmt", "data"}}
+		require.NoError(t, nm.EnsureNetworkResources(ctx, env))
+
+		require.Len(t, env.NetworkInfos, 2)
+		assert.Same(t, env.NetworkInfos[0], env.NetworkInfo)
+		assert.Equal(t, "tap-mgmt", env.NetworkInfos[0].TapName)
+		assert.Equal(t, "tap-data", env.NetworkInfos[1].TapName)
+		assert.Equal(t, "10.0.2.2", env.NetworkInfos[1].IP.String())
+
+		require.NoError(t, nm.ReleaseNetworkResources(ctx, env))
+		assert.Equal(t, []string{
+			"ADD mgmt eth0",
+			"ADD data eth1",
+			"DEL data eth1",
+			"DEL mgmt eth0",
+		}, fake.calls)
+		assert.Nil(t, env.NetworkInfos)
+	})
+
+	t.Run("default network when none requested", func(t *testing.T) {
+		nm, fake := newManager(t)
+
+		env := &Environment{ID: "single"}
+		require.NoError(t, nm.EnsureNetworkResources(ctx, env))
+		require.Len(t, env.NetworkInfos, 1)
+		require.NoError(t, nm.ReleaseNetworkResources(ctx, env))
+		assert.Equal(t, []string{"ADD  eth0", "DEL  eth0"}, fake.calls)
+	})
+
+	t.Run("failed network rolls back earlier networks", func(t *testing.T) {
+		nm, fake := newManager(t)
+		fake.failOn = "data"
+
+		env := &Environment{ID: "partial", Networks: []string{"mgmt", "data"}}
+		err := nm.EnsureNetworkResources(ctx, env)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "network data")
+		assert.Equal(t, []string{"ADD mgmt eth0", "ADD data eth1", "DEL mgmt eth0"}, fake.calls)
+	})
+
+	t.Run("changed networks invalidate cached setup", func(t *testing.T) {
+		nm, fake := newManager(t)
+
+		env := &Environment{ID: "changing", Networks: []string{"mgmt"}}
+		require.NoError(t, nm.EnsureNetworkResources(ctx, env))
+		require.NoError(t, nm.EnsureNetworkResources(ctx, env))
+		assert.Len(t, fake.calls, 1, "unchanged networks should reuse the setup")
+
+		env.Networks = []string{"mgmt", "data"}
+		require.NoError(t, nm.EnsureNetworkResources(ctx, env))
+		assert.Equal(t, []string{
+			"ADD mgmt eth0",
+			"DEL mgmt eth0",
+			"ADD mgmt eth0",
+			"ADD data eth1",
+		}, fake.calls)
+	})
+
+	t.Run("teardown accumulates errors", func(t *testing.T) {
+		nm, _, _, _ := newTestCNIManager(t)
+		failing := &failingTeardownNetworks{}
+		nm.cniNets = failing
+
+		err := nm.teardownNetworks(ctx, "c", "/fake/netns", []string{"mgmt", "data"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "network mgmt")
+		assert.Contains(t, err.Error(), "network data")
+		assert.Equal(t, 2, failing.calls, "every network should be attempted")
+	})
+}
+
+// failingTeardownNetworks fails every CNI DEL.
+type failingTeardownNetworks struct {
+	calls int
+}
+
+func (f *failingTeardownNetworks) SetupNetwork(context.Context, string, string, string, string) (*cni.CNIResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *failingTeardownNetworks) TeardownNetwork(context.Context, string, string, string, string) error {
+	f.calls++
+	return errors.New("DEL failed")
+}
+
+// hangingCNINetworks blocks CNI ADD of one network until its context is done,
+// like a DHCP IPAM plugin waiting on a dead server.
+type hangingCNINetworks struct {
+	fakeCNINetworks
+	hangOn string
+}
+
+func (h *hangingCNINetworks) SetupNetwork(ctx context.Context, vmID, netns, network, ifName string) (*cni.CNIResult, error) {
+	if network != h.hangOn {
+		return h.fakeCNINetworks.SetupNetwork(ctx, vmID, netns, network, ifName)
+	}
+	h.mu.Lock()
+	h.calls = append(h.calls, "ADD "+network+" "+ifName)
+	h.mu.Unlock()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(10 * time.Second):
+		return nil, errors.New("fake plugin was not cancelled")
+	}
+}
+
+func TestCNIOperationTimeout(t *testing.T) {
+	t.Run("ADD timeout rolls back the hung network", func(t *testing.T) {
+		nm, _, _, _ := newTestCNIManager(t)
+		nm.config.OperationTimeout = 50 * time.Millisecond
+		fake := &hangingCNINetworks{hangOn: "data"}
+		nm.cniNets = fake
+		nm.setup = func(ctx context.Context, containerID string, networks []string) ([]*cni.CNIResult, error) {
+			return nm.setupNetworks(ctx, containerID, "/fake/netns", networks)
+		}
+
+		env := &Environment{ID: "hung", Networks: []string{"mgmt", "data"}}
+		start := time.Now()
+		err := nm.EnsureNetworkResources(context.Background(), env)
+		require.ErrorIs(t, err, cni.ErrCNITimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 5*time.Second)
+
+		assert.Equal(t, []string{
+			"ADD mgmt eth0",
+			"ADD data eth1",
+			"DEL data eth1",
+			"DEL mgmt eth0",
+		}, fake.calls)
+		assert.Equal(t, int64(1), nm.metrics.OperationTimeouts.Load())
+		assert.Equal(t, int64(1), nm.metrics.SetupFailures.Load())
+	})
+
+	t.Run("caller cancellation is not a timeout", func(t *testing.T) {
+		nm, _, _, _ := newTestCNIManager(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := nm.runCNIOp(ctx, "ADD", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		assert.NotErrorIs(t, err, cni.ErrCNITimeout)
+		assert.Zero(t, nm.metrics.OperationTimeouts.Load())
+	})
+
+	t.Run("negative timeout disables the deadline", func(t *testing.T) {
+		nm, _, _, _ := newTestCNIManager(t)
+		nm.config.OperationTimeout = -1
+
+		require.NoError(t, nm.runCNIOp(context.Background(), "DEL", func(ctx context.Context) error {
+			_, ok := ctx.Deadline()
+			assert.False(t, ok)
+			return nil
+		}))
+		assert.Equal(t, DefaultOperationTimeout, (&cniNetworkManager{}).operationTimeout())
+	})
+}
@@ -3,6 +3,13 @@
 package network
 
 import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -21,12 +28,33 @@ type Metrics struct {
 	TeardownSuccesses atomic.Int64
 	TeardownFailures  atomic.Int64
 
+	// CNI ADD/DEL operations that exceeded the operation timeout
+	OperationTimeouts atomic.Int64
+
 	// IPAM metrics
 	IPAMLeaksDetected atomic.Int64
 
+	// Containers whose resources were reclaimed at startup
+	OrphansReclaimed atomic.Int64
+
 	// Timing (nanoseconds, use time.Duration for display)
 	TotalSetupTimeNs    atomic.Int64
 	TotalTeardownTimeNs atomic.Int64
+
+	// Per-plugin timing (protected by pluginMu)
+	pluginMu      sync.Mutex
+	pluginTimings map[PluginTimingKey]*pluginTiming
+}
+
+// PluginTimingKey identifies a CNI plugin operation, e.g. {"bridge", "ADD"}.
+type PluginTimingKey struct {
+	Plugin string
+	Op     string
+}
+
+type pluginTiming struct {
+	count   int64
+	totalNs int64
 }
 
 // RecordSetup records a setup attempt result.
@@ -56,11 +84,39 @@ func (m *Metrics) RecordTeardown(success bool, duration time.Duration) {
 	}
 }
 
+// RecordCNITimeout records a CNI ADD or DEL that timed out.
+func (m *Metrics) RecordCNITimeout() {
+	m.OperationTimeouts.Add(1)
+}
+
+// RecordPluginTiming records the duration of a single CNI plugin invocation.
+func (m *Metrics) RecordPluginTiming(plugin string, op string, d time.Duration) {
+	m.pluginMu.Lock()
+	defer m.pluginMu.Unlock()
+
+	if m.pluginTimings == nil {
+		m.pluginTimings = make(map[PluginTimingKey]*pluginTiming)
+	}
+	key := PluginTimingKey{Plugin: plugin, Op: op}
+	pt, ok := m.pluginTimings[key]
+	if !ok {
+		pt = &pluginTiming{}
+		m.pluginTimings[key] = pt
+	}
+	pt.count++
+	pt.totalNs += int64(d)
+}
+
 // RecordIPAMLeak records a detected IPAM leak.
 func (m *Metrics) RecordIPAMLeak() {
 	m.IPAMLeaksDetected.Add(1)
 }
 
+// RecordOrphanReclaimed records an orphaned container whose resources were reclaimed.
+func (m *Metrics) RecordOrphanReclaimed() {
+	m.OrphansReclaimed.Add(1)
+}
+
 // Reset resets all metrics to zero. Useful for testing.
 func (m *Metrics) Reset() {
 	m.SetupAttempts.Store(0)
@@ -70,9 +126,15 @@ func (m *Metrics) Reset() {
 	m.TeardownAttempts.Store(0)
 	m.TeardownSuccesses.Store(0)
 	m.TeardownFailures.Store(0)
+	m.OperationTimeouts.Store(0)
 	m.IPAMLeaksDetected.Store(0)
+	m.OrphansReclaimed.Store(0)
 	m.TotalSetupTimeNs.Store(0)
 	m.TotalTeardownTimeNs.Store(0)
+
+	m.pluginMu.Lock()
+	m.pluginTimings = nil
+	m.pluginMu.Unlock()
 }
 
 // MetricsSnapshot is a point-in-time copy of metrics values.
@@ -85,9 +147,14 @@ type MetricsSnapshot struct {
 	TeardownAttempts  int64
 	TeardownSuccesses int64
 	TeardownFailures  int64
+	OperationTimeouts int64
 	IPAMLeaksDetected int64
+	OrphansReclaimed  int64
 	AvgSetupTimeMs    float64
 	AvgTeardownTimeMs float64
+
+	// Average duration per plugin operation, nil if no plugin ran
+	PluginAvgTimeMs map[PluginTimingKey]float64
 }
 
 // Snapshot returns a point-in-time copy of metrics.
@@ -103,7 +170,9 @@ func (m *Metrics) Snapshot() MetricsSnapshot {
 		TeardownAttempts:  teardownAttempts,
 		TeardownSuccesses: m.TeardownSuccesses.Load(),
 		TeardownFailures:  m.TeardownFailures.Load(),
+		OperationTimeouts: m.OperationTimeouts.Load(),
 		IPAMLeaksDetected: m.IPAMLeaksDetected.Load(),
+		OrphansReclaimed:  m.OrphansReclaimed.Load(),
 	}
 
 	// Calculate averages
@@ -114,5 +183,76 @@ func (m *Metrics) Snapshot() MetricsSnapshot {
 		snap.AvgTeardownTimeMs = float64(m.TotalTeardownTimeNs.Load()) / float64(teardownAttempts) / 1e6
 	}
 
+	m.pluginMu.Lock()
+	if len(m.pluginTimings) > 0 {
+		snap.PluginAvgTimeMs = make(map[PluginTimingKey]float64, len(m.pluginTimings))
+		for key, pt := range m.pluginTimings {
+			snap.PluginAvgTimeMs[key] = float64(pt.totalNs) / float64(pt.count) / 1e6
+		}
+	}
+	m.pluginMu.Unlock()
+
 	return snap
 }
+
+// promMetric is a single metric in the Prometheus text exposition.
+type promMetric struct {
+	name  string
+	help  string
+	typ   string // "counter" or "gauge"
+	value float64
+}
+
+// promLabelEscaper escapes a label value for the Prometheus text exposition
+// format, which only escapes backslash, double quote and newline.
+var promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteProm writes the metrics in the Prometheus text exposition format.
+// Metric names are stable so operators can scrape the shim without a
+// Prometheus client dependency.
+func (m *Metrics) WriteProm(w io.Writer) error {
+	snap := m.Snapshot()
+
+	metrics := []promMetric{
+		{"spinbox_cni_setup_total", "Total CNI setup attempts.", "counter", float64(snap.SetupAttempts)},
+		{"spinbox_cni_setup_successes_total", "Total successful CNI setups.", "counter", float64(snap.SetupSuccesses)},
+		{"spinbox_cni_setup_failures_total", "Total failed CNI setups.", "counter", float64(snap.SetupFailures)},
+		{"spinbox_cni_resource_conflicts_total", "Total CNI setups that failed due to a resource conflict.", "counter", float64(snap.ResourceConflicts)},
+		{"spinbox_cni_teardown_total", "Total CNI teardown attempts.", "counter", float64(snap.TeardownAttempts)},
+		{"spinbox_cni_teardown_successes_total", "Total successful CNI teardowns.", "counter", float64(snap.TeardownSuccesses)},
+		{"spinbox_cni_teardown_failures_total", "Total failed CNI teardowns.", "counter", float64(snap.TeardownFailures)},
+		{"spinbox_cni_operation_timeouts_total", "Total CNI ADD/DEL operations that exceeded the operation timeout.", "counter", float64(snap.OperationTimeouts)},
+		{"spinbox_cni_ipam_leaks_total", "Total IP allocations found leaked after teardown.", "counter", float64(snap.IPAMLeaksDetected)},
+		{"spinbox_cni_orphans_reclaimed_total", "Total orphaned containers whose network resources were reclaimed at startup.", "counter", float64(snap.OrphansReclaimed)},
+		{"spinbox_cni_setup_duration_avg_seconds", "Average CNI setup duration in seconds.", "gauge", snap.AvgSetupTimeMs / 1e3},
+		{"spinbox_cni_teardown_duration_avg_seconds", "Average CNI teardown duration in seconds.", "gauge", snap.AvgTeardownTimeMs / 1e3},
+	}
+
+	for _, pm := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %g\n",
+			pm.name, pm.help, pm.name, pm.typ, pm.name, pm.value); err != nil {
+			return fmt.Errorf("write metric %s: %w", pm.name, err)
+		}
+	}
+
+	if len(snap.PluginAvgTimeMs) == 0 {
+		return nil
+	}
+
+	const pluginMetric = "spinbox_cni_plugin_duration_avg_seconds"
+	if _, err := fmt.Fprintf(w, "# HELP %s Average CNI plugin invocation duration in seconds.\n# TYPE %s gauge\n",
+		pluginMetric, pluginMetric); err != nil {
+		return fmt.Errorf("write metric %s: %w", pluginMetric, err)
+	}
+	keys := slices.SortedFunc(maps.Keys(snap.PluginAvgTimeMs), func(a, b PluginTimingKey) int {
+		return cmp.Or(cmp.Compare(a.Plugin, b.Plugin), cmp.Compare(a.Op, b.Op))
+	})
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(w, "%s{plugin=\"%s\",op=\"%s\"} %g\n",
+			pluginMetric, promLabelEscaper.Replace(key.Plugin), promLabelEscaper.Replace(key.Op),
+			snap.PluginAvgTimeMs[key]/1e3); err != nil {
+			return fmt.Errorf("write metric %s: %w", pluginMetric, err)
+		}
+	}
+	return nil
+}
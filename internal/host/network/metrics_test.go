@@ -3,10 +3,13 @@
 package network
 
 import (
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMetricsRecording(t *testing.T) {
@@ -81,3 +84,96 @@ func TestMetricsReset(t *testing.T) {
 	assert.Equal(t, int64(0), m.TeardownAttempts.Load())
 	assert.Equal(t, int64(0), m.IPAMLeaksDetected.Load())
 }
+
+func TestMetricsWriteProm(t *testing.T) {
+	m := &Metrics{}
+	m.RecordSetup(true, false, 100*time.Millisecond)
+	m.RecordSetup(true, false, 300*time.Millisecond)
+	m.RecordSetup(false, true, 200*time.Millisecond)
+	m.RecordTeardown(true, 50*time.Millisecond)
+	m.RecordIPAMLeak()
+
+	var buf strings.Builder
+	require.NoError(t, m.WriteProm(&buf))
+	out := buf.String()
+
+	for _, line := range []string{
+		"# HELP spinbox_cni_setup_total Total CNI setup attempts.",
+		"# TYPE spinbox_cni_setup_total counter",
+		"spinbox_cni_setup_total 3",
+		"spinbox_cni_setup_successes_total 2",
+		"spinbox_cni_setup_failures_total 1",
+		"spinbox_cni_resource_conflicts_total 1",
+		"spinbox_cni_teardown_total 1",
+		"spinbox_cni_teardown_failures_total 0",
+		"spinbox_cni_operation_timeouts_total 0",
+		"# TYPE spinbox_cni_ipam_leaks_total counter",
+		"spinbox_cni_ipam_leaks_total 1",
+		"# TYPE spinbox_cni_orphans_reclaimed_total counter",
+		"spinbox_cni_orphans_reclaimed_total 0",
+		"# TYPE spinbox_cni_setup_duration_avg_seconds gauge",
+		"spinbox_cni_setup_duration_avg_seconds 0.2",
+		"spinbox_cni_teardown_duration_avg_seconds 0.05",
+	} {
+		assert.Contains(t, strings.Split(out, "\n"), line)
+	}
+}
+
+func TestMetricsPluginTiming(t *testing.T) {
+	m := &Metrics{}
+	assert.Nil(t, m.Snapshot().PluginAvgTimeMs)
+
+	m.RecordPluginTiming("bridge", "ADD", 10*time.Millisecond)
+	m.RecordPluginTiming("bridge", "ADD", 30*time.Millisecond)
+	m.RecordPluginTiming("bridge", "DEL", 5*time.Millisecond)
+	m.RecordPluginTiming("host-local", "ADD", 4*time.Millisecond)
+	m.RecordPluginTiming("firewall", "ADD", 100*time.Millisecond)
+
+	snap := m.Snapshot()
+	require.Len(t, snap.PluginAvgTimeMs, 4)
+	assert.InDelta(t, 20.0, snap.PluginAvgTimeMs[PluginTimingKey{"bridge", "ADD"}], 0.001)
+	assert.InDelta(t, 5.0, snap.PluginAvgTimeMs[PluginTimingKey{"bridge", "DEL"}], 0.001)
+	assert.InDelta(t, 4.0, snap.PluginAvgTimeMs[PluginTimingKey{"host-local", "ADD"}], 0.001)
+	assert.InDelta(t, 100.0, snap.PluginAvgTimeMs[PluginTimingKey{"firewall", "ADD"}], 0.001)
+
+	var buf strings.Builder
+	require.NoError(t, m.WriteProm(&buf))
+	lines := strings.Split(buf.String(), "\n")
+	assert.Contains(t, lines, "# TYPE spinbox_cni_plugin_duration_avg_seconds gauge")
+	assert.Contains(t, lines, `spinbox_cni_plugin_duration_avg_seconds{plugin="bridge",op="ADD"} 0.02`)
+	assert.Contains(t, lines, `spinbox_cni_plugin_duration_avg_seconds{plugin="host-local",op="ADD"} 0.004`)
+
+	m.Reset()
+	assert.Nil(t, m.Snapshot().PluginAvgTimeMs)
+}
+
+func TestMetricsPluginTimingLabelEscaping(t *testing.T) {
+	m := &Metrics{}
+	m.RecordPluginTiming("my\"plug\\in\n", "ADDé", 10*time.Millisecond)
+
+	var buf strings.Builder
+	require.NoError(t, m.WriteProm(&buf))
+	// Only backslash, double quote and newline are escaped; other
+	// characters, including non-ASCII, are written as is
+	assert.Contains(t, strings.Split(buf.String(), "\n"),
+		`spinbox_cni_plugin_duration_avg_seconds{plugin="my\"plug\\in\n",op="ADDé"} 0.01`)
+}
+
+func TestMetricsPluginTimingConcurrent(t *testing.T) {
+	m := &Metrics{}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				m.RecordPluginTiming("bridge", "ADD", time.Millisecond)
+				_ = m.Snapshot()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.InDelta(t, 1.0, m.Snapshot().PluginAvgTimeMs[PluginTimingKey{"bridge", "ADD"}], 0.001)
+}
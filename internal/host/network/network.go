@@ -10,14 +10,20 @@
 //
 // 1. Result Cache (cniResults map, protected by cniMu RWMutex):
 //   - Stores completed CNI setup results for cleanup
+//   - Each entry records the hash of the CNI config it was set up with
 //   - Read lock for checking if already configured (fast path)
 //   - Write lock only when storing/removing results
+//   - An entry whose config hash no longer matches the config on disk is
+//     stale: it is torn down and setup runs again with the reloaded config
 //
 // 2. In-Flight Coordination (inFlight map, protected by inflightMu Mutex):
 //   - Prevents duplicate CNI setup for the same container ID
 //   - First caller becomes the "worker" and performs setup
-//   - Subsequent callers block on a channel until worker completes
+//   - Subsequent callers block on a channel until worker completes, their
+//     context is done, or NetworkConfig.SetupWaitTimeout elapses
 //   - Worker shares result/error with all waiters via the channel
+//   - If the worker panics, waiters get an error and the entry is removed
+//     so later callers retry the setup
 //
 // Locking Order (when holding multiple locks):
 //  1. inflightMu (always acquire first if needed)
@@ -31,33 +37,80 @@
 //   - No background goroutines - all operations are synchronous
 //
 // Resource Lifecycle:
-//   - Network namespace: Created during setup, deleted during teardown
-//   - TAP device: Created by CNI plugins, destroyed during teardown
+//   - Network namespace: Created during setup, deleted during teardown.
+//     Shared by all networks of a container (interfaces eth0, eth1, ...)
+//   - TAP device: Created by CNI plugins (one per network), destroyed during teardown
 //   - IP allocation: Managed by CNI IPAM plugin, released during teardown
 //   - cniResults entry: Stored after successful setup, removed during teardown
+//     or when the CNI config or the requested networks change
 //   - inFlight entry: Created when setup starts, removed when setup completes
 package network
 
 import (
+	"cmp"
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
 
 	"github.com/containerd/log"
+	"github.com/containernetworking/cni/libcni"
 
+	"github.com/spin-stack/spinbox/internal/config"
 	"github.com/spin-stack/spinbox/internal/host/network/cni"
 )
 
-// LoadNetworkConfig loads CNI network configuration using a three-tier fallback:
-//  1. Environment variables (SPINBOX_CNI_CONF_DIR, SPINBOX_CNI_BIN_DIR)
-//  2. Spinbox-bundled CNI config (if exists)
-//  3. Standard system CNI paths (/etc/cni/net.d, /opt/cni/bin)
+// LoadNetworkConfig loads CNI network configuration using a four-tier fallback:
+//  1. The spinbox config file (paths.cni_conf_dir, paths.cni_bin_dir)
+//  2. Environment variables (SPINBOX_CNI_CONF_DIR, SPINBOX_CNI_BIN_DIR)
+//  3. Spinbox-bundled CNI config (if exists)
+//  4. Standard system CNI paths (/etc/cni/net.d, /opt/cni/bin)
 //
 // Network configuration is auto-discovered from the first .conflist file
 // in the CNI config directory (sorted alphabetically by filename).
+//
+// The IPAM leak policy comes from SPINBOX_IPAM_LEAK_POLICY.
 func LoadNetworkConfig() NetworkConfig {
-	// Priority 1: Environment variable override (user-specified paths)
+	var pathsCfg config.PathsConfig
+	if cfg, err := config.Get(); err == nil {
+		pathsCfg = cfg.Paths
+	}
+	return loadNetworkConfig(pathsCfg)
+}
+
+func loadNetworkConfig(pathsCfg config.PathsConfig) NetworkConfig {
+	cfg := loadCNIDirs(pathsCfg)
+	cfg.IPAMLeakPolicy = ipamLeakPolicyFromEnv()
+	return cfg
+}
+
+// ipamLeakPolicyFromEnv reads SPINBOX_IPAM_LEAK_POLICY: "remediate" opts in
+// to IPAMLeakRemediateMatching, while unset or "report" keeps
+// IPAMLeakReportOnly. Unknown values are logged and reported only.
+func ipamLeakPolicyFromEnv() IPAMLeakPolicy {
+	switch v := os.Getenv("SPINBOX_IPAM_LEAK_POLICY"); v {
+	case "", "report":
+		return IPAMLeakReportOnly
+	case "remediate":
+		return IPAMLeakRemediateMatching
+	default:
+		log.L.WithField("value", v).Warn("ignoring unknown SPINBOX_IPAM_LEAK_POLICY, leaked IPAM reservations are only reported")
+		return IPAMLeakReportOnly
+	}
+}
+
+// loadCNIDirs resolves the CNI config and plugin directories.
+func loadCNIDirs(pathsCfg config.PathsConfig) NetworkConfig {
+	// Priority 1: Config file (validated when the config was loaded)
+	if pathsCfg.CNIConfDir != "" {
+		return NetworkConfig{
+			CNIConfDir: pathsCfg.CNIConfDir,
+			CNIBinDir:  cmp.Or(pathsCfg.CNIBinDir, "/opt/cni/bin"),
+		}
+	}
+
+	// Priority 2: Environment variable override (user-specified paths)
 	// Allows users to override CNI config location without changing code
 	if confDir := os.Getenv("SPINBOX_CNI_CONF_DIR"); confDir != "" {
 		binDir := os.Getenv("SPINBOX_CNI_BIN_DIR")
@@ -71,7 +124,7 @@ func LoadNetworkConfig() NetworkConfig {
 		}
 	}
 
-	// Priority 2: Spinbox-bundled CNI paths (if they exist)
+	// Priority 3: Spinbox-bundled CNI paths (if they exist)
 	// Used when spinbox is installed with its own CNI plugins
 	spinboxConfDir := filepath.Join("/usr/share/spinbox", "config", "cni", "net.d")
 	spinboxBinDir := filepath.Join("/usr/share/spinbox", "libexec", "cni")
@@ -82,21 +135,65 @@ func LoadNetworkConfig() NetworkConfig {
 		}
 	}
 
-	// Priority 3: Standard system CNI paths (fallback)
-	// Used when neither env vars nor spinbox paths are available
+	// Priority 4: Standard system CNI paths (fallback)
+	// Used when neither the config file, env vars nor spinbox paths are available
 	return NetworkConfig{
 		CNIConfDir: "/etc/cni/net.d",
 		CNIBinDir:  "/opt/cni/bin",
 	}
 }
 
+// Validate checks that the CNI directories are usable: CNIConfDir must be a
+// directory holding at least one network config and CNIBinDir a non-empty
+// directory. Errors name the offending path so misconfiguration is caught at
+// startup rather than at the first container's network setup.
+func (c NetworkConfig) Validate() error {
+	info, err := os.Stat(c.CNIConfDir)
+	if err != nil {
+		return fmt.Errorf("CNI config directory %q: %w (set SPINBOX_CNI_CONF_DIR or install a CNI config)", c.CNIConfDir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("CNI config directory %q is not a directory", c.CNIConfDir)
+	}
+	confs, err := libcni.ConfFiles(c.CNIConfDir, []string{".conflist", ".conf"})
+	if err != nil {
+		return fmt.Errorf("read CNI config directory %q: %w", c.CNIConfDir, err)
+	}
+	if len(confs) == 0 {
+		return fmt.Errorf("CNI config directory %q contains no CNI network config (*.conflist or *.conf)", c.CNIConfDir)
+	}
+
+	info, err = os.Stat(c.CNIBinDir)
+	if err != nil {
+		return fmt.Errorf("CNI plugin directory %q: %w (set SPINBOX_CNI_BIN_DIR or install CNI plugins)", c.CNIBinDir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("CNI plugin directory %q is not a directory", c.CNIBinDir)
+	}
+	plugins, err := os.ReadDir(c.CNIBinDir)
+	if err != nil {
+		return fmt.Errorf("read CNI plugin directory %q: %w", c.CNIBinDir, err)
+	}
+	if len(plugins) == 0 {
+		return fmt.Errorf("CNI plugin directory %q is empty", c.CNIBinDir)
+	}
+	return nil
+}
+
 // setupInFlight tracks an in-progress CNI setup operation.
 // Multiple goroutines attempting to setup the same container ID will coordinate
 // through this struct - the first one does the work, others wait on the channel.
 type setupInFlight struct {
-	done   chan struct{} // closed when setup completes (success or failure)
-	result *cni.CNIResult
-	err    error
+	done    chan struct{} // closed when setup completes (success or failure)
+	results []*cni.CNIResult
+	err     error
+}
+
+// cniSetup is a completed CNI setup and the config it was performed with.
+type cniSetup struct {
+	results  []*cni.CNIResult // one per network, in networks order
+	networks []string         // requested network names, nil for the default
+	confHash string           // hash of the CNI config file, empty if unknown
 }
 
 // cniNetworkManager manages lifecycle of host networking resources using CNI.
@@ -106,10 +203,18 @@ type cniNetworkManager struct {
 	// CNI manager for network configuration
 	cniManager *cni.CNIManager
 
+	// Runs CNI ADD/DEL per network. Defaults to cniManager. Configurable for testing.
+	cniNets cniNetworks
+
 	// CNI state storage (maps VM ID to CNI result for cleanup)
-	cniResults map[string]*cni.CNIResult
+	cniResults map[string]*cniSetup
 	cniMu      sync.RWMutex
 
+	// Hash of the CNI config currently loaded by cniManager.
+	// Used to reload the config before setup when it changed on disk.
+	loadedConfHash string
+	confMu         sync.Mutex
+
 	// Tracks in-flight setup operations to avoid duplicate work
 	// Multiple concurrent calls for the same ID will coordinate through this map
 	inFlight   map[string]*setupInFlight
@@ -126,6 +231,22 @@ type cniNetworkManager struct {
 	// ipamDir is the directory where IPAM state files are stored.
 	// Defaults to /var/lib/cni/networks. Configurable for testing.
 	ipamDir string
+
+	// setup and teardown run the CNI ADD and DEL steps.
+	// Default to performCNISetup and performCNITeardown. Configurable for testing.
+	setup    func(ctx context.Context, containerID string, networks []string) ([]*cni.CNIResult, error)
+	teardown func(ctx context.Context, env *Environment) CleanupResult
+
+	// reclaim releases the CNI resources of an orphaned container.
+	// Defaults to reclaimOrphan. Configurable for testing.
+	reclaim func(ctx context.Context, containerID string, networks []string) error
+}
+
+// cniNetworks runs the CNI plugin chain of a single network.
+// Implemented by *cni.CNIManager.
+type cniNetworks interface {
+	SetupNetwork(ctx context.Context, vmID, netns, network, ifName string) (*cni.CNIResult, error)
+	TeardownNetwork(ctx context.Context, vmID, netns, network, ifName string) error
 }
 
 // NewNetworkManager creates a network manager for the configured mode.
@@ -136,7 +257,37 @@ func NewNetworkManager(
 	// Log the network mode
 	log.G(ctx).Info("Initializing CNI network manager")
 
-	return newCNINetworkManager(config)
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid network config: %w", err)
+	}
+
+	nm, err := newCNINetworkManager(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.RunningContainers != nil {
+		nm.startupReconcile(ctx)
+	}
+	return nm, nil
+}
+
+// startupReconcile reclaims resources left behind by containers that are no
+// longer running. Failures are logged: a leftover reservation must not keep
+// the manager from starting.
+func (nm *cniNetworkManager) startupReconcile(ctx context.Context) {
+	running, err := nm.config.RunningContainers(ctx)
+	if err != nil {
+		log.G(ctx).WithError(err).Warn("Skipping orphaned network cleanup: cannot list running containers")
+		return
+	}
+	reclaimed, err := nm.reconcileOrphans(ctx, running)
+	if err != nil {
+		log.G(ctx).WithError(err).Warn("Orphaned network cleanup incomplete")
+	}
+	if reclaimed > 0 {
+		log.G(ctx).WithField("reclaimed", reclaimed).Info("Orphaned network cleanup complete")
+	}
 }
 
 // Close stops the network manager and releases internal resources.
@@ -3,9 +3,15 @@
 package network
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spin-stack/spinbox/internal/config"
 )
 
 func TestLoadNetworkConfig(t *testing.T) {
@@ -24,6 +30,41 @@ func TestLoadNetworkConfig(t *testing.T) {
 		assert.NotEmpty(t, cfg.CNIBinDir)
 	})
 
+	t.Run("config file takes precedence over environment", func(t *testing.T) {
+		t.Setenv("SPINBOX_CNI_CONF_DIR", "/env/conf")
+		t.Setenv("SPINBOX_CNI_BIN_DIR", "/env/bin")
+
+		cfg := loadNetworkConfig(config.PathsConfig{CNIConfDir: "/cfg/conf", CNIBinDir: "/cfg/bin"})
+		assert.Equal(t, "/cfg/conf", cfg.CNIConfDir)
+		assert.Equal(t, "/cfg/bin", cfg.CNIBinDir)
+
+		cfg = loadNetworkConfig(config.PathsConfig{CNIConfDir: "/cfg/conf"})
+		assert.Equal(t, "/cfg/conf", cfg.CNIConfDir)
+		assert.Equal(t, "/opt/cni/bin", cfg.CNIBinDir)
+	})
+
+	t.Run("environment without config file paths", func(t *testing.T) {
+		t.Setenv("SPINBOX_CNI_CONF_DIR", "/env/conf")
+		t.Setenv("SPINBOX_CNI_BIN_DIR", "/env/bin")
+
+		cfg := loadNetworkConfig(config.PathsConfig{})
+		assert.Equal(t, "/env/conf", cfg.CNIConfDir)
+		assert.Equal(t, "/env/bin", cfg.CNIBinDir)
+	})
+
+	t.Run("IPAM leak policy from environment", func(t *testing.T) {
+		for value, want := range map[string]IPAMLeakPolicy{
+			"":          IPAMLeakReportOnly,
+			"report":    IPAMLeakReportOnly,
+			"remediate": IPAMLeakRemediateMatching,
+			"bogus":     IPAMLeakReportOnly,
+		} {
+			t.Setenv("SPINBOX_IPAM_LEAK_POLICY", value)
+			cfg := loadNetworkConfig(config.PathsConfig{CNIConfDir: "/cfg/conf"})
+			assert.Equal(t, want, cfg.IPAMLeakPolicy, "SPINBOX_IPAM_LEAK_POLICY=%q", value)
+		}
+	})
+
 	t.Run("idempotent", func(t *testing.T) {
 		cfg1 := LoadNetworkConfig()
 		cfg2 := LoadNetworkConfig()
@@ -61,3 +102,76 @@ func TestNetworkConfig_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestNetworkConfigValidate(t *testing.T) {
+	// newDirs returns a config dir and a bin dir holding one plugin
+	newDirs := func(t *testing.T) (string, string) {
+		t.Helper()
+		confDir, binDir := t.TempDir(), t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(binDir, "bridge"), []byte("#!/bin/sh\n"), 0755))
+		return confDir, binDir
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		confDir, binDir := newDirs(t)
+		require.NoError(t, os.WriteFile(filepath.Join(confDir, "10-net.conflist"), []byte("{}"), 0644))
+
+		assert.NoError(t, NetworkConfig{CNIConfDir: confDir, CNIBinDir: binDir}.Validate())
+	})
+
+	t.Run("no conflist", func(t *testing.T) {
+		confDir, binDir := newDirs(t)
+		require.NoError(t, os.WriteFile(filepath.Join(confDir, "README"), []byte("docs"), 0644))
+
+		err := NetworkConfig{CNIConfDir: confDir, CNIBinDir: binDir}.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), confDir)
+	})
+
+	t.Run("missing config dir", func(t *testing.T) {
+		_, binDir := newDirs(t)
+		missing := filepath.Join(t.TempDir(), "net.d")
+
+		err := NetworkConfig{CNIConfDir: missing, CNIBinDir: binDir}.Validate()
+		require.Error(t, err)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+		assert.Contains(t, err.Error(), missing)
+	})
+
+	t.Run("config path is a file", func(t *testing.T) {
+		_, binDir := newDirs(t)
+		file := filepath.Join(t.TempDir(), "net.conflist")
+		require.NoError(t, os.WriteFile(file, []byte("{}"), 0644))
+
+		err := NetworkConfig{CNIConfDir: file, CNIBinDir: binDir}.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a directory")
+	})
+
+	t.Run("missing bin dir", func(t *testing.T) {
+		confDir, _ := newDirs(t)
+		require.NoError(t, os.WriteFile(filepath.Join(confDir, "10-net.conflist"), []byte("{}"), 0644))
+		missing := filepath.Join(t.TempDir(), "bin")
+
+		err := NetworkConfig{CNIConfDir: confDir, CNIBinDir: missing}.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), missing)
+	})
+
+	t.Run("empty bin dir", func(t *testing.T) {
+		confDir, _ := newDirs(t)
+		require.NoError(t, os.WriteFile(filepath.Join(confDir, "10-net.conflist"), []byte("{}"), 0644))
+		binDir := t.TempDir()
+
+		err := NetworkConfig{CNIConfDir: confDir, CNIBinDir: binDir}.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is empty")
+	})
+}
+
+func TestNewNetworkManagerValidates(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "net.d")
+	_, err := NewNetworkManager(context.Background(), NetworkConfig{CNIConfDir: missing, CNIBinDir: t.TempDir()})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), missing)
+}
@@ -4,6 +4,7 @@ package network
 import (
 	"context"
 	"net"
+	"time"
 )
 
 // NetworkConfig describes the CNI configuration locations.
@@ -15,8 +16,44 @@ type NetworkConfig struct {
 	// CNIBinDir is the directory containing CNI plugin binaries.
 	// Default: /opt/cni/bin
 	CNIBinDir string
+
+	// SetupWaitTimeout bounds how long a caller waits for another caller's
+	// in-flight CNI setup of the same container. Zero waits until the
+	// caller's context is done.
+	SetupWaitTimeout time.Duration
+
+	// OperationTimeout bounds each CNI ADD and DEL, so a hung plugin (e.g. a
+	// DHCP IPAM plugin waiting on a dead server) cannot block setup forever.
+	// Zero selects DefaultOperationTimeout; a negative value disables it.
+	OperationTimeout time.Duration
+
+	// IPAMLeakPolicy controls what happens when an IP reservation is still
+	// held by a container after CNI teardown. Default: IPAMLeakReportOnly.
+	IPAMLeakPolicy IPAMLeakPolicy
+
+	// RunningContainers, if set, lists the IDs of containers that are still
+	// running. NewNetworkManager then reclaims the network resources of any
+	// other container still holding an IPAM reservation. Leave it nil unless
+	// the caller knows every container on the host: reservations of
+	// containers it does not list are torn down.
+	RunningContainers func(ctx context.Context) ([]string, error)
 }
 
+// DefaultOperationTimeout is the CNI ADD/DEL timeout used when
+// NetworkConfig.OperationTimeout is zero.
+const DefaultOperationTimeout = 30 * time.Second
+
+// IPAMLeakPolicy selects how leaked IPAM reservations are handled.
+type IPAMLeakPolicy int
+
+const (
+	// IPAMLeakReportOnly logs and counts leaked reservations.
+	IPAMLeakReportOnly IPAMLeakPolicy = iota
+	// IPAMLeakRemediateMatching also removes reservation files that still
+	// name the released container, preventing IP exhaustion over long uptimes.
+	IPAMLeakRemediateMatching
+)
+
 // NetworkInfo holds internal network configuration
 type NetworkInfo struct {
 	TapName string `json:"tap_name"`
@@ -24,6 +61,21 @@ type NetworkInfo struct {
 	IP      net.IP `json:"ip"`
 	Netmask string `json:"netmask"`
 	Gateway net.IP `json:"gateway"`
+	MTU     int    `json:"mtu,omitempty"`
+
+	// IPv6 configuration, set on dual-stack and IPv6-only networks
+	IPv6          net.IP `json:"ipv6,omitempty"`
+	IPv6PrefixLen int    `json:"ipv6_prefix_len,omitempty"`
+	IPv6Gateway   net.IP `json:"ipv6_gateway,omitempty"`
+
+	// Routes are the routes beyond the default route via Gateway
+	Routes []Route `json:"routes,omitempty"`
+}
+
+// Route is a static route reported by CNI for an interface.
+type Route struct {
+	Dst net.IPNet `json:"dst"`
+	GW  net.IP    `json:"gw,omitempty"`
 }
 
 // Environment represents a VM/container network environment
@@ -31,9 +83,18 @@ type Environment struct {
 	// ID is the unique identifier (container ID or VM ID)
 	ID string
 
-	// NetworkInfo contains allocated network configuration
+	// Networks are the CNI network names to attach, in interface order
+	// (eth0, eth1, ...). Empty selects the default network, the first
+	// .conflist in the CNI config directory.
+	Networks []string
+
+	// NetworkInfo contains allocated network configuration of the first network
 	// Set after EnsureNetworkResources() succeeds
 	NetworkInfo *NetworkInfo
+
+	// NetworkInfos contains the allocated configuration of every network,
+	// in Networks order. NetworkInfos[0] is NetworkInfo.
+	NetworkInfos []*NetworkInfo
 }
 
 // NetworkManager defines the interface for network management operations
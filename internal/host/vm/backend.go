@@ -0,0 +1,104 @@
+package vm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/containerd/errdefs"
+)
+
+// Backend names a VMM implementation.
+type Backend string
+
+const (
+	// BackendQEMU is the QEMU/KVM backend (package qemu).
+	BackendQEMU Backend = "qemu"
+	// BackendCloudHypervisor is the Cloud Hypervisor backend.
+	BackendCloudHypervisor Backend = "cloud-hypervisor"
+
+	// DefaultBackend is used when neither the caller nor BackendEnvVar
+	// selects a backend.
+	DefaultBackend = BackendQEMU
+
+	// BackendEnvVar selects the backend when NewInstance is called without one.
+	BackendEnvVar = "SPINBOX_VMM"
+)
+
+// knownBackends are the backends NewInstance accepts. A known backend may
+// still be unavailable if it is not registered on this platform.
+var knownBackends = map[Backend]struct{}{
+	BackendQEMU:            {},
+	BackendCloudHypervisor: {},
+}
+
+// Factory creates a VM instance for a backend. name identifies the VM
+// (the container ID) and stateDir holds its sockets and runtime state.
+type Factory func(ctx context.Context, name, stateDir string, cfg *VMResourceConfig) (Instance, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[Backend]Factory)
+)
+
+// Register makes a backend available to NewInstance. Backend packages call
+// it from init, so importing a backend package enables it. Registering an
+// unknown backend or the same backend twice panics.
+func Register(b Backend, f Factory) {
+	if _, ok := knownBackends[b]; !ok {
+		panic(fmt.Sprintf("vm: register unknown backend %q", b))
+	}
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if _, dup := factories[b]; dup {
+		panic(fmt.Sprintf("vm: backend %q registered twice", b))
+	}
+	factories[b] = f
+}
+
+// ResolveBackend returns the backend named by backend, falling back to
+// BackendEnvVar and then DefaultBackend when it is empty.
+func ResolveBackend(backend string) (Backend, error) {
+	if backend == "" {
+		backend = os.Getenv(BackendEnvVar)
+	}
+	if backend == "" {
+		return DefaultBackend, nil
+	}
+	b := Backend(backend)
+	if _, ok := knownBackends[b]; !ok {
+		return "", fmt.Errorf("unknown VM backend %q (supported: %s): %w", backend, supportedBackends(), errdefs.ErrInvalidArgument)
+	}
+	return b, nil
+}
+
+// NewInstance creates a VM instance using the selected backend. An empty
+// backend selects the one named by BackendEnvVar, or DefaultBackend.
+// A backend that is known but not built for this platform returns an error
+// wrapping errdefs.ErrNotImplemented.
+func NewInstance(ctx context.Context, backend, name, stateDir string, cfg *VMResourceConfig) (Instance, error) {
+	b, err := ResolveBackend(backend)
+	if err != nil {
+		return nil, err
+	}
+
+	factoriesMu.RLock()
+	f, ok := factories[b]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("VM backend %q is not available on this platform: %w", b, errdefs.ErrNotImplemented)
+	}
+	return f(ctx, name, stateDir, cfg)
+}
+
+func supportedBackends() string {
+	names := make([]string, 0, len(knownBackends))
+	for b := range knownBackends {
+		names = append(names, string(b))
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,119 @@
+package vm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containerd/errdefs"
+)
+
+// withFactories replaces the registered backends for the duration of a test.
+func withFactories(t *testing.T, fs map[Backend]Factory) {
+	t.Helper()
+	factoriesMu.Lock()
+	saved := factories
+	factories = fs
+	factoriesMu.Unlock()
+	t.Cleanup(func() {
+		factoriesMu.Lock()
+		factories = saved
+		factoriesMu.Unlock()
+	})
+}
+
+func TestResolveBackend(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend string
+		env     string
+		want    Backend
+		wantErr bool
+	}{
+		{name: "default", want: DefaultBackend},
+		{name: "explicit qemu", backend: "qemu", want: BackendQEMU},
+		{name: "explicit cloud-hypervisor", backend: "cloud-hypervisor", want: BackendCloudHypervisor},
+		{name: "from env", env: "cloud-hypervisor", want: BackendCloudHypervisor},
+		{name: "explicit wins over env", backend: "qemu", env: "cloud-hypervisor", want: BackendQEMU},
+		{name: "unknown", backend: "firecracker", wantErr: true},
+		{name: "unknown from env", env: "firecracker", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(BackendEnvVar, tt.env)
+
+			got, err := ResolveBackend(tt.backend)
+			if tt.wantErr {
+				if !errdefs.IsInvalidArgument(err) {
+					t.Fatalf("ResolveBackend(%q) error = %v, want invalid argument", tt.backend, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveBackend(%q) error = %v", tt.backend, err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveBackend(%q) = %q, want %q", tt.backend, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewInstanceDispatch(t *testing.T) {
+	t.Setenv(BackendEnvVar, "")
+
+	type call struct {
+		backend        Backend
+		name, stateDir string
+		cfg            *VMResourceConfig
+	}
+	var calls []call
+	factory := func(b Backend) Factory {
+		return func(_ context.Context, name, stateDir string, cfg *VMResourceConfig) (Instance, error) {
+			calls = append(calls, call{b, name, stateDir, cfg})
+			return nil, nil
+		}
+	}
+	withFactories(t, map[Backend]Factory{BackendQEMU: factory(BackendQEMU)})
+
+	cfg := &VMResourceConfig{BootCPUs: 1, MaxCPUs: 1, MemorySize: 1}
+	if _, err := NewInstance(t.Context(), "", "ctr", "/state", cfg); err != nil {
+		t.Fatalf("NewInstance() error = %v", err)
+	}
+	if len(calls) != 1 || calls[0] != (call{BackendQEMU, "ctr", "/state", cfg}) {
+		t.Fatalf("factory calls = %+v, want one qemu call", calls)
+	}
+
+	// Known but not registered on this platform
+	_, err := NewInstance(t.Context(), string(BackendCloudHypervisor), "ctr", "/state", cfg)
+	if !errdefs.IsNotImplemented(err) {
+		t.Errorf("NewInstance(cloud-hypervisor) error = %v, want not implemented", err)
+	}
+
+	_, err = NewInstance(t.Context(), "firecracker", "ctr", "/state", cfg)
+	if !errdefs.IsInvalidArgument(err) {
+		t.Errorf("NewInstance(firecracker) error = %v, want invalid argument", err)
+	}
+	if len(calls) != 1 {
+		t.Errorf("factory called %d times, want 1", len(calls))
+	}
+}
+
+func TestRegister(t *testing.T) {
+	withFactories(t, map[Backend]Factory{})
+	f := func(context.Context, string, string, *VMResourceConfig) (Instance, error) { return nil, nil }
+
+	Register(BackendCloudHypervisor, f)
+
+	expectPanic := func(name string, fn func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s: expected panic", name)
+			}
+		}()
+		fn()
+	}
+	expectPanic("duplicate", func() { Register(BackendCloudHypervisor, f) })
+	expectPanic("unknown", func() { Register("firecracker", f) })
+}
@@ -0,0 +1,140 @@
+//go:build linux
+
+package qemu
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/containerd/log"
+)
+
+const (
+	// defaultBootTimeout bounds QMP and vsock readiness when the caller does
+	// not pass vm.WithBootTimeout.
+	defaultBootTimeout = 30 * time.Second
+
+	// bootDiagnosticLines is how many trailing lines of the console and QEMU
+	// logs are captured when boot times out.
+	bootDiagnosticLines = 30
+
+	// bootDiagnosticMaxBytes caps how much of each log is read from its end.
+	bootDiagnosticMaxBytes = 64 * 1024
+)
+
+// BootTimeoutError is returned by Start when the guest does not become ready
+// within the boot timeout. It carries the tail of the guest console and of
+// QEMU's stdout/stderr so a hung boot can be diagnosed from the error alone.
+type BootTimeoutError struct {
+	Timeout time.Duration
+	Phases  BootTrace // start phases up to and including the one that timed out
+	Console []string  // last lines of the guest serial console
+	Stderr  []string  // last lines of QEMU stdout/stderr
+}
+
+func (e *BootTimeoutError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "VM did not become ready within %s", e.Timeout)
+	if len(e.Phases.Phases) > 0 {
+		fmt.Fprintf(&b, " (phases: %s)", e.Phases)
+	}
+	writeSection := func(name string, lines []string) {
+		fmt.Fprintf(&b, "\n--- %s (last %d lines) ---", name, len(lines))
+		for _, l := range lines {
+			b.WriteString("\n")
+			b.WriteString(l)
+		}
+	}
+	writeSection("console", e.Console)
+	writeSection("qemu stderr", e.Stderr)
+	return b.String()
+}
+
+// Unwrap makes errors.Is(err, context.DeadlineExceeded) hold.
+func (e *BootTimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// waitBoot runs ready with a context bounded by timeout. If the timeout
+// expires first, the returned error is a *BootTimeoutError holding the tail of
+// the console and QEMU logs, and the phases recorded by tr so far (tr may be
+// nil). Cancellation of ctx itself is returned as is.
+func (q *Instance) waitBoot(ctx context.Context, timeout time.Duration, tr *bootTracer, ready func(context.Context) error) error {
+	if timeout <= 0 {
+		timeout = defaultBootTimeout
+	}
+	bootCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := ready(bootCtx)
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil || !errors.Is(bootCtx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+
+	timeoutErr := &BootTimeoutError{
+		Timeout: timeout,
+		Phases:  tr.result(),
+		Console: tailLines(q.consolePath, bootDiagnosticLines),
+		Stderr:  tailLines(q.qemuLogPath, bootDiagnosticLines),
+	}
+	log.G(ctx).WithFields(log.Fields{
+		"timeout": timeout,
+		"phases":  timeoutErr.Phases.String(),
+		"console": q.consolePath,
+		"qemuLog": q.qemuLogPath,
+	}).Error("qemu: VM boot timed out")
+	return timeoutErr
+}
+
+// tailLines returns up to n trailing lines of the file at path, reading at most
+// bootDiagnosticMaxBytes from its end. Missing or unreadable files yield nil.
+func tailLines(path string, n int) []string {
+	if path == "" || n <= 0 {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil
+	}
+	offset := max(info.Size()-bootDiagnosticMaxBytes, 0)
+	data, err := io.ReadAll(io.NewSectionReader(f, offset, info.Size()-offset))
+	if err != nil {
+		return nil
+	}
+	if offset > 0 {
+		// Drop the partial first line
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+
+	data = bytes.TrimRight(data, "\r\n")
+	if len(data) == 0 {
+		return nil
+	}
+	lines := strings.Split(string(data), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, "\r")
+	}
+	return lines
+}
@@ -0,0 +1,198 @@
+//go:build linux
+
+package qemu
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spin-stack/spinbox/internal/config"
+	"github.com/spin-stack/spinbox/internal/paths"
+)
+
+// BootArtifacts holds the kernel and initrd used to boot a VM of one architecture.
+type BootArtifacts struct {
+	Arch   string // Normalized architecture (e.g., "x86_64", "aarch64")
+	Kernel string // Absolute path to the kernel image
+	Initrd string // Absolute path to the initrd
+}
+
+// bootArtifactCache resolves and caches boot artifacts per share directory
+// and architecture. Entries are re-validated on each lookup so a variant that
+// is removed from disk is reported at create time rather than at boot, and
+// an entry whose files were replaced is resolved again.
+type bootArtifactCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedBootArtifacts // shareDir + "/" + arch -> artifacts
+}
+
+// cachedBootArtifacts records the modification times seen when the
+// artifacts were resolved. A changed mtime means the share directory was
+// updated (e.g., by a package upgrade), which may also have installed a
+// variant that is preferred over the cached one.
+type cachedBootArtifacts struct {
+	BootArtifacts
+	kernelMtime time.Time
+	initrdMtime time.Time
+}
+
+func newBootArtifactCache() *bootArtifactCache {
+	return &bootArtifactCache{entries: make(map[string]cachedBootArtifacts)}
+}
+
+var defaultBootArtifacts = newBootArtifactCache()
+
+// ResolveBootArtifacts returns the kernel and initrd for the given guest
+// architecture, validating that both exist. An empty arch means the host
+// architecture. Results are cached, so this can be called up front (for
+// example when a host starts serving a new platform) to pre-resolve variants.
+func ResolveBootArtifacts(arch string) (BootArtifacts, error) {
+	cfg, err := config.Get()
+	if err != nil {
+		return BootArtifacts{}, fmt.Errorf("failed to get config: %w", err)
+	}
+	return defaultBootArtifacts.resolve(cfg.Paths, arch)
+}
+
+func (c *bootArtifactCache) resolve(pathsCfg config.PathsConfig, arch string) (BootArtifacts, error) {
+	normalized, err := paths.NormalizeArch(arch)
+	if err != nil {
+		return BootArtifacts{}, err
+	}
+	key := filepath.Join(pathsCfg.ShareDir, normalized)
+
+	kernelOverride, initrdOverride, err := bootArtifactOverrides(normalized)
+	if err != nil {
+		return BootArtifacts{}, err
+	}
+	if kernelOverride != "" || initrdOverride != "" {
+		// Overrides are not cached so they can change between lookups
+		artifacts := BootArtifacts{Arch: normalized, Kernel: kernelOverride, Initrd: initrdOverride}
+		if artifacts.Kernel == "" {
+			if artifacts.Kernel, err = findKernel(pathsCfg, normalized); err != nil {
+				return BootArtifacts{}, err
+			}
+		}
+		if artifacts.Initrd == "" {
+			if artifacts.Initrd, err = c.findInitrd(pathsCfg, normalized); err != nil {
+				return BootArtifacts{}, err
+			}
+		}
+		return artifacts, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.entries[key]; ok {
+		if unchanged(cached.Kernel, cached.kernelMtime) && unchanged(cached.Initrd, cached.initrdMtime) {
+			return cached.BootArtifacts, nil
+		}
+		delete(c.entries, key)
+	}
+
+	kernel, err := findKernel(pathsCfg, normalized)
+	if err != nil {
+		return BootArtifacts{}, err
+	}
+
+	initrd, err := c.findInitrd(pathsCfg, normalized)
+	if err != nil {
+		return BootArtifacts{}, err
+	}
+
+	artifacts := BootArtifacts{Arch: normalized, Kernel: kernel, Initrd: initrd}
+	kernelInfo, kerr := os.Stat(kernel)
+	initrdInfo, ierr := os.Stat(initrd)
+	if kerr == nil && ierr == nil {
+		c.entries[key] = cachedBootArtifacts{
+			BootArtifacts: artifacts,
+			kernelMtime:   kernelInfo.ModTime(),
+			initrdMtime:   initrdInfo.ModTime(),
+		}
+	}
+	return artifacts, nil
+}
+
+// reset drops all cached entries.
+func (c *bootArtifactCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+// bootArtifactOverrides returns the kernel and initrd set through
+// config.KernelPathEnvVar and config.InitrdPathEnvVar for arch, letting CI and
+// developers boot a locally built image without installing it. Overrides only
+// apply to the host architecture. An override naming a missing file is an
+// error rather than silently falling back to the share directory.
+func bootArtifactOverrides(arch string) (kernel, initrd string, err error) {
+	if hostArch, err := paths.NormalizeArch(""); err != nil || arch != hostArch {
+		return "", "", nil
+	}
+	for _, o := range []struct {
+		env  string
+		path *string
+	}{
+		{config.KernelPathEnvVar, &kernel},
+		{config.InitrdPathEnvVar, &initrd},
+	} {
+		p := os.Getenv(o.env)
+		if p == "" {
+			continue
+		}
+		if !regularFileExists(p) {
+			return "", "", fmt.Errorf("%s is set to %s, but the file does not exist", o.env, p)
+		}
+		*o.path = p
+	}
+	return kernel, initrd, nil
+}
+
+// findKernel returns the kernel for arch from the share directory.
+func findKernel(pathsCfg config.PathsConfig, arch string) (string, error) {
+	search, err := paths.KernelSearchPaths(pathsCfg, arch)
+	if err != nil {
+		return "", err
+	}
+	candidates := paths.ResolveAll(search)
+	if kernel, ok := paths.FirstFound(candidates); ok {
+		return kernel, nil
+	}
+	return "", fmt.Errorf("kernel for %s not found, searched %s (use SPINBOX_SHARE_DIR to override)", arch, paths.FormatCandidates(candidates))
+}
+
+// findInitrd prefers an arch-specific initrd. The arch-neutral initrd is only
+// accepted for the host architecture, since it is built for the host.
+func (c *bootArtifactCache) findInitrd(pathsCfg config.PathsConfig, arch string) (string, error) {
+	search, err := paths.InitrdSearchPaths(pathsCfg, arch)
+	if err != nil {
+		return "", err
+	}
+	candidates := paths.ResolveAll(search)
+	if initrd, ok := paths.FirstFound(candidates); ok {
+		return initrd, nil
+	}
+
+	hint := "use SPINBOX_SHARE_DIR to override"
+	if hostArch, err := paths.NormalizeArch(""); err == nil && arch == hostArch {
+		hint = "use SPINBOX_SHARE_DIR or " + config.InitrdPathEnvVar + " to override"
+	}
+	return "", fmt.Errorf("initrd for %s not found, searched %s (%s)", arch, paths.FormatCandidates(candidates), hint)
+}
+
+// unchanged reports whether path is still a regular file with the given
+// modification time.
+func unchanged(path string, mtime time.Time) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir() && info.ModTime().Equal(mtime)
+}
+
+// regularFileExists reports whether path exists and is not a directory.
+func regularFileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
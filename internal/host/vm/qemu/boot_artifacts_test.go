@@ -0,0 +1,221 @@
+//go:build linux
+
+package qemu
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spin-stack/spinbox/internal/config"
+)
+
+func writeBootFile(t *testing.T, shareDir, name string) string {
+	t.Helper()
+	path := filepath.Join(shareDir, "kernel", name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+	require.NoError(t, os.WriteFile(path, []byte(name), 0600))
+	return path
+}
+
+func TestBootArtifactCache_Resolve(t *testing.T) {
+	shareDir := t.TempDir()
+	pathsCfg := config.PathsConfig{ShareDir: shareDir}
+
+	x86Kernel := writeBootFile(t, shareDir, "spinbox-kernel-x86_64")
+	x86Initrd := writeBootFile(t, shareDir, "spinbox-initrd-x86_64")
+	armKernel := writeBootFile(t, shareDir, "spinbox-kernel-aarch64")
+	armInitrd := writeBootFile(t, shareDir, "spinbox-initrd-aarch64")
+
+	cache := newBootArtifactCache()
+
+	for _, arch := range []string{"amd64", "x86_64"} {
+		got, err := cache.resolve(pathsCfg, arch)
+		require.NoError(t, err)
+		assert.Equal(t, BootArtifacts{Arch: "x86_64", Kernel: x86Kernel, Initrd: x86Initrd}, got)
+	}
+
+	for _, arch := range []string{"arm64", "aarch64"} {
+		got, err := cache.resolve(pathsCfg, arch)
+		require.NoError(t, err)
+		assert.Equal(t, BootArtifacts{Arch: "aarch64", Kernel: armKernel, Initrd: armInitrd}, got)
+	}
+
+	assert.Len(t, cache.entries, 2)
+}
+
+func TestBootArtifactCache_MissingVariant(t *testing.T) {
+	shareDir := t.TempDir()
+	pathsCfg := config.PathsConfig{ShareDir: shareDir}
+	writeBootFile(t, shareDir, "spinbox-kernel-x86_64")
+	writeBootFile(t, shareDir, "spinbox-initrd-x86_64")
+
+	cache := newBootArtifactCache()
+
+	_, err := cache.resolve(pathsCfg, "arm64")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "kernel for aarch64 not found")
+	assert.Contains(t, err.Error(), filepath.Join(shareDir, "kernel", "spinbox-kernel-aarch64")+" (not found)")
+
+	// Kernel present but no initrd for a foreign arch: the arch-neutral
+	// initrd must not be used.
+	writeBootFile(t, shareDir, "spinbox-kernel-aarch64")
+	writeBootFile(t, shareDir, "spinbox-initrd")
+	if runtime.GOARCH != "arm64" {
+		_, err = cache.resolve(pathsCfg, "arm64")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "initrd for aarch64 not found")
+	}
+
+	_, err = cache.resolve(pathsCfg, "riscv64")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported architecture")
+}
+
+func TestBootArtifactCache_HostArchFallsBackToNeutralInitrd(t *testing.T) {
+	hostArch := map[string]string{"amd64": "x86_64", "arm64": "aarch64"}[runtime.GOARCH]
+	if hostArch == "" {
+		t.Skipf("unsupported host architecture %s", runtime.GOARCH)
+	}
+
+	shareDir := t.TempDir()
+	pathsCfg := config.PathsConfig{ShareDir: shareDir}
+	kernel := writeBootFile(t, shareDir, "spinbox-kernel-"+hostArch)
+	initrd := writeBootFile(t, shareDir, "spinbox-initrd")
+
+	cache := newBootArtifactCache()
+	got, err := cache.resolve(pathsCfg, "")
+	require.NoError(t, err)
+	assert.Equal(t, kernel, got.Kernel)
+	assert.Equal(t, initrd, got.Initrd)
+}
+
+func TestBootArtifactCache_RevalidatesCachedEntry(t *testing.T) {
+	shareDir := t.TempDir()
+	pathsCfg := config.PathsConfig{ShareDir: shareDir}
+	kernel := writeBootFile(t, shareDir, "spinbox-kernel-x86_64")
+	writeBootFile(t, shareDir, "spinbox-initrd-x86_64")
+
+	cache := newBootArtifactCache()
+	_, err := cache.resolve(pathsCfg, "amd64")
+	require.NoError(t, err)
+
+	require.NoError(t, os.Remove(kernel))
+	_, err = cache.resolve(pathsCfg, "amd64")
+	require.Error(t, err)
+	assert.Empty(t, cache.entries)
+}
+
+func TestBootArtifactCache_EnvOverrides(t *testing.T) {
+	hostArch := map[string]string{"amd64": "x86_64", "arm64": "aarch64"}[runtime.GOARCH]
+	foreignArch := map[string]string{"amd64": "arm64", "arm64": "amd64"}[runtime.GOARCH]
+	if hostArch == "" {
+		t.Skipf("unsupported host architecture %s", runtime.GOARCH)
+	}
+
+	shareDir := t.TempDir()
+	pathsCfg := config.PathsConfig{ShareDir: shareDir}
+	shareKernel := writeBootFile(t, shareDir, "spinbox-kernel-"+hostArch)
+	shareInitrd := writeBootFile(t, shareDir, "spinbox-initrd-"+hostArch)
+
+	localDir := t.TempDir()
+	localKernel := filepath.Join(localDir, "bzImage")
+	localInitrd := filepath.Join(localDir, "initrd.cpio")
+	require.NoError(t, os.WriteFile(localKernel, []byte("kernel"), 0600))
+	require.NoError(t, os.WriteFile(localInitrd, []byte("initrd"), 0600))
+
+	t.Run("kernel override", func(t *testing.T) {
+		t.Setenv(config.KernelPathEnvVar, localKernel)
+		cache := newBootArtifactCache()
+
+		got, err := cache.resolve(pathsCfg, "")
+		require.NoError(t, err)
+		assert.Equal(t, BootArtifacts{Arch: hostArch, Kernel: localKernel, Initrd: shareInitrd}, got)
+		assert.Empty(t, cache.entries, "overridden artifacts must not be cached")
+	})
+
+	t.Run("both overrides without share dir", func(t *testing.T) {
+		t.Setenv(config.KernelPathEnvVar, localKernel)
+		t.Setenv(config.InitrdPathEnvVar, localInitrd)
+		cache := newBootArtifactCache()
+
+		got, err := cache.resolve(config.PathsConfig{ShareDir: t.TempDir()}, "")
+		require.NoError(t, err)
+		assert.Equal(t, BootArtifacts{Arch: hostArch, Kernel: localKernel, Initrd: localInitrd}, got)
+	})
+
+	t.Run("missing override file", func(t *testing.T) {
+		missing := filepath.Join(localDir, "missing")
+		for _, env := range []string{config.KernelPathEnvVar, config.InitrdPathEnvVar} {
+			t.Run(env, func(t *testing.T) {
+				t.Setenv(env, missing)
+				cache := newBootArtifactCache()
+
+				_, err := cache.resolve(pathsCfg, "")
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), env)
+				assert.Contains(t, err.Error(), missing)
+			})
+		}
+	})
+
+	t.Run("foreign arch ignores overrides", func(t *testing.T) {
+		t.Setenv(config.KernelPathEnvVar, localKernel)
+		t.Setenv(config.InitrdPathEnvVar, filepath.Join(localDir, "missing"))
+		foreign := map[string]string{"amd64": "x86_64", "arm64": "aarch64"}[foreignArch]
+		kernel := writeBootFile(t, shareDir, "spinbox-kernel-"+foreign)
+		initrd := writeBootFile(t, shareDir, "spinbox-initrd-"+foreign)
+		cache := newBootArtifactCache()
+
+		got, err := cache.resolve(pathsCfg, foreignArch)
+		require.NoError(t, err)
+		assert.Equal(t, BootArtifacts{Arch: foreign, Kernel: kernel, Initrd: initrd}, got)
+	})
+
+	t.Run("no override", func(t *testing.T) {
+		cache := newBootArtifactCache()
+		got, err := cache.resolve(pathsCfg, "")
+		require.NoError(t, err)
+		assert.Equal(t, BootArtifacts{Arch: hostArch, Kernel: shareKernel, Initrd: shareInitrd}, got)
+	})
+}
+
+func TestBootArtifactCache_InvalidatesOnMtimeChange(t *testing.T) {
+	hostArch := map[string]string{"amd64": "x86_64", "arm64": "aarch64"}[runtime.GOARCH]
+	if hostArch == "" {
+		t.Skipf("unsupported host architecture %s", runtime.GOARCH)
+	}
+
+	shareDir := t.TempDir()
+	pathsCfg := config.PathsConfig{ShareDir: shareDir}
+	kernel := writeBootFile(t, shareDir, "spinbox-kernel-"+hostArch)
+	neutral := writeBootFile(t, shareDir, "spinbox-initrd")
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(kernel, past, past))
+	require.NoError(t, os.Chtimes(neutral, past, past))
+
+	cache := newBootArtifactCache()
+	got, err := cache.resolve(pathsCfg, "")
+	require.NoError(t, err)
+	assert.Equal(t, neutral, got.Initrd)
+
+	// A new variant alone does not invalidate the cached entry
+	specific := writeBootFile(t, shareDir, "spinbox-initrd-"+hostArch)
+	got, err = cache.resolve(pathsCfg, "")
+	require.NoError(t, err)
+	assert.Equal(t, neutral, got.Initrd, "expected cached result")
+
+	// Updating a cached file does, and picks up the preferred variant
+	require.NoError(t, os.Chtimes(kernel, time.Now(), time.Now()))
+	got, err = cache.resolve(pathsCfg, "")
+	require.NoError(t, err)
+	assert.Equal(t, BootArtifacts{Arch: hostArch, Kernel: kernel, Initrd: specific}, got)
+
+	cache.reset()
+	assert.Empty(t, cache.entries)
+}
@@ -0,0 +1,118 @@
+//go:build linux
+
+package qemu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// neverReady is a boot readiness check for a guest that never comes up.
+func neverReady(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func newBootTestInstance(t *testing.T, console, qemuLog string) *Instance {
+	t.Helper()
+	dir := t.TempDir()
+	q := &Instance{
+		consolePath: filepath.Join(dir, "console.log"),
+		qemuLogPath: filepath.Join(dir, "qemu.log"),
+	}
+	require.NoError(t, os.WriteFile(q.consolePath, []byte(console), 0o600))
+	require.NoError(t, os.WriteFile(q.qemuLogPath, []byte(qemuLog), 0o600))
+	return q
+}
+
+func TestWaitBootTimeout(t *testing.T) {
+	q := newBootTestInstance(t,
+		"[    0.000000] Linux version 6.12\n[    1.234567] Kernel panic - not syncing: VFS: Unable to mount root fs\n",
+		"qemu-system-x86_64: warning: host doesn't support requested feature\n",
+	)
+
+	start := time.Now()
+	err := q.waitBoot(context.Background(), 50*time.Millisecond, nil, neverReady)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	var bootErr *BootTimeoutError
+	require.ErrorAs(t, err, &bootErr)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 50*time.Millisecond, bootErr.Timeout)
+	assert.Equal(t, []string{
+		"[    0.000000] Linux version 6.12",
+		"[    1.234567] Kernel panic - not syncing: VFS: Unable to mount root fs",
+	}, bootErr.Console)
+	assert.Equal(t, []string{"qemu-system-x86_64: warning: host doesn't support requested feature"}, bootErr.Stderr)
+
+	msg := err.Error()
+	assert.Contains(t, msg, "VM did not become ready within 50ms")
+	assert.Contains(t, msg, "Kernel panic - not syncing")
+	assert.Contains(t, msg, "host doesn't support requested feature")
+}
+
+func TestWaitBootDefaultTimeout(t *testing.T) {
+	q := newBootTestInstance(t, "", "")
+	var deadline time.Time
+	err := q.waitBoot(context.Background(), 0, nil, func(ctx context.Context) error {
+		deadline, _ = ctx.Deadline()
+		return nil
+	})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(defaultBootTimeout), deadline, time.Second)
+}
+
+func TestWaitBootPassesThroughOtherErrors(t *testing.T) {
+	q := newBootTestInstance(t, "console output\n", "")
+
+	// A readiness failure before the deadline is not a boot timeout
+	errQMP := errors.New("failed to connect to QMP")
+	err := q.waitBoot(context.Background(), time.Minute, nil, func(context.Context) error {
+		return errQMP
+	})
+	assert.Same(t, errQMP, err)
+
+	// Neither is cancellation of the caller's context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = q.waitBoot(ctx, time.Minute, nil, neverReady)
+	require.ErrorIs(t, err, context.Canceled)
+	var bootErr *BootTimeoutError
+	assert.NotErrorAs(t, err, &bootErr)
+}
+
+func TestTailLines(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+		return p
+	}
+
+	assert.Nil(t, tailLines(filepath.Join(dir, "missing"), 10))
+	assert.Nil(t, tailLines(write("empty", ""), 10))
+	assert.Equal(t, []string{"a", "b"}, tailLines(write("short", "a\r\nb\n"), 10))
+	assert.Equal(t, []string{"c", "d"}, tailLines(write("long", "a\nb\nc\nd"), 2))
+
+	// Only the end of a large file is read, and the partial line is dropped
+	var b strings.Builder
+	for i := range 10000 {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	lines := tailLines(write("large", b.String()), bootDiagnosticLines)
+	require.Len(t, lines, bootDiagnosticLines)
+	assert.Equal(t, "line 9999", lines[len(lines)-1])
+
+	lines = tailLines(write("huge-line", strings.Repeat("x", 2*bootDiagnosticMaxBytes)+"\nlast\n"), 10)
+	assert.Equal(t, []string{"last"}, lines)
+}
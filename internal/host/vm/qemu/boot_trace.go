@@ -0,0 +1,110 @@
+//go:build linux
+
+package qemu
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BootPhase names a step of Instance.Start.
+type BootPhase string
+
+const (
+	BootPhaseValidate BootPhase = "validate" // check kernel, initrd, disks and resources
+	BootPhaseConsole  BootPhase = "console"  // create the console FIFO and open the log
+	BootPhaseNetwork  BootPhase = "network"  // open TAP file descriptors in the sandbox netns
+	BootPhaseSpawn    BootPhase = "spawn"    // build the command line and start QEMU
+	BootPhaseQMP      BootPhase = "qmp"      // wait for the QMP socket
+	BootPhaseVsock    BootPhase = "vsock"    // wait for vminitd to answer on vsock
+)
+
+// PhaseTiming is the timing of one boot phase. Start is the offset from the
+// beginning of Instance.Start.
+type PhaseTiming struct {
+	Phase    BootPhase
+	Start    time.Duration
+	Duration time.Duration
+	Failed   bool
+}
+
+// BootTrace records how long each phase of Instance.Start took, in the order
+// the phases ran. A failed start ends with the phase that failed.
+type BootTrace struct {
+	Phases []PhaseTiming
+}
+
+// Total returns the time from the start of the first phase to the end of the
+// last.
+func (t BootTrace) Total() time.Duration {
+	if len(t.Phases) == 0 {
+		return 0
+	}
+	last := t.Phases[len(t.Phases)-1]
+	return last.Start + last.Duration
+}
+
+// Phase returns the timing of phase p, if it ran.
+func (t BootTrace) Phase(p BootPhase) (PhaseTiming, bool) {
+	for _, pt := range t.Phases {
+		if pt.Phase == p {
+			return pt, true
+		}
+	}
+	return PhaseTiming{}, false
+}
+
+// String formats the trace as "phase=duration" pairs, e.g.
+// "console=1ms spawn=20ms qmp=150ms vsock=1.2s(failed)".
+func (t BootTrace) String() string {
+	parts := make([]string, 0, len(t.Phases))
+	for _, pt := range t.Phases {
+		s := fmt.Sprintf("%s=%s", pt.Phase, pt.Duration.Round(time.Microsecond))
+		if pt.Failed {
+			s += "(failed)"
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " ")
+}
+
+// bootTracer times the phases of a single Start call. It is not safe for
+// concurrent use; phases run sequentially.
+type bootTracer struct {
+	now   func() time.Time
+	start time.Time
+	trace BootTrace
+}
+
+func newBootTracer() *bootTracer {
+	return newBootTracerWithClock(time.Now)
+}
+
+func newBootTracerWithClock(now func() time.Time) *bootTracer {
+	return &bootTracer{now: now, start: now()}
+}
+
+// phase runs fn and records its duration as phase p. A nil tracer just runs fn.
+func (t *bootTracer) phase(p BootPhase, fn func() error) error {
+	if t == nil {
+		return fn()
+	}
+	begin := t.now()
+	err := fn()
+	t.trace.Phases = append(t.trace.Phases, PhaseTiming{
+		Phase:    p,
+		Start:    begin.Sub(t.start),
+		Duration: t.now().Sub(begin),
+		Failed:   err != nil,
+	})
+	return err
+}
+
+// result returns a copy of the phases recorded so far.
+func (t *bootTracer) result() BootTrace {
+	if t == nil {
+		return BootTrace{}
+	}
+	return BootTrace{Phases: append([]PhaseTiming(nil), t.trace.Phases...)}
+}
@@ -0,0 +1,114 @@
+//go:build linux
+
+package qemu
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock advances by step every time it is read.
+type fakeClock struct {
+	now  time.Time
+	step time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.now = c.now.Add(c.step)
+	return c.now
+}
+
+func TestBootTracer(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0), step: time.Millisecond}
+	tr := newBootTracerWithClock(clock.Now)
+
+	errVsock := errors.New("vminitd not listening")
+	require.NoError(t, tr.phase(BootPhaseValidate, func() error { return nil }))
+	require.NoError(t, tr.phase(BootPhaseSpawn, func() error {
+		clock.now = clock.now.Add(20 * time.Millisecond)
+		return nil
+	}))
+	require.NoError(t, tr.phase(BootPhaseQMP, func() error { return nil }))
+	require.ErrorIs(t, tr.phase(BootPhaseVsock, func() error {
+		clock.now = clock.now.Add(time.Second)
+		return errVsock
+	}), errVsock)
+
+	trace := tr.result()
+	assert.Equal(t, []PhaseTiming{
+		{Phase: BootPhaseValidate, Start: time.Millisecond, Duration: time.Millisecond},
+		{Phase: BootPhaseSpawn, Start: 3 * time.Millisecond, Duration: 21 * time.Millisecond},
+		{Phase: BootPhaseQMP, Start: 25 * time.Millisecond, Duration: time.Millisecond},
+		{Phase: BootPhaseVsock, Start: 27 * time.Millisecond, Duration: 1001 * time.Millisecond, Failed: true},
+	}, trace.Phases)
+	assert.Equal(t, 1028*time.Millisecond, trace.Total())
+	assert.Equal(t, "validate=1ms spawn=21ms qmp=1ms vsock=1.001s(failed)", trace.String())
+
+	vsock, ok := trace.Phase(BootPhaseVsock)
+	require.True(t, ok)
+	assert.True(t, vsock.Failed)
+	_, ok = trace.Phase(BootPhaseNetwork)
+	assert.False(t, ok)
+
+	// The result is a snapshot
+	require.NoError(t, tr.phase(BootPhaseConsole, func() error { return nil }))
+	assert.Len(t, trace.Phases, 4)
+}
+
+func TestBootTracerNil(t *testing.T) {
+	var tr *bootTracer
+	ran := false
+	require.NoError(t, tr.phase(BootPhaseQMP, func() error {
+		ran = true
+		return nil
+	}))
+	assert.True(t, ran)
+	assert.Empty(t, tr.result().Phases)
+	assert.Zero(t, tr.result().Total())
+}
+
+func TestWaitBootTimeoutPhases(t *testing.T) {
+	q := newBootTestInstance(t, "", "")
+	tr := newBootTracer()
+
+	// A guest whose QMP socket comes up but whose vminitd never answers
+	err := q.waitBoot(context.Background(), 50*time.Millisecond, tr, func(ctx context.Context) error {
+		if err := tr.phase(BootPhaseQMP, func() error { return nil }); err != nil {
+			return err
+		}
+		return tr.phase(BootPhaseVsock, func() error { return neverReady(ctx) })
+	})
+
+	var bootErr *BootTimeoutError
+	require.ErrorAs(t, err, &bootErr)
+	require.Len(t, bootErr.Phases.Phases, 2)
+
+	qmp, vsock := bootErr.Phases.Phases[0], bootErr.Phases.Phases[1]
+	assert.Equal(t, BootPhaseQMP, qmp.Phase)
+	assert.False(t, qmp.Failed)
+	assert.Equal(t, BootPhaseVsock, vsock.Phase)
+	assert.True(t, vsock.Failed)
+	assert.GreaterOrEqual(t, vsock.Start, qmp.Start+qmp.Duration)
+	assert.GreaterOrEqual(t, vsock.Duration, 40*time.Millisecond)
+	assert.Contains(t, err.Error(), "(phases: qmp=")
+	assert.Contains(t, err.Error(), "(failed)")
+}
+
+func TestStartRecordsBootTrace(t *testing.T) {
+	q := &Instance{}
+	assert.Empty(t, q.BootTrace().Phases)
+
+	// Validation fails: the kernel does not exist
+	require.Error(t, q.Start(t.Context()))
+
+	trace := q.BootTrace()
+	require.Len(t, trace.Phases, 1)
+	assert.Equal(t, BootPhaseValidate, trace.Phases[0].Phase)
+	assert.True(t, trace.Phases[0].Failed)
+	assert.Equal(t, vmStateNew, q.getState())
+}
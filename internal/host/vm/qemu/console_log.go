@@ -0,0 +1,166 @@
+//go:build linux
+
+package qemu
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultConsoleLogMaxSize is the size at which the console log rotates.
+	defaultConsoleLogMaxSize = 10 * 1024 * 1024
+	// defaultConsoleLogMaxFiles is how many rotated console logs are kept.
+	defaultConsoleLogMaxFiles = 3
+)
+
+// ConsoleLogConfig controls the console log kept under the VM's log
+// directory. The log is appended to across VM lifetimes of the same
+// container, so boot output survives for post-mortem analysis.
+type ConsoleLogConfig struct {
+	// MaxSize is the size in bytes at which console.log is rotated.
+	MaxSize int64
+	// MaxFiles is how many rotated files (console.log.1 being the newest)
+	// are kept. Zero discards the old log on rotation.
+	MaxFiles int
+}
+
+// DefaultConsoleLogConfig returns the console log limits used by newInstance.
+func DefaultConsoleLogConfig() ConsoleLogConfig {
+	return ConsoleLogConfig{
+		MaxSize:  defaultConsoleLogMaxSize,
+		MaxFiles: defaultConsoleLogMaxFiles,
+	}
+}
+
+// Validate checks that the size cap is positive and MaxFiles is not negative.
+func (c ConsoleLogConfig) Validate() error {
+	if c.MaxSize <= 0 {
+		return fmt.Errorf("console log max size must be positive, got %d", c.MaxSize)
+	}
+	if c.MaxFiles < 0 {
+		return fmt.Errorf("console log max files must not be negative, got %d", c.MaxFiles)
+	}
+	return nil
+}
+
+// SetConsoleLogConfig overrides the console log limits of the instance.
+// It takes effect the next time the VM is started.
+func (q *Instance) SetConsoleLogConfig(cfg ConsoleLogConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.consoleLogCfg = cfg
+	return nil
+}
+
+// consoleLogLimits returns the configured limits, or the defaults for an
+// instance that was not built by newInstance.
+// Must be called with q.mu held.
+func (q *Instance) consoleLogLimits() ConsoleLogConfig {
+	if q.consoleLogCfg == (ConsoleLogConfig{}) {
+		return DefaultConsoleLogConfig()
+	}
+	return q.consoleLogCfg
+}
+
+// consoleLog is an append-only log file rotated once it reaches a size cap.
+// A single write larger than the cap goes to a fresh file as a whole.
+type consoleLog struct {
+	path string
+	cfg  ConsoleLogConfig
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+}
+
+// openConsoleLog opens the log at path for appending and writes a marker
+// separating this VM's output from earlier runs.
+func openConsoleLog(path string, cfg ConsoleLogConfig) (*consoleLog, error) {
+	c := &consoleLog{path: path, cfg: cfg}
+	if err := c.open(os.O_APPEND); err != nil {
+		return nil, err
+	}
+	marker := fmt.Sprintf("--- spinbox: VM console opened at %s ---\n", time.Now().UTC().Format(time.RFC3339))
+	if _, err := c.Write([]byte(marker)); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *consoleLog) open(flag int) error {
+	f, err := os.OpenFile(c.path, os.O_WRONLY|os.O_CREATE|flag, 0o600)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	c.f, c.size = f, fi.Size()
+	return nil
+}
+
+func (c *consoleLog) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.f == nil {
+		return 0, os.ErrClosed
+	}
+	if c.size > 0 && c.size+int64(len(p)) > c.cfg.MaxSize {
+		if err := c.rotate(); err != nil {
+			return 0, fmt.Errorf("rotate console log: %w", err)
+		}
+	}
+	n, err := c.f.Write(p)
+	c.size += int64(n)
+	return n, err
+}
+
+// rotate shifts console.log.N to console.log.N+1, dropping the oldest, and
+// starts a new console.log. Must be called with c.mu held.
+func (c *consoleLog) rotate() error {
+	if err := c.f.Close(); err != nil {
+		return err
+	}
+	c.f = nil
+	if c.cfg.MaxFiles == 0 {
+		if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	for i := c.cfg.MaxFiles; i > 0; i-- {
+		src := c.path
+		if i > 1 {
+			src = fmt.Sprintf("%s.%d", c.path, i-1)
+		}
+		if err := os.Rename(src, fmt.Sprintf("%s.%d", c.path, i)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return c.open(os.O_TRUNC)
+}
+
+// Close closes the log. Like (*os.File).Close it is safe on a nil log.
+func (c *consoleLog) Close() error {
+	if c == nil {
+		return os.ErrInvalid
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.f == nil {
+		return os.ErrClosed
+	}
+	err := c.f.Close()
+	c.f = nil
+	return err
+}
@@ -0,0 +1,113 @@
+//go:build linux
+
+package qemu
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsoleLogConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultConsoleLogConfig().Validate())
+	assert.NoError(t, ConsoleLogConfig{MaxSize: 1}.Validate())
+	assert.Error(t, ConsoleLogConfig{MaxFiles: 1}.Validate())
+	assert.Error(t, ConsoleLogConfig{MaxSize: 1, MaxFiles: -1}.Validate())
+
+	q := &Instance{}
+	assert.Equal(t, DefaultConsoleLogConfig(), q.consoleLogLimits())
+	require.Error(t, q.SetConsoleLogConfig(ConsoleLogConfig{}))
+	require.NoError(t, q.SetConsoleLogConfig(ConsoleLogConfig{MaxSize: 4096, MaxFiles: 1}))
+	assert.Equal(t, ConsoleLogConfig{MaxSize: 4096, MaxFiles: 1}, q.consoleLogLimits())
+}
+
+func TestConsoleLogRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "console.log")
+	readFile := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(name)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	c, err := openConsoleLog(path, ConsoleLogConfig{MaxSize: 100, MaxFiles: 2})
+	require.NoError(t, err)
+	marker := readFile(path)
+	assert.Contains(t, marker, "VM console opened")
+
+	line := strings.Repeat("a", 39) + "\n"
+	_, err = c.Write([]byte(line))
+	require.NoError(t, err)
+	assert.Equal(t, marker+line, readFile(path), "under the cap nothing rotates")
+	assert.NoFileExists(t, path+".1")
+
+	// Each file holds two lines of 40 bytes, the first the marker and one line
+	for _, l := range []string{"b", "c", "d", "e"} {
+		_, err = c.Write([]byte(strings.Repeat(l, 39) + "\n"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, c.Close())
+
+	assert.FileExists(t, path+".1")
+	assert.FileExists(t, path+".2")
+	assert.NoFileExists(t, path+".3")
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		fi, err := os.Stat(name)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, fi.Size(), int64(100), name)
+	}
+	assert.Equal(t, strings.Repeat("d", 39)+"\n"+strings.Repeat("e", 39)+"\n", readFile(path))
+	assert.Equal(t, strings.Repeat("b", 39)+"\n"+strings.Repeat("c", 39)+"\n", readFile(path+".1"))
+	assert.Equal(t, marker+line, readFile(path+".2"), "oldest rotated file keeps the start of the log")
+
+	_, err = c.Write([]byte("late"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+
+	t.Run("appends across VM lifetimes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "console.log")
+		for _, out := range []string{"first boot\n", "second boot\n"} {
+			c, err := openConsoleLog(path, DefaultConsoleLogConfig())
+			require.NoError(t, err)
+			_, err = c.Write([]byte(out))
+			require.NoError(t, err)
+			require.NoError(t, c.Close())
+		}
+		data := readFile(path)
+		assert.Contains(t, data, "first boot\n")
+		assert.Contains(t, data, "second boot\n")
+		assert.Equal(t, 2, strings.Count(data, "VM console opened"))
+	})
+}
+
+func TestSetupConsoleFIFO(t *testing.T) {
+	dir := t.TempDir()
+	q := &Instance{
+		consolePath:     filepath.Join(dir, "console.log"),
+		consoleFifoPath: filepath.Join(dir, "console.fifo"),
+	}
+	require.NoError(t, q.SetConsoleLogConfig(ConsoleLogConfig{MaxSize: 64, MaxFiles: 1}))
+	require.NoError(t, q.setupConsoleFIFO(t.Context()))
+
+	// Act as QEMU writing the serial console
+	fifo, err := os.OpenFile(q.consoleFifoPath, os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = fifo.WriteString("[    0.000000] Linux version 6.1.0\n")
+	require.NoError(t, err)
+	_, err = fifo.WriteString("[    0.500000] vminitd: started\n")
+	require.NoError(t, err)
+	require.NoError(t, fifo.Close())
+
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(q.consolePath)
+		return err == nil && strings.Contains(string(data), "vminitd: started")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	rotated, err := os.ReadFile(q.consolePath + ".1")
+	require.NoError(t, err, "writes past the size cap rotate the log")
+	assert.Contains(t, string(rotated), "VM console opened")
+}
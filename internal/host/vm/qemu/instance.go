@@ -126,36 +126,19 @@ func findQemu() (string, error) {
 	return "", fmt.Errorf("qemu-system-x86_64 binary not found at %s", path)
 }
 
-// findKernel returns the path to the kernel binary for QEMU
-func findKernel() (string, error) {
-	cfg, err := config.Get()
-	if err != nil {
-		return "", fmt.Errorf("failed to get config: %w", err)
-	}
-
-	path := paths.KernelPath(cfg.Paths)
-	if _, err := os.Stat(path); err == nil {
-		return path, nil
-	}
-	return "", fmt.Errorf("kernel not found at %s (use SPINBOX_SHARE_DIR to override)", path)
-}
-
-// findInitrd returns the path to the initrd for QEMU
-func findInitrd() (string, error) {
-	cfg, err := config.Get()
-	if err != nil {
-		return "", fmt.Errorf("failed to get config: %w", err)
-	}
-
-	path := paths.InitrdPath(cfg.Paths)
-	if _, err := os.Stat(path); err == nil {
-		return path, nil
-	}
-	return "", fmt.Errorf("initrd not found at %s (use SPINBOX_SHARE_DIR to override)", path)
+func init() {
+	vm.Register(vm.BackendQEMU, NewInstance)
 }
 
 // NewInstance creates a new QEMU VM instance.
 func NewInstance(ctx context.Context, containerID, stateDir string, cfg *vm.VMResourceConfig) (vm.Instance, error) {
+	// A nil config selects the defaults; an explicit one must be consistent
+	if cfg != nil {
+		if err := cfg.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid VM resource config: %w", err)
+		}
+	}
+
 	binaryPath, err := findQemu()
 	if err != nil {
 		return nil, err
@@ -170,19 +153,17 @@ func NewInstance(ctx context.Context, containerID, stateDir string, cfg *vm.VMRe
 
 // newInstance creates a new QEMU microvm instance
 func newInstance(ctx context.Context, containerID, binaryPath, stateDir string, resourceCfg *vm.VMResourceConfig) (*Instance, error) {
-	kernelPath, err := findKernel()
-	if err != nil {
-		return nil, err
-	}
+	// Validate and apply defaults to resource configuration
+	resourceCfg = validateResourceConfig(resourceCfg)
 
-	initrdPath, err := findInitrd()
+	// Resolve the kernel and initrd now so a missing variant fails at create
+	// time rather than at boot. Guests always run the host architecture: the
+	// QEMU binary is the host's and runs with KVM.
+	boot, err := ResolveBootArtifacts("")
 	if err != nil {
 		return nil, err
 	}
 
-	// Validate and apply defaults to resource configuration
-	resourceCfg = validateResourceConfig(resourceCfg)
-
 	// Get config for log directory
 	cfg, err := config.Get()
 	if err != nil {
@@ -213,8 +194,8 @@ func newInstance(ctx context.Context, containerID, binaryPath, stateDir string,
 		binaryPath:      binaryPath,
 		stateDir:        p.stateDir,
 		logDir:          p.logDir,
-		kernelPath:      kernelPath,
-		initrdPath:      initrdPath,
+		kernelPath:      boot.Kernel,
+		initrdPath:      boot.Initrd,
 		qmpSocketPath:   p.qmpSocketPath,
 		vsockPath:       p.vsockPath,
 		consolePath:     p.consolePath,
@@ -225,11 +206,19 @@ func newInstance(ctx context.Context, containerID, binaryPath, stateDir string,
 		resourceCfg:     resourceCfg,
 		guestCID:        lease.CID,
 		cidLease:        lease,
+		shutdownCfg:     DefaultShutdownConfig(),
+		consoleLogCfg:   DefaultConsoleLogConfig(),
+	}
+
+	if err := inst.SetShutdownConfig(shutdownConfigFromTimeouts(&cfg.Timeouts)); err != nil {
+		_ = lease.Release()
+		return nil, fmt.Errorf("invalid shutdown timeouts: %w", err)
 	}
 
 	log.G(ctx).WithFields(log.Fields{
 		"containerID":   containerID,
 		"guestCID":      lease.CID,
+		"arch":          boot.Arch,
 		"bootCPUs":      resourceCfg.BootCPUs,
 		"maxCPUs":       resourceCfg.MaxCPUs,
 		"memorySize":    resourceCfg.MemorySize,
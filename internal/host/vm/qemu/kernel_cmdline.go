@@ -84,6 +84,15 @@ func BuildKernelCmdline(cfg KernelCmdlineConfig) string {
 	if netParam := buildNetworkParam(cfg.Network); netParam != "" {
 		parts = append(parts, netParam)
 	}
+	if ipv6Param := buildIPv6Param(cfg.Network); ipv6Param != "" {
+		parts = append(parts, ipv6Param)
+	}
+	if mtuParam := buildMTUParam(cfg.Network); mtuParam != "" {
+		parts = append(parts, mtuParam)
+	}
+	if routesParam := buildRoutesParam(cfg.Network); routesParam != "" {
+		parts = append(parts, routesParam)
+	}
 
 	// Init command with vsock args
 	initArgs := buildInitArgs(cfg)
@@ -118,6 +127,51 @@ func buildNetworkParam(netCfg *vm.NetworkConfig) string {
 	return b.String()
 }
 
+// hasAddress reports whether netCfg configures an IPv4 or IPv6 address.
+func hasAddress(netCfg *vm.NetworkConfig) bool {
+	return netCfg != nil && (netCfg.IP != "" || netCfg.IPv6 != "")
+}
+
+// buildIPv6Param builds the spin.ipv6=<addr/prefix>[@<gateway>] parameter
+// vminitd applies to eth0. The kernel ip= parameter is IPv4-only.
+func buildIPv6Param(netCfg *vm.NetworkConfig) string {
+	if netCfg == nil || netCfg.IPv6 == "" {
+		return ""
+	}
+	param := "spin.ipv6=" + netCfg.IPv6
+	if netCfg.IPv6Gateway != "" {
+		param += "@" + netCfg.IPv6Gateway
+	}
+	return param
+}
+
+// buildMTUParam builds the spin.mtu= parameter vminitd applies to eth0.
+// The kernel ip= parameter has no MTU field.
+func buildMTUParam(netCfg *vm.NetworkConfig) string {
+	if !hasAddress(netCfg) || netCfg.MTU <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("spin.mtu=%d", netCfg.MTU)
+}
+
+// buildRoutesParam builds the spin.routes= parameter vminitd installs on
+// eth0: comma-separated <dst-cidr>[@<gateway>] entries. The default route
+// is carried by the ip= gateway.
+func buildRoutesParam(netCfg *vm.NetworkConfig) string {
+	if !hasAddress(netCfg) || len(netCfg.Routes) == 0 {
+		return ""
+	}
+	entries := make([]string, 0, len(netCfg.Routes))
+	for _, r := range netCfg.Routes {
+		entry := r.Dst
+		if r.Gateway != "" {
+			entry += "@" + r.Gateway
+		}
+		entries = append(entries, entry)
+	}
+	return "spin.routes=" + strings.Join(entries, ",")
+}
+
 // buildInitArgs constructs the init arguments list.
 func buildInitArgs(cfg KernelCmdlineConfig) []string {
 	args := []string{
@@ -211,6 +211,37 @@ func TestBuildNetworkParam(t *testing.T) {
 	}
 }
 
+func TestBuildMTUParam(t *testing.T) {
+	assert.Empty(t, buildMTUParam(nil))
+	assert.Empty(t, buildMTUParam(&vm.NetworkConfig{MTU: 1450}), "no IP means no network config")
+	assert.Empty(t, buildMTUParam(&vm.NetworkConfig{IP: "192.168.1.10"}))
+	assert.Equal(t, "spin.mtu=1450", buildMTUParam(&vm.NetworkConfig{IP: "192.168.1.10", MTU: 1450}))
+}
+
+func TestBuildRoutesParam(t *testing.T) {
+	assert.Empty(t, buildRoutesParam(nil))
+	assert.Empty(t, buildRoutesParam(&vm.NetworkConfig{IP: "192.168.1.10"}))
+	assert.Equal(t, "spin.routes=10.96.0.0/12@192.168.1.254,172.16.0.0/24",
+		buildRoutesParam(&vm.NetworkConfig{
+			IP: "192.168.1.10",
+			Routes: []vm.Route{
+				{Dst: "10.96.0.0/12", Gateway: "192.168.1.254"},
+				{Dst: "172.16.0.0/24"},
+			},
+		}))
+}
+
+func TestBuildIPv6Param(t *testing.T) {
+	assert.Empty(t, buildIPv6Param(nil))
+	assert.Empty(t, buildIPv6Param(&vm.NetworkConfig{IP: "192.168.1.10"}))
+	assert.Equal(t, "spin.ipv6=fd00::5/64", buildIPv6Param(&vm.NetworkConfig{IPv6: "fd00::5/64"}))
+	assert.Equal(t, "spin.ipv6=fd00::5/64@fd00::1",
+		buildIPv6Param(&vm.NetworkConfig{IPv6: "fd00::5/64", IPv6Gateway: "fd00::1"}))
+
+	// IPv6-only networks still carry the MTU
+	assert.Equal(t, "spin.mtu=1400", buildMTUParam(&vm.NetworkConfig{IPv6: "fd00::5/64", MTU: 1400}))
+}
+
 func TestBuildInitArgs(t *testing.T) {
 	tests := []struct {
 		name string
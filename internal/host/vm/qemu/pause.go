@@ -0,0 +1,79 @@
+//go:build linux
+
+package qemu
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+)
+
+// Pause freezes all vCPUs of a running VM with QMP stop. Guest memory and
+// devices are kept; the VM stops consuming host CPU until Resume. While
+// paused, guest RPCs are unavailable and only Resume and Shutdown are allowed.
+func (q *Instance) Pause(ctx context.Context) error {
+	if !q.compareAndSwapState(vmStateRunning, vmStatePaused) {
+		return fmt.Errorf("cannot pause VM in state %d: %w", q.getState(), errdefs.ErrFailedPrecondition)
+	}
+
+	q.mu.Lock()
+	qmpClient := q.qmpClient
+	q.mu.Unlock()
+
+	if qmpClient == nil {
+		q.compareAndSwapState(vmStatePaused, vmStateRunning)
+		return fmt.Errorf("cannot pause VM: QMP client not available: %w", errdefs.ErrUnavailable)
+	}
+	if err := qmpClient.Stop(ctx); err != nil {
+		// Shutdown may have taken over meanwhile; only roll back our own state
+		q.compareAndSwapState(vmStatePaused, vmStateRunning)
+		return fmt.Errorf("pause VM: %w", err)
+	}
+
+	log.G(ctx).Info("qemu: VM paused")
+	return nil
+}
+
+// Resume continues a VM paused by Pause with QMP cont.
+func (q *Instance) Resume(ctx context.Context) error {
+	if q.getState() != vmStatePaused {
+		return fmt.Errorf("cannot resume VM in state %d: %w", q.getState(), errdefs.ErrFailedPrecondition)
+	}
+
+	q.mu.Lock()
+	qmpClient := q.qmpClient
+	q.mu.Unlock()
+
+	if qmpClient == nil {
+		return fmt.Errorf("cannot resume VM: QMP client not available: %w", errdefs.ErrUnavailable)
+	}
+
+	// Mark the VM running only once vCPUs run again, so that a failed cont
+	// leaves it paused and a concurrent Shutdown still sees the paused state.
+	if err := qmpClient.Cont(ctx); err != nil {
+		return fmt.Errorf("resume VM: %w", err)
+	}
+	if !q.compareAndSwapState(vmStatePaused, vmStateRunning) {
+		return fmt.Errorf("cannot resume VM in state %d: %w", q.getState(), errdefs.ErrFailedPrecondition)
+	}
+
+	log.G(ctx).Info("qemu: VM resumed")
+	return nil
+}
+
+// resumeForShutdown continues the vCPUs of a VM that was paused when Shutdown
+// was called, so the guest can react to the powerdown request. Failure is
+// logged only: stopQemuProcess quits or kills QEMU regardless.
+func (q *Instance) resumeForShutdown(ctx context.Context, logger *log.Entry) {
+	if q.qmpClient == nil {
+		return
+	}
+	logger.Info("qemu: resuming paused VM before shutdown")
+	contCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), q.shutdownTimeouts().QMPTimeout)
+	defer cancel()
+	if err := q.qmpClient.Cont(contCtx); err != nil {
+		logger.WithError(err).Warning("qemu: failed to resume paused VM, shutdown will quit QEMU")
+	}
+}
@@ -0,0 +1,103 @@
+//go:build linux
+
+package qemu
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstance_PauseResume(t *testing.T) {
+	t.Run("pause and resume", func(t *testing.T) {
+		q, srv := newFakeQMPInstance(t)
+
+		require.NoError(t, q.Pause(t.Context()))
+		assert.Equal(t, vmStatePaused, q.getState())
+
+		require.NoError(t, q.Resume(t.Context()))
+		assert.Equal(t, vmStateRunning, q.getState())
+
+		assert.Equal(t, []string{"stop", "cont"}, srv.received())
+	})
+
+	t.Run("paused VM rejects guest access", func(t *testing.T) {
+		q, _ := newFakeQMPInstance(t)
+		require.NoError(t, q.Pause(t.Context()))
+
+		_, err := q.Client()
+		assert.ErrorIs(t, err, errdefs.ErrFailedPrecondition)
+		_, err = q.CPUHotplugger()
+		assert.ErrorIs(t, err, errdefs.ErrFailedPrecondition)
+	})
+
+	t.Run("rejects pause when not running", func(t *testing.T) {
+		for _, state := range []vmState{vmStateNew, vmStateStarting, vmStatePaused, vmStateShutdown} {
+			q, srv := newFakeQMPInstance(t)
+			q.setState(state)
+
+			err := q.Pause(t.Context())
+			assert.ErrorIs(t, err, errdefs.ErrFailedPrecondition, "state %d", state)
+			assert.Equal(t, state, q.getState())
+			assert.Empty(t, srv.received())
+		}
+	})
+
+	t.Run("rejects resume when not paused", func(t *testing.T) {
+		for _, state := range []vmState{vmStateNew, vmStateStarting, vmStateRunning, vmStateShutdown} {
+			q, srv := newFakeQMPInstance(t)
+			q.setState(state)
+
+			err := q.Resume(t.Context())
+			assert.ErrorIs(t, err, errdefs.ErrFailedPrecondition, "state %d", state)
+			assert.Equal(t, state, q.getState())
+			assert.Empty(t, srv.received())
+		}
+	})
+
+	t.Run("failed stop keeps VM running", func(t *testing.T) {
+		q := newFakeQMPInstanceWith(t, &fakeQMPServer{
+			handle: func(string, map[string]any) (any, error) {
+				return nil, errors.New("stop failed")
+			},
+		})
+
+		require.Error(t, q.Pause(t.Context()))
+		assert.Equal(t, vmStateRunning, q.getState())
+	})
+
+	t.Run("failed cont keeps VM paused", func(t *testing.T) {
+		srv := &fakeQMPServer{
+			handle: func(execute string, _ map[string]any) (any, error) {
+				if execute == "cont" {
+					return nil, errors.New("cont failed")
+				}
+				return nil, nil
+			},
+		}
+		q := newFakeQMPInstanceWith(t, srv)
+
+		require.NoError(t, q.Pause(t.Context()))
+		require.Error(t, q.Resume(t.Context()))
+		assert.Equal(t, vmStatePaused, q.getState())
+	})
+
+	t.Run("shutdown from paused state", func(t *testing.T) {
+		q, srv := newFakeQMPInstance(t)
+		require.NoError(t, q.Pause(t.Context()))
+
+		require.NoError(t, q.Shutdown(t.Context()))
+		assert.Equal(t, vmStateShutdown, q.getState())
+
+		// vCPUs are resumed so the guest can handle the powerdown request
+		calls := srv.received()
+		require.GreaterOrEqual(t, len(calls), 3)
+		assert.Equal(t, []string{"stop", "cont"}, calls[:2])
+
+		assert.ErrorIs(t, q.Resume(t.Context()), errdefs.ErrFailedPrecondition)
+		assert.ErrorIs(t, q.Pause(t.Context()), errdefs.ErrFailedPrecondition)
+	})
+}
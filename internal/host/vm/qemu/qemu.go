@@ -7,13 +7,15 @@
 // The VM instance follows a strict state machine for lifecycle management:
 //
 //	vmStateNew → vmStateStarting → vmStateRunning → vmStateShutdown
-//	    ↑              ↓
-//	    └──────────────┘ (on Start failure)
+//	    ↑              ↓         Pause() ↓  ↑ Resume()      ↑
+//	    └──────────────┘          vmStatePaused ────────────┘
+//	  (on Start failure)
 //
 // State transitions are atomic (using sync/atomic) and checked at API boundaries:
-//   - New: Instance created, not started. AddDisk/AddNIC allowed.
+//   - New: Instance created, not started. AddDisk/AddBlockDevice/AddNIC allowed.
 //   - Starting: Start() in progress. No API calls allowed.
-//   - Running: VM is running. Client/DialClient/StartStream/Shutdown allowed.
+//   - Running: VM is running. Client/DialClient/StartStream/AddBlockDevice/Pause/Shutdown allowed.
+//   - Paused: vCPUs stopped via QMP. Only Resume/Shutdown allowed.
 //   - Shutdown: Shutdown() called or completed. No further operations.
 //
 // # Goroutine Ownership
@@ -68,7 +70,7 @@ type vmState int32
 
 const (
 	// vmStateNew: Instance created but Start() not called.
-	// Allowed operations: AddDisk(), AddNIC(), AddTAPNIC(), Start()
+	// Allowed operations: AddDisk(), AddBlockDevice(), AddNIC(), AddTAPNIC(), Start()
 	vmStateNew vmState = iota
 
 	// vmStateStarting: Start() is executing.
@@ -76,9 +78,13 @@ const (
 	vmStateStarting
 
 	// vmStateRunning: VM is fully initialized and running.
-	// Allowed operations: Client(), DialClient(), StartStream(), Shutdown()
+	// Allowed operations: Client(), DialClient(), StartStream(), AddBlockDevice(), Shutdown()
 	vmStateRunning
 
+	// vmStatePaused: Pause() stopped all vCPUs; memory and devices are kept.
+	// Allowed operations: Resume(), Shutdown()
+	vmStatePaused
+
 	// vmStateShutdown: Shutdown() was called or VM exited.
 	// No operations allowed. Terminal state.
 	vmStateShutdown
@@ -129,32 +135,43 @@ type Instance struct {
 	// - cmd, qmpClient, client, vsockConn: Written during Start(), read during operation
 	mu sync.Mutex
 
+	// memHotplugMu serializes AddMemory calls.
+	memHotplugMu sync.Mutex
+
+	// diskHotplugMu serializes AddBlockDevice calls on a running VM.
+	diskHotplugMu sync.Mutex
+
 	// vmState tracks lifecycle (see vmState constants).
 	// Accessed atomically, no mutex needed.
 	vmState atomic.Uint32
 
+	// bootTrace holds the phase timings of the last Start call.
+	bootTrace atomic.Pointer[BootTrace]
+
 	// streamC is a monotonically increasing stream ID counter.
 	// Accessed atomically for unique stream IDs.
 	streamC uint32
 
 	// Configuration
-	binaryPath  string
-	stateDir    string
-	logDir      string
-	kernelPath  string
-	initrdPath  string
-	resourceCfg *vm.VMResourceConfig
-	guestCID    uint32            // Unique vsock CID for this VM (3+)
-	cidLease    *vsockalloc.Lease // CID reservation (released on close)
+	binaryPath    string
+	stateDir      string
+	logDir        string
+	kernelPath    string
+	initrdPath    string
+	resourceCfg   *vm.VMResourceConfig
+	guestCID      uint32            // Unique vsock CID for this VM (3+)
+	cidLease      *vsockalloc.Lease // CID reservation (released on close)
+	shutdownCfg   ShutdownConfig    // Shutdown stage timeouts (see SetShutdownConfig)
+	consoleLogCfg ConsoleLogConfig  // Console log rotation (see SetConsoleLogConfig)
 
 	// Runtime paths
-	qmpSocketPath   string   // QMP control socket
-	vsockPath       string   // Vsock socket
-	consolePath     string   // Persistent console log file (logDir) - receives console output from FIFO reader
-	consoleFifoPath string   // Ephemeral FIFO pipe (stateDir) - QEMU writes here, prevents blocking on slow disk I/O
-	qemuLogPath     string   // QEMU stderr log
-	consoleFile     *os.File // Console log file handle
-	consoleFifo     *os.File // FIFO reader handle (closed on shutdown to cancel console goroutine)
+	qmpSocketPath   string      // QMP control socket
+	vsockPath       string      // Vsock socket
+	consolePath     string      // Persistent console log file (logDir) - receives console output from FIFO reader
+	consoleFifoPath string      // Ephemeral FIFO pipe (stateDir) - QEMU writes here, prevents blocking on slow disk I/O
+	qemuLogPath     string      // QEMU stderr log
+	consoleFile     *consoleLog // Console log file (rotated, see SetConsoleLogConfig)
+	consoleFifo     *os.File    // FIFO reader handle (closed on shutdown to cancel console goroutine)
 
 	// Runtime state
 	cmd       *exec.Cmd
@@ -163,6 +180,10 @@ type Instance struct {
 	client    *ttrpc.Client
 	vsockConn net.Conn
 
+	// dialVsock connects to a guest vsock port; nil means vsock.Dial.
+	// Tests replace it to simulate a guest that is still booting.
+	dialVsock func(cid, port uint32) (net.Conn, error)
+
 	// Long-lived context for background monitors started after the VM boots.
 	// This is a valid exception to the "no context in struct" rule because:
 	// 1. The context represents the VM instance's lifetime, not a single operation
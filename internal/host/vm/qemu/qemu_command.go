@@ -177,15 +177,7 @@ func (b *qemuCommandBuilder) setQMPUnixSocket(socketPath string) *qemuCommandBui
 //   - .qcow2 → qcow2
 //   - default → raw
 func (b *qemuCommandBuilder) addDisk(id string, disk *DiskConfig) *qemuCommandBuilder {
-	// Detect format based on file extension
-	format := "raw"
-	if strings.HasSuffix(disk.Path, ".vmdk") {
-		format = "vmdk"
-	} else if strings.HasSuffix(disk.Path, ".qcow2") {
-		format = "qcow2"
-	}
-
-	driveArgs := fmt.Sprintf("file=%s,if=none,id=%s,format=%s", disk.Path, id, format)
+	driveArgs := fmt.Sprintf("file=%s,if=none,id=%s,format=%s", disk.Path, id, diskFormat(disk.Path))
 	if disk.Readonly {
 		driveArgs += ",readonly=on"
 	}
@@ -194,6 +186,27 @@ func (b *qemuCommandBuilder) addDisk(id string, disk *DiskConfig) *qemuCommandBu
 	return b
 }
 
+// diskFormat detects the image format of a disk from its file extension.
+func diskFormat(path string) string {
+	switch {
+	case strings.HasSuffix(path, ".vmdk"):
+		return "vmdk"
+	case strings.HasSuffix(path, ".qcow2"):
+		return "qcow2"
+	default:
+		return "raw"
+	}
+}
+
+// addPCIeRootPort adds an empty PCIe root port that devices can be
+// hotplugged into. Devices on the q35 root complex itself cannot be
+// hotplugged. chassis must be unique per port.
+//
+//	-device pcie-root-port,id=<id>,bus=pcie.0,chassis=<chassis>
+func (b *qemuCommandBuilder) addPCIeRootPort(id string, chassis int) *qemuCommandBuilder {
+	return b.addDevice(fmt.Sprintf("pcie-root-port,id=%s,bus=pcie.0,chassis=%d", id, chassis))
+}
+
 // NICConfig represents a network interface configuration.
 type NICConfig struct {
 	TapFD int    // File descriptor number (3+ for ExtraFiles)
@@ -0,0 +1,152 @@
+//go:build linux
+
+package qemu
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+)
+
+func blockHotplugPortID(i int) string {
+	return fmt.Sprintf("blkport%d", i)
+}
+
+// HotplugBlockDevice attaches the image at path to the running VM as a
+// virtio-blk device with ID id, plugged into the PCIe root port bus.
+func (q *qmpClient) HotplugBlockDevice(ctx context.Context, id, bus, path string, readonly bool) error {
+	nodeName := id + "-node"
+	if _, err := q.execute(ctx, "blockdev-add", map[string]any{
+		"driver":    diskFormat(path),
+		"node-name": nodeName,
+		"read-only": readonly,
+		"file": map[string]any{
+			"driver":    "file",
+			"filename":  path,
+			"read-only": readonly,
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to add block backend: %w", err)
+	}
+
+	if err := q.DeviceAdd(ctx, "virtio-blk-pci", map[string]any{
+		"id":    id,
+		"drive": nodeName,
+		"bus":   bus,
+	}); err != nil {
+		if _, delErr := q.execute(ctx, "blockdev-del", map[string]any{"node-name": nodeName}); delErr != nil {
+			log.G(ctx).WithError(delErr).Warn("qemu: failed to cleanup block backend after device_add failure")
+		}
+		return fmt.Errorf("failed to hotplug block device: %w", err)
+	}
+	return nil
+}
+
+// AddBlockDevice attaches the disk image at path to the VM as a virtio-blk
+// data disk and returns the name the guest gives it (vdb, vdc, ...); the
+// guest's devices.WaitForBlockDevices waits for it to appear.
+//
+// Before Start the disk is added to the QEMU command line like AddDisk. On a
+// running VM it is hotplugged over QMP into one of the root ports reserved at
+// boot (VMResourceConfig.BlockHotplugPorts); without reserved ports the call
+// fails with ErrFailedPrecondition, and once all are used with
+// ErrResourceExhausted. A path that does not exist fails with ErrNotFound.
+func (q *Instance) AddBlockDevice(ctx context.Context, path string, readonly bool) (string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("block device image %s: %w", path, errdefs.ErrNotFound)
+		}
+		return "", fmt.Errorf("failed to stat block device image: %w", err)
+	}
+	if fi.IsDir() {
+		return "", fmt.Errorf("block device image %s is a directory: %w", path, errdefs.ErrInvalidArgument)
+	}
+	diskID, err := generateStableDiskID(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate stable disk ID: %w", err)
+	}
+
+	switch q.getState() {
+	case vmStateNew:
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		name := guestBlockDeviceName(len(q.disks))
+		q.disks = append(q.disks, &DiskConfig{ID: diskID, Path: path, Readonly: readonly})
+
+		log.G(ctx).WithFields(log.Fields{
+			"path":     path,
+			"readonly": readonly,
+			"device":   name,
+		}).Debug("qemu: scheduled data disk")
+		return name, nil
+	case vmStateRunning:
+	default:
+		return "", fmt.Errorf("vm not running: %w", errdefs.ErrFailedPrecondition)
+	}
+
+	// Serialize so concurrent calls cannot pick the same port or name
+	q.diskHotplugMu.Lock()
+	defer q.diskHotplugMu.Unlock()
+
+	q.mu.Lock()
+	qmpClient := q.qmpClient
+	ports := 0
+	if q.resourceCfg != nil {
+		ports = q.resourceCfg.BlockHotplugPorts
+	}
+	index := len(q.disks)
+	used := make(map[string]bool, len(q.disks))
+	for _, d := range q.disks {
+		used[d.Port] = true
+	}
+	q.mu.Unlock()
+
+	if qmpClient == nil {
+		return "", fmt.Errorf("QMP client not available: %w", errdefs.ErrFailedPrecondition)
+	}
+
+	if ports == 0 {
+		return "", fmt.Errorf("no block hotplug ports reserved at boot: %w", errdefs.ErrFailedPrecondition)
+	}
+	port := ""
+	for i := range ports {
+		if id := blockHotplugPortID(i); !used[id] {
+			port = id
+			break
+		}
+	}
+	if port == "" {
+		return "", fmt.Errorf("all %d block hotplug ports are in use: %w", ports, errdefs.ErrResourceExhausted)
+	}
+
+	name := guestBlockDeviceName(index)
+	log.G(ctx).WithFields(log.Fields{
+		"path":     path,
+		"readonly": readonly,
+		"port":     port,
+		"device":   name,
+	}).Info("qemu: hotplugging data disk")
+
+	if err := qmpClient.HotplugBlockDevice(ctx, fmt.Sprintf("blk%d", index), port, path, readonly); err != nil {
+		return "", err
+	}
+
+	q.mu.Lock()
+	q.disks = append(q.disks, &DiskConfig{ID: diskID, Path: path, Readonly: readonly, Port: port})
+	q.mu.Unlock()
+	return name, nil
+}
+
+// guestBlockDeviceName returns the name the guest kernel gives the virtio-blk
+// device with the given index: vda to vdz, then vdaa, vdab, ...
+func guestBlockDeviceName(index int) string {
+	var suffix []byte
+	for ; index >= 0; index = index/26 - 1 {
+		suffix = append([]byte{byte('a' + index%26)}, suffix...)
+	}
+	return "vd" + string(suffix)
+}
@@ -0,0 +1,177 @@
+//go:build linux
+
+package qemu
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spin-stack/spinbox/internal/host/vm"
+)
+
+func writeDiskImage(t *testing.T, name string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, make([]byte, 4096), 0o600))
+	return path
+}
+
+func TestGuestBlockDeviceName(t *testing.T) {
+	for index, want := range map[int]string{
+		0:   "vda",
+		1:   "vdb",
+		25:  "vdz",
+		26:  "vdaa",
+		27:  "vdab",
+		701: "vdzz",
+		702: "vdaaa",
+	} {
+		assert.Equal(t, want, guestBlockDeviceName(index), "index %d", index)
+	}
+}
+
+func TestInstance_AddBlockDevice(t *testing.T) {
+	t.Run("before start adds the disk to the command line", func(t *testing.T) {
+		q := &Instance{}
+		rootfs := writeDiskImage(t, "rootfs.img")
+		data := writeDiskImage(t, "data.qcow2")
+
+		name, err := q.AddBlockDevice(t.Context(), rootfs, true)
+		require.NoError(t, err)
+		assert.Equal(t, "vda", name)
+		name, err = q.AddBlockDevice(t.Context(), data, false)
+		require.NoError(t, err)
+		assert.Equal(t, "vdb", name)
+
+		require.Len(t, q.disks, 2)
+		assert.Equal(t, data, q.disks[1].Path)
+		assert.False(t, q.disks[1].Readonly)
+		assert.Empty(t, q.disks[1].Port)
+
+		b := newQemuCommandBuilder()
+		for i, d := range q.disks {
+			b.addDisk(guestBlockDeviceName(i), d)
+		}
+		assert.Equal(t, []string{
+			"-drive", "file=" + rootfs + ",if=none,id=vda,format=raw,readonly=on",
+			"-device", "virtio-blk-pci,drive=vda",
+			"-drive", "file=" + data + ",if=none,id=vdb,format=qcow2",
+			"-device", "virtio-blk-pci,drive=vdb",
+		}, b.build())
+	})
+
+	t.Run("running VM hotplugs over QMP", func(t *testing.T) {
+		q, srv := newFakeQMPInstance(t)
+		q.resourceCfg = &vm.VMResourceConfig{BlockHotplugPorts: 2}
+		q.disks = []*DiskConfig{{ID: "rootfs", Path: "/rootfs.img"}}
+		data := writeDiskImage(t, "data.img")
+
+		name, err := q.AddBlockDevice(t.Context(), data, true)
+		require.NoError(t, err)
+		assert.Equal(t, "vdb", name)
+
+		srv.mu.Lock()
+		calls := srv.calls
+		srv.mu.Unlock()
+		require.Len(t, calls, 2)
+		assert.Equal(t, fakeQMPCall{Execute: "blockdev-add", Arguments: map[string]any{
+			"driver":    "raw",
+			"node-name": "blk1-node",
+			"read-only": true,
+			"file": map[string]any{
+				"driver":    "file",
+				"filename":  data,
+				"read-only": true,
+			},
+		}}, calls[0])
+		assert.Equal(t, fakeQMPCall{Execute: "device_add", Arguments: map[string]any{
+			"driver": "virtio-blk-pci",
+			"id":     "blk1",
+			"drive":  "blk1-node",
+			"bus":    "blkport0",
+		}}, calls[1])
+
+		require.Len(t, q.disks, 2)
+		assert.Equal(t, "blkport0", q.disks[1].Port)
+
+		// The next disk goes into the next free port
+		name, err = q.AddBlockDevice(t.Context(), writeDiskImage(t, "scratch.img"), false)
+		require.NoError(t, err)
+		assert.Equal(t, "vdc", name)
+		assert.Equal(t, "blkport1", q.disks[2].Port)
+	})
+
+	t.Run("failed device_add removes the backend", func(t *testing.T) {
+		srv := &fakeQMPServer{handle: func(execute string, args map[string]any) (any, error) {
+			if execute == "device_add" {
+				return nil, errors.New("bus 'blkport0' not found")
+			}
+			return nil, nil
+		}}
+		q := newFakeQMPInstanceWith(t, srv)
+		q.resourceCfg = &vm.VMResourceConfig{BlockHotplugPorts: 1}
+
+		_, err := q.AddBlockDevice(t.Context(), writeDiskImage(t, "data.img"), false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+		assert.Equal(t, []string{"blockdev-add", "device_add", "blockdev-del"}, srv.received())
+		assert.Empty(t, q.disks)
+	})
+
+	t.Run("all hotplug ports in use", func(t *testing.T) {
+		q, srv := newFakeQMPInstance(t)
+		q.resourceCfg = &vm.VMResourceConfig{BlockHotplugPorts: 2}
+		for i := range 2 {
+			q.disks = append(q.disks, &DiskConfig{Port: blockHotplugPortID(i)})
+		}
+
+		_, err := q.AddBlockDevice(t.Context(), writeDiskImage(t, "data.img"), false)
+		require.ErrorIs(t, err, errdefs.ErrResourceExhausted)
+		assert.Empty(t, srv.received())
+	})
+
+	t.Run("no hotplug ports reserved", func(t *testing.T) {
+		q, srv := newFakeQMPInstance(t)
+		q.resourceCfg = &vm.VMResourceConfig{}
+
+		_, err := q.AddBlockDevice(t.Context(), writeDiskImage(t, "data.img"), false)
+		require.ErrorIs(t, err, errdefs.ErrFailedPrecondition)
+		assert.Empty(t, srv.received())
+	})
+
+	t.Run("rejects nonexistent paths", func(t *testing.T) {
+		q, srv := newFakeQMPInstance(t)
+		_, err := q.AddBlockDevice(t.Context(), filepath.Join(t.TempDir(), "missing.img"), false)
+		require.ErrorIs(t, err, errdefs.ErrNotFound)
+		assert.Empty(t, srv.received())
+
+		q = &Instance{}
+		_, err = q.AddBlockDevice(t.Context(), filepath.Join(t.TempDir(), "missing.img"), false)
+		require.ErrorIs(t, err, errdefs.ErrNotFound)
+		assert.Empty(t, q.disks)
+	})
+
+	t.Run("rejects directories", func(t *testing.T) {
+		q := &Instance{}
+		_, err := q.AddBlockDevice(t.Context(), t.TempDir(), false)
+		require.ErrorIs(t, err, errdefs.ErrInvalidArgument)
+	})
+
+	t.Run("rejects shut down VM", func(t *testing.T) {
+		q := &Instance{}
+		q.setState(vmStateShutdown)
+		_, err := q.AddBlockDevice(t.Context(), writeDiskImage(t, "data.img"), false)
+		require.ErrorIs(t, err, errdefs.ErrFailedPrecondition)
+	})
+}
+
+func TestAddPCIeRootPort(t *testing.T) {
+	args := newQemuCommandBuilder().addPCIeRootPort(blockHotplugPortID(0), 1).build()
+	assert.Equal(t, []string{"-device", "pcie-root-port,id=blkport0,bus=pcie.0,chassis=1"}, args)
+}
@@ -232,6 +232,18 @@ func (q *qmpClient) Quit(ctx context.Context) error {
 	return err
 }
 
+// Stop pauses all vCPUs.
+func (q *qmpClient) Stop(ctx context.Context) error {
+	_, err := q.execute(ctx, "stop", nil)
+	return err
+}
+
+// Cont resumes all vCPUs.
+func (q *qmpClient) Cont(ctx context.Context) error {
+	_, err := q.execute(ctx, "cont", nil)
+	return err
+}
+
 // QueryStatus returns the current VM status (running, paused, shutdown, etc).
 func (q *qmpClient) QueryStatus(ctx context.Context) (*qmpStatus, error) {
 	return qmpQuery[*qmpStatus](q, ctx, "query-status")
@@ -6,7 +6,9 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"slices"
 
+	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
 
 	"github.com/spin-stack/spinbox/internal/host/vm"
@@ -161,3 +163,99 @@ func (q *qmpClient) UnplugCPU(ctx context.Context, cpuID int) error {
 
 	return q.DeviceDelete(ctx, deviceID)
 }
+
+// SetVCPUs hotplugs or unplugs vCPUs until count are present, for dynamic
+// right-sizing of a running VM. count must lie between the boot and maximum
+// vCPUs of the resource configuration. It is a no-op when count vCPUs are
+// already present. Scale-up fails with ErrNotImplemented if QEMU reports no
+// free hotpluggable CPU slots.
+func (q *Instance) SetVCPUs(ctx context.Context, count int) error {
+	if q.getState() != vmStateRunning {
+		return fmt.Errorf("vm not running: %w", errdefs.ErrFailedPrecondition)
+	}
+
+	q.mu.Lock()
+	qmpClient := q.qmpClient
+	resources := validateResourceConfig(q.resourceCfg)
+	q.mu.Unlock()
+
+	if count > resources.MaxCPUs {
+		return fmt.Errorf("requested %d vCPUs exceeds configured maximum of %d: %w",
+			count, resources.MaxCPUs, errdefs.ErrInvalidArgument)
+	}
+	if count < resources.BootCPUs {
+		return fmt.Errorf("requested %d vCPUs is below the %d boot vCPUs, which cannot be unplugged: %w",
+			count, resources.BootCPUs, errdefs.ErrInvalidArgument)
+	}
+	if qmpClient == nil {
+		return fmt.Errorf("QMP client not available: %w", errdefs.ErrFailedPrecondition)
+	}
+
+	cpus, err := qmpClient.QueryCPUs(ctx)
+	if err != nil {
+		return fmt.Errorf("query CPUs: %w", err)
+	}
+	current := len(cpus)
+	existing := make(map[int]bool, current)
+	for _, cpu := range cpus {
+		existing[cpu.CPUIndex] = true
+	}
+
+	logger := log.G(ctx).WithFields(log.Fields{
+		"current": current,
+		"target":  count,
+	})
+
+	switch {
+	case count == current:
+		return nil
+
+	case count > current:
+		slots, err := qmpClient.QueryHotpluggableCPUs(ctx)
+		if err != nil {
+			return fmt.Errorf("query hotpluggable CPUs: %w", err)
+		}
+		free := 0
+		for _, slot := range slots {
+			if slot.QOMPath == "" {
+				free++
+			}
+		}
+		if free == 0 {
+			return fmt.Errorf("VM does not support CPU hotplug: no free CPU slots: %w", errdefs.ErrNotImplemented)
+		}
+		if need := count - current; free < need {
+			return fmt.Errorf("VM has %d free CPU slots, need %d: %w", free, need, errdefs.ErrResourceExhausted)
+		}
+
+		logger.Info("qemu: hotplugging vCPUs")
+		for id := 0; current < count && id < resources.MaxCPUs; id++ {
+			if existing[id] {
+				continue
+			}
+			if err := qmpClient.HotplugCPU(ctx, id); err != nil {
+				return fmt.Errorf("hotplug CPU %d: %w", id, err)
+			}
+			current++
+		}
+
+	default:
+		logger.Info("qemu: unplugging vCPUs")
+		// Remove the highest IDs first; CPU 0 is the boot processor
+		ids := slices.Sorted(maps.Keys(existing))
+		for i := len(ids) - 1; i >= 0 && current > count; i-- {
+			if ids[i] == 0 {
+				break
+			}
+			if err := qmpClient.UnplugCPU(ctx, ids[i]); err != nil {
+				return fmt.Errorf("unplug CPU %d: %w", ids[i], err)
+			}
+			current--
+		}
+	}
+
+	if current != count {
+		return fmt.Errorf("reached %d vCPUs, requested %d", current, count)
+	}
+	return nil
+}
@@ -4,7 +4,17 @@ package qemu
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
 	"testing"
+
+	"github.com/containerd/errdefs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spin-stack/spinbox/internal/host/vm"
 )
 
 // TestQMPCPUHotplug tests the CPU hotplug functionality via QMP
@@ -106,3 +116,138 @@ func TestQueryCPUs(t *testing.T) {
 		t.Error("expected at least 1 CPU")
 	}
 }
+
+// fakeCPUQMP simulates the CPU hotplug commands of a VM with maxCPUs slots.
+type fakeCPUQMP struct {
+	mu      sync.Mutex
+	maxCPUs int
+	present map[int]bool
+}
+
+func newFakeCPUQMP(maxCPUs int, present ...int) *fakeCPUQMP {
+	f := &fakeCPUQMP{maxCPUs: maxCPUs, present: make(map[int]bool)}
+	for _, id := range present {
+		f.present[id] = true
+	}
+	return f
+}
+
+func (f *fakeCPUQMP) handle(execute string, args map[string]any) (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch execute {
+	case "query-cpus-fast":
+		var cpus []map[string]any
+		for _, id := range slices.Sorted(maps.Keys(f.present)) {
+			cpus = append(cpus, map[string]any{"cpu-index": id, "qom-path": fmt.Sprintf("/machine/cpu%d", id)})
+		}
+		return cpus, nil
+	case "query-hotpluggable-cpus":
+		var slots []map[string]any
+		for id := range f.maxCPUs {
+			slot := map[string]any{
+				"type":        "host-x86_64-cpu",
+				"vcpus-count": 1,
+				"props":       map[string]any{"socket-id": 0, "core-id": id, "thread-id": 0},
+			}
+			if f.present[id] {
+				slot["qom-path"] = fmt.Sprintf("/machine/cpu%d", id)
+			}
+			slots = append(slots, slot)
+		}
+		return slots, nil
+	case "device_add":
+		id, _ := intFromProp(args["core-id"])
+		f.present[id] = true
+		return nil, nil
+	case "device_del":
+		var id int
+		if _, err := fmt.Sscanf(args["id"].(string), "cpu%d", &id); err != nil {
+			return nil, err
+		}
+		delete(f.present, id)
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unexpected command %s", execute)
+}
+
+// deviceOps returns the device_add/device_del calls as "op id".
+func deviceOps(srv *fakeQMPServer) []string {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	var ops []string
+	for _, c := range srv.calls {
+		if c.Execute == "device_add" || c.Execute == "device_del" {
+			ops = append(ops, fmt.Sprintf("%s %v", c.Execute, c.Arguments["id"]))
+		}
+	}
+	return ops
+}
+
+func TestInstance_SetVCPUs(t *testing.T) {
+	newInstance := func(t *testing.T, cpus *fakeCPUQMP, boot, max int) (*Instance, *fakeQMPServer) {
+		srv := &fakeQMPServer{handle: cpus.handle}
+		q := newFakeQMPInstanceWith(t, srv)
+		q.resourceCfg = &vm.VMResourceConfig{BootCPUs: boot, MaxCPUs: max, MemorySize: defaultMemorySize}
+		return q, srv
+	}
+
+	t.Run("scale up", func(t *testing.T) {
+		cpus := newFakeCPUQMP(4, 0)
+		q, srv := newInstance(t, cpus, 1, 4)
+
+		require.NoError(t, q.SetVCPUs(t.Context(), 3))
+		assert.Equal(t, []string{"device_add cpu1", "device_add cpu2"}, deviceOps(srv))
+
+		calls := srv.received()
+		assert.Equal(t, []string{"query-cpus-fast", "query-hotpluggable-cpus"}, calls[:2])
+		assert.Equal(t, "host-x86_64-cpu", srv.calls[4].Arguments["driver"])
+	})
+
+	t.Run("same count is a no-op", func(t *testing.T) {
+		cpus := newFakeCPUQMP(4, 0, 1)
+		q, srv := newInstance(t, cpus, 1, 4)
+
+		require.NoError(t, q.SetVCPUs(t.Context(), 2))
+		assert.Equal(t, []string{"query-cpus-fast"}, srv.received())
+	})
+
+	t.Run("scale down removes highest first", func(t *testing.T) {
+		cpus := newFakeCPUQMP(4, 0, 1, 2, 3)
+		q, srv := newInstance(t, cpus, 1, 4)
+
+		require.NoError(t, q.SetVCPUs(t.Context(), 2))
+		assert.Equal(t, []string{"device_del cpu3", "device_del cpu2"}, deviceOps(srv))
+	})
+
+	t.Run("over max is rejected", func(t *testing.T) {
+		cpus := newFakeCPUQMP(4, 0)
+		q, srv := newInstance(t, cpus, 1, 4)
+
+		err := q.SetVCPUs(t.Context(), 5)
+		require.ErrorIs(t, err, errdefs.ErrInvalidArgument)
+		assert.Empty(t, srv.received(), "rejected request must not reach QEMU")
+	})
+
+	t.Run("below boot CPUs is rejected", func(t *testing.T) {
+		cpus := newFakeCPUQMP(4, 0, 1)
+		q, _ := newInstance(t, cpus, 2, 4)
+
+		require.ErrorIs(t, q.SetVCPUs(t.Context(), 1), errdefs.ErrInvalidArgument)
+	})
+
+	t.Run("no hotplug slots", func(t *testing.T) {
+		// Every slot is occupied although max allows more
+		cpus := newFakeCPUQMP(1, 0)
+		q, srv := newInstance(t, cpus, 1, 2)
+
+		require.ErrorIs(t, q.SetVCPUs(t.Context(), 2), errdefs.ErrNotImplemented)
+		assert.Empty(t, deviceOps(srv))
+	})
+
+	t.Run("not running", func(t *testing.T) {
+		q := &Instance{}
+		require.ErrorIs(t, q.SetVCPUs(context.Background(), 2), errdefs.ErrFailedPrecondition)
+	})
+}
@@ -0,0 +1,77 @@
+//go:build linux
+
+package qemu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+)
+
+// unsafeQMPCommands end or reset the guest. QMPExecute refuses them unless
+// WithUnsafeQMP is passed; the lifecycle methods own these transitions.
+var unsafeQMPCommands = map[string]bool{
+	"quit":             true,
+	"system_reset":     true,
+	"system_powerdown": true,
+}
+
+// QMPExecuteOpt configures QMPExecute.
+type QMPExecuteOpt func(*qmpExecuteOpts)
+
+type qmpExecuteOpts struct {
+	allowUnsafe bool
+}
+
+// WithUnsafeQMP allows commands that terminate or reset the guest.
+func WithUnsafeQMP() QMPExecuteOpt {
+	return func(o *qmpExecuteOpts) {
+		o.allowUnsafe = true
+	}
+}
+
+// QMPExecute runs an arbitrary QMP command for diagnostics, such as
+// query-status or query-memory-devices during incident response, and
+// returns the raw "return" value of the response. The command is bounded by
+// the QMP command timeout. A QMP error response is returned as an error.
+func (q *Instance) QMPExecute(ctx context.Context, command string, args map[string]any, opts ...QMPExecuteOpt) (json.RawMessage, error) {
+	var o qmpExecuteOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if command == "" {
+		return nil, fmt.Errorf("QMP command is empty: %w", errdefs.ErrInvalidArgument)
+	}
+	if unsafeQMPCommands[command] && !o.allowUnsafe {
+		return nil, fmt.Errorf("QMP command %q stops or resets the VM and requires allowing unsafe commands: %w",
+			command, errdefs.ErrPermissionDenied)
+	}
+
+	if q.getState() != vmStateRunning {
+		return nil, fmt.Errorf("vm not running: %w", errdefs.ErrFailedPrecondition)
+	}
+
+	q.mu.Lock()
+	qmpClient := q.qmpClient
+	q.mu.Unlock()
+
+	if qmpClient == nil {
+		return nil, fmt.Errorf("QMP client not available: %w", errdefs.ErrFailedPrecondition)
+	}
+
+	log.G(ctx).WithField("command", command).Debug("qemu: executing diagnostic QMP command")
+
+	resp, err := qmpClient.execute(ctx, command, args)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(resp.Return)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s response: %w", command, err)
+	}
+	return raw, nil
+}
@@ -0,0 +1,182 @@
+//go:build linux
+
+package qemu
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeQMPServer speaks enough QMP for a qmpClient: it sends the greeting,
+// accepts qmp_capabilities and answers every command with handle. Without
+// a handler, commands are echoed back in their return value and commands
+// named "fail" get a QMP error response.
+type fakeQMPServer struct {
+	// handle returns the return value of a command, or an error to send
+	// as a QMP GenericError.
+	handle func(execute string, args map[string]any) (any, error)
+
+	mu    sync.Mutex
+	calls []fakeQMPCall
+}
+
+// fakeQMPCall is a command received by fakeQMPServer.
+type fakeQMPCall struct {
+	Execute   string
+	Arguments map[string]any
+}
+
+func (s *fakeQMPServer) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	for _, c := range s.calls {
+		names = append(names, c.Execute)
+	}
+	return names
+}
+
+func echoQMP(execute string, args map[string]any) (any, error) {
+	if execute == "fail" {
+		return nil, errors.New("boom")
+	}
+	return map[string]any{"command": execute, "arguments": args}, nil
+}
+
+func (s *fakeQMPServer) serve(l net.Listener) {
+	conn, err := l.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	handle := s.handle
+	if handle == nil {
+		handle = echoQMP
+	}
+
+	enc := json.NewEncoder(conn)
+	_ = enc.Encode(map[string]any{
+		"QMP": map[string]any{
+			"version":      map[string]any{"qemu": map[string]int{"major": 9, "minor": 0, "micro": 0}},
+			"capabilities": []string{},
+		},
+	})
+
+	// Commands are not newline-terminated, decode them as a JSON stream
+	dec := json.NewDecoder(conn)
+	for {
+		var cmd struct {
+			Execute   string         `json:"execute"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		if err := dec.Decode(&cmd); err != nil {
+			return
+		}
+		if cmd.Execute == "qmp_capabilities" {
+			_ = enc.Encode(map[string]any{"return": map[string]any{}})
+			continue
+		}
+
+		s.mu.Lock()
+		s.calls = append(s.calls, fakeQMPCall{Execute: cmd.Execute, Arguments: cmd.Arguments})
+		s.mu.Unlock()
+
+		ret, err := handle(cmd.Execute, cmd.Arguments)
+		if err != nil {
+			_ = enc.Encode(map[string]any{"error": map[string]string{"class": "GenericError", "desc": err.Error()}})
+			continue
+		}
+		if ret == nil {
+			ret = map[string]any{}
+		}
+		_ = enc.Encode(map[string]any{"return": ret})
+	}
+}
+
+// newFakeQMPInstance returns a running Instance connected to a fake QMP
+// server that echoes commands.
+func newFakeQMPInstance(t *testing.T) (*Instance, *fakeQMPServer) {
+	t.Helper()
+	srv := &fakeQMPServer{}
+	return newFakeQMPInstanceWith(t, srv), srv
+}
+
+// newFakeQMPInstanceWith returns a running Instance connected to srv.
+func newFakeQMPInstanceWith(t *testing.T, srv *fakeQMPServer) *Instance {
+	t.Helper()
+
+	// Unix socket paths are limited to 108 bytes; t.TempDir() can exceed it
+	dir, err := os.MkdirTemp("", "qmp")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	socketPath := filepath.Join(dir, "qmp.sock")
+	l, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	go srv.serve(l)
+
+	client, err := newQMPClient(t.Context(), socketPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	q := &Instance{qmpClient: client}
+	q.setState(vmStateRunning)
+	return q
+}
+
+func TestInstance_QMPExecute(t *testing.T) {
+	t.Run("returns raw response", func(t *testing.T) {
+		q, srv := newFakeQMPInstance(t)
+
+		raw, err := q.QMPExecute(t.Context(), "query-memory-devices", map[string]any{"verbose": true})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"command":"query-memory-devices","arguments":{"verbose":true}}`, string(raw))
+		assert.Equal(t, []string{"query-memory-devices"}, srv.received())
+	})
+
+	t.Run("QMP error is returned", func(t *testing.T) {
+		q, _ := newFakeQMPInstance(t)
+
+		_, err := q.QMPExecute(t.Context(), "fail", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "boom")
+	})
+
+	t.Run("unsafe commands require opt-in", func(t *testing.T) {
+		q, srv := newFakeQMPInstance(t)
+
+		for _, cmd := range []string{"quit", "system_reset"} {
+			_, err := q.QMPExecute(t.Context(), cmd, nil)
+			require.ErrorIs(t, err, errdefs.ErrPermissionDenied)
+		}
+		assert.Empty(t, srv.received(), "rejected commands must not reach QEMU")
+
+		_, err := q.QMPExecute(t.Context(), "system_reset", nil, WithUnsafeQMP())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"system_reset"}, srv.received())
+	})
+
+	t.Run("not running", func(t *testing.T) {
+		q := &Instance{}
+		_, err := q.QMPExecute(t.Context(), "query-status", nil)
+		require.ErrorIs(t, err, errdefs.ErrFailedPrecondition)
+	})
+
+	t.Run("empty command", func(t *testing.T) {
+		q := &Instance{}
+		_, err := q.QMPExecute(t.Context(), "", nil)
+		require.ErrorIs(t, err, errdefs.ErrInvalidArgument)
+	})
+}
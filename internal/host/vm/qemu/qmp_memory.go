@@ -6,15 +6,10 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
 )
 
-// MemoryDeviceInfo represents a hotplugged memory device.
-type MemoryDeviceInfo struct {
-	Type string         `json:"type"` // "dimm" or "virtio-mem"
-	Data map[string]any `json:"data"`
-}
-
 // QueryMemoryDevices returns all hotplugged memory devices.
 func (q *qmpClient) QueryMemoryDevices(ctx context.Context) ([]MemoryDeviceInfo, error) {
 	return qmpQuery[[]MemoryDeviceInfo](q, ctx, "query-memory-devices")
@@ -133,3 +128,91 @@ func (q *qmpClient) UnplugMemory(ctx context.Context, slotID int) error {
 
 	return nil
 }
+
+// memoryBlockSize is the granularity of memory hotplug. It matches the
+// x86_64 guest memory block size and the pc-dimm alignment HotplugMemory
+// requires.
+const memoryBlockSize = 128 * 1024 * 1024
+
+// AddMemory hotplugs a pc-dimm of size bytes into the running VM, growing
+// it towards the MemoryHotplugSize ceiling of the resource configuration,
+// and returns the slot it was plugged into.
+//
+// size is rounded up to the 128 MiB memory block size; a partial block
+// cannot be onlined by the guest. The plugged total and the free slot are
+// read from QEMU on each call, so repeated AddMemory calls accumulate.
+// Requests that would exceed the ceiling fail with ErrInvalidArgument before
+// anything is plugged. The memory hotplug controller reads the occupied
+// slots from QEMU as well, and only unplugs slots it plugged itself, so the
+// two can be used side by side.
+//
+// The caller must online the new memory in the guest, by passing the
+// returned slot as the memory ID of the vminitd OnlineMemory RPC, as the
+// memory hotplug controller does.
+func (q *Instance) AddMemory(ctx context.Context, size uint64) (int, error) {
+	if size == 0 {
+		return 0, fmt.Errorf("memory size must be positive: %w", errdefs.ErrInvalidArgument)
+	}
+	if q.getState() != vmStateRunning {
+		return 0, fmt.Errorf("vm not running: %w", errdefs.ErrFailedPrecondition)
+	}
+
+	// Serialize so concurrent calls cannot pick the same slot or both
+	// pass the ceiling check
+	q.memHotplugMu.Lock()
+	defer q.memHotplugMu.Unlock()
+
+	q.mu.Lock()
+	qmpClient := q.qmpClient
+	resources := validateResourceConfig(q.resourceCfg)
+	q.mu.Unlock()
+
+	if qmpClient == nil {
+		return 0, fmt.Errorf("QMP client not available: %w", errdefs.ErrFailedPrecondition)
+	}
+
+	aligned := (size + memoryBlockSize - 1) / memoryBlockSize * memoryBlockSize
+
+	summary, err := qmpClient.QueryMemorySizeSummary(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("query memory size: %w", err)
+	}
+	total := uint64(summary.BaseMemory + summary.PluggedMemory)
+	if total+aligned > uint64(resources.MemoryHotplugSize) {
+		return 0, fmt.Errorf("adding %d bytes to %d bytes exceeds memory hotplug ceiling of %d bytes: %w",
+			aligned, total, resources.MemoryHotplugSize, errdefs.ErrInvalidArgument)
+	}
+
+	devices, err := qmpClient.QueryMemoryDevices(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("query memory devices: %w", err)
+	}
+	used := make(map[string]bool, len(devices))
+	for _, d := range devices {
+		if id, ok := d.Data["id"].(string); ok {
+			used[id] = true
+		}
+	}
+	slot := -1
+	for i := range resources.MemorySlots {
+		if !used[fmt.Sprintf("dimm%d", i)] {
+			slot = i
+			break
+		}
+	}
+	if slot < 0 {
+		return 0, fmt.Errorf("all %d memory slots are in use: %w", resources.MemorySlots, errdefs.ErrResourceExhausted)
+	}
+
+	log.G(ctx).WithFields(log.Fields{
+		"requested_bytes": size,
+		"aligned_bytes":   aligned,
+		"slot_id":         slot,
+		"total_mb":        total / (1024 * 1024),
+	}).Info("qemu: adding memory")
+
+	if err := qmpClient.HotplugMemory(ctx, slot, int64(aligned)); err != nil {
+		return 0, err
+	}
+	return slot, nil
+}
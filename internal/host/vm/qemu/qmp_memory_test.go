@@ -4,10 +4,17 @@ package qemu
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
 	"testing"
 
+	"github.com/containerd/errdefs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/spin-stack/spinbox/internal/host/vm"
 )
 
 const qmpTestSocketPath = "/tmp/test-qemu-qmp.sock"
@@ -338,3 +345,126 @@ func TestObjectAddDel(t *testing.T) {
 
 	t.Logf("Deleted memory backend: %s", backendID)
 }
+
+// fakeMemoryQMP simulates the memory hotplug commands of a VM.
+type fakeMemoryQMP struct {
+	mu       sync.Mutex
+	base     int64
+	backends map[string]int64 // backend ID -> size
+	dimms    map[string]string
+}
+
+func newFakeMemoryQMP(base int64) *fakeMemoryQMP {
+	return &fakeMemoryQMP{base: base, backends: make(map[string]int64), dimms: make(map[string]string)}
+}
+
+func (f *fakeMemoryQMP) plugged() int64 {
+	var total int64
+	for _, backend := range f.dimms {
+		total += f.backends[backend]
+	}
+	return total
+}
+
+func (f *fakeMemoryQMP) handle(execute string, args map[string]any) (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch execute {
+	case "query-memory-size-summary":
+		return MemorySizeSummary{BaseMemory: f.base, PluggedMemory: f.plugged()}, nil
+	case "query-memory-devices":
+		var devices []MemoryDeviceInfo
+		for _, id := range slices.Sorted(maps.Keys(f.dimms)) {
+			devices = append(devices, MemoryDeviceInfo{Type: "dimm", Data: map[string]any{
+				"id":   id,
+				"size": f.backends[f.dimms[id]],
+			}})
+		}
+		return devices, nil
+	case "object-add":
+		f.backends[args["id"].(string)] = int64(args["size"].(float64))
+		return nil, nil
+	case "device_add":
+		f.dimms[args["id"].(string)] = args["memdev"].(string)
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unexpected command %s", execute)
+}
+
+func TestInstance_AddMemory(t *testing.T) {
+	const mib = 1024 * 1024
+
+	newInstance := func(t *testing.T, mem *fakeMemoryQMP, ceiling int64, slots int) (*Instance, *fakeQMPServer) {
+		srv := &fakeQMPServer{handle: mem.handle}
+		q := newFakeQMPInstanceWith(t, srv)
+		q.resourceCfg = &vm.VMResourceConfig{
+			BootCPUs:          1,
+			MaxCPUs:           1,
+			MemorySize:        mem.base,
+			MemoryHotplugSize: ceiling,
+			MemorySlots:       slots,
+		}
+		return q, srv
+	}
+	add := func(t *testing.T, q *Instance, size uint64) error {
+		_, err := q.AddMemory(t.Context(), size)
+		return err
+	}
+
+	t.Run("repeated calls accumulate", func(t *testing.T) {
+		mem := newFakeMemoryQMP(512 * mib)
+		q, _ := newInstance(t, mem, 1024*mib, 8)
+
+		slot, err := q.AddMemory(t.Context(), 256*mib)
+		require.NoError(t, err)
+		assert.Equal(t, 0, slot)
+		slot, err = q.AddMemory(t.Context(), 128*mib)
+		require.NoError(t, err)
+		assert.Equal(t, 1, slot)
+
+		assert.Equal(t, map[string]string{"dimm0": "mem0", "dimm1": "mem1"}, mem.dimms)
+		assert.Equal(t, int64(384*mib), mem.plugged())
+	})
+
+	t.Run("size is rounded up to the block size", func(t *testing.T) {
+		mem := newFakeMemoryQMP(512 * mib)
+		q, _ := newInstance(t, mem, 1024*mib, 8)
+
+		require.NoError(t, add(t, q, 100*mib))
+		assert.Equal(t, int64(128*mib), mem.backends["mem0"])
+
+		require.NoError(t, add(t, q, 129*mib))
+		assert.Equal(t, int64(256*mib), mem.backends["mem1"])
+	})
+
+	t.Run("ceiling is enforced across calls", func(t *testing.T) {
+		mem := newFakeMemoryQMP(512 * mib)
+		q, _ := newInstance(t, mem, 1024*mib, 8)
+
+		require.NoError(t, add(t, q, 384*mib))
+		_, err := q.AddMemory(t.Context(), 256*mib)
+		require.ErrorIs(t, err, errdefs.ErrInvalidArgument)
+		assert.Equal(t, int64(384*mib), mem.plugged())
+
+		// Rounding counts against the ceiling too
+		require.ErrorIs(t, add(t, q, 129*mib), errdefs.ErrInvalidArgument)
+		// The remaining block still fits
+		require.NoError(t, add(t, q, 128*mib))
+		assert.Equal(t, int64(512*mib), mem.plugged())
+	})
+
+	t.Run("no free slot", func(t *testing.T) {
+		mem := newFakeMemoryQMP(512 * mib)
+		q, _ := newInstance(t, mem, 2048*mib, 1)
+
+		require.NoError(t, add(t, q, 128*mib))
+		require.ErrorIs(t, add(t, q, 128*mib), errdefs.ErrResourceExhausted)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		q := &Instance{}
+		require.ErrorIs(t, add(t, q, 0), errdefs.ErrInvalidArgument)
+		require.ErrorIs(t, add(t, q, 128*mib), errdefs.ErrFailedPrecondition)
+	})
+}
@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/containerd/log"
+
+	"github.com/spin-stack/spinbox/internal/config"
 )
 
 // Shutdown timing constants.
@@ -33,6 +35,96 @@ const (
 	shutdownKillWait = 2 * time.Second
 )
 
+// maxShutdownTotal caps the sum of all shutdown stages so a misconfigured
+// instance cannot stall container teardown indefinitely.
+const maxShutdownTotal = 5 * time.Minute
+
+// ShutdownConfig holds the per-stage timeouts of Shutdown. Slow guests can
+// raise ACPIWait to flush workloads before QEMU is told to quit; CI can
+// shorten every stage.
+type ShutdownConfig struct {
+	// QMPTimeout bounds the CTRL+ALT+DELETE / ACPI powerdown QMP command.
+	QMPTimeout time.Duration
+	// ACPIWait is how long the guest gets to shut down before quit is sent.
+	ACPIWait time.Duration
+	// QuitTimeout bounds the QMP quit command.
+	QuitTimeout time.Duration
+	// QuitWait is how long to wait for QEMU to exit after quit.
+	QuitWait time.Duration
+	// KillWait is how long to wait for QEMU to exit after SIGKILL.
+	KillWait time.Duration
+}
+
+// DefaultShutdownConfig returns the default shutdown timeouts.
+func DefaultShutdownConfig() ShutdownConfig {
+	return ShutdownConfig{
+		QMPTimeout:  shutdownQMPTimeout,
+		ACPIWait:    shutdownACPIWait,
+		QuitTimeout: shutdownQuitTimeout,
+		QuitWait:    shutdownQuitWait,
+		KillWait:    shutdownKillWait,
+	}
+}
+
+// Total returns the worst-case duration of a shutdown.
+func (c ShutdownConfig) Total() time.Duration {
+	return c.QMPTimeout + c.ACPIWait + c.QuitTimeout + c.QuitWait + c.KillWait
+}
+
+// Validate checks that every stage is positive and the total stays within
+// maxShutdownTotal.
+func (c ShutdownConfig) Validate() error {
+	for _, stage := range []struct {
+		name string
+		d    time.Duration
+	}{
+		{"QMP timeout", c.QMPTimeout},
+		{"ACPI wait", c.ACPIWait},
+		{"quit timeout", c.QuitTimeout},
+		{"quit wait", c.QuitWait},
+		{"kill wait", c.KillWait},
+	} {
+		if stage.d <= 0 {
+			return fmt.Errorf("shutdown %s must be positive, got %s", stage.name, stage.d)
+		}
+	}
+	if total := c.Total(); total > maxShutdownTotal {
+		return fmt.Errorf("total shutdown time %s exceeds limit of %s", total, maxShutdownTotal)
+	}
+	return nil
+}
+
+// SetShutdownConfig overrides the shutdown timeouts of the instance.
+func (q *Instance) SetShutdownConfig(cfg ShutdownConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.shutdownCfg = cfg
+	return nil
+}
+
+// shutdownConfigFromTimeouts returns the shutdown timeouts set in the
+// config: shutdown_acpi is the ACPI wait and shutdown_grace the wait for
+// QEMU to quit before SIGKILL. The other stages keep their defaults.
+func shutdownConfigFromTimeouts(t *config.TimeoutsConfig) ShutdownConfig {
+	cfg := DefaultShutdownConfig()
+	cfg.ACPIWait = t.Duration("shutdown_acpi")
+	cfg.QuitWait = t.Duration("shutdown_grace")
+	return cfg
+}
+
+// shutdownTimeouts returns the configured timeouts, or the defaults for an
+// instance that was not built by newInstance.
+// Must be called with q.mu held.
+func (q *Instance) shutdownTimeouts() ShutdownConfig {
+	if q.shutdownCfg == (ShutdownConfig{}) {
+		return DefaultShutdownConfig()
+	}
+	return q.shutdownCfg
+}
+
 func (q *Instance) shutdownGuest(ctx context.Context, logger *log.Entry) {
 	// Send graceful shutdown to guest OS
 	// Try CTRL+ALT+DELETE first (more reliable for some distributions), then ACPI powerdown
@@ -40,7 +132,7 @@ func (q *Instance) shutdownGuest(ctx context.Context, logger *log.Entry) {
 	// but we still need time to properly shut down the VM.
 	if q.qmpClient != nil {
 		logger.Info("qemu: sending CTRL+ALT+DELETE via QMP")
-		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownQMPTimeout)
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), q.shutdownTimeouts().QMPTimeout)
 		if err := q.qmpClient.SendCtrlAltDelete(shutdownCtx); err != nil {
 			logger.WithError(err).Debug("qemu: failed to send CTRL+ALT+DELETE, trying ACPI powerdown")
 			// Fall back to ACPI powerdown
@@ -67,6 +159,7 @@ func (q *Instance) stopQemuProcess(ctx context.Context, logger *log.Entry) error
 	if q.cmd == nil || q.cmd.Process == nil {
 		return nil
 	}
+	timeouts := q.shutdownTimeouts()
 
 	// Wait for guest to receive ACPI signal
 	select {
@@ -75,14 +168,14 @@ func (q *Instance) stopQemuProcess(ctx context.Context, logger *log.Entry) error
 		logger.WithError(exitErr).Debug("qemu: process exited during ACPI wait")
 		q.cmd = nil
 		return nil
-	case <-time.After(shutdownACPIWait):
+	case <-time.After(timeouts.ACPIWait):
 		// Expected - continue to quit command
 	}
 
 	// Send quit command to tell QEMU to exit
 	if q.qmpClient != nil {
 		logger.Debug("qemu: sending quit command to QEMU")
-		quitCtx, quitCancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownQuitTimeout)
+		quitCtx, quitCancel := context.WithTimeout(context.WithoutCancel(ctx), timeouts.QuitTimeout)
 		if err := q.qmpClient.Quit(quitCtx); err != nil {
 			logger.WithError(err).Debug("qemu: failed to send quit command")
 			quitCancel()
@@ -99,7 +192,7 @@ func (q *Instance) stopQemuProcess(ctx context.Context, logger *log.Entry) error
 				}
 				q.cmd = nil
 				return nil
-			case <-time.After(shutdownQuitWait):
+			case <-time.After(timeouts.QuitWait):
 				// Quit didn't work - fall through to SIGKILL
 				logger.Warning("qemu: quit command timeout, sending SIGKILL")
 			}
@@ -122,7 +215,7 @@ func (q *Instance) stopQemuProcess(ctx context.Context, logger *log.Entry) error
 		if exitErr != nil {
 			logger.WithError(exitErr).Debug("qemu: process exited after SIGKILL")
 		}
-	case <-time.After(shutdownKillWait):
+	case <-time.After(timeouts.KillWait):
 		logger.Error("qemu: process did not exit after SIGKILL")
 		q.cmd = nil
 		q.cleanupAfterFailedKill()
@@ -226,10 +319,14 @@ func (q *Instance) Shutdown(ctx context.Context) error {
 	logger.Info("qemu: Shutdown() called, initiating VM shutdown")
 
 	// Phase 1: State transition check (idempotent - prevents re-entry)
+	wasPaused := false
 	if !q.compareAndSwapState(vmStateRunning, vmStateShutdown) {
-		currentState := q.getState()
-		logger.WithField("state", currentState).Debug("qemu: VM not in running state, shutdown may already be in progress")
-		return nil // Not an error - idempotent shutdown
+		wasPaused = q.compareAndSwapState(vmStatePaused, vmStateShutdown)
+		if !wasPaused {
+			currentState := q.getState()
+			logger.WithField("state", currentState).Debug("qemu: VM not in running state, shutdown may already be in progress")
+			return nil // Not an error - idempotent shutdown
+		}
 	}
 
 	// Phase 1: Cancel background monitors before acquiring lock
@@ -240,6 +337,9 @@ func (q *Instance) Shutdown(ctx context.Context) error {
 	defer q.mu.Unlock()
 
 	q.closeClientConnections(logger)
+	if wasPaused {
+		q.resumeForShutdown(ctx, logger)
+	}
 	q.shutdownGuest(ctx, logger)
 
 	if err := q.stopQemuProcess(ctx, logger); err != nil {
@@ -4,12 +4,15 @@ package qemu
 
 import (
 	"io"
+	"os/exec"
 	"testing"
 	"time"
 
 	"github.com/containerd/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/spin-stack/spinbox/internal/config"
 )
 
 func TestShutdownConstants(t *testing.T) {
@@ -25,6 +28,102 @@ func TestShutdownConstants(t *testing.T) {
 	assert.LessOrEqual(t, totalTimeout, 10*time.Second, "total shutdown timeout should not exceed 10 seconds")
 }
 
+func TestShutdownConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultShutdownConfig().Validate())
+	assert.Equal(t, 7500*time.Millisecond, DefaultShutdownConfig().Total())
+
+	t.Run("non-positive stage", func(t *testing.T) {
+		cfg := DefaultShutdownConfig()
+		cfg.QuitWait = 0
+		err := cfg.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "quit wait")
+	})
+
+	t.Run("total exceeds cap", func(t *testing.T) {
+		cfg := DefaultShutdownConfig()
+		cfg.ACPIWait = maxShutdownTotal
+		err := cfg.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds limit")
+	})
+
+	t.Run("long ACPI wait within cap", func(t *testing.T) {
+		cfg := DefaultShutdownConfig()
+		cfg.ACPIWait = time.Minute
+		assert.NoError(t, cfg.Validate())
+	})
+}
+
+func TestInstance_SetShutdownConfig(t *testing.T) {
+	t.Run("zero value uses defaults", func(t *testing.T) {
+		q := &Instance{}
+		assert.Equal(t, DefaultShutdownConfig(), q.shutdownTimeouts())
+	})
+
+	t.Run("custom timeouts are kept", func(t *testing.T) {
+		q := &Instance{}
+		cfg := ShutdownConfig{
+			QMPTimeout:  time.Second,
+			ACPIWait:    30 * time.Second,
+			QuitTimeout: time.Second,
+			QuitWait:    time.Second,
+			KillWait:    time.Second,
+		}
+		require.NoError(t, q.SetShutdownConfig(cfg))
+		assert.Equal(t, cfg, q.shutdownTimeouts())
+
+		// Shutdown of a non-running instance is a no-op
+		q.setState(vmStateShutdown)
+		require.NoError(t, q.Shutdown(t.Context()))
+		assert.Equal(t, cfg, q.shutdownTimeouts())
+	})
+
+	t.Run("invalid config is rejected", func(t *testing.T) {
+		q := &Instance{}
+		require.Error(t, q.SetShutdownConfig(ShutdownConfig{ACPIWait: time.Second}))
+		assert.Equal(t, DefaultShutdownConfig(), q.shutdownTimeouts())
+	})
+}
+
+func TestShutdownConfigFromTimeouts(t *testing.T) {
+	cfg := shutdownConfigFromTimeouts(&config.TimeoutsConfig{
+		ShutdownACPI:  "10s",
+		ShutdownGrace: "3s",
+	})
+
+	want := DefaultShutdownConfig()
+	want.ACPIWait = 10 * time.Second
+	want.QuitWait = 3 * time.Second
+	assert.Equal(t, want, cfg)
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestInstance_StopQemuProcessHonorsTimeouts(t *testing.T) {
+	cmd := exec.Command("sleep", "30")
+	require.NoError(t, cmd.Start())
+	waitCh := make(chan error, 1)
+	go func() { waitCh <- cmd.Wait() }()
+
+	q := &Instance{cmd: cmd, waitCh: waitCh}
+	require.NoError(t, q.SetShutdownConfig(ShutdownConfig{
+		QMPTimeout:  time.Second,
+		ACPIWait:    50 * time.Millisecond,
+		QuitTimeout: time.Second,
+		QuitWait:    time.Second,
+		KillWait:    time.Second,
+	}))
+
+	// Without QMP the process is killed once the ACPI wait elapses
+	start := time.Now()
+	require.NoError(t, q.stopQemuProcess(t.Context(), log.L.WithField("test", true)))
+	elapsed := time.Since(start)
+
+	assert.Nil(t, q.cmd)
+	assert.GreaterOrEqual(t, elapsed, 50*time.Millisecond)
+	assert.Less(t, elapsed, shutdownACPIWait, "custom ACPI wait should replace the default")
+}
+
 func TestCloseAndLog(t *testing.T) {
 	logger := log.L.WithField("test", true)
 
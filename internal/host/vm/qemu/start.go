@@ -28,8 +28,12 @@ func (q *Instance) setupConsoleFIFO(ctx context.Context) error {
 		return fmt.Errorf("failed to create console FIFO: %w", err)
 	}
 
-	// Create persistent console log file
-	consoleFile, err := os.Create(q.consolePath)
+	// Open the persistent console log; it is appended to across VM
+	// lifetimes and rotated at the configured size
+	q.mu.Lock()
+	limits := q.consoleLogLimits()
+	q.mu.Unlock()
+	consoleFile, err := openConsoleLog(q.consolePath, limits)
 	if err != nil {
 		_ = os.Remove(q.consoleFifoPath)
 		return fmt.Errorf("failed to create console log file: %w", err)
@@ -337,14 +341,18 @@ func (q *Instance) Start(ctx context.Context, opts ...vm.StartOpt) error {
 		return fmt.Errorf("cannot start VM in state %d", currentState)
 	}
 
+	// Time each phase so a slow start can be attributed; see BootTrace
+	tr := newBootTracer()
+	defer q.recordBootTrace(ctx, tr)
+
 	// Validate configuration before starting
-	if err := q.validateConfiguration(); err != nil {
+	if err := tr.phase(BootPhaseValidate, q.validateConfiguration); err != nil {
 		q.setState(vmStateNew)
 		return fmt.Errorf("configuration validation failed: %w", err)
 	}
 
 	// Setup console FIFO for real-time streaming
-	if err := q.setupConsoleFIFO(ctx); err != nil {
+	if err := tr.phase(BootPhaseConsole, func() error { return q.setupConsoleFIFO(ctx) }); err != nil {
 		q.setState(vmStateNew)
 		return fmt.Errorf("failed to setup console FIFO: %w", err)
 	}
@@ -377,36 +385,33 @@ func (q *Instance) Start(ctx context.Context, opts ...vm.StartOpt) error {
 	// QEMU (running in init netns for vhost-vsock) will use these FDs to attach to
 	// TAP devices that stay in their sandbox namespaces. This is the Kata Containers approach:
 	// FDs are namespace-agnostic, so no need to move TAPs between namespaces.
-	if err := q.openTapFiles(ctx, startOpts.NetworkNamespace); err != nil {
+	if err := tr.phase(BootPhaseNetwork, func() error {
+		return q.openTapFiles(ctx, startOpts.NetworkNamespace)
+	}); err != nil {
 		return err
 	}
 
-	// Build kernel command line
-	cmdlineArgs := q.buildKernelCommandLine(startOpts)
-
-	// Build QEMU command line (now uses the renamed TAP names)
-	qemuArgs, err := q.buildQemuCommandLine(cmdlineArgs)
-	if err != nil {
-		return err
-	}
+	if err := tr.phase(BootPhaseSpawn, func() error {
+		// Build kernel command line
+		cmdlineArgs := q.buildKernelCommandLine(startOpts)
 
-	// Print full command for manual testing
-	log.G(ctx).WithFields(log.Fields{
-		"binary":  q.binaryPath,
-		"cmdline": strings.Join(qemuArgs, " "),
-	}).Debug("qemu: starting VM process")
+		// Build QEMU command line (now uses the renamed TAP names)
+		qemuArgs, err := q.buildQemuCommandLine(cmdlineArgs)
+		if err != nil {
+			return err
+		}
 
-	if err := q.startQemuProcess(ctx, qemuArgs); err != nil {
-		return err
-	}
+		// Print full command for manual testing
+		log.G(ctx).WithFields(log.Fields{
+			"binary":  q.binaryPath,
+			"cmdline": strings.Join(qemuArgs, " "),
+		}).Debug("qemu: starting VM process")
 
-	// Connect to QMP for control
-	if err := q.connectQMP(ctx); err != nil {
+		return q.startQemuProcess(ctx, qemuArgs)
+	}); err != nil {
 		return err
 	}
 
-	log.G(ctx).Info("qemu: QMP connected, waiting for vsock...")
-
 	// Create long-lived context for background monitors; Start ctx may be cancelled by callers.
 	// We use context.Background() here because the background monitors need to outlive
 	// the Start() call and continue running until explicit Shutdown().
@@ -415,8 +420,20 @@ func (q *Instance) Start(ctx context.Context, opts ...vm.StartOpt) error {
 	q.runCtx = runCtx
 	q.runCancel = runCancel
 
-	// Connect to vsock RPC server
-	if err := q.connectVsockClient(ctx); err != nil {
+	// Wait for QMP and the guest RPC server within the boot timeout. Both
+	// connect helpers kill QEMU on failure; rollbackStart cleans up the rest.
+	if err := q.waitBoot(ctx, startOpts.BootTimeout, tr, func(bootCtx context.Context) error {
+		// Connect to QMP for control
+		if err := tr.phase(BootPhaseQMP, func() error { return q.connectQMP(bootCtx) }); err != nil {
+			return err
+		}
+
+		log.G(ctx).Info("qemu: QMP connected, waiting for vsock...")
+
+		// Connect to vsock RPC server
+		return tr.phase(BootPhaseVsock, func() error { return q.connectVsockClient(bootCtx) })
+	}); err != nil {
+		runCancel()
 		return err
 	}
 
@@ -433,6 +450,28 @@ func (q *Instance) Start(ctx context.Context, opts ...vm.StartOpt) error {
 	return nil
 }
 
+// recordBootTrace stores the phases of the last Start call for BootTrace and
+// logs them.
+func (q *Instance) recordBootTrace(ctx context.Context, tr *bootTracer) {
+	trace := tr.result()
+	q.bootTrace.Store(&trace)
+
+	fields := log.Fields{"total": trace.Total()}
+	for _, pt := range trace.Phases {
+		fields[string(pt.Phase)] = pt.Duration
+	}
+	log.G(ctx).WithFields(fields).Debug("qemu: start phases")
+}
+
+// BootTrace returns the phase timings of the most recent Start call, whether
+// it succeeded or not. It is empty before Start is called.
+func (q *Instance) BootTrace() BootTrace {
+	if t := q.bootTrace.Load(); t != nil {
+		return *t
+	}
+	return BootTrace{}
+}
+
 // buildKernelCommandLine constructs the kernel command line
 func (q *Instance) buildKernelCommandLine(startOpts vm.StartOpts) string {
 	cfg := DefaultKernelCmdlineConfig()
@@ -492,6 +531,11 @@ func (q *Instance) buildQemuCommandLine(cmdlineArgs string) ([]string, error) {
 		builder.addDisk(fmt.Sprintf("blk%d", i), disk)
 	}
 
+	// Reserve root ports for block devices hotplugged by AddBlockDevice
+	for i := range q.resourceCfg.BlockHotplugPorts {
+		builder.addPCIeRootPort(blockHotplugPortID(i), i+1)
+	}
+
 	// Add NICs
 	for i, nic := range q.nets {
 		// Use Kata Containers approach: pass TAP via file descriptor
@@ -5,10 +5,13 @@ package qemu
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"net"
+	"os"
 	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/containerd/errdefs"
@@ -82,6 +85,9 @@ func (q *Instance) StartStream(ctx context.Context) (uint32, net.Conn, error) {
 // connectVsockRPC establishes a connection to the vsock RPC server (vminitd)
 // using exponential backoff. The connection is verified with a TTRPC ping
 // before being returned to ensure the server is ready to accept requests.
+//
+// Dial errors that mean the guest is still booting (see retryableDialError)
+// are retried until ctx expires; any other dial error is returned at once.
 func (q *Instance) connectVsockRPC(ctx context.Context) (net.Conn, error) {
 	log.G(ctx).WithFields(log.Fields{
 		"cid":  q.guestCID,
@@ -93,64 +99,108 @@ func (q *Instance) connectVsockRPC(ctx context.Context) (net.Conn, error) {
 		maxBackoff     = 200 * time.Millisecond
 	)
 
+	// Without a caller deadline (e.g. the boot timeout), give up after
+	// connectRetryTimeout.
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, connectRetryTimeout)
+		defer cancel()
+	}
+
+	dial := q.dialVsock
+	if dial == nil {
+		dial = dialVsock
+	}
+
 	retryStart := time.Now()
 	backoff := initialBackoff
 	pingDeadline := 50 * time.Millisecond
+	attempts := 0
 
 	for {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
+		if attempts > 0 {
+			t := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+			case <-t.C:
+			}
+			backoff = min(backoff*2, maxBackoff)
 		}
-
-		if time.Since(retryStart) > connectRetryTimeout {
-			return nil, fmt.Errorf("timeout waiting for vminitd to accept connections")
+		if err := ctx.Err(); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil, fmt.Errorf("timeout waiting for vminitd to accept connections after %d attempts: %w", attempts, err)
+			}
+			return nil, err
 		}
+		attempts++
 
 		// Connect directly via vsock using kernel's vhost-vsock driver
-		conn, err := vsock.Dial(q.guestCID, vsockports.DefaultRPCPort, nil)
+		conn, err := dial(q.guestCID, vsockports.DefaultRPCPort)
 		if err != nil {
-			time.Sleep(backoff)
-			backoff = min(backoff*2, maxBackoff)
+			if !retryableDialError(err) {
+				return nil, fmt.Errorf("failed to dial vminitd on vsock cid %d: %w", q.guestCID, err)
+			}
 			continue
 		}
 
 		// Try to ping the TTRPC server with a deadline
 		if err := conn.SetReadDeadline(time.Now().Add(pingDeadline)); err != nil {
 			_ = conn.Close()
-			time.Sleep(backoff)
-			backoff = min(backoff*2, maxBackoff)
 			continue
 		}
 		if err := pingTTRPC(conn); err != nil {
 			_ = conn.Close()
 			pingDeadline += 10 * time.Millisecond
-			time.Sleep(backoff)
-			backoff = min(backoff*2, maxBackoff)
 			continue
 		}
 
 		// Clear the deadline and verify connection is still alive
 		if err := conn.SetReadDeadline(time.Time{}); err != nil {
 			_ = conn.Close()
-			time.Sleep(backoff)
-			backoff = min(backoff*2, maxBackoff)
 			continue
 		}
 		if err := pingTTRPC(conn); err != nil {
 			_ = conn.Close()
-			time.Sleep(backoff)
-			backoff = min(backoff*2, maxBackoff)
 			continue
 		}
 
 		// Connection is ready
-		log.G(ctx).WithField("retry_time", time.Since(retryStart)).Info("qemu: TTRPC connection established")
+		log.G(ctx).WithFields(log.Fields{
+			"retry_time": time.Since(retryStart),
+			"attempts":   attempts,
+		}).Info("qemu: TTRPC connection established")
 		return conn, nil
 	}
 }
 
+// dialVsock is the default Instance.dialVsock.
+func dialVsock(cid, port uint32) (net.Conn, error) {
+	return vsock.Dial(cid, port, nil)
+}
+
+// vhostVsockDevice is the host device QEMU's vhost-vsock-pci needs.
+var vhostVsockDevice = "/dev/vhost-vsock"
+
+// retryableDialError reports whether a vsock dial error may resolve by
+// waiting. While the guest boots, the dial fails in many ways: connections
+// are refused or reset until vminitd binds its port, and the transport may
+// not be up yet. So every error is retried except the known-permanent ones:
+// a permission error, a host without AF_VSOCK, or ENODEV on a host without
+// vhost-vsock.
+func retryableDialError(err error) bool {
+	switch {
+	case errors.Is(err, syscall.EACCES),
+		errors.Is(err, syscall.EPERM),
+		errors.Is(err, syscall.EAFNOSUPPORT):
+		return false
+	case errors.Is(err, syscall.ENODEV):
+		_, statErr := os.Stat(vhostVsockDevice)
+		return statErr == nil
+	}
+	return true
+}
+
 // monitorGuestRPC periodically checks if the in-guest vminitd RPC server is reachable.
 // If the server disappears (e.g., guest reboot/poweroff), log a warning for debugging.
 // Shutdown() is responsible for coordinating all shutdown actions.
@@ -170,6 +220,12 @@ func (q *Instance) monitorGuestRPC(ctx context.Context) {
 		case <-t.C:
 		}
 
+		// A paused guest cannot answer; that is not a hang
+		if q.getState() == vmStatePaused {
+			failures = 0
+			continue
+		}
+
 		conn, err := vsock.Dial(q.guestCID, vsockports.DefaultRPCPort, nil)
 		if err == nil {
 			if err := conn.SetDeadline(time.Now().Add(200 * time.Millisecond)); err != nil {
@@ -0,0 +1,142 @@
+//go:build linux
+
+package qemu
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVminitd answers TTRPC pings on conn the way vminitd rejects a frame
+// with stream ID 0, until conn is closed.
+func fakeVminitd(conn net.Conn) {
+	defer conn.Close()
+	msg := []byte("invalid stream")
+	for {
+		req := make([]byte, 10)
+		if _, err := io.ReadFull(conn, req); err != nil {
+			return
+		}
+		resp := make([]byte, 10, 10+len(msg))
+		binary.BigEndian.PutUint32(resp[:4], uint32(len(msg)))
+		if _, err := conn.Write(append(resp, msg...)); err != nil {
+			return
+		}
+	}
+}
+
+// bootingGuestDialer fails the first len(errs) dials with errs in order and
+// then connects to a fakeVminitd.
+func bootingGuestDialer(t *testing.T, errs ...error) (func(cid, port uint32) (net.Conn, error), *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	return func(cid, port uint32) (net.Conn, error) {
+		n := int(calls.Add(1))
+		if n <= len(errs) {
+			return nil, errs[n-1]
+		}
+		client, server := net.Pipe()
+		go fakeVminitd(server)
+		t.Cleanup(func() { _ = client.Close() })
+		return client, nil
+	}, &calls
+}
+
+// withVhostVsock points vhostVsockDevice at a device that exists when
+// present is true and at a missing one otherwise.
+func withVhostVsock(t *testing.T, present bool) {
+	t.Helper()
+	dev := filepath.Join(t.TempDir(), "vhost-vsock")
+	if present {
+		require.NoError(t, os.WriteFile(dev, nil, 0o600))
+	}
+	old := vhostVsockDevice
+	vhostVsockDevice = dev
+	t.Cleanup(func() { vhostVsockDevice = old })
+}
+
+func TestConnectVsockRPC(t *testing.T) {
+	t.Run("retries until the guest listens", func(t *testing.T) {
+		withVhostVsock(t, true)
+		dial, calls := bootingGuestDialer(t,
+			syscall.ENODEV, syscall.ECONNRESET, syscall.ECONNREFUSED,
+			&net.OpError{Op: "dial", Net: "vsock", Err: os.ErrDeadlineExceeded})
+		q := &Instance{guestCID: 3, dialVsock: dial}
+
+		ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+		defer cancel()
+
+		conn, err := q.connectVsockRPC(ctx)
+		require.NoError(t, err)
+		assert.NotNil(t, conn)
+		assert.Equal(t, int32(5), calls.Load())
+	})
+
+	t.Run("fatal dial error is not retried", func(t *testing.T) {
+		dial, calls := bootingGuestDialer(t, syscall.EAFNOSUPPORT)
+		q := &Instance{guestCID: 3, dialVsock: dial}
+
+		_, err := q.connectVsockRPC(t.Context())
+		assert.ErrorIs(t, err, syscall.EAFNOSUPPORT)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("gives up at the boot deadline", func(t *testing.T) {
+		errs := make([]error, 1000)
+		for i := range errs {
+			errs[i] = syscall.ECONNREFUSED
+		}
+		dial, calls := bootingGuestDialer(t, errs...)
+		q := &Instance{guestCID: 3, dialVsock: dial}
+
+		ctx, cancel := context.WithTimeout(t.Context(), 150*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := q.connectVsockRPC(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Greater(t, calls.Load(), int32(1))
+	})
+}
+
+func TestRetryableDialError(t *testing.T) {
+	withVhostVsock(t, true)
+	for _, err := range []error{
+		syscall.ECONNREFUSED,
+		syscall.ECONNRESET,
+		syscall.ENODEV,
+		syscall.ETIMEDOUT,
+		syscall.EINVAL,
+		syscall.ENETUNREACH,
+		&net.OpError{Op: "dial", Net: "vsock", Err: os.NewSyscallError("connect", syscall.ECONNRESET)},
+		&net.OpError{Op: "dial", Net: "vsock", Err: os.ErrDeadlineExceeded},
+	} {
+		assert.True(t, retryableDialError(err), "%v should be retried", err)
+	}
+	for _, err := range []error{
+		syscall.EAFNOSUPPORT,
+		syscall.EACCES,
+		syscall.EPERM,
+		&net.OpError{Op: "dial", Net: "vsock", Err: os.NewSyscallError("socket", syscall.EAFNOSUPPORT)},
+	} {
+		assert.False(t, retryableDialError(err), "%v should not be retried", err)
+	}
+
+	t.Run("ENODEV without vhost-vsock", func(t *testing.T) {
+		withVhostVsock(t, false)
+		assert.False(t, retryableDialError(syscall.ENODEV))
+		assert.False(t, retryableDialError(&net.OpError{Op: "dial", Net: "vsock", Err: os.NewSyscallError("connect", syscall.ENODEV)}))
+	})
+}
@@ -7,6 +7,7 @@ type DiskConfig struct {
 	ID       string
 	Path     string
 	Readonly bool
+	Port     string // PCIe root port of a hotplugged disk; empty for boot disks
 }
 
 // NetConfig represents a virtio-net device configuration.
@@ -22,3 +23,9 @@ type MemorySizeSummary struct {
 	BaseMemory    int64 `json:"base-memory"`    // Boot memory in bytes
 	PluggedMemory int64 `json:"plugged-memory"` // Hotplugged memory in bytes
 }
+
+// MemoryDeviceInfo represents a hotplugged memory device.
+type MemoryDeviceInfo struct {
+	Type string         `json:"type"` // "dimm" or "virtio-mem"
+	Data map[string]any `json:"data"`
+}
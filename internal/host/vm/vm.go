@@ -4,8 +4,11 @@ package vm
 
 import (
 	"context"
+	"fmt"
 	"net"
+	"time"
 
+	"github.com/containerd/errdefs"
 	"github.com/containerd/ttrpc"
 )
 
@@ -26,6 +29,16 @@ type NetworkConfig struct {
 	Gateway       string   // Gateway IP (e.g., "10.88.0.1")
 	Netmask       string   // Netmask (e.g., "255.255.255.0")
 	DNS           []string // DNS servers
+	MTU           int      // Interface MTU (0 leaves the guest default)
+	Routes        []Route  // Static routes beyond the default route via Gateway
+	IPv6          string   // IPv6 address with prefix length (e.g., "fd00::5/64")
+	IPv6Gateway   string   // IPv6 gateway (e.g., "fd00::1")
+}
+
+// Route is a static route installed on a guest interface.
+type Route struct {
+	Dst     string // Destination CIDR (e.g., "10.96.0.0/12")
+	Gateway string // Next hop; empty for an on-link route
 }
 
 // VMResourceConfig defines VM resource limits (shared across all VMM backends).
@@ -35,6 +48,36 @@ type VMResourceConfig struct {
 	MemorySize        int64 // Initial memory in bytes (default: 512 MiB)
 	MemoryHotplugSize int64 // Max memory for hotplug in bytes (default: 2 GiB)
 	MemorySlots       int   // Memory hotplug slots (default: 8, must match VMM config)
+	BlockHotplugPorts int   // Ports reserved for data disks hotplugged while running (default: 0)
+}
+
+// MaxBlockHotplugPorts is the largest VMResourceConfig.BlockHotplugPorts.
+const MaxBlockHotplugPorts = 16
+
+// Validate reports inconsistent resource limits before they reach the VMM,
+// where they would fail with an opaque error. MemoryHotplugSize may be zero
+// to disable memory hotplug. Errors wrap errdefs.ErrInvalidArgument.
+func (c *VMResourceConfig) Validate() error {
+	if c.BootCPUs < 1 {
+		return fmt.Errorf("BootCPUs must be at least 1, got %d: %w", c.BootCPUs, errdefs.ErrInvalidArgument)
+	}
+	if c.MaxCPUs < c.BootCPUs {
+		return fmt.Errorf("MaxCPUs (%d) must not be less than BootCPUs (%d): %w", c.MaxCPUs, c.BootCPUs, errdefs.ErrInvalidArgument)
+	}
+	if c.MemorySize <= 0 {
+		return fmt.Errorf("MemorySize must be positive, got %d: %w", c.MemorySize, errdefs.ErrInvalidArgument)
+	}
+	if c.MemoryHotplugSize != 0 && c.MemoryHotplugSize < c.MemorySize {
+		return fmt.Errorf("MemoryHotplugSize (%d) must be zero or not less than MemorySize (%d): %w",
+			c.MemoryHotplugSize, c.MemorySize, errdefs.ErrInvalidArgument)
+	}
+	if c.MemorySlots < 0 {
+		return fmt.Errorf("MemorySlots must not be negative, got %d: %w", c.MemorySlots, errdefs.ErrInvalidArgument)
+	}
+	if c.BlockHotplugPorts < 0 || c.BlockHotplugPorts > MaxBlockHotplugPorts {
+		return fmt.Errorf("BlockHotplugPorts must be between 0 and %d, got %d: %w", MaxBlockHotplugPorts, c.BlockHotplugPorts, errdefs.ErrInvalidArgument)
+	}
+	return nil
 }
 
 // StartOpts defines configuration options for starting a VM.
@@ -42,6 +85,10 @@ type StartOpts struct {
 	InitArgs         []string
 	NetworkConfig    *NetworkConfig
 	NetworkNamespace string // Path to network namespace (e.g., "/var/run/netns/cni-xxx")
+
+	// BootTimeout bounds the time from launching the VM until the guest
+	// accepts RPC connections. Zero selects the backend default.
+	BootTimeout time.Duration
 }
 
 // StartOpt configures VM start options.
@@ -68,6 +115,13 @@ func WithNetworkNamespace(path string) StartOpt {
 	}
 }
 
+// WithBootTimeout sets the maximum time to wait for the guest to become ready.
+func WithBootTimeout(d time.Duration) StartOpt {
+	return func(o *StartOpts) {
+		o.BootTimeout = d
+	}
+}
+
 // MountConfig defines configuration for mounting disks into the VM.
 type MountConfig struct {
 	Readonly bool
@@ -146,6 +200,14 @@ type ResourceManager interface {
 	CPUHotplugger() (CPUHotplugger, error)
 }
 
+// BlockDeviceAdder is implemented by VM backends that can attach data disks,
+// both before Start and to a running VM.
+type BlockDeviceAdder interface {
+	// AddBlockDevice attaches the disk image at path as a virtio-blk device
+	// and returns its name in the guest, e.g. "vdb".
+	AddBlockDevice(ctx context.Context, path string, readonly bool) (string, error)
+}
+
 // Instance represents a VM instance that can run containers.
 // This interface abstracts the VMM backend (QEMU) and composes
 // focused interfaces for different aspects of VM management.
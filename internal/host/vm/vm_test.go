@@ -0,0 +1,65 @@
+package vm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/containerd/errdefs"
+)
+
+func TestVMResourceConfigValidate(t *testing.T) {
+	const mib = 1024 * 1024
+	valid := VMResourceConfig{
+		BootCPUs:          1,
+		MaxCPUs:           2,
+		MemorySize:        512 * mib,
+		MemoryHotplugSize: 1024 * mib,
+		MemorySlots:       8,
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*VMResourceConfig)
+		field  string // expected in the error, empty for valid configs
+	}{
+		{name: "valid", modify: func(*VMResourceConfig) {}},
+		{name: "hotplug disabled", modify: func(c *VMResourceConfig) { c.MemoryHotplugSize = 0 }},
+		{name: "hotplug equals memory", modify: func(c *VMResourceConfig) { c.MemoryHotplugSize = c.MemorySize }},
+		{name: "max equals boot", modify: func(c *VMResourceConfig) { c.MaxCPUs = c.BootCPUs }},
+		{name: "zero config", modify: func(c *VMResourceConfig) { *c = VMResourceConfig{} }, field: "BootCPUs"},
+		{name: "zero boot CPUs", modify: func(c *VMResourceConfig) { c.BootCPUs = 0 }, field: "BootCPUs"},
+		{name: "negative boot CPUs", modify: func(c *VMResourceConfig) { c.BootCPUs = -1 }, field: "BootCPUs"},
+		{name: "max below boot", modify: func(c *VMResourceConfig) { c.BootCPUs, c.MaxCPUs = 4, 2 }, field: "MaxCPUs"},
+		{name: "zero memory", modify: func(c *VMResourceConfig) { c.MemorySize = 0 }, field: "MemorySize"},
+		{name: "negative memory", modify: func(c *VMResourceConfig) { c.MemorySize = -mib }, field: "MemorySize"},
+		{name: "hotplug below memory", modify: func(c *VMResourceConfig) { c.MemoryHotplugSize = 256 * mib }, field: "MemoryHotplugSize"},
+		{name: "negative slots", modify: func(c *VMResourceConfig) { c.MemorySlots = -1 }, field: "MemorySlots"},
+		{name: "block hotplug ports", modify: func(c *VMResourceConfig) { c.BlockHotplugPorts = MaxBlockHotplugPorts }},
+		{name: "negative block hotplug ports", modify: func(c *VMResourceConfig) { c.BlockHotplugPorts = -1 }, field: "BlockHotplugPorts"},
+		{name: "too many block hotplug ports", modify: func(c *VMResourceConfig) { c.BlockHotplugPorts = MaxBlockHotplugPorts + 1 }, field: "BlockHotplugPorts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() = nil, want error naming %s", tt.field)
+			}
+			if !errdefs.IsInvalidArgument(err) {
+				t.Errorf("Validate() = %v, want invalid argument", err)
+			}
+			if !strings.Contains(err.Error(), tt.field) {
+				t.Errorf("Validate() = %q, want it to name %s", err, tt.field)
+			}
+		})
+	}
+}
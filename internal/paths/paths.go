@@ -4,20 +4,145 @@
 package paths
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 
 	"github.com/spin-stack/spinbox/internal/config"
 )
 
-// KernelPath returns the full path to the kernel binary based on the provided configuration
+// hostArch is the architecture of the host, as a GOARCH value. It is a
+// variable so tests can resolve names for other architectures.
+var hostArch = runtime.GOARCH
+
+// KernelName returns the file name of the host architecture's kernel image.
+// See config.KernelName.
+func KernelName() string {
+	return config.KernelName(hostArch)
+}
+
+// InitrdName returns the file name of the arch-neutral initrd.
+// See config.InitrdName.
+func InitrdName() string {
+	return config.InitrdName()
+}
+
+// KernelPath returns the full path to the host architecture's kernel binary
+// based on the provided configuration
 func KernelPath(pathsCfg config.PathsConfig) string {
-	return filepath.Join(pathsCfg.ShareDir, "kernel", "spinbox-kernel-x86_64")
+	return filepath.Join(pathsCfg.ShareDir, "kernel", KernelName())
 }
 
 // InitrdPath returns the full path to the initrd binary based on the provided configuration
 func InitrdPath(pathsCfg config.PathsConfig) string {
-	return filepath.Join(pathsCfg.ShareDir, "kernel", "spinbox-initrd")
+	return filepath.Join(pathsCfg.ShareDir, "kernel", InitrdName())
+}
+
+// NormalizeArch maps a Go (GOARCH) or kernel (uname -m) architecture name to
+// the suffix used by spinbox kernel images. An empty arch means the host
+// architecture.
+func NormalizeArch(arch string) (string, error) {
+	if arch == "" {
+		arch = hostArch
+	}
+	return config.NormalizeArch(arch)
+}
+
+// KernelPathForArch returns the full path to the kernel binary for the given
+// architecture (e.g., "amd64" or "aarch64"). For the host architecture this
+// is KernelPath, so config.KernelNameEnvVar applies.
+func KernelPathForArch(pathsCfg config.PathsConfig, arch string) (string, error) {
+	a, err := NormalizeArch(arch)
+	if err != nil {
+		return "", err
+	}
+	if host, err := NormalizeArch(""); err == nil && a == host {
+		return KernelPath(pathsCfg), nil
+	}
+	return filepath.Join(pathsCfg.ShareDir, "kernel", "spinbox-kernel-"+a), nil
+}
+
+// InitrdPathForArch returns the full path to the arch-specific initrd.
+// Hosts serving a single architecture may ship only the arch-neutral
+// InitrdPath instead.
+func InitrdPathForArch(pathsCfg config.PathsConfig, arch string) (string, error) {
+	a, err := NormalizeArch(arch)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(pathsCfg.ShareDir, "kernel", "spinbox-initrd-"+a), nil
+}
+
+// KernelSearchPaths returns the locations searched for the kernel of the
+// given architecture, in order of preference.
+func KernelSearchPaths(pathsCfg config.PathsConfig, arch string) ([]string, error) {
+	kernel, err := KernelPathForArch(pathsCfg, arch)
+	if err != nil {
+		return nil, err
+	}
+	return []string{kernel}, nil
+}
+
+// InitrdSearchPaths returns the locations searched for the initrd of the
+// given architecture, in order of preference. The arch-neutral InitrdPath is
+// only searched for the host architecture, since it is built for the host.
+func InitrdSearchPaths(pathsCfg config.PathsConfig, arch string) ([]string, error) {
+	initrd, err := InitrdPathForArch(pathsCfg, arch)
+	if err != nil {
+		return nil, err
+	}
+	search := []string{initrd}
+	if a, _ := NormalizeArch(arch); a != "" {
+		if host, err := NormalizeArch(""); err == nil && a == host {
+			search = append(search, InitrdPath(pathsCfg))
+		}
+	}
+	return search, nil
+}
+
+// Candidate is a location checked while resolving a file.
+type Candidate struct {
+	Path  string // Absolute path that was checked
+	Found bool   // Whether a regular file exists at Path
+}
+
+// ResolveAll checks every path in search, in order, so callers can report
+// the full search trail when nothing is found.
+func ResolveAll(search []string) []Candidate {
+	candidates := make([]Candidate, 0, len(search))
+	for _, p := range search {
+		if abs, err := filepath.Abs(p); err == nil {
+			p = abs
+		}
+		candidates = append(candidates, Candidate{Path: p, Found: fileExists(p)})
+	}
+	return candidates
+}
+
+// FirstFound returns the path of the first found candidate.
+func FirstFound(candidates []Candidate) (string, bool) {
+	for _, c := range candidates {
+		if c.Found {
+			return c.Path, true
+		}
+	}
+	return "", false
+}
+
+// FormatCandidates renders a search trail for error messages, e.g.
+// "/a (not found), /b (not found)".
+func FormatCandidates(candidates []Candidate) string {
+	parts := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		state := "not found"
+		if c.Found {
+			state = "found"
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s)", c.Path, state))
+	}
+	return strings.Join(parts, ", ")
 }
 
 // QemuPath returns the full path to the qemu-system-x86_64 binary based on the provided configuration
@@ -3,6 +3,7 @@ package paths
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/spin-stack/spinbox/internal/config"
@@ -173,7 +174,70 @@ func TestDirExists(t *testing.T) {
 	}
 }
 
+func TestKernelName(t *testing.T) {
+	setHostArch := func(t *testing.T, arch string) {
+		t.Helper()
+		old := hostArch
+		hostArch = arch
+		t.Cleanup(func() { hostArch = old })
+	}
+
+	tests := []struct {
+		arch     string
+		override string
+		want     string
+	}{
+		{arch: "amd64", want: "spinbox-kernel-x86_64"},
+		{arch: "arm64", want: "spinbox-kernel-aarch64"},
+		{arch: "riscv64", want: "spinbox-kernel-riscv64"},
+		{arch: "amd64", override: "custom-kernel", want: "custom-kernel"},
+		{arch: "arm64", override: "custom-kernel", want: "custom-kernel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arch+"/"+tt.override, func(t *testing.T) {
+			setHostArch(t, tt.arch)
+			t.Setenv(config.KernelNameEnvVar, tt.override)
+
+			if got := KernelName(); got != tt.want {
+				t.Errorf("KernelName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("KernelPathForArch honors the override for the host arch only", func(t *testing.T) {
+		setHostArch(t, "arm64")
+		t.Setenv(config.KernelNameEnvVar, "custom-kernel")
+		cfg := config.PathsConfig{ShareDir: "/usr/share/spinbox"}
+
+		for arch, want := range map[string]string{
+			"arm64":   "/usr/share/spinbox/kernel/custom-kernel",
+			"aarch64": "/usr/share/spinbox/kernel/custom-kernel",
+			"amd64":   "/usr/share/spinbox/kernel/spinbox-kernel-x86_64",
+		} {
+			got, err := KernelPathForArch(cfg, arch)
+			if err != nil {
+				t.Fatalf("KernelPathForArch(%q) failed: %v", arch, err)
+			}
+			if got != want {
+				t.Errorf("KernelPathForArch(%q) = %q, want %q", arch, got, want)
+			}
+		}
+	})
+
+	t.Run("InitrdName override", func(t *testing.T) {
+		t.Setenv(config.InitrdNameEnvVar, "custom-initrd")
+		if got := InitrdName(); got != "custom-initrd" {
+			t.Errorf("InitrdName() = %q, want %q", got, "custom-initrd")
+		}
+	})
+}
+
 func TestPathFunctions(t *testing.T) {
+	old := hostArch
+	hostArch = "amd64"
+	t.Cleanup(func() { hostArch = old })
+
 	tests := []struct {
 		name string
 		cfg  config.PathsConfig
@@ -354,3 +418,53 @@ func TestQemuSharePathDiscovery(t *testing.T) {
 		t.Errorf("QemuSharePath() with discovery = %q, want %q", got, qemuShareDir)
 	}
 }
+
+func TestResolveAll(t *testing.T) {
+	old := hostArch
+	hostArch = "amd64"
+	t.Cleanup(func() { hostArch = old })
+
+	shareDir := t.TempDir()
+	cfg := config.PathsConfig{ShareDir: shareDir}
+	kernelDir := filepath.Join(shareDir, "kernel")
+	if err := os.MkdirAll(kernelDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	neutral := filepath.Join(kernelDir, "spinbox-initrd")
+	if err := os.WriteFile(neutral, []byte("initrd"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	search, err := InitrdSearchPaths(cfg, "amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	candidates := ResolveAll(search)
+	want := []Candidate{
+		{Path: filepath.Join(kernelDir, "spinbox-initrd-x86_64"), Found: false},
+		{Path: neutral, Found: true},
+	}
+	if !slices.Equal(candidates, want) {
+		t.Fatalf("ResolveAll() = %v, want %v", candidates, want)
+	}
+	if got, ok := FirstFound(candidates); !ok || got != neutral {
+		t.Errorf("FirstFound() = %q, %v, want %q, true", got, ok, neutral)
+	}
+
+	// The neutral initrd is never searched for a foreign architecture
+	search, err = InitrdSearchPaths(cfg, "arm64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	candidates = ResolveAll(search)
+	if len(candidates) != 1 || candidates[0].Found {
+		t.Fatalf("ResolveAll() = %v, want one missing candidate", candidates)
+	}
+	if _, ok := FirstFound(candidates); ok {
+		t.Error("FirstFound() reported a candidate as found")
+	}
+	wantTrail := filepath.Join(kernelDir, "spinbox-initrd-aarch64") + " (not found)"
+	if got := FormatCandidates(candidates); got != wantTrail {
+		t.Errorf("FormatCandidates() = %q, want %q", got, wantTrail)
+	}
+}
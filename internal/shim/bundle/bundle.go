@@ -2,22 +2,71 @@
 package bundle
 
 import (
+	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/containerd/errdefs"
 	"github.com/opencontainers/runtime-spec/specs-go"
 )
 
+const (
+	// maxTransferSize is the largest message the ttrpc transport to the VM
+	// accepts (ttrpc's unexported messageLengthMax). The whole bundle is
+	// sent in a single CreateRequest, so all of it must fit.
+	maxTransferSize = 4 << 20
+	// transferReserve is the part of a transfer kept free for config.json
+	// and the message framing.
+	transferReserve = 1 << 20
+
+	// MaxTransferExtraFilesSize is what is left of a transfer after
+	// transferReserve: the largest combined size of all extra files that
+	// can still be sent to the VM. Limits raised for host-provided files
+	// must stay within it.
+	MaxTransferExtraFilesSize = maxTransferSize - transferReserve
+
+	// DefaultMaxExtraFilesSize is the largest combined size of all extra
+	// files when Bundle.MaxExtraFilesSize is unset.
+	DefaultMaxExtraFilesSize = MaxTransferExtraFilesSize
+	// DefaultMaxExtraFileSize is the largest extra file a bundle accepts
+	// when Bundle.MaxExtraFileSize is unset. A single file may use the
+	// whole budget.
+	DefaultMaxExtraFileSize = DefaultMaxExtraFilesSize
+)
+
+// ErrExtraFileTooLarge is returned by AddExtraFile when a file exceeds the
+// per-file or total size limit.
+var ErrExtraFileTooLarge = errors.New("extra file too large")
+
 // Bundle represents an OCI bundle with extra files for the VM.
 type Bundle struct {
 	Path   string // Path is the bundle path.
 	Spec   specs.Spec
 	Rootfs string // Rootfs is the absolute path to the root filesystem.
 
+	// IndentSpec makes Files emit an indented config.json so the bundle
+	// transferred to the VM is readable when debugging. Compact output is
+	// the default to keep the transfer small.
+	IndentSpec bool
+
+	// MaxExtraFileSize and MaxExtraFilesSize bound the size of a single
+	// extra file and of all extra files combined, protecting the transfer
+	// to the VM from oversized bundles. Zero selects DefaultMaxExtraFileSize
+	// and DefaultMaxExtraFilesSize.
+	MaxExtraFileSize  int
+	MaxExtraFilesSize int
+
 	// extraFiles are files that are not part of the OCI bundle but are needed
 	// to setup containers in the VM. Keep it unexported to force consumers to
 	// call Files to get all the files, including the updated OCI spec.
@@ -63,7 +112,19 @@ func Load(ctx context.Context, path string, transformers ...Transformer) (*Bundl
 	return b, nil
 }
 
+// WithExtraFileLimits returns a Transformer that sets the extra file size
+// limits. Pass it to Load before any transformer that adds extra files.
+func WithExtraFileLimits(perFile, total int) Transformer {
+	return func(_ context.Context, b *Bundle) error {
+		b.MaxExtraFileSize = perFile
+		b.MaxExtraFilesSize = total
+		return nil
+	}
+}
+
 // AddExtraFile adds an extra file to the bundle that is not part of the OCI spec.
+// Files over the per-file limit, or that would take the extra files over the
+// total limit, are rejected with an error wrapping ErrExtraFileTooLarge.
 func (b *Bundle) AddExtraFile(name string, data []byte) error {
 	if name == "" {
 		return fmt.Errorf("file name cannot be empty")
@@ -78,6 +139,19 @@ func (b *Bundle) AddExtraFile(name string, data []byte) error {
 		return fmt.Errorf("file name %q must not contain path separators or relative components", name)
 	}
 
+	if limit := cmp.Or(b.MaxExtraFileSize, DefaultMaxExtraFileSize); len(data) > limit {
+		return fmt.Errorf("extra file %q is %d bytes, limit is %d bytes: %w", name, len(data), limit, ErrExtraFileTooLarge)
+	}
+	total := len(data)
+	for n, d := range b.extraFiles {
+		if n != name {
+			total += len(d)
+		}
+	}
+	if limit := cmp.Or(b.MaxExtraFilesSize, DefaultMaxExtraFilesSize); total > limit {
+		return fmt.Errorf("adding extra file %q brings extra files to %d bytes, limit is %d bytes: %w", name, total, limit, ErrExtraFileTooLarge)
+	}
+
 	b.extraFiles[name] = data
 	return nil
 }
@@ -91,7 +165,15 @@ func (b *Bundle) Files() (map[string][]byte, error) {
 		files[k] = append([]byte(nil), v...)
 	}
 
-	specBytes, err := json.Marshal(b.Spec)
+	var (
+		specBytes []byte
+		err       error
+	)
+	if b.IndentSpec {
+		specBytes, err = json.MarshalIndent(b.Spec, "", "  ")
+	} else {
+		specBytes, err = json.Marshal(b.Spec)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal spec: %w", err)
 	}
@@ -100,6 +182,82 @@ func (b *Bundle) Files() (map[string][]byte, error) {
 	return files, nil
 }
 
+// File is a bundle file to set up inside the VM.
+type File struct {
+	Name string
+	Data []byte
+}
+
+// OrderedFiles returns the same files as Files in a stable order: config.json
+// first, followed by the extra files sorted by name. Use it where the output
+// is hashed, logged or otherwise compared across calls.
+func (b *Bundle) OrderedFiles() ([]File, error) {
+	files, err := b.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := make([]File, 0, len(files))
+	ordered = append(ordered, File{Name: "config.json", Data: files["config.json"]})
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		if name != "config.json" {
+			ordered = append(ordered, File{Name: name, Data: files[name]})
+		}
+	}
+	return ordered, nil
+}
+
+// Hash returns a hex-encoded SHA256 digest of the bundle files, including
+// config.json. Identical bundles hash the same regardless of the order extra
+// files were added, so the host can compare hashes to skip re-sending a
+// bundle the VM already has.
+func (b *Bundle) Hash() (string, error) {
+	files, err := b.OrderedFiles()
+	if err != nil {
+		return "", err
+	}
+
+	// Length-prefix names and contents so that moving bytes between a
+	// name and its data, or between adjacent files, changes the digest.
+	h := sha256.New()
+	var n [8]byte
+	for _, f := range files {
+		binary.BigEndian.PutUint64(n[:], uint64(len(f.Name)))
+		h.Write(n[:])
+		h.Write([]byte(f.Name))
+		binary.BigEndian.PutUint64(n[:], uint64(len(f.Data)))
+		h.Write(n[:])
+		h.Write(f.Data)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Describe returns a human-readable summary of the bundle for debug logging:
+// the spec version, rootfs and process arguments, and the name, size and a
+// guessed content type of each extra file. File contents are not included.
+func (b *Bundle) Describe() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "bundle %s\n", b.Path)
+	fmt.Fprintf(&sb, "  spec version: %s\n", b.Spec.Version)
+	fmt.Fprintf(&sb, "  rootfs: %s\n", b.Rootfs)
+	if b.Spec.Process != nil {
+		fmt.Fprintf(&sb, "  process args: %q\n", b.Spec.Process.Args)
+	} else {
+		sb.WriteString("  process args: none\n")
+	}
+
+	var total int
+	for _, d := range b.extraFiles {
+		total += len(d)
+	}
+	fmt.Fprintf(&sb, "  extra files: %d (%d bytes)", len(b.extraFiles), total)
+	for _, name := range slices.Sorted(maps.Keys(b.extraFiles)) {
+		data := b.extraFiles[name]
+		fmt.Fprintf(&sb, "\n    %s: %d bytes, %s", name, len(data), http.DetectContentType(data))
+	}
+	return sb.String()
+}
+
 // resolveRootfsPath is a Transformer that resolves the absolute rootfs path on the host
 // and normalizes it to "rootfs" in the spec for the VM.
 // The context parameter is unused but required to match the Transformer signature.
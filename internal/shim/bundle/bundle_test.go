@@ -1,15 +1,22 @@
 package bundle
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/containerd/errdefs"
 	"github.com/opencontainers/runtime-spec/specs-go"
+	"google.golang.org/protobuf/proto"
+
+	bundleAPI "github.com/spin-stack/spinbox/api/services/bundle/v1"
 )
 
 const testRootfsPath = "rootfs"
@@ -296,6 +303,133 @@ func TestAddExtraFile(t *testing.T) {
 	}
 }
 
+func TestAddExtraFile_SizeLimits(t *testing.T) {
+	newBundle := func() *Bundle {
+		return &Bundle{
+			MaxExtraFileSize:  10,
+			MaxExtraFilesSize: 25,
+			extraFiles:        make(map[string][]byte),
+		}
+	}
+
+	t.Run("per-file limit", func(t *testing.T) {
+		b := newBundle()
+		if err := b.AddExtraFile("ok", make([]byte, 10)); err != nil {
+			t.Fatalf("AddExtraFile() at limit error = %v", err)
+		}
+		err := b.AddExtraFile("big", make([]byte, 11))
+		if !errors.Is(err, ErrExtraFileTooLarge) {
+			t.Fatalf("AddExtraFile() over limit error = %v, want ErrExtraFileTooLarge", err)
+		}
+		for _, want := range []string{`"big"`, "10 bytes"} {
+			if !contains(err.Error(), want) {
+				t.Errorf("error %q does not contain %q", err, want)
+			}
+		}
+		if _, ok := b.extraFiles["big"]; ok {
+			t.Error("oversized file was added")
+		}
+	})
+
+	t.Run("total limit", func(t *testing.T) {
+		b := newBundle()
+		for _, name := range []string{"a", "b"} {
+			if err := b.AddExtraFile(name, make([]byte, 10)); err != nil {
+				t.Fatalf("AddExtraFile(%q) error = %v", name, err)
+			}
+		}
+		if err := b.AddExtraFile("c", make([]byte, 5)); err != nil {
+			t.Fatalf("AddExtraFile() reaching total limit error = %v", err)
+		}
+		err := b.AddExtraFile("d", make([]byte, 1))
+		if !errors.Is(err, ErrExtraFileTooLarge) {
+			t.Fatalf("AddExtraFile() over total limit error = %v, want ErrExtraFileTooLarge", err)
+		}
+		for _, want := range []string{`"d"`, "25 bytes"} {
+			if !contains(err.Error(), want) {
+				t.Errorf("error %q does not contain %q", err, want)
+			}
+		}
+
+		// Replacing a file only counts its new size
+		if err := b.AddExtraFile("c", make([]byte, 4)); err != nil {
+			t.Fatalf("AddExtraFile() replacing a file error = %v", err)
+		}
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		b := &Bundle{extraFiles: make(map[string][]byte)}
+		if err := b.AddExtraFile("max", make([]byte, DefaultMaxExtraFileSize)); err != nil {
+			t.Fatalf("AddExtraFile() at default limit error = %v", err)
+		}
+		if err := b.AddExtraFile("over", make([]byte, DefaultMaxExtraFileSize+1)); !errors.Is(err, ErrExtraFileTooLarge) {
+			t.Fatalf("AddExtraFile() over default limit error = %v, want ErrExtraFileTooLarge", err)
+		}
+	})
+
+	t.Run("bundle at the limit fits in one ttrpc message", func(t *testing.T) {
+		// A generous spec: long environment and many mounts, indented
+		spec := specs.Spec{
+			Version:  "1.0.0",
+			Hostname: "test",
+			Process:  &specs.Process{Args: []string{"/bin/sh", "-c", "true"}},
+		}
+		for i := range 2000 {
+			spec.Process.Env = append(spec.Process.Env, fmt.Sprintf("VARIABLE_%d=%s", i, strings.Repeat("v", 64)))
+			if i < 500 {
+				spec.Mounts = append(spec.Mounts, specs.Mount{
+					Destination: fmt.Sprintf("/mnt/data/%d", i),
+					Type:        "bind",
+					Source:      fmt.Sprintf("/var/lib/data/volumes/%d", i),
+					Options:     []string{"rbind", "ro", "nosuid", "nodev"},
+				})
+			}
+		}
+		b := &Bundle{
+			Spec:              spec,
+			IndentSpec:        true,
+			MaxExtraFileSize:  MaxTransferExtraFilesSize,
+			MaxExtraFilesSize: MaxTransferExtraFilesSize,
+			extraFiles:        make(map[string][]byte),
+		}
+		// One large file and many small ones, adding up to the limit
+		const small = 1024
+		if err := b.AddExtraFile("spin-supervisor", make([]byte, MaxTransferExtraFilesSize-100*small)); err != nil {
+			t.Fatalf("AddExtraFile() error = %v", err)
+		}
+		for i := range 100 {
+			if err := b.AddExtraFile(fmt.Sprintf("file-%d", i), make([]byte, small)); err != nil {
+				t.Fatalf("AddExtraFile() error = %v", err)
+			}
+		}
+		if err := b.AddExtraFile("over", []byte{0}); !errors.Is(err, ErrExtraFileTooLarge) {
+			t.Fatalf("AddExtraFile() over the limit error = %v, want ErrExtraFileTooLarge", err)
+		}
+
+		files, err := b.Files()
+		if err != nil {
+			t.Fatalf("Files() error = %v", err)
+		}
+		data, err := proto.Marshal(&bundleAPI.CreateRequest{ID: "test-container", Files: files})
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		if len(data) > maxTransferSize {
+			t.Fatalf("CreateRequest is %d bytes, transfer limit is %d bytes", len(data), maxTransferSize)
+		}
+	})
+
+	t.Run("set by transformer", func(t *testing.T) {
+		b := &Bundle{extraFiles: make(map[string][]byte)}
+		if err := WithExtraFileLimits(1, 2)(context.Background(), b); err != nil {
+			t.Fatalf("WithExtraFileLimits() error = %v", err)
+		}
+		if err := b.AddExtraFile("x", []byte("ab")); !errors.Is(err, ErrExtraFileTooLarge) {
+			t.Fatalf("AddExtraFile() error = %v, want ErrExtraFileTooLarge", err)
+		}
+	})
+}
+
 func TestFiles(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -400,6 +534,193 @@ func TestFiles(t *testing.T) {
 	}
 }
 
+func TestOrderedFiles(t *testing.T) {
+	b := &Bundle{
+		Spec:       specs.Spec{Version: "1.0.0"},
+		extraFiles: make(map[string][]byte),
+	}
+	for _, name := range []string{"resolv.conf", "app.conf", "zz.sh", "init.sh", "data.txt"} {
+		if err := b.AddExtraFile(name, []byte(name)); err != nil {
+			t.Fatalf("AddExtraFile(%q) error = %v", name, err)
+		}
+	}
+
+	want := []string{"config.json", "app.conf", "data.txt", "init.sh", "resolv.conf", "zz.sh"}
+	for i := range 10 {
+		files, err := b.OrderedFiles()
+		if err != nil {
+			t.Fatalf("OrderedFiles() error = %v", err)
+		}
+		names := make([]string, 0, len(files))
+		for _, f := range files {
+			names = append(names, f.Name)
+		}
+		if !reflect.DeepEqual(names, want) {
+			t.Fatalf("call %d: OrderedFiles() names = %v, want %v", i, names, want)
+		}
+		for _, f := range files[1:] {
+			if string(f.Data) != f.Name {
+				t.Errorf("file %q data = %q, want %q", f.Name, f.Data, f.Name)
+			}
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	newBundle := func(t *testing.T, files ...string) *Bundle {
+		t.Helper()
+		b := &Bundle{
+			Spec:       specs.Spec{Version: "1.0.0", Hostname: "test"},
+			extraFiles: make(map[string][]byte),
+		}
+		for _, name := range files {
+			if err := b.AddExtraFile(name, []byte("data-"+name)); err != nil {
+				t.Fatalf("AddExtraFile(%q) error = %v", name, err)
+			}
+		}
+		return b
+	}
+	hash := func(t *testing.T, b *Bundle) string {
+		t.Helper()
+		h, err := b.Hash()
+		if err != nil {
+			t.Fatalf("Hash() error = %v", err)
+		}
+		return h
+	}
+
+	base := hash(t, newBundle(t, "a", "b", "c"))
+	if len(base) != 64 {
+		t.Fatalf("Hash() = %q, want 64 hex characters", base)
+	}
+
+	t.Run("stable for identical bundles", func(t *testing.T) {
+		for range 10 {
+			if got := hash(t, newBundle(t, "c", "a", "b")); got != base {
+				t.Fatalf("Hash() = %s, want %s", got, base)
+			}
+		}
+	})
+
+	for _, tc := range []struct {
+		name   string
+		mutate func(*Bundle)
+	}{
+		{"file contents", func(b *Bundle) { b.extraFiles["b"] = []byte("changed") }},
+		{"file added", func(b *Bundle) { b.extraFiles["d"] = nil }},
+		{"file removed", func(b *Bundle) { delete(b.extraFiles, "c") }},
+		{"file renamed", func(b *Bundle) {
+			b.extraFiles["z"] = b.extraFiles["c"]
+			delete(b.extraFiles, "c")
+		}},
+		{"spec", func(b *Bundle) { b.Spec.Hostname = "other" }},
+		{"spec formatting", func(b *Bundle) { b.IndentSpec = true }},
+	} {
+		t.Run("changes with "+tc.name, func(t *testing.T) {
+			b := newBundle(t, "a", "b", "c")
+			tc.mutate(b)
+			if got := hash(t, b); got == base {
+				t.Fatalf("Hash() = %s, want a different hash", got)
+			}
+		})
+	}
+
+	t.Run("boundary between name and contents", func(t *testing.T) {
+		b1 := newBundle(t)
+		b1.extraFiles["ab"] = []byte("c")
+		b2 := newBundle(t)
+		b2.extraFiles["a"] = []byte("bc")
+		if hash(t, b1) == hash(t, b2) {
+			t.Fatal("Hash() is equal for bundles that only differ in where the name ends")
+		}
+	})
+}
+
+func TestDescribe(t *testing.T) {
+	b := &Bundle{
+		Path:   "/run/bundles/test",
+		Rootfs: "/run/bundles/test/rootfs",
+		Spec: specs.Spec{
+			Version: "1.0.2",
+			Process: &specs.Process{Args: []string{"/bin/sh", "-c", "echo hi"}},
+		},
+		extraFiles: make(map[string][]byte),
+	}
+	files := map[string][]byte{
+		"resolv.conf": []byte("nameserver 10.0.0.1\n"),
+		"supervisor":  {0x7f, 'E', 'L', 'F', 0, 0, 0, 0},
+		"empty":       nil,
+	}
+	for name, data := range files {
+		if err := b.AddExtraFile(name, data); err != nil {
+			t.Fatalf("AddExtraFile(%q) error = %v", name, err)
+		}
+	}
+
+	got := b.Describe()
+	for _, want := range []string{
+		"bundle /run/bundles/test\n",
+		"spec version: 1.0.2\n",
+		"rootfs: /run/bundles/test/rootfs\n",
+		`process args: ["/bin/sh" "-c" "echo hi"]`,
+		"extra files: 3 (28 bytes)",
+		"\n    empty: 0 bytes, text/plain; charset=utf-8",
+		"\n    resolv.conf: 20 bytes, text/plain; charset=utf-8",
+		"\n    supervisor: 8 bytes, application/octet-stream",
+	} {
+		if !contains(got, want) {
+			t.Errorf("Describe() = %q, missing %q", got, want)
+		}
+	}
+	if contains(got, "nameserver") {
+		t.Errorf("Describe() = %q, must not include file contents", got)
+	}
+	if i, j := strings.Index(got, "empty:"), strings.Index(got, "supervisor:"); i > j {
+		t.Errorf("Describe() = %q, extra files not sorted", got)
+	}
+}
+
+func TestFiles_IndentSpec(t *testing.T) {
+	spec := specs.Spec{
+		Version:  "1.0.2",
+		Hostname: "test",
+		Process:  &specs.Process{Args: []string{"/bin/sh", "-c", "echo hi"}},
+		Root:     &specs.Root{Path: testRootfsPath},
+	}
+
+	compact := &Bundle{Spec: spec, extraFiles: make(map[string][]byte)}
+	indented := &Bundle{Spec: spec, IndentSpec: true, extraFiles: make(map[string][]byte)}
+
+	compactFiles, err := compact.Files()
+	if err != nil {
+		t.Fatalf("compact Files() error = %v", err)
+	}
+	indentedFiles, err := indented.Files()
+	if err != nil {
+		t.Fatalf("indented Files() error = %v", err)
+	}
+
+	compactJSON := compactFiles["config.json"]
+	indentedJSON := indentedFiles["config.json"]
+	if bytes.Contains(compactJSON, []byte("\n")) {
+		t.Error("compact config.json should not contain newlines")
+	}
+	if !bytes.Contains(indentedJSON, []byte("\n  ")) {
+		t.Error("indented config.json should contain indentation")
+	}
+
+	var fromCompact, fromIndented specs.Spec
+	if err := json.Unmarshal(compactJSON, &fromCompact); err != nil {
+		t.Fatalf("failed to unmarshal compact config.json: %v", err)
+	}
+	if err := json.Unmarshal(indentedJSON, &fromIndented); err != nil {
+		t.Fatalf("failed to unmarshal indented config.json: %v", err)
+	}
+	if !reflect.DeepEqual(fromCompact, fromIndented) {
+		t.Errorf("specs differ:\ncompact:  %+v\nindented: %+v", fromCompact, fromIndented)
+	}
+}
+
 func TestResolveRootfsPath(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -18,8 +18,10 @@ import (
 	"github.com/containerd/ttrpc"
 	"golang.org/x/sys/unix"
 
+	"github.com/spin-stack/spinbox/internal/config"
 	"github.com/spin-stack/spinbox/internal/host/vm"
-	"github.com/spin-stack/spinbox/internal/host/vm/qemu"
+	// Register the QEMU backend
+	_ "github.com/spin-stack/spinbox/internal/host/vm/qemu"
 )
 
 const (
@@ -56,7 +58,7 @@ func (m *Manager) CreateVM(ctx context.Context, containerID, bundlePath string,
 	}
 
 	// Determine VM state directory
-	vmStateRoot := os.Getenv("SPINBOX_VM_STATE_DIR")
+	vmStateRoot := os.Getenv(config.VMStateDirEnvVar)
 	vmState := filepath.Join(bundlePath, "vm")
 	if vmStateRoot != "" {
 		namespace, ok := namespaces.Namespace(ctx)
@@ -70,9 +72,13 @@ func (m *Manager) CreateVM(ctx context.Context, containerID, bundlePath string,
 		return nil, fmt.Errorf("failed to create vm state directory %q: %w", vmState, err)
 	}
 
-	// Create QEMU instance
-	var err error
-	m.instance, err = qemu.NewInstance(ctx, containerID, vmState, resourceCfg)
+	cfg, err := config.Get()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+
+	// Create the VM with the configured backend
+	m.instance, err = vm.NewInstance(ctx, configuredBackend(cfg.Runtime.VMM), containerID, vmState, resourceCfg)
 	if err != nil {
 		return nil, err
 	}
@@ -80,6 +86,17 @@ func (m *Manager) CreateVM(ctx context.Context, containerID, bundlePath string,
 	return m.instance, nil
 }
 
+// configuredBackend returns the backend to pass to vm.NewInstance for the
+// configured runtime.vmm. The config fills in the default backend when vmm
+// is unset, so the default is treated as unset: vm.NewInstance then
+// consults vm.BackendEnvVar before falling back to it.
+func configuredBackend(vmm string) string {
+	if vmm == string(vm.DefaultBackend) {
+		return ""
+	}
+	return vmm
+}
+
 // Instance returns the current VM instance.
 // Returns an error if no VM has been created.
 func (m *Manager) Instance() (vm.Instance, error) {
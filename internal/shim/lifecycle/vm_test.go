@@ -0,0 +1,25 @@
+package lifecycle
+
+import (
+	"testing"
+
+	"github.com/spin-stack/spinbox/internal/config"
+	"github.com/spin-stack/spinbox/internal/host/vm"
+)
+
+func TestConfiguredBackend(t *testing.T) {
+	t.Setenv(vm.BackendEnvVar, string(vm.BackendCloudHypervisor))
+
+	// The config default must not hide the environment override
+	b, err := vm.ResolveBackend(configuredBackend(config.DefaultConfig().Runtime.VMM))
+	if err != nil {
+		t.Fatalf("ResolveBackend() error = %v", err)
+	}
+	if b != vm.BackendCloudHypervisor {
+		t.Errorf("backend = %q, want %q", b, vm.BackendCloudHypervisor)
+	}
+
+	if got := configuredBackend(""); got != "" {
+		t.Errorf("configuredBackend(\"\") = %q, want empty", got)
+	}
+}
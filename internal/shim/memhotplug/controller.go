@@ -24,6 +24,7 @@ type qmpMemoryClient interface {
 	HotplugMemory(ctx context.Context, slotID int, sizeBytes int64) error
 	UnplugMemory(ctx context.Context, slotID int) error
 	QueryMemorySizeSummary(ctx context.Context) (*qemu.MemorySizeSummary, error)
+	QueryMemoryDevices(ctx context.Context) ([]qemu.MemoryDeviceInfo, error)
 }
 
 // Controller manages dynamic memory allocation for a VM based on memory usage
@@ -40,7 +41,7 @@ type Controller struct {
 
 	// Current state
 	currentMemory int64        // Current online memory in bytes
-	usedSlots     map[int]bool // Track which memory slots are used
+	usedSlots     map[int]bool // Track which memory slots this controller plugged
 
 	// Configuration
 	config Config
@@ -372,8 +373,21 @@ func (c *Controller) scaleUp(ctx context.Context, targetMemory int64) error {
 		return nil
 	}
 
+	// Slots can also be plugged outside the controller (Instance.AddMemory),
+	// so read the occupied dimm IDs from QEMU before picking one
+	devices, err := c.qmpClient.QueryMemoryDevices(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query memory devices: %w", err)
+	}
+	occupied := make(map[string]bool, len(devices))
+	for _, d := range devices {
+		if id, ok := d.Data["id"].(string); ok {
+			occupied[id] = true
+		}
+	}
+
 	// Find available slot
-	slotID := c.findFreeSlot()
+	slotID := c.findFreeSlot(occupied)
 	if slotID < 0 {
 		log.G(ctx).WithField("container_id", c.containerID).
 			Warn("memory-hotplug: no free memory slots available")
@@ -475,10 +489,11 @@ func (c *Controller) scaleDown(ctx context.Context, targetMemory int64) error {
 	return nil
 }
 
-// findFreeSlot finds the first available memory slot
-func (c *Controller) findFreeSlot() int {
+// findFreeSlot finds the first available memory slot that is neither
+// tracked by the controller nor occupied by a dimm device in QEMU
+func (c *Controller) findFreeSlot(occupied map[string]bool) int {
 	for i := range c.config.MaxSlots {
-		if !c.usedSlots[i] {
+		if !c.usedSlots[i] && !occupied[fmt.Sprintf("dimm%d", i)] {
 			return i
 		}
 	}
@@ -20,6 +20,8 @@ type mockQMPClient struct {
 	querySummaryErr  error
 	hotplugCallCount int
 	unplugCallCount  int
+	hotplugSlots     []int
+	devices          []qemu.MemoryDeviceInfo // dimms plugged outside the controller
 }
 
 func (m *mockQMPClient) HotplugMemory(ctx context.Context, slotID int, sizeBytes int64) error {
@@ -30,6 +32,7 @@ func (m *mockQMPClient) HotplugMemory(ctx context.Context, slotID int, sizeBytes
 		return m.hotplugErr
 	}
 	m.pluggedMemory += sizeBytes
+	m.hotplugSlots = append(m.hotplugSlots, slotID)
 	return nil
 }
 
@@ -60,6 +63,12 @@ func (m *mockQMPClient) QueryMemorySizeSummary(ctx context.Context) (*qemu.Memor
 	}, nil
 }
 
+func (m *mockQMPClient) QueryMemoryDevices(ctx context.Context) ([]qemu.MemoryDeviceInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.devices, nil
+}
+
 // mockStatsProvider simulates cgroup memory stats
 type mockStatsProvider struct {
 	mu          sync.Mutex
@@ -260,6 +269,39 @@ func TestControllerScaleUp(t *testing.T) {
 	}
 }
 
+func TestControllerScaleUpSkipsOccupiedSlots(t *testing.T) {
+	// dimm0 was plugged outside the controller (Instance.AddMemory)
+	mockQMP := &mockQMPClient{
+		baseMemory:    512 * 1024 * 1024,
+		pluggedMemory: 128 * 1024 * 1024,
+		devices: []qemu.MemoryDeviceInfo{
+			{Type: "dimm", Data: map[string]any{"id": "dimm0"}},
+		},
+	}
+	mockMem := &mockMemoryManager{}
+
+	ctrl := &Controller{
+		containerID:   "test-container",
+		qmpClient:     mockQMP,
+		onlineMemory:  mockMem.online,
+		offlineMemory: mockMem.offline,
+		currentMemory: 640 * 1024 * 1024,
+		usedSlots:     make(map[int]bool),
+		config:        Config{MaxSlots: 8},
+	}
+
+	if err := ctrl.scaleUp(context.Background(), 768*1024*1024); err != nil {
+		t.Fatalf("scaleUp() error = %v", err)
+	}
+	if len(mockQMP.hotplugSlots) != 1 || mockQMP.hotplugSlots[0] != 1 {
+		t.Errorf("expected hotplug into slot 1, got %v", mockQMP.hotplugSlots)
+	}
+	// Scale-down only considers the slot the controller plugged
+	if got := ctrl.findUsedSlot(); got != 1 {
+		t.Errorf("findUsedSlot() = %d, want 1", got)
+	}
+}
+
 func TestControllerNoScaleUpBelowThreshold(t *testing.T) {
 	mockQMP := &mockQMPClient{
 		baseMemory: 512 * 1024 * 1024,
@@ -525,6 +567,7 @@ func TestFindFreeSlot(t *testing.T) {
 	tests := []struct {
 		name      string
 		usedSlots map[int]bool
+		occupied  map[string]bool
 		want      int
 	}{
 		{
@@ -532,6 +575,12 @@ func TestFindFreeSlot(t *testing.T) {
 			usedSlots: map[int]bool{},
 			want:      0,
 		},
+		{
+			name:      "slot occupied in QEMU",
+			usedSlots: map[int]bool{1: true},
+			occupied:  map[string]bool{"dimm0": true},
+			want:      2,
+		},
 		{
 			name:      "first slot used",
 			usedSlots: map[int]bool{0: true},
@@ -555,7 +604,7 @@ func TestFindFreeSlot(t *testing.T) {
 				usedSlots: tt.usedSlots,
 				config:    Config{MaxSlots: 8},
 			}
-			if got := controller.findFreeSlot(); got != tt.want {
+			if got := controller.findFreeSlot(tt.occupied); got != tt.want {
 				t.Errorf("findFreeSlot() = %d, want %d", got, tt.want)
 			}
 		})
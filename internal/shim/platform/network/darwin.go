@@ -20,6 +20,6 @@ func (m *darwinManager) InitNetworkManager(ctx context.Context) (network.Network
 	return nil, fmt.Errorf("network manager not supported on darwin")
 }
 
-func (m *darwinManager) Setup(ctx context.Context, nm network.NetworkManager, vmi vm.Instance, containerID, netnsPath string) (*vm.NetworkConfig, error) {
+func (m *darwinManager) Setup(ctx context.Context, nm network.NetworkManager, vmi vm.Instance, containerID, netnsPath string, networks []string) (*vm.NetworkConfig, error) {
 	return nil, fmt.Errorf("networking not supported on darwin")
 }
@@ -6,11 +6,13 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"slices"
 
 	"github.com/containerd/log"
 	"github.com/docker/docker/libnetwork/resolvconf"
 
 	"github.com/spin-stack/spinbox/internal/host/network"
+	"github.com/spin-stack/spinbox/internal/host/network/cni"
 	"github.com/spin-stack/spinbox/internal/host/vm"
 )
 
@@ -40,14 +42,22 @@ func (m *linuxManager) InitNetworkManager(ctx context.Context) (network.NetworkM
 
 // Setup sets up networking using NetworkManager for dynamic IP allocation
 // and TAP device management. NetworkManager handles bridge creation, IP allocation,
-// TAP device lifecycle, and NFTables rules.
-// Returns the network configuration that should be passed to the VM kernel.
-func (m *linuxManager) Setup(ctx context.Context, nm network.NetworkManager, vmi vm.Instance, containerID, netnsPath string) (*vm.NetworkConfig, error) {
-	log.G(ctx).WithField("id", containerID).Info("setting up NetworkManager-based networking")
+// TAP device lifecycle, and NFTables rules. One TAP is attached per network in
+// networks (nil for the default network), in guest interface order, and
+// resources are released if any attach fails.
+// Returns the network configuration of the primary interface, eth0, that
+// should be passed to the VM kernel; the other interfaces are not configured
+// in the guest (see AnnotationNetworks).
+func (m *linuxManager) Setup(ctx context.Context, nm network.NetworkManager, vmi vm.Instance, containerID, netnsPath string, networks []string) (*vm.NetworkConfig, error) {
+	log.G(ctx).WithFields(log.Fields{
+		"id":       containerID,
+		"networks": networks,
+	}).Info("setting up NetworkManager-based networking")
 
 	// Create environment for this container
 	env := &network.Environment{
-		ID: containerID,
+		ID:       containerID,
+		Networks: networks,
 	}
 
 	// Allocate network resources (IP + TAP device)
@@ -55,43 +65,17 @@ func (m *linuxManager) Setup(ctx context.Context, nm network.NetworkManager, vmi
 		return nil, fmt.Errorf("allocate network resources: %w", err)
 	}
 
-	log.G(ctx).WithFields(log.Fields{
-		"tap":     env.NetworkInfo.TapName,
-		"ip":      env.NetworkInfo.IP.String(),
-		"gateway": env.NetworkInfo.Gateway.String(),
-		"netmask": env.NetworkInfo.Netmask,
-	}).Info("network resources allocated")
-
-	if env.NetworkInfo.MAC == "" {
+	release := func() {
 		if err := nm.ReleaseNetworkResources(ctx, env); err != nil {
 			log.G(ctx).WithError(err).Warn("failed to release network resources")
 		}
-		return nil, fmt.Errorf("CNI did not report TAP MAC address")
 	}
 
-	guestMAC, err := net.ParseMAC(env.NetworkInfo.MAC)
-	if err != nil {
-		if err := nm.ReleaseNetworkResources(ctx, env); err != nil {
-			log.G(ctx).WithError(err).Warn("failed to release network resources")
-		}
-		return nil, fmt.Errorf("invalid CNI TAP MAC address %q: %w", env.NetworkInfo.MAC, err)
+	infos := env.NetworkInfos
+	if len(infos) == 0 {
+		infos = []*network.NetworkInfo{env.NetworkInfo}
 	}
 
-	log.G(ctx).WithFields(log.Fields{
-		"tap":       env.NetworkInfo.TapName,
-		"guest_mac": guestMAC.String(),
-	}).Debug("generated unique guest MAC address")
-
-	// Attach TAP to VM (QEMU opens by name)
-	if err := vmi.AddTAPNIC(ctx, env.NetworkInfo.TapName, guestMAC); err != nil {
-		if err := nm.ReleaseNetworkResources(ctx, env); err != nil {
-			log.G(ctx).WithError(err).Warn("failed to release network resources")
-		}
-		return nil, fmt.Errorf("add TAP NIC to VM: %w", err)
-	}
-
-	log.G(ctx).WithField("tap", env.NetworkInfo.TapName).Info("TAP device attached to VM")
-
 	dnsServers := resolveHostDNSServers(ctx)
 	if len(dnsServers) == 0 {
 		dnsServers = []string{"8.8.8.8", "8.8.4.4"}
@@ -99,16 +83,103 @@ func (m *linuxManager) Setup(ctx context.Context, nm network.NetworkManager, vmi
 
 	log.G(ctx).WithField("dns", dnsServers).Debug("configured DNS servers")
 
+	var primary *vm.NetworkConfig
+	// Guest MACs are the CNI-reported TAP MACs; NICs sharing one would
+	// collide on any L2 segment they have in common
+	seenMACs := make(map[string]string, len(infos))
+	for i, info := range infos {
+		ifName := cni.IfName(i)
+
+		log.G(ctx).WithFields(log.Fields{
+			"interface": ifName,
+			"tap":       info.TapName,
+			"ip":        ipString(info.IP),
+			"gateway":   ipString(info.Gateway),
+			"netmask":   info.Netmask,
+			"ipv6":      ipString(info.IPv6),
+			"mtu":       info.MTU,
+		}).Info("network resources allocated")
+
+		if info.MAC == "" {
+			release()
+			return nil, fmt.Errorf("CNI did not report TAP MAC address for %s", ifName)
+		}
+
+		guestMAC, err := net.ParseMAC(info.MAC)
+		if err != nil {
+			release()
+			return nil, fmt.Errorf("invalid CNI TAP MAC address %q for %s: %w", info.MAC, ifName, err)
+		}
+		if other, ok := seenMACs[guestMAC.String()]; ok {
+			release()
+			return nil, fmt.Errorf("CNI reported TAP MAC address %s for both %s and %s", guestMAC, other, ifName)
+		}
+		seenMACs[guestMAC.String()] = ifName
+
+		log.G(ctx).WithFields(log.Fields{
+			"tap":       info.TapName,
+			"guest_mac": guestMAC.String(),
+		}).Debug("generated unique guest MAC address")
+
+		netCfg := &vm.NetworkConfig{
+			InterfaceName: ifName,
+			IP:            ipString(info.IP),
+			Netmask:       info.Netmask,
+			MTU:           info.MTU,
+			Routes:        guestRoutes(info.Routes),
+		}
+		if info.IPv6 != nil {
+			netCfg.IPv6 = (&net.IPNet{IP: info.IPv6, Mask: net.CIDRMask(info.IPv6PrefixLen, 128)}).String()
+		}
+		// Only the primary interface carries the default route and DNS
+		if i == 0 {
+			netCfg.Gateway = ipString(info.Gateway)
+			netCfg.IPv6Gateway = ipString(info.IPv6Gateway)
+			netCfg.DNS = dnsServers
+			primary = netCfg
+		}
+
+		// Attach TAP to VM (QEMU opens by name)
+		if err := vmi.AddTAPNIC(ctx, info.TapName, guestMAC); err != nil {
+			release()
+			return nil, fmt.Errorf("add TAP NIC to VM: %w", err)
+		}
+
+		log.G(ctx).WithField("tap", info.TapName).Info("TAP device attached to VM")
+	}
+
 	// Return network configuration for VM kernel
-	return &vm.NetworkConfig{
-		InterfaceName: "eth0",
-		IP:            env.NetworkInfo.IP.String(),
-		Gateway:       env.NetworkInfo.Gateway.String(),
-		Netmask:       env.NetworkInfo.Netmask,
-		DNS:           dnsServers,
-	}, nil
+	return primary, nil
+}
+
+// ipString formats ip, or returns "" for a nil address instead of "<nil>".
+func ipString(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
 }
 
+// guestRoutes converts CNI routes to the VM network configuration format.
+func guestRoutes(routes []network.Route) []vm.Route {
+	if len(routes) == 0 {
+		return nil
+	}
+	out := make([]vm.Route, 0, len(routes))
+	for _, r := range routes {
+		route := vm.Route{Dst: r.Dst.String()}
+		if r.GW != nil {
+			route.Gateway = r.GW.String()
+		}
+		out = append(out, route)
+	}
+	return out
+}
+
+// maxDNSServers is the number of nameservers the guest resolver uses
+// (MAXNS in resolv.h); further entries would be ignored.
+const maxDNSServers = 3
+
 func resolveHostDNSServers(ctx context.Context) []string {
 	path := resolvconf.Path()
 	file, err := resolvconf.GetSpecific(path)
@@ -117,13 +188,7 @@ func resolveHostDNSServers(ctx context.Context) []string {
 		return nil
 	}
 
-	filtered, err := resolvconf.FilterResolvDNS(file.Content, false)
-	if err != nil {
-		log.G(ctx).WithError(err).WithField("path", path).Warn("failed to filter host resolv.conf for DNS")
-		return nil
-	}
-
-	nameservers := resolvconf.GetNameservers(filtered.Content, resolvconf.IPv4)
+	nameservers := dnsServersFromResolvConf(file.Content)
 	if len(nameservers) == 0 {
 		log.G(ctx).WithField("path", path).Warn("no valid DNS servers found in host resolv.conf")
 		return nil
@@ -136,3 +201,22 @@ func resolveHostDNSServers(ctx context.Context) []string {
 
 	return nameservers
 }
+
+// dnsServersFromResolvConf returns the IPv4 nameservers in resolv.conf
+// content that are usable from inside the VM: loopback addresses (such as
+// the systemd-resolved stub 127.0.0.53) are dropped, duplicates are removed
+// keeping first-seen order, and the list is capped at maxDNSServers.
+func dnsServersFromResolvConf(content []byte) []string {
+	var servers []string
+	for _, ns := range resolvconf.GetNameservers(content, resolvconf.IPv4) {
+		ip := net.ParseIP(ns)
+		if ip == nil || ip.IsLoopback() || slices.Contains(servers, ns) {
+			continue
+		}
+		servers = append(servers, ns)
+		if len(servers) == maxDNSServers {
+			break
+		}
+	}
+	return servers
+}
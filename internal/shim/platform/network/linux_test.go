@@ -4,10 +4,18 @@ package network
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
 	"testing"
 
+	"github.com/containerd/errdefs"
+	"github.com/containerd/ttrpc"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/spin-stack/spinbox/internal/host/network"
+	"github.com/spin-stack/spinbox/internal/host/vm"
 )
 
 func TestManager(t *testing.T) {
@@ -34,3 +42,214 @@ func TestResolveHostDNSServers(t *testing.T) {
 		assert.NotEmpty(t, s, "each server should be a valid address")
 	}
 }
+
+func TestDNSServersFromResolvConf(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "keeps order",
+			content: "nameserver 10.0.0.2\nnameserver 1.1.1.1\n",
+			want:    []string{"10.0.0.2", "1.1.1.1"},
+		},
+		{
+			name:    "removes duplicates",
+			content: "nameserver 1.1.1.1\nnameserver 9.9.9.9\nnameserver 1.1.1.1\n",
+			want:    []string{"1.1.1.1", "9.9.9.9"},
+		},
+		{
+			name:    "drops loopback stubs",
+			content: "nameserver 127.0.0.53\nnameserver 10.0.0.2\nnameserver 127.0.0.1\n",
+			want:    []string{"10.0.0.2"},
+		},
+		{
+			name:    "caps at three",
+			content: "nameserver 10.0.0.1\nnameserver 10.0.0.2\nnameserver 10.0.0.2\nnameserver 10.0.0.3\nnameserver 10.0.0.4\n",
+			want:    []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"},
+		},
+		{
+			name:    "skips IPv6",
+			content: "nameserver 2001:4860:4860::8888\nnameserver 8.8.8.8\n",
+			want:    []string{"8.8.8.8"},
+		},
+		{
+			name:    "only systemd-resolved stub",
+			content: "nameserver 127.0.0.53\noptions edns0 trust-ad\n",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, dnsServersFromResolvConf([]byte(tt.content)))
+		})
+	}
+}
+
+// opRecorder collects the operations performed by the fakes, in order.
+type opRecorder struct {
+	ops []string
+}
+
+func (r *opRecorder) add(op string) { r.ops = append(r.ops, op) }
+
+// fakeNetworkManager allocates a fixed TAP and records calls.
+type fakeNetworkManager struct {
+	rec *opRecorder
+	// sameMAC gives every network the primary TAP's MAC
+	sameMAC bool
+}
+
+func (f *fakeNetworkManager) Close() error { return nil }
+
+func (f *fakeNetworkManager) EnsureNetworkResources(ctx context.Context, env *network.Environment) error {
+	f.rec.add("allocate")
+	env.NetworkInfo = &network.NetworkInfo{
+		TapName: "tap0",
+		MAC:     "02:00:00:00:00:01",
+		IP:      net.ParseIP("10.88.0.2"),
+		Netmask: "255.255.0.0",
+		Gateway: net.ParseIP("10.88.0.1"),
+		MTU:     1450,
+
+		IPv6:          net.ParseIP("fd00::2"),
+		IPv6PrefixLen: 64,
+		IPv6Gateway:   net.ParseIP("fd00::1"),
+		Routes: []network.Route{{
+			Dst: net.IPNet{IP: net.IPv4(10, 96, 0, 0).To4(), Mask: net.CIDRMask(12, 32)},
+			GW:  net.ParseIP("10.88.0.254"),
+		}},
+	}
+	env.NetworkInfos = []*network.NetworkInfo{env.NetworkInfo}
+	for i := 1; i < len(env.Networks); i++ {
+		mac := fmt.Sprintf("02:00:00:00:00:%02x", i+1)
+		if f.sameMAC {
+			mac = env.NetworkInfo.MAC
+		}
+		env.NetworkInfos = append(env.NetworkInfos, &network.NetworkInfo{
+			TapName: fmt.Sprintf("tap%d", i),
+			MAC:     mac,
+			IP:      net.ParseIP(fmt.Sprintf("10.%d.0.2", 88+i)),
+			Netmask: "255.255.0.0",
+			Gateway: net.ParseIP(fmt.Sprintf("10.%d.0.1", 88+i)),
+		})
+	}
+	return nil
+}
+
+func (f *fakeNetworkManager) ReleaseNetworkResources(ctx context.Context, env *network.Environment) error {
+	f.rec.add("release")
+	return nil
+}
+
+func (f *fakeNetworkManager) Metrics() *network.Metrics { return &network.Metrics{} }
+
+// fakeVM records NIC and lifecycle operations.
+type fakeVM struct {
+	rec       *opRecorder
+	running   bool
+	attachErr error
+}
+
+func (f *fakeVM) AddDisk(ctx context.Context, blockID, mountPath string, opts ...vm.MountOpt) error {
+	return nil
+}
+
+func (f *fakeVM) AddTAPNIC(ctx context.Context, tapName string, mac net.HardwareAddr) error {
+	if f.running {
+		return errors.New("cannot add NIC after VM started")
+	}
+	f.rec.add("add-nic " + tapName)
+	return f.attachErr
+}
+
+func (f *fakeVM) AddNIC(ctx context.Context, endpoint string, mac net.HardwareAddr, mode vm.NetworkMode, features, flags uint32) error {
+	return nil
+}
+
+func (f *fakeVM) Start(ctx context.Context, opts ...vm.StartOpt) error {
+	f.rec.add("boot")
+	f.running = true
+	return nil
+}
+
+func (f *fakeVM) Shutdown(ctx context.Context) error { return nil }
+
+func (f *fakeVM) Client() (*ttrpc.Client, error) { return nil, errdefs.ErrNotImplemented }
+
+func (f *fakeVM) DialClient(ctx context.Context) (*ttrpc.Client, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (f *fakeVM) StartStream(ctx context.Context) (uint32, net.Conn, error) {
+	return 0, nil, errdefs.ErrNotImplemented
+}
+
+func (f *fakeVM) CPUHotplugger() (vm.CPUHotplugger, error) { return nil, errdefs.ErrNotImplemented }
+
+func (f *fakeVM) VMInfo() vm.VMInfo { return vm.VMInfo{Type: "fake"} }
+
+func TestSetup(t *testing.T) {
+	ctx := context.Background()
+	m := &linuxManager{}
+
+	t.Run("allocates before boot", func(t *testing.T) {
+		rec := &opRecorder{}
+		nm := &fakeNetworkManager{rec: rec}
+		vmi := &fakeVM{rec: rec}
+
+		cfg, err := m.Setup(ctx, nm, vmi, "c1", "/var/run/netns/c1", nil)
+		require.NoError(t, err)
+		require.NoError(t, vmi.Start(ctx, vm.WithNetworkConfig(cfg)))
+
+		assert.Equal(t, []string{"allocate", "add-nic tap0", "boot"}, rec.ops)
+		assert.Equal(t, "10.88.0.2", cfg.IP)
+		assert.Equal(t, 1450, cfg.MTU)
+		assert.Equal(t, "fd00::2/64", cfg.IPv6)
+		assert.Equal(t, "fd00::1", cfg.IPv6Gateway)
+		assert.Equal(t, []vm.Route{{Dst: "10.96.0.0/12", Gateway: "10.88.0.254"}}, cfg.Routes)
+	})
+
+	t.Run("failed attach releases resources", func(t *testing.T) {
+		rec := &opRecorder{}
+		nm := &fakeNetworkManager{rec: rec}
+		vmi := &fakeVM{rec: rec, attachErr: errors.New("netdev_add failed")}
+
+		_, err := m.Setup(ctx, nm, vmi, "c1", "/var/run/netns/c1", nil)
+		require.Error(t, err)
+
+		assert.Equal(t, []string{"allocate", "add-nic tap0", "release"}, rec.ops)
+	})
+}
+
+func TestSetupMultipleNetworks(t *testing.T) {
+	ctx := context.Background()
+	m := &linuxManager{}
+
+	t.Run("attaches one NIC per network", func(t *testing.T) {
+		rec := &opRecorder{}
+		nm := &fakeNetworkManager{rec: rec}
+		vmi := &fakeVM{rec: rec}
+
+		cfg, err := m.Setup(ctx, nm, vmi, "c1", "/var/run/netns/c1", []string{"mgmt", "data"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"allocate", "add-nic tap0", "add-nic tap1"}, rec.ops)
+		assert.Equal(t, "eth0", cfg.InterfaceName)
+		assert.Equal(t, "10.88.0.2", cfg.IP)
+	})
+
+	t.Run("duplicate MACs are rejected", func(t *testing.T) {
+		rec := &opRecorder{}
+		nm := &fakeNetworkManager{rec: rec, sameMAC: true}
+		vmi := &fakeVM{rec: rec}
+
+		_, err := m.Setup(ctx, nm, vmi, "c1", "/var/run/netns/c1", []string{"mgmt", "data"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "eth0 and eth1")
+
+		assert.Equal(t, []string{"allocate", "add-nic tap0", "release"}, rec.ops)
+	})
+}
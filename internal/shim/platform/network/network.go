@@ -14,9 +14,11 @@ type Manager interface {
 	// InitNetworkManager creates and initializes a NetworkManager for the platform.
 	InitNetworkManager(ctx context.Context) (network.NetworkManager, error)
 
-	// Setup configures networking for a VM instance.
-	// Returns the network configuration and an error if setup fails.
-	Setup(ctx context.Context, nm network.NetworkManager, vmi vm.Instance, containerID, netnsPath string) (*vm.NetworkConfig, error)
+	// Setup configures networking for a VM instance that has not started,
+	// attaching one NIC per CNI network in networks (nil for the default).
+	// Returns the primary interface's network configuration and an error
+	// if setup fails.
+	Setup(ctx context.Context, nm network.NetworkManager, vmi vm.Instance, containerID, netnsPath string, networks []string) (*vm.NetworkConfig, error)
 }
 
 // New creates a platform-specific network manager.
@@ -0,0 +1,46 @@
+package network
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// AnnotationNetworks lists the CNI networks to attach to the container's VM,
+// comma-separated, by the "name" field of their conflist. The first network
+// is the guest's eth0 and carries the default route; the rest become eth1,
+// eth2, ... When absent the default (first) conflist is used.
+//
+// Only eth0 is configured inside the guest: its IPv4 address comes from the
+// kernel ip= parameter, and vminitd applies its MTU, extra routes and IPv6
+// address. eth1 and later are attached with the MAC from their CNI result
+// but get no address, MTU or routes; the workload has to configure them.
+const AnnotationNetworks = "io.spin.network.names"
+
+// NetworksFromSpec returns the CNI network names requested by the spec
+// annotations, or nil to use the default network.
+func NetworksFromSpec(spec *specs.Spec) ([]string, error) {
+	if spec == nil || spec.Annotations == nil {
+		return nil, nil
+	}
+	v, ok := spec.Annotations[AnnotationNetworks]
+	if !ok {
+		return nil, nil
+	}
+
+	var networks []string
+	seen := make(map[string]bool)
+	for name := range strings.SplitSeq(v, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("invalid %s annotation %q: empty network name", AnnotationNetworks, v)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("invalid %s annotation %q: network %q listed twice", AnnotationNetworks, v, name)
+		}
+		seen[name] = true
+		networks = append(networks, name)
+	}
+	return networks, nil
+}
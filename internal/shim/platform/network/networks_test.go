@@ -0,0 +1,53 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworksFromSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    *specs.Spec
+		want    []string
+		wantErr bool
+	}{
+		{name: "nil spec", spec: nil, want: nil},
+		{name: "no annotation", spec: &specs.Spec{}, want: nil},
+		{
+			name: "single network",
+			spec: &specs.Spec{Annotations: map[string]string{AnnotationNetworks: "mgmt"}},
+			want: []string{"mgmt"},
+		},
+		{
+			name: "multiple networks keep order",
+			spec: &specs.Spec{Annotations: map[string]string{AnnotationNetworks: "data, mgmt"}},
+			want: []string{"data", "mgmt"},
+		},
+		{
+			name:    "empty entry",
+			spec:    &specs.Spec{Annotations: map[string]string{AnnotationNetworks: "mgmt,,data"}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate",
+			spec:    &specs.Spec{Annotations: map[string]string{AnnotationNetworks: "mgmt,mgmt"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NetworksFromSpec(tt.spec)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -81,6 +81,11 @@ func ComputeConfig(ctx context.Context, spec *specs.Spec) (*vm.VMResourceConfig,
 		memoryHotplugSize = alignMemory(memoryHotplugSize, virtioMemAlignment)
 	}
 
+	// Requests above the host capacity boot as requested; never let the
+	// hotplug ceiling fall below the boot size
+	maxCPUs = max(maxCPUs, cpuRequest)
+	memoryHotplugSize = max(memoryHotplugSize, memoryRequest)
+
 	resourceCfg := &vm.VMResourceConfig{
 		BootCPUs:          cpuRequest,
 		MaxCPUs:           maxCPUs,
@@ -2,7 +2,12 @@
 
 package resources
 
-import "testing"
+import (
+	"context"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
 
 func TestParseCPUSet(t *testing.T) {
 	tests := []struct {
@@ -99,3 +104,21 @@ func TestParseCPUSet(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeConfigIsValid(t *testing.T) {
+	quota := int64(1 << 20 * 100000) // far more CPUs than any host
+	period := uint64(100000)
+	limit := int64(1 << 50) // far more memory than any host
+
+	spec := &specs.Spec{Linux: &specs.Linux{Resources: &specs.LinuxResources{
+		CPU:    &specs.LinuxCPU{Quota: &quota, Period: &period},
+		Memory: &specs.LinuxMemory{Limit: &limit},
+	}}}
+
+	for name, s := range map[string]*specs.Spec{"defaults": {}, "above host": spec} {
+		cfg, _ := ComputeConfig(context.Background(), s)
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("%s: ComputeConfig returned invalid config: %v", name, err)
+		}
+	}
+}
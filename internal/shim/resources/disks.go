@@ -0,0 +1,32 @@
+package resources
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/spin-stack/spinbox/internal/host/vm"
+)
+
+// AnnotationBlockHotplugPorts sets how many data disks can be hotplugged
+// into the running VM, from 0 (the default) to vm.MaxBlockHotplugPorts.
+// Each one reserves a PCIe root port at boot.
+const AnnotationBlockHotplugPorts = "io.spin.disk.hotplug-ports"
+
+// BlockHotplugPorts returns the number of block hotplug ports requested by
+// the spec annotations.
+func BlockHotplugPorts(spec *specs.Spec) (int, error) {
+	if spec == nil || spec.Annotations == nil {
+		return 0, nil
+	}
+	value, ok := spec.Annotations[AnnotationBlockHotplugPorts]
+	if !ok || value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 || n > vm.MaxBlockHotplugPorts {
+		return 0, fmt.Errorf("invalid %s annotation %q: expected a number between 0 and %d", AnnotationBlockHotplugPorts, value, vm.MaxBlockHotplugPorts)
+	}
+	return n, nil
+}
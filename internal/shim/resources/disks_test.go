@@ -0,0 +1,36 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func TestBlockHotplugPorts(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    int
+		expectErr   bool
+	}{
+		{name: "no annotations reserves none"},
+		{name: "empty value", annotations: map[string]string{AnnotationBlockHotplugPorts: ""}},
+		{name: "explicit count", annotations: map[string]string{AnnotationBlockHotplugPorts: "4"}, expected: 4},
+		{name: "maximum", annotations: map[string]string{AnnotationBlockHotplugPorts: "16"}, expected: 16},
+		{name: "over maximum", annotations: map[string]string{AnnotationBlockHotplugPorts: "17"}, expectErr: true},
+		{name: "negative", annotations: map[string]string{AnnotationBlockHotplugPorts: "-1"}, expectErr: true},
+		{name: "not a number", annotations: map[string]string{AnnotationBlockHotplugPorts: "four"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := BlockHotplugPorts(&specs.Spec{Annotations: tt.annotations})
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("BlockHotplugPorts() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if n != tt.expected {
+				t.Errorf("BlockHotplugPorts() = %d, want %d", n, tt.expected)
+			}
+		})
+	}
+}
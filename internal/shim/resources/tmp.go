@@ -0,0 +1,44 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// AnnotationTmpBacking selects how /tmp is backed inside the VM.
+//
+// Accepted values:
+//   - "tmpfs" (default): /tmp is a tmpfs and competes with the workload for memory
+//   - "disk:<device>[:<fstype>]": /tmp is the filesystem on a guest data disk
+//     (e.g., "disk:/dev/vdb" or "disk:/dev/vdb:xfs"; fstype defaults to ext4)
+const AnnotationTmpBacking = "io.spin.tmp.backing"
+
+// paramTmp is the kernel cmdline parameter read by the guest init
+// (must match guest vminit/system package).
+const paramTmp = "spin.tmp"
+
+// TmpInitArgs returns the kernel cmdline arguments selecting the /tmp backing
+// requested by the spec annotations. Returns nil for the default tmpfs backing.
+func TmpInitArgs(spec *specs.Spec) ([]string, error) {
+	if spec == nil || spec.Annotations == nil {
+		return nil, nil
+	}
+
+	value, ok := spec.Annotations[AnnotationTmpBacking]
+	if !ok || value == "" || value == "tmpfs" {
+		return nil, nil
+	}
+
+	rest, ok := strings.CutPrefix(value, "disk:")
+	if !ok {
+		return nil, fmt.Errorf("invalid %s annotation %q: expected tmpfs or disk:<device>", AnnotationTmpBacking, value)
+	}
+	device, _, _ := strings.Cut(rest, ":")
+	if !strings.HasPrefix(device, "/dev/") || strings.ContainsAny(value, " \t\n") {
+		return nil, fmt.Errorf("invalid %s annotation %q: disk backing requires a /dev/ device", AnnotationTmpBacking, value)
+	}
+
+	return []string{fmt.Sprintf("%s=%s", paramTmp, value)}, nil
+}
@@ -0,0 +1,71 @@
+//go:build linux
+
+package resources
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func TestTmpInitArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    []string
+		expectErr   bool
+	}{
+		{
+			name:        "no annotations defaults to tmpfs",
+			annotations: nil,
+		},
+		{
+			name:        "explicit tmpfs",
+			annotations: map[string]string{AnnotationTmpBacking: "tmpfs"},
+		},
+		{
+			name:        "disk backing",
+			annotations: map[string]string{AnnotationTmpBacking: "disk:/dev/vdb"},
+			expected:    []string{"spin.tmp=disk:/dev/vdb"},
+		},
+		{
+			name:        "disk backing with fstype",
+			annotations: map[string]string{AnnotationTmpBacking: "disk:/dev/vdc:xfs"},
+			expected:    []string{"spin.tmp=disk:/dev/vdc:xfs"},
+		},
+		{
+			name:        "disk without device",
+			annotations: map[string]string{AnnotationTmpBacking: "disk:"},
+			expectErr:   true,
+		},
+		{
+			name:        "unknown backing",
+			annotations: map[string]string{AnnotationTmpBacking: "ramdisk"},
+			expectErr:   true,
+		},
+		{
+			name:        "whitespace would split cmdline",
+			annotations: map[string]string{AnnotationTmpBacking: "disk:/dev/vdb init=/bin/sh"},
+			expectErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := TmpInitArgs(&specs.Spec{Annotations: tt.annotations})
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("TmpInitArgs() expected error, got args %v", args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TmpInitArgs() unexpected error: %v", err)
+			}
+			if !slices.Equal(args, tt.expected) {
+				t.Errorf("TmpInitArgs() = %v, want %v", args, tt.expected)
+			}
+		})
+	}
+}
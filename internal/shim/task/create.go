@@ -4,6 +4,7 @@
 package task
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"strings"
@@ -18,10 +19,12 @@ import (
 	"github.com/containerd/log"
 
 	bundleAPI "github.com/spin-stack/spinbox/api/services/bundle/v1"
+	"github.com/spin-stack/spinbox/internal/config"
 	"github.com/spin-stack/spinbox/internal/host/network"
 	"github.com/spin-stack/spinbox/internal/host/vm"
 	"github.com/spin-stack/spinbox/internal/shim/bundle"
 	"github.com/spin-stack/spinbox/internal/shim/lifecycle"
+	platformNetwork "github.com/spin-stack/spinbox/internal/shim/platform/network"
 	"github.com/spin-stack/spinbox/internal/shim/resources"
 	"github.com/spin-stack/spinbox/internal/shim/supervisor"
 	"github.com/spin-stack/spinbox/internal/shim/transform"
@@ -68,6 +71,8 @@ type createState struct {
 	guestIO       stdio.Stdio
 	cleanup       createCleanup
 	supervisorCfg *supervisor.Config
+	tmpInitArgs   []string
+	networks      []string
 }
 
 // validateCreateRequest performs all pre-creation validation.
@@ -118,8 +123,16 @@ func (s *service) setupVMInstance(ctx context.Context, state *createState) error
 		return err
 	}
 
+	cfg, err := config.Get()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
 	// Load and transform bundle
-	b, err := transform.LoadForCreate(ctx, r.Bundle)
+	b, err := transform.LoadForCreate(ctx, r.Bundle, transform.PrivilegePolicy{
+		Mode:         transform.PrivilegeMode(cfg.Security.PrivilegedPolicy),
+		Capabilities: cfg.Security.PrivilegedCapabilities,
+	}, cfg.Security.MaxMounts)
 	if err != nil {
 		return err
 	}
@@ -127,6 +140,9 @@ func (s *service) setupVMInstance(ctx context.Context, state *createState) error
 
 	// Compute resource configuration
 	resourceCfg, _ := resources.ComputeConfig(ctx, &b.Spec)
+	if resourceCfg.BlockHotplugPorts, err = resources.BlockHotplugPorts(&b.Spec); err != nil {
+		return err
+	}
 	state.resourceCfg = resourceCfg
 
 	log.G(ctx).WithFields(log.Fields{
@@ -134,6 +150,7 @@ func (s *service) setupVMInstance(ctx context.Context, state *createState) error
 		"max_cpus":   resourceCfg.MaxCPUs,
 		"memory_mb":  resourceCfg.MemorySize / (1024 * 1024),
 		"hotplug_mb": resourceCfg.MemoryHotplugSize / (1024 * 1024),
+		"disk_ports": resourceCfg.BlockHotplugPorts,
 	}).Debug("VM resource configuration")
 
 	// Extract supervisor configuration from annotations
@@ -155,6 +172,19 @@ func (s *service) setupVMInstance(ctx context.Context, state *createState) error
 		}
 	}
 
+	// Validate /tmp backing before creating the VM
+	tmpInitArgs, err := resources.TmpInitArgs(&b.Spec)
+	if err != nil {
+		return err
+	}
+	state.tmpInitArgs = tmpInitArgs
+
+	networks, err := platformNetwork.NetworksFromSpec(&b.Spec)
+	if err != nil {
+		return err
+	}
+	state.networks = networks
+
 	// Create VM instance
 	vmi, err := s.vmLifecycle.CreateVM(ctx, r.ID, r.Bundle, resourceCfg)
 	if err != nil {
@@ -180,7 +210,7 @@ func (s *service) setupVMInstance(ctx context.Context, state *createState) error
 
 	// Setup networking
 	state.netnsPath = "/var/run/netns/" + r.ID
-	netCfg, err := s.platformNetwork.Setup(ctx, s.networkManager, vmi, r.ID, state.netnsPath)
+	netCfg, err := s.platformNetwork.Setup(ctx, s.networkManager, vmi, r.ID, state.netnsPath, state.networks)
 	if err != nil {
 		return err
 	}
@@ -188,7 +218,7 @@ func (s *service) setupVMInstance(ctx context.Context, state *createState) error
 
 	// Register network cleanup
 	state.cleanup.add("network", func(ctx context.Context) error {
-		env := &network.Environment{ID: r.ID}
+		env := &network.Environment{ID: r.ID, Networks: networks}
 		return s.networkManager.ReleaseNetworkResources(ctx, env)
 	})
 
@@ -197,9 +227,15 @@ func (s *service) setupVMInstance(ctx context.Context, state *createState) error
 
 // startVM boots the VM and establishes the event stream connection.
 func (s *service) startVM(ctx context.Context, state *createState) error {
+	cfg, err := config.Get()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
 	startOpts := []vm.StartOpt{
 		vm.WithNetworkConfig(state.netConfig),
 		vm.WithNetworkNamespace(state.netnsPath),
+		vm.WithBootTimeout(cfg.Timeouts.Duration("vm_start")),
 	}
 
 	// Add supervisor init args if supervisor is configured
@@ -208,6 +244,12 @@ func (s *service) startVM(ctx context.Context, state *createState) error {
 		log.G(ctx).WithField("init_args", state.supervisorCfg.InitArgs()).Debug("adding supervisor init args to kernel cmdline")
 	}
 
+	// Select /tmp backing in the guest (tmpfs unless a data disk is requested)
+	if len(state.tmpInitArgs) > 0 {
+		startOpts = append(startOpts, vm.WithInitArgs(state.tmpInitArgs...))
+		log.G(ctx).WithField("init_args", state.tmpInitArgs).Debug("adding /tmp backing init args to kernel cmdline")
+	}
+
 	prestart := time.Now()
 	if err := state.vmInstance.Start(ctx, startOpts...); err != nil {
 		return err
@@ -239,11 +281,17 @@ func (s *service) createTaskInVM(ctx context.Context, state *createState) (*task
 
 	// Inject supervisor binary into bundle if configured
 	if state.supervisorCfg != nil && len(state.supervisorCfg.BinaryContent) > 0 {
+		// The supervisor binary is host-provided and may exceed the limits
+		// meant for container bind mounts, so make room for it, but only
+		// as far as the whole bundle still fits in one transfer.
+		size := len(state.supervisorCfg.BinaryContent)
+		state.bundle.MaxExtraFileSize = min(max(cmp.Or(state.bundle.MaxExtraFileSize, bundle.DefaultMaxExtraFileSize), size), bundle.MaxTransferExtraFilesSize)
+		state.bundle.MaxExtraFilesSize = min(cmp.Or(state.bundle.MaxExtraFilesSize, bundle.DefaultMaxExtraFilesSize)+size, bundle.MaxTransferExtraFilesSize)
 		if err := state.bundle.AddExtraFile(supervisor.BundleFileName, state.supervisorCfg.BinaryContent); err != nil {
 			log.G(ctx).WithError(err).Error("failed to add supervisor binary to bundle")
 			return nil, err
 		}
-		log.G(ctx).WithField("size", len(state.supervisorCfg.BinaryContent)).Debug("added supervisor binary to bundle")
+		log.G(ctx).WithField("size", size).Debug("added supervisor binary to bundle")
 	}
 
 	// Dial TTRPC client for Create RPCs.
@@ -255,11 +303,15 @@ func (s *service) createTaskInVM(ctx context.Context, state *createState) (*task
 		return nil, err
 	}
 
-	// Create bundle in VM
+	// Create bundle in VM. Emit a readable config.json when debug logging is on.
+	state.bundle.IndentSpec = log.GetLevel() >= log.DebugLevel
 	bundleFiles, err := state.bundle.Files()
 	if err != nil {
 		return nil, err
 	}
+	if log.GetLevel() >= log.DebugLevel {
+		log.G(ctx).Debugf("creating bundle in VM:\n%s", state.bundle.Describe())
+	}
 
 	bundleService := bundleAPI.NewTTRPCBundleClient(rpcClient)
 	br, err := bundleService.Create(ctx, &bundleAPI.CreateRequest{
@@ -335,6 +387,7 @@ func (s *service) finalizeCreate(ctx context.Context, state *createState, resp *
 			exec: make(map[string]processIOState),
 		},
 		mountCleanup: state.mountCleanup,
+		networks:     state.networks,
 	}
 
 	s.containerMu.Lock()
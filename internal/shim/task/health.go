@@ -0,0 +1,101 @@
+//go:build linux
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	healthAPI "github.com/spin-stack/spinbox/api/services/health/v1"
+	systemAPI "github.com/spin-stack/spinbox/api/services/system/v1"
+	"github.com/spin-stack/spinbox/internal/host/vm"
+)
+
+// healthPingTimeout bounds the vminitd ping of a health check. A guest that
+// cannot answer a System.Info call in this time is reported unresponsive.
+const healthPingTimeout = 2 * time.Second
+
+// healthService implements the Health TTRPC service on the shim's server.
+type healthService struct {
+	// instance returns the VM, or an error if none has been created
+	instance func() (vm.Instance, error)
+	// eventStreamUp reports whether the guest event stream is connected
+	eventStreamUp func() bool
+	// lastEvent returns when the last guest event was received (zero if none)
+	lastEvent func() time.Time
+	// timeout bounds the guest ping (zero means healthPingTimeout)
+	timeout time.Duration
+}
+
+var _ healthAPI.TTRPCHealthService = &healthService{}
+
+func (s *service) healthService() *healthService {
+	return &healthService{
+		instance:      s.vmLifecycle.Instance,
+		eventStreamUp: s.eventStreamUp.Load,
+		lastEvent: func() time.Time {
+			if ns := s.lastEventNanos.Load(); ns != 0 {
+				return time.Unix(0, ns)
+			}
+			return time.Time{}
+		},
+	}
+}
+
+// Check reports the health of the VM and vminitd. Failures of the VM or the
+// guest are reported in the response, not as an RPC error.
+func (h *healthService) Check(ctx context.Context, _ *emptypb.Empty) (*healthAPI.CheckResponse, error) {
+	resp := &healthAPI.CheckResponse{Status: healthAPI.Status_UNRESPONSIVE}
+	if t := h.lastEvent(); !t.IsZero() {
+		resp.LastEvent = timestamppb.New(t)
+	}
+
+	inst, err := h.instance()
+	if err == nil {
+		// Client fails unless the VM is running (e.g. paused or shut down)
+		_, err = inst.Client()
+	}
+	if err != nil {
+		resp.Reason = err.Error()
+		return resp, nil
+	}
+	resp.VmRunning = true
+
+	timeout := h.timeout
+	if timeout == 0 {
+		timeout = healthPingTimeout
+	}
+	if err := pingGuest(ctx, inst, timeout); err != nil {
+		resp.Reason = fmt.Sprintf("vminitd did not answer: %v", err)
+		return resp, nil
+	}
+	resp.VsockConnected = true
+
+	if !h.eventStreamUp() {
+		resp.Status = healthAPI.Status_DEGRADED
+		resp.Reason = "guest event stream is disconnected"
+		return resp, nil
+	}
+	resp.Status = healthAPI.Status_HEALTHY
+	return resp, nil
+}
+
+// pingGuest calls System.Info on a dedicated connection, so a wedged guest
+// cannot block the connections used by task RPCs and the event stream.
+func pingGuest(ctx context.Context, inst vm.Instance, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	vmc, err := inst.DialClient(ctx)
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	defer vmc.Close()
+
+	_, err = systemAPI.NewTTRPCSystemClient(vmc).Info(ctx, &emptypb.Empty{})
+	return err
+}
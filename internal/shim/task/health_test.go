@@ -0,0 +1,182 @@
+//go:build linux
+
+package task
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/ttrpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	healthAPI "github.com/spin-stack/spinbox/api/services/health/v1"
+	systemAPI "github.com/spin-stack/spinbox/api/services/system/v1"
+	"github.com/spin-stack/spinbox/internal/host/vm"
+)
+
+// fakeGuest is a vminitd System service whose Info answers immediately or
+// blocks until the call is cancelled.
+type fakeGuest struct {
+	systemAPI.TTRPCSystemService
+	hang bool
+}
+
+func (g *fakeGuest) Info(ctx context.Context, _ *emptypb.Empty) (*systemAPI.InfoResponse, error) {
+	if g.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &systemAPI.InfoResponse{Version: "test"}, nil
+}
+
+// serveGuest serves g on a unix socket and returns a dial function for it.
+func serveGuest(t *testing.T, g *fakeGuest) func(context.Context) (*ttrpc.Client, error) {
+	t.Helper()
+	srv, err := ttrpc.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	systemAPI.RegisterTTRPCSystemService(srv, g)
+
+	l, err := net.Listen("unix", filepath.Join(t.TempDir(), "guest.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = srv.Serve(context.Background(), l) }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	return func(ctx context.Context) (*ttrpc.Client, error) {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "unix", l.Addr().String())
+		if err != nil {
+			return nil, err
+		}
+		return ttrpc.NewClient(conn), nil
+	}
+}
+
+// healthVMInstance is a running VM whose guest connections come from dial.
+type healthVMInstance struct {
+	vm.Instance
+	clientErr error
+	dial      func(context.Context) (*ttrpc.Client, error)
+}
+
+func (h *healthVMInstance) Client() (*ttrpc.Client, error) {
+	return nil, h.clientErr
+}
+
+func (h *healthVMInstance) DialClient(ctx context.Context) (*ttrpc.Client, error) {
+	return h.dial(ctx)
+}
+
+func TestHealthCheck(t *testing.T) {
+	lastEvent := time.Unix(1700000000, 0)
+
+	closedConn := func(context.Context) (*ttrpc.Client, error) {
+		local, remote := net.Pipe()
+		_ = remote.Close()
+		return ttrpc.NewClient(local), nil
+	}
+
+	for _, tc := range []struct {
+		name          string
+		inst          vm.Instance
+		instErr       error
+		streamDown    bool
+		want          healthAPI.Status
+		wantVMRunning bool
+		wantVsock     bool
+		wantReason    string
+	}{
+		{
+			name:          "guest responds",
+			inst:          &healthVMInstance{dial: serveGuest(t, &fakeGuest{})},
+			want:          healthAPI.Status_HEALTHY,
+			wantVMRunning: true,
+			wantVsock:     true,
+		},
+		{
+			name:          "guest responds with event stream down",
+			inst:          &healthVMInstance{dial: serveGuest(t, &fakeGuest{})},
+			streamDown:    true,
+			want:          healthAPI.Status_DEGRADED,
+			wantVMRunning: true,
+			wantVsock:     true,
+			wantReason:    "event stream",
+		},
+		{
+			name:          "guest times out",
+			inst:          &healthVMInstance{dial: serveGuest(t, &fakeGuest{hang: true})},
+			want:          healthAPI.Status_UNRESPONSIVE,
+			wantVMRunning: true,
+			wantReason:    "deadline exceeded",
+		},
+		{
+			name:          "guest connection closed",
+			inst:          &healthVMInstance{dial: closedConn},
+			want:          healthAPI.Status_UNRESPONSIVE,
+			wantVMRunning: true,
+			wantReason:    "vminitd did not answer",
+		},
+		{
+			name:       "vm not running",
+			inst:       &healthVMInstance{clientErr: errdefs.ErrFailedPrecondition},
+			want:       healthAPI.Status_UNRESPONSIVE,
+			wantReason: "failed precondition",
+		},
+		{
+			name:       "no vm",
+			instErr:    errdefs.ErrFailedPrecondition,
+			want:       healthAPI.Status_UNRESPONSIVE,
+			wantReason: "failed precondition",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := &healthService{
+				instance:      func() (vm.Instance, error) { return tc.inst, tc.instErr },
+				eventStreamUp: func() bool { return !tc.streamDown },
+				lastEvent:     func() time.Time { return lastEvent },
+				timeout:       100 * time.Millisecond,
+			}
+
+			resp, err := h.Check(context.Background(), &emptypb.Empty{})
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if resp.GetStatus() != tc.want {
+				t.Fatalf("status = %v (%s), want %v", resp.GetStatus(), resp.GetReason(), tc.want)
+			}
+			if resp.GetVmRunning() != tc.wantVMRunning || resp.GetVsockConnected() != tc.wantVsock {
+				t.Fatalf("vm_running = %v, vsock_connected = %v, want %v, %v",
+					resp.GetVmRunning(), resp.GetVsockConnected(), tc.wantVMRunning, tc.wantVsock)
+			}
+			if !strings.Contains(resp.GetReason(), tc.wantReason) {
+				t.Fatalf("reason = %q, want it to contain %q", resp.GetReason(), tc.wantReason)
+			}
+			if !resp.GetLastEvent().AsTime().Equal(lastEvent) {
+				t.Fatalf("last_event = %v, want %v", resp.GetLastEvent().AsTime(), lastEvent)
+			}
+		})
+	}
+
+	t.Run("no events received", func(t *testing.T) {
+		h := &healthService{
+			instance:      func() (vm.Instance, error) { return nil, errdefs.ErrFailedPrecondition },
+			eventStreamUp: func() bool { return false },
+			lastEvent:     func() time.Time { return time.Time{} },
+		}
+		resp, err := h.Check(context.Background(), &emptypb.Empty{})
+		if err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+		if resp.GetLastEvent() != nil {
+			t.Fatalf("last_event = %v, want unset", resp.GetLastEvent())
+		}
+	})
+}
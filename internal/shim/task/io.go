@@ -83,6 +83,10 @@ type IOForwarder interface {
 	CloseStdin()
 	// WaitForComplete blocks until I/O is complete (without shutting down).
 	WaitForComplete()
+	// Drain waits until the guest has closed the output streams and all
+	// output has been copied to the host, or ctx is done. Unlike Shutdown it
+	// does not close the streams, so no output is cut off.
+	Drain(ctx context.Context) error
 }
 
 // noopForwarder is a no-op IOForwarder for passthrough or null I/O modes.
@@ -96,11 +100,14 @@ func (n *noopForwarder) Start(context.Context) error    { return nil }
 func (n *noopForwarder) Shutdown(context.Context) error { return nil }
 func (n *noopForwarder) CloseStdin()                    {}
 func (n *noopForwarder) WaitForComplete()               {}
+func (n *noopForwarder) Drain(context.Context) error    { return nil }
 
 type directForwarder struct {
 	guest     stdio.Stdio
 	shutdown  func(context.Context) error
 	keepalive fifoKeepalive
+	// done is closed once every output stream reached EOF and was copied
+	done <-chan struct{}
 }
 
 func (d *directForwarder) GuestStdio() stdio.Stdio {
@@ -123,6 +130,18 @@ func (d *directForwarder) Shutdown(ctx context.Context) error {
 
 func (d *directForwarder) CloseStdin() {}
 
+func (d *directForwarder) Drain(ctx context.Context) error {
+	if d.done == nil {
+		return nil
+	}
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (d *directForwarder) WaitForComplete() {
 	// Direct forwarder uses synchronous io.Copy goroutines.
 	// WaitForComplete is a no-op since the shutdown function handles waiting.
@@ -293,6 +312,7 @@ func (s *service) forwardIOWithIDs(ctx context.Context, vmi vm.Instance, contain
 		guest:     pio,
 		shutdown:  shutdown,
 		keepalive: keepalives,
+		done:      ioDone,
 	}, nil
 }
 
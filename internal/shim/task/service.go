@@ -52,13 +52,15 @@
 //
 // Shutdown Sequence:
 //  1. shutdown() called (via Delete or process exit)
-//  2. Lock containerMu and controllerMu
-//  3. Stop all hotplug controllers (graceful stop)
-//  4. Close all I/O streams (exec processes, then container)
-//  5. Shutdown VM (sends SIGTERM to QEMU, waits for exit)
-//  6. Release network resources (CNI teardown)
-//  7. Close network manager
-//  8. Close events channel (signals forwarder to exit)
+//  2. Drain I/O: wait for final output to reach the host (bounded by the
+//     I/O wait timeout); new exec requests are rejected from here on
+//  3. Lock containerMu and controllerMu
+//  4. Stop all hotplug controllers (graceful stop)
+//  5. Close all I/O streams (exec processes, then container)
+//  6. Shutdown VM (sends SIGTERM to QEMU, waits for exit)
+//  7. Release network resources (CNI teardown)
+//  8. Close network manager
+//  9. Close events channel (signals forwarder to exit)
 //
 // Concurrency Invariants:
 //   - Only one container per service (enforced in Create)
@@ -69,6 +71,7 @@
 package task
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -95,6 +98,7 @@ import (
 	"github.com/containerd/ttrpc"
 	"github.com/containerd/typeurl/v2"
 
+	healthAPI "github.com/spin-stack/spinbox/api/services/health/v1"
 	"github.com/spin-stack/spinbox/api/services/vmevents/v1"
 	"github.com/spin-stack/spinbox/internal/host/network"
 	"github.com/spin-stack/spinbox/internal/shim/cpuhotplug"
@@ -144,6 +148,16 @@ const (
 	// This smooths over transient vsock routing issues (e.g., CID reuse).
 	taskClientRetryTimeout = 1 * time.Second
 
+	// defaultIOWaitTimeout bounds how long a TaskExit is held back while the
+	// I/O forwarder drains. Override with ioWaitTimeoutEnv.
+	defaultIOWaitTimeout = 30 * time.Second
+	// minIOWaitTimeout is the smallest accepted override. Shorter waits would
+	// routinely forward exits before the process output has been copied.
+	minIOWaitTimeout = 1 * time.Second
+	// ioWaitTimeoutEnv overrides defaultIOWaitTimeout, for deployments where
+	// guest output crosses a slow link (e.g., vsock bridged to a remote host).
+	ioWaitTimeoutEnv = "SPINBOX_HOST_IO_TIMEOUT"
+
 	defaultNamespace = "default"
 )
 
@@ -173,6 +187,7 @@ func NewTaskService(ctx context.Context, publisher shim.Publisher, sd shutdown.S
 		initiateShutdown:         sd.Shutdown,
 		shutdownSvc:              sd,
 		connManager:              NewConnectionManager(vmLM.DialClient, vmLM.DialClientWithRetry),
+		ioWaitTimeout:            ioWaitTimeoutFromEnv(ctx),
 	}
 	sd.RegisterCallback(s.shutdown)
 
@@ -207,6 +222,10 @@ type container struct {
 	io *taskIO
 	// mountCleanup releases host-side mount manager state.
 	mountCleanup func(context.Context) error
+	// networks are the CNI networks the VM is attached to (nil for the
+	// default network), passed on release so secondary networks are torn
+	// down even when the network manager has no cached result.
+	networks []string
 }
 
 type execIO struct {
@@ -272,10 +291,19 @@ type service struct {
 
 	initStarted atomic.Bool // True once the init process has been started
 	connManager *ConnectionManager
+
+	// Guest event stream state, reported by the Health service
+	eventStreamUp  atomic.Bool  // True while the guest event stream is connected
+	lastEventNanos atomic.Int64 // Unix time of the last guest event (0 if none)
+
+	// ioWaitTimeout bounds the I/O drain before a TaskExit is forwarded
+	// (zero means defaultIOWaitTimeout)
+	ioWaitTimeout time.Duration
 }
 
 func (s *service) RegisterTTRPC(server *ttrpc.Server) error {
 	taskAPI.RegisterTTRPCTaskService(server, s)
+	healthAPI.RegisterTTRPCHealthService(server, s.healthService())
 	return nil
 }
 
@@ -283,6 +311,12 @@ func (s *service) shutdown(ctx context.Context) error {
 	// Transition to ShuttingDown state
 	s.stateMachine.ForceTransition(lifecycle.StateShuttingDown)
 
+	// Let final output reach the host before the streams are closed and the
+	// VM is stopped
+	if err := s.Drain(ctx); err != nil {
+		log.G(ctx).WithError(err).Warn("proceeding with shutdown before all I/O was drained")
+	}
+
 	// Get container ID before cleanup (briefly hold lock)
 	s.containerMu.Lock()
 	containerID := s.containerID
@@ -346,6 +380,43 @@ func (s *service) stopAllHotplugControllers(_ context.Context) error {
 	return nil
 }
 
+// Drain stops accepting new exec processes and waits for the output of every
+// tracked process to be copied to the host, bounded by the I/O wait timeout.
+// It does not close any stream; shutdownAllIO does that afterwards.
+func (s *service) Drain(ctx context.Context) error {
+	s.stateMachine.ForceTransition(lifecycle.StateShuttingDown)
+
+	s.containerMu.Lock()
+	var forwarders []IOForwarder
+	if s.container != nil && s.container.io != nil {
+		forwarders = append(forwarders, s.container.io.init.forwarder)
+		for _, pio := range s.container.io.exec {
+			forwarders = append(forwarders, pio.forwarder)
+		}
+	}
+	s.containerMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, cmp.Or(s.ioWaitTimeout, defaultIOWaitTimeout))
+	defer cancel()
+
+	errs := make(chan error, len(forwarders))
+	for _, f := range forwarders {
+		go func() {
+			errs <- f.Drain(ctx)
+		}()
+	}
+	var pending int
+	for range forwarders {
+		if err := <-errs; err != nil {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("%d of %d I/O streams not drained: %w", pending, len(forwarders), ctx.Err())
+	}
+	return nil
+}
+
 // shutdownAllIO shuts down all I/O forwarders for the container and execs.
 // It collects forwarders under lock and shuts them down outside the lock.
 func (s *service) shutdownAllIO(ctx context.Context) error {
@@ -384,6 +455,18 @@ func (s *service) extractMountCleanup() func(context.Context) error {
 	return cleanup
 }
 
+// containerNetworks returns the CNI networks of the container with the given
+// ID, or nil if it is not the current container.
+func (s *service) containerNetworks(containerID string) []string {
+	s.containerMu.Lock()
+	defer s.containerMu.Unlock()
+
+	if s.container == nil || s.containerID != containerID {
+		return nil
+	}
+	return s.container.networks
+}
+
 // closeEvents safely closes the events channel.
 // This signals the event forwarder goroutine to exit.
 func (s *service) closeEvents() error {
@@ -398,6 +481,7 @@ func (s *service) closeEvents() error {
 // The containerID is used for network cleanup.
 func (s *service) buildCleanupPhases(containerID string) lifecycle.CleanupPhases {
 	mountCleanup := s.extractMountCleanup()
+	networks := s.containerNetworks(containerID)
 
 	return lifecycle.CleanupPhases{
 		HotplugStop: s.stopAllHotplugControllers,
@@ -412,7 +496,7 @@ func (s *service) buildCleanupPhases(containerID string) lifecycle.CleanupPhases
 			if containerID == "" {
 				return nil
 			}
-			env := &network.Environment{ID: containerID}
+			env := &network.Environment{ID: containerID, Networks: networks}
 			return s.networkManager.ReleaseNetworkResources(ctx, env)
 		},
 		MountCleanup: func(ctx context.Context) error {
@@ -449,14 +533,19 @@ func (s *service) getTaskClient(ctx context.Context) (*ttrpc.Client, func(), err
 
 func (s *service) startEventForwarder(ctx context.Context, vmc *ttrpc.Client) error {
 	currentClient := vmc
-	sc, err := vmevents.NewTTRPCEventsClient(currentClient).Stream(ctx, &ptypes.Empty{})
+	sc, err := vmevents.NewTTRPCEventsClient(currentClient).Stream(ctx, &vmevents.StreamRequest{})
 	if err != nil {
 		return err
 	}
+	s.eventStreamUp.Store(true)
 	go func() {
+		// lastSeq is the sequence number of the last event received, so a
+		// reconnected stream resumes after it instead of losing events
+		var lastSeq uint64
 		for {
-			ev, err := sc.Recv()
+			sev, err := sc.Recv()
 			if err != nil {
+				s.eventStreamUp.Store(false)
 				// Check intentional shutdown first to avoid spurious warnings during normal shutdown
 				if s.stateMachine.IsIntentionalShutdown() {
 					log.G(ctx).Debug("vm event stream closed (intentional shutdown)")
@@ -474,10 +563,11 @@ func (s *service) startEventForwarder(ctx context.Context, vmc *ttrpc.Client) er
 					log.G(ctx).WithError(err).Info("vm event stream closed unexpectedly, attempting reconnect")
 
 					// Try to reconnect
-					newClient, newStream, reconnected := s.reconnectEventStream(ctx, currentClient)
+					newClient, newStream, reconnected := s.reconnectEventStream(ctx, currentClient, lastSeq)
 					if reconnected {
 						currentClient = newClient
 						sc = newStream
+						s.eventStreamUp.Store(true)
 						log.G(ctx).Info("vm event stream reconnected")
 						continue // Restart the receive loop with new stream
 					}
@@ -493,6 +583,19 @@ func (s *service) startEventForwarder(ctx context.Context, vmc *ttrpc.Client) er
 				s.requestShutdownAndExit(ctx, "vm event stream closed")
 				return
 			}
+			s.lastEventNanos.Store(time.Now().UnixNano())
+			if sev.Missed > 0 {
+				log.G(ctx).WithFields(log.Fields{
+					"missed":   sev.Missed,
+					"last_seq": lastSeq,
+					"seq":      sev.Seq,
+				}).Error("guest dropped events from its history before they were forwarded")
+			}
+			lastSeq = sev.Seq
+			ev := sev.GetEnvelope()
+			if ev == nil {
+				continue
+			}
 
 			// For TaskExit events, wait for I/O forwarder to complete before forwarding.
 			// This ensures all stdout/stderr data is written to FIFOs before containerd
@@ -509,11 +612,13 @@ func (s *service) startEventForwarder(ctx context.Context, vmc *ttrpc.Client) er
 }
 
 // reconnectEventStream attempts to reconnect the event stream within a deadline.
+// The new stream resumes after event lastSeq; the guest replays the events
+// published while the stream was down.
 // Uses exponential backoff with jitter to avoid thundering herd on reconnection.
 // Returns the new client, stream, and whether reconnection succeeded.
 // Note: The caller is responsible for closing the old client if needed. We don't close
 // it here because it might be the cached client from the VM instance, which is shared.
-func (s *service) reconnectEventStream(ctx context.Context, oldClient *ttrpc.Client) (*ttrpc.Client, vmevents.TTRPCEvents_StreamClient, bool) {
+func (s *service) reconnectEventStream(ctx context.Context, oldClient *ttrpc.Client, lastSeq uint64) (*ttrpc.Client, vmevents.TTRPCEvents_StreamClient, bool) {
 	const (
 		initialBackoff = 50 * time.Millisecond
 		maxBackoff     = 500 * time.Millisecond
@@ -537,7 +642,7 @@ func (s *service) reconnectEventStream(ctx context.Context, oldClient *ttrpc.Cli
 			continue
 		}
 
-		newStream, streamErr := vmevents.NewTTRPCEventsClient(newClient).Stream(ctx, &ptypes.Empty{})
+		newStream, streamErr := vmevents.NewTTRPCEventsClient(newClient).Stream(ctx, &vmevents.StreamRequest{AfterSeq: lastSeq})
 		if streamErr != nil {
 			_ = newClient.Close()
 			log.G(ctx).WithError(streamErr).Debug("event stream reconnect: stream failed")
@@ -549,7 +654,7 @@ func (s *service) reconnectEventStream(ctx context.Context, oldClient *ttrpc.Cli
 		// Success! Don't close the old client here - it might be the cached client
 		// from the VM instance which is shared. The event forwarder will track
 		// which clients it owns and can close them when appropriate.
-		log.G(ctx).Info("event stream reconnect: success")
+		log.G(ctx).WithField("after_seq", lastSeq).Info("event stream reconnect: success")
 		return newClient, newStream, true
 	}
 
@@ -625,6 +730,7 @@ type deleteCleanup struct {
 	cpuController    cpuhotplug.CPUHotplugController
 	memController    memhotplug.MemoryHotplugController
 	mountCleanup     func(context.Context) error
+	networks         []string
 	needNetworkClean bool
 	needVMShutdown   bool
 }
@@ -662,6 +768,7 @@ func (s *service) collectDeleteCleanup(r *taskAPI.DeleteRequest) deleteCleanup {
 				}
 			}
 			cleanup.mountCleanup = s.container.mountCleanup
+			cleanup.networks = s.container.networks
 			cleanup.needNetworkClean = true
 			cleanup.needVMShutdown = true
 			s.container = nil
@@ -716,7 +823,7 @@ func (s *service) runDeleteCleanup(ctx context.Context, r *taskAPI.DeleteRequest
 				if !cleanup.needNetworkClean {
 					return nil
 				}
-				env := &network.Environment{ID: r.ID}
+				env := &network.Environment{ID: r.ID, Networks: cleanup.networks}
 				return s.networkManager.ReleaseNetworkResources(ctx, env)
 			},
 			MountCleanup: func(ctx context.Context) error {
@@ -801,6 +908,10 @@ func (s *service) Delete(ctx context.Context, r *taskAPI.DeleteRequest) (*taskAP
 func (s *service) Exec(ctx context.Context, r *taskAPI.ExecProcessRequest) (*ptypes.Empty, error) {
 	log.G(ctx).WithFields(log.Fields{"id": r.ID, "exec": r.ExecID}).Debug("exec request")
 
+	if s.stateMachine.IsShuttingDown() {
+		return nil, errgrpc.ToGRPCf(errdefs.ErrFailedPrecondition, "cannot exec in %s: shim is shutting down", r.ID)
+	}
+
 	vmc, cleanup, err := s.getTaskClient(ctx)
 	if err != nil {
 		return nil, err
@@ -1156,6 +1267,25 @@ func (s *service) getIOForwarder(containerID, execID string) IOForwarder {
 	return nil
 }
 
+// ioWaitTimeoutFromEnv returns the I/O wait timeout set by ioWaitTimeoutEnv,
+// or defaultIOWaitTimeout if it is unset or invalid.
+func ioWaitTimeoutFromEnv(ctx context.Context) time.Duration {
+	v := os.Getenv(ioWaitTimeoutEnv)
+	if v == "" {
+		return defaultIOWaitTimeout
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < minIOWaitTimeout {
+		log.G(ctx).WithFields(log.Fields{
+			"value":   v,
+			"minimum": minIOWaitTimeout,
+			"default": defaultIOWaitTimeout,
+		}).Warnf("invalid %s, using default", ioWaitTimeoutEnv)
+		return defaultIOWaitTimeout
+	}
+	return d
+}
+
 // waitForIOBeforeExit waits for the I/O forwarder to complete before forwarding a TaskExit event.
 // This ensures that all stdout/stderr data is written to FIFOs before containerd receives the exit event.
 func (s *service) waitForIOBeforeExit(ctx context.Context, ev *types.Envelope) {
@@ -1198,7 +1328,7 @@ func (s *service) waitForIOBeforeExit(ctx context.Context, ev *types.Envelope) {
 	// Wait for I/O to complete. With direct stream I/O, the guest closes the stream
 	// when the process exits, and we see EOF. The timeout is a safety measure for
 	// cases where the stream doesn't close cleanly (e.g., vsock connection lost).
-	const ioWaitTimeout = 30 * time.Second
+	ioWaitTimeout := cmp.Or(s.ioWaitTimeout, defaultIOWaitTimeout)
 
 	done := make(chan struct{})
 	go func() {
@@ -1248,9 +1378,13 @@ func (s *service) requestShutdownAndExit(ctx context.Context, reason string) {
 	// Delete() path may not run, leaving CNI allocations orphaned.
 	s.containerMu.Lock()
 	containerID := s.containerID
+	var networks []string
+	if s.container != nil {
+		networks = s.container.networks
+	}
 	s.containerMu.Unlock()
 	if containerID != "" {
-		env := &network.Environment{ID: containerID}
+		env := &network.Environment{ID: containerID, Networks: networks}
 		if err := s.networkManager.ReleaseNetworkResources(ctx, env); err != nil {
 			log.G(ctx).WithError(err).WithField("id", containerID).Warn("failed to release network resources during unexpected shutdown")
 		} else {
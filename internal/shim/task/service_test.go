@@ -0,0 +1,142 @@
+//go:build linux
+
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spin-stack/spinbox/internal/host/network"
+	"github.com/spin-stack/spinbox/internal/shim/lifecycle"
+)
+
+func TestIOWaitTimeoutFromEnv(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "unset", value: "", want: defaultIOWaitTimeout},
+		{name: "override", value: "2m", want: 2 * time.Minute},
+		{name: "minimum", value: "1s", want: time.Second},
+		{name: "below minimum", value: "500ms", want: defaultIOWaitTimeout},
+		{name: "negative", value: "-5s", want: defaultIOWaitTimeout},
+		{name: "not a duration", value: "30", want: defaultIOWaitTimeout},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(ioWaitTimeoutEnv, tc.value)
+			if got := ioWaitTimeoutFromEnv(context.Background()); got != tc.want {
+				t.Fatalf("ioWaitTimeoutFromEnv() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDrain(t *testing.T) {
+	newService := func(timeout time.Duration, forwarders ...IOForwarder) *service {
+		io := &taskIO{init: processIOState{forwarder: forwarders[0]}, exec: map[string]processIOState{}}
+		for i, f := range forwarders[1:] {
+			io.exec[fmt.Sprintf("exec-%d", i)] = processIOState{forwarder: f}
+		}
+		return &service{
+			stateMachine:  lifecycle.NewStateMachine(),
+			container:     &container{io: io},
+			ioWaitTimeout: timeout,
+		}
+	}
+
+	t.Run("waits for all streams", func(t *testing.T) {
+		var (
+			forwarders []IOForwarder
+			closers    []chan struct{}
+		)
+		for range 3 {
+			done := make(chan struct{})
+			closers = append(closers, done)
+			forwarders = append(forwarders, &directForwarder{done: done})
+		}
+		forwarders = append(forwarders, &noopForwarder{})
+		s := newService(time.Minute, forwarders...)
+
+		var finished atomic.Int32
+		for i, done := range closers {
+			go func() {
+				time.Sleep(time.Duration(i+1) * 20 * time.Millisecond)
+				finished.Add(1)
+				close(done)
+			}()
+		}
+
+		if err := s.Drain(context.Background()); err != nil {
+			t.Fatalf("Drain() error = %v", err)
+		}
+		if n := finished.Load(); n != int32(len(closers)) {
+			t.Fatalf("Drain returned with %d of %d streams finished", n, len(closers))
+		}
+		if !s.stateMachine.IsShuttingDown() {
+			t.Fatalf("state = %s, want shutting down", s.stateMachine.State())
+		}
+	})
+
+	t.Run("bounded by the I/O wait timeout", func(t *testing.T) {
+		finished := make(chan struct{})
+		close(finished)
+		s := newService(50*time.Millisecond, &directForwarder{done: finished}, &directForwarder{done: make(chan struct{})})
+
+		start := time.Now()
+		err := s.Drain(context.Background())
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Drain() error = %v, want deadline exceeded", err)
+		}
+		if !strings.Contains(err.Error(), "1 of 2") {
+			t.Fatalf("Drain() error = %v, want it to count the undrained stream", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("Drain took %v", elapsed)
+		}
+	})
+
+	t.Run("no container", func(t *testing.T) {
+		s := &service{stateMachine: lifecycle.NewStateMachine()}
+		if err := s.Drain(context.Background()); err != nil {
+			t.Fatalf("Drain() error = %v", err)
+		}
+	})
+}
+
+// releaseRecorder is a network.NetworkManager that records released
+// environments.
+type releaseRecorder struct {
+	network.NetworkManager
+	released []*network.Environment
+}
+
+func (r *releaseRecorder) ReleaseNetworkResources(_ context.Context, env *network.Environment) error {
+	r.released = append(r.released, env)
+	return nil
+}
+
+func TestBuildCleanupPhasesReleasesAllNetworks(t *testing.T) {
+	nm := &releaseRecorder{}
+	s := &service{
+		networkManager: nm,
+		container:      &container{networks: []string{"primary", "storage"}},
+		containerID:    "c1",
+	}
+
+	if err := s.buildCleanupPhases("c1").NetworkCleanup(context.Background()); err != nil {
+		t.Fatalf("NetworkCleanup() error = %v", err)
+	}
+	if len(nm.released) != 1 {
+		t.Fatalf("released %d environments, want 1", len(nm.released))
+	}
+	env := nm.released[0]
+	if env.ID != "c1" || strings.Join(env.Networks, ",") != "primary,storage" {
+		t.Fatalf("released %s with networks %v, want c1 with [primary storage]", env.ID, env.Networks)
+	}
+}
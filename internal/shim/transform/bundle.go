@@ -4,11 +4,18 @@
 package transform
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
+	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
 	"github.com/opencontainers/runc/libcontainer/capabilities"
 	"github.com/opencontainers/runtime-spec/specs-go"
@@ -16,35 +23,199 @@ import (
 	"github.com/spin-stack/spinbox/internal/shim/bundle"
 )
 
+// reservedFilePrefix starts the names of bundle files the shim adds for
+// vminitd. TransformBindMounts rejects bind sources that map to such a name,
+// so a file from the bundle cannot take their place.
+const reservedFilePrefix = ".spinbox-"
+
+// ResolvConfFileName is the bundle file carrying a container's own
+// resolv.conf. vminitd bind-mounts it over /etc/resolv.conf in place of the
+// VM-wide one (must match guest runc package).
+const ResolvConfFileName = reservedFilePrefix + "resolv.conf"
+
+// TransformResolvConf ships a container-specific /etc/resolv.conf bind mount
+// (such as the one CRI generates for a pod) to the VM as a bundle file, so
+// containers sharing a VM can use different DNS settings. The mount is
+// dropped from the spec; vminitd mounts the bundle copy in its place.
+func TransformResolvConf(ctx context.Context, b *bundle.Bundle) error {
+	for i, m := range b.Spec.Mounts {
+		if m.Destination != "/etc/resolv.conf" || m.Type != "bind" {
+			continue
+		}
+		buf, err := os.ReadFile(m.Source)
+		if err != nil {
+			return fmt.Errorf("failed to read resolv.conf %q: %w", m.Source, err)
+		}
+		if err := b.AddExtraFile(ResolvConfFileName, buf); err != nil {
+			return fmt.Errorf("failed to add extra file %q: %w", ResolvConfFileName, err)
+		}
+		b.Spec.Mounts = slices.Delete(b.Spec.Mounts, i, i+1)
+		log.G(ctx).WithField("source", m.Source).Debug("passing container resolv.conf to VM")
+		return nil
+	}
+	return nil
+}
+
+// DirArchiveSuffix marks a bundle file holding a tar archive of a directory
+// bind mount. vminitd extracts "<name>.dir.tar" to "<name>" in the bundle
+// directory and drops the archive (must match guest services package).
+const DirArchiveSuffix = ".dir.tar"
+
 // TransformBindMounts converts bind mounts to extra files for the VM.
+// Every bind mount whose source is inside the bundle directory is captured,
+// including sources in subdirectories. Bundle files are flat, so a source
+// in a subdirectory is sent under its path with the separators replaced by
+// "_" (conf/app.yaml becomes conf_app.yaml). Directory sources are sent as
+// a tar archive named with DirArchiveSuffix; only regular files and
+// directories are supported inside them. A file source whose bundle name
+// ends in DirArchiveSuffix is rejected.
 func TransformBindMounts(ctx context.Context, b *bundle.Bundle) error {
+	sources := make(map[string]string)
 	for i, m := range b.Spec.Mounts {
-		if m.Type == "bind" {
-			filename := filepath.Base(m.Source)
-			if filepath.Base(filepath.Dir(m.Source)) != filepath.Base(b.Path) {
-				log.G(ctx).WithField("source", m.Source).Debug("ignoring bind mount")
-				continue
-			}
+		if !isBindMount(m.Type, m.Options) {
+			continue
+		}
+		rel, err := filepath.Rel(b.Path, m.Source)
+		if err != nil || !filepath.IsAbs(m.Source) || rel == ".." || strings.HasPrefix(rel, "../") {
+			log.G(ctx).WithField("source", m.Source).Debug("ignoring bind mount")
+			continue
+		}
+		if rel == "." {
+			return fmt.Errorf("mount at %s: the bundle directory itself cannot be bind mounted: %w", m.Destination, errdefs.ErrInvalidArgument)
+		}
 
-			buf, err := os.ReadFile(m.Source)
+		filename := strings.ReplaceAll(rel, string(filepath.Separator), "_")
+		if strings.HasPrefix(filename, reservedFilePrefix) {
+			return fmt.Errorf("mount source %q maps to bundle file %q, names starting with %q are reserved: %w", m.Source, filename, reservedFilePrefix, errdefs.ErrInvalidArgument)
+		}
+		if prev, ok := sources[filename]; ok && prev != m.Source {
+			return fmt.Errorf("mount sources %q and %q both map to bundle file %q: %w", prev, m.Source, filename, errdefs.ErrInvalidArgument)
+		}
+		sources[filename] = m.Source
+
+		fi, err := os.Stat(m.Source)
+		if err != nil {
+			return fmt.Errorf("failed to stat mount source %q: %w", rel, err)
+		}
+
+		name := filename
+		var buf []byte
+		if !fi.IsDir() && strings.HasSuffix(filename, DirArchiveSuffix) {
+			// vminitd would extract it as a directory archive
+			return fmt.Errorf("mount source %q maps to bundle file %q, file names ending in %q are reserved for directory archives: %w", m.Source, filename, DirArchiveSuffix, errdefs.ErrInvalidArgument)
+		}
+		if fi.IsDir() {
+			name = filename + DirArchiveSuffix
+			buf, err = archiveDir(m.Source)
 			if err != nil {
-				return fmt.Errorf("failed to read mount file %q: %w", filename, err)
+				return fmt.Errorf("failed to archive mount directory %q: %w", rel, err)
 			}
-			b.Spec.Mounts[i].Source = filename
-			if err := b.AddExtraFile(filename, buf); err != nil {
-				return fmt.Errorf("failed to add extra file %q: %w", filename, err)
+		} else {
+			buf, err = os.ReadFile(m.Source)
+			if err != nil {
+				return fmt.Errorf("failed to read mount file %q: %w", rel, err)
 			}
 		}
+		b.Spec.Mounts[i].Source = filename
+		if err := b.AddExtraFile(name, buf); err != nil {
+			return fmt.Errorf("failed to add extra file %q: %w", name, err)
+		}
 	}
 	return nil
 }
 
+// archiveDir returns a tar archive of the contents of dir, with entry names
+// relative to dir. Symlinks and special files are rejected rather than
+// followed so the archive cannot reference anything outside dir.
+func archiveDir(dir string) ([]byte, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() && !fi.Mode().IsRegular() {
+			return fmt.Errorf("unsupported file type %s for %q", fi.Mode().Type(), rel)
+		}
+
+		hdr, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if fi.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// AnnotationSecurityMode selects how much in-container hardening is kept
+// when the spec is adapted for the VM (must match guest vminit/runc package).
+//
+// Accepted values:
+//   - "relaxed" (default): readonly/masked paths and seccomp are removed,
+//     the VM is the security boundary
+//   - "seccomp": the seccomp profile is kept, readonly/masked paths are
+//     removed
+//   - "preserve": readonly/masked paths and seccomp are kept for
+//     defense in depth; device access is still opened up
+const AnnotationSecurityMode = "io.spin.security.mode"
+
+const (
+	securityModeRelaxed  = "relaxed"
+	securityModeSeccomp  = "seccomp"
+	securityModePreserve = "preserve"
+)
+
 // AdaptForVM adapts the OCI spec for running inside a VM.
 // The VM provides isolation, so we:
 // - Remove network/cgroup namespaces (container uses VM's)
 // - Ensure cgroup2 mount exists
 // - Grant full capabilities (VM is the security boundary)
+// - Remove readonly/masked paths, unless AnnotationSecurityMode is "preserve"
 func AdaptForVM(ctx context.Context, b *bundle.Bundle) error {
+	preserve := false
+	switch mode := b.Spec.Annotations[AnnotationSecurityMode]; mode {
+	case "", securityModeRelaxed, securityModeSeccomp:
+	case securityModePreserve:
+		preserve = true
+	default:
+		return fmt.Errorf("invalid %s annotation %q: expected %s, %s or %s",
+			AnnotationSecurityMode, mode, securityModeRelaxed, securityModeSeccomp, securityModePreserve)
+	}
+
 	// Remove network and cgroup namespaces
 	if b.Spec.Linux != nil {
 		var namespaces []specs.LinuxNamespace
@@ -88,7 +259,7 @@ func AdaptForVM(ctx context.Context, b *bundle.Bundle) error {
 	}
 
 	// Clear readonly/masked paths - VM provides isolation, not OCI restrictions
-	if b.Spec.Linux != nil {
+	if b.Spec.Linux != nil && !preserve {
 		b.Spec.Linux.ReadonlyPaths = nil
 		b.Spec.Linux.MaskedPaths = nil
 	}
@@ -116,10 +287,62 @@ func ensureRW(opts []string) []string {
 	return result
 }
 
+// SpinboxAnnotationPrefix prefixes the annotations that configure spinbox
+// itself. TransformAnnotations always keeps them.
+const SpinboxAnnotationPrefix = "io.spin."
+
+// TransformAnnotations returns a transformer that keeps only the spec
+// annotations matching one of prefixes (and spinbox's own annotations), so
+// host-specific metadata set by containerd or CRI is not copied into the VM
+// unless it is needed there, e.g. "io.kubernetes.cri.sandbox-id" for
+// correlation.
+func TransformAnnotations(prefixes []string) bundle.Transformer {
+	return func(ctx context.Context, b *bundle.Bundle) error {
+		for k := range b.Spec.Annotations {
+			keep := strings.HasPrefix(k, SpinboxAnnotationPrefix) ||
+				slices.ContainsFunc(prefixes, func(p string) bool { return strings.HasPrefix(k, p) })
+			if !keep {
+				log.G(ctx).WithField("annotation", k).Debug("dropping annotation from VM spec")
+				delete(b.Spec.Annotations, k)
+			}
+		}
+		return nil
+	}
+}
+
 // LoadForCreate loads and transforms an OCI bundle for container creation.
-func LoadForCreate(ctx context.Context, bundlePath string) (*bundle.Bundle, error) {
-	return bundle.Load(ctx, bundlePath,
+// The privilege policy is checked against the host-generated spec and
+// applied after AdaptForVM. Specs with more than maxMounts mounts are
+// rejected; zero means DefaultMaxMounts.
+func LoadForCreate(ctx context.Context, bundlePath string, policy PrivilegePolicy, maxMounts int) (*bundle.Bundle, error) {
+	return bundle.Load(ctx, bundlePath, createTransformers(policy, maxMounts)...)
+}
+
+// LoadForCreateFiltered is LoadForCreate, additionally dropping every
+// annotation that does not match annotationPrefixes (see TransformAnnotations).
+func LoadForCreateFiltered(ctx context.Context, bundlePath string, policy PrivilegePolicy, maxMounts int, annotationPrefixes []string) (*bundle.Bundle, error) {
+	transformers := append(createTransformers(policy, maxMounts), TransformAnnotations(annotationPrefixes))
+	return bundle.Load(ctx, bundlePath, transformers...)
+}
+
+func createTransformers(policy PrivilegePolicy, maxMounts int) []bundle.Transformer {
+	if maxMounts == 0 {
+		maxMounts = DefaultMaxMounts
+	}
+	checkPrivilege, applyPrivilege := policy.transformers()
+	return []bundle.Transformer{
+		// Checked first so no other transformer walks an oversized list
+		LimitMounts(maxMounts),
+		ResolveMountSources,
+		TransformResolvConf,
+		// CRI bind-mounts the pod sandbox's /dev/shm into each container.
+		// Every container has its own VM, so it gets the VM's /dev/shm
+		// instead, which is the expected behavior.
+		CheckMountCollisions("/dev/shm"),
+		NormalizeMountOptions,
 		TransformBindMounts,
+		checkPrivilege,
 		AdaptForVM,
-	)
+		applyPrivilege,
+	}
 }
@@ -3,12 +3,17 @@
 package transform
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/containerd/errdefs"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -72,6 +77,169 @@ func TestTransformBindMounts(t *testing.T) {
 		assert.Equal(t, testContent, files["config.yaml"])
 	})
 
+	t.Run("archives directory bind mount from bundle path", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		bundlePath := filepath.Join(tmpDir, "test-container")
+		createTestBundle(t, bundlePath)
+
+		confDir := filepath.Join(bundlePath, "conf.d")
+		require.NoError(t, os.MkdirAll(filepath.Join(confDir, "sub"), 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(confDir, "a.conf"), []byte("a=1\n"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(confDir, "sub", "b.conf"), []byte("b=2\n"), 0640))
+
+		b, err := bundle.Load(ctx, bundlePath)
+		require.NoError(t, err)
+		b.Spec.Mounts = append(b.Spec.Mounts, specs.Mount{
+			Destination: "/etc/app/conf.d",
+			Type:        "bind",
+			Source:      confDir,
+		})
+
+		require.NoError(t, TransformBindMounts(ctx, b))
+
+		assert.Equal(t, "conf.d", b.Spec.Mounts[len(b.Spec.Mounts)-1].Source)
+		files, err := b.Files()
+		require.NoError(t, err)
+		require.Contains(t, files, "conf.d"+DirArchiveSuffix)
+		assert.NotContains(t, files, "conf.d")
+
+		got := make(map[string]string)
+		tr := tar.NewReader(bytes.NewReader(files["conf.d"+DirArchiveSuffix]))
+		for {
+			hdr, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			data, err := io.ReadAll(tr)
+			require.NoError(t, err)
+			got[hdr.Name] = string(data)
+			if hdr.Name == "sub/b.conf" {
+				assert.Equal(t, int64(0640), hdr.Mode&0777)
+			}
+		}
+		assert.Equal(t, map[string]string{
+			"a.conf":     "a=1\n",
+			"sub/":       "",
+			"sub/b.conf": "b=2\n",
+		}, got)
+	})
+
+	t.Run("rejects symlink in directory bind mount", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		bundlePath := filepath.Join(tmpDir, "test-container")
+		createTestBundle(t, bundlePath)
+
+		confDir := filepath.Join(bundlePath, "conf.d")
+		require.NoError(t, os.MkdirAll(confDir, 0750))
+		require.NoError(t, os.Symlink("/etc/shadow", filepath.Join(confDir, "shadow")))
+
+		b, err := bundle.Load(ctx, bundlePath)
+		require.NoError(t, err)
+		b.Spec.Mounts = append(b.Spec.Mounts, specs.Mount{
+			Destination: "/etc/app/conf.d",
+			Type:        "bind",
+			Source:      confDir,
+		})
+
+		err = TransformBindMounts(ctx, b)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "shadow")
+	})
+
+	t.Run("captures relative sources in bundle subdirectories", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		bundlePath := filepath.Join(tmpDir, "test-container")
+		createTestBundle(t, bundlePath)
+		require.NoError(t, os.MkdirAll(filepath.Join(bundlePath, "conf", "certs"), 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(bundlePath, "conf", "app.yaml"), []byte("a: 1\n"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(bundlePath, "conf", "certs", "ca.pem"), []byte("pem"), 0600))
+
+		b, err := bundle.Load(ctx, bundlePath)
+		require.NoError(t, err)
+		b.Spec.Mounts = []specs.Mount{
+			{Destination: "/etc/app.yaml", Type: "bind", Source: "conf/app.yaml"},
+			// Type "" bind mounts are recognized by their options
+			{Destination: "/etc/certs", Source: "conf/certs", Options: []string{"rbind", "ro"}},
+		}
+
+		require.NoError(t, ResolveMountSources(ctx, b))
+		require.NoError(t, TransformBindMounts(ctx, b))
+
+		assert.Equal(t, "conf_app.yaml", b.Spec.Mounts[0].Source)
+		assert.Equal(t, "conf_certs", b.Spec.Mounts[1].Source)
+		files, err := b.Files()
+		require.NoError(t, err)
+		assert.Equal(t, []byte("a: 1\n"), files["conf_app.yaml"])
+		assert.Contains(t, files, "conf_certs"+DirArchiveSuffix)
+	})
+
+	t.Run("rejects sources that map to the same bundle file", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		bundlePath := filepath.Join(tmpDir, "test-container")
+		createTestBundle(t, bundlePath)
+		require.NoError(t, os.MkdirAll(filepath.Join(bundlePath, "conf"), 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(bundlePath, "conf", "app"), nil, 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(bundlePath, "conf_app"), nil, 0600))
+
+		b, err := bundle.Load(ctx, bundlePath)
+		require.NoError(t, err)
+		b.Spec.Mounts = []specs.Mount{
+			{Destination: "/a", Type: "bind", Source: filepath.Join(bundlePath, "conf", "app")},
+			{Destination: "/b", Type: "bind", Source: filepath.Join(bundlePath, "conf_app")},
+		}
+		require.ErrorIs(t, TransformBindMounts(ctx, b), errdefs.ErrInvalidArgument)
+	})
+
+	t.Run("rejects sources that map to a reserved bundle file", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		bundlePath := filepath.Join(tmpDir, "test-container")
+		createTestBundle(t, bundlePath)
+		require.NoError(t, os.WriteFile(filepath.Join(bundlePath, ResolvConfFileName), []byte("nameserver 10.0.0.53\n"), 0600))
+
+		b, err := bundle.Load(ctx, bundlePath)
+		require.NoError(t, err)
+		b.Spec.Mounts = []specs.Mount{
+			{Destination: "/etc/hosts", Type: "bind", Source: filepath.Join(bundlePath, ResolvConfFileName)},
+		}
+		require.ErrorIs(t, TransformBindMounts(ctx, b), errdefs.ErrInvalidArgument)
+	})
+
+	t.Run("rejects file sources named like a directory archive", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		bundlePath := filepath.Join(tmpDir, "test-container")
+		createTestBundle(t, bundlePath)
+		require.NoError(t, os.WriteFile(filepath.Join(bundlePath, "data"+DirArchiveSuffix), []byte("data"), 0600))
+		require.NoError(t, os.MkdirAll(filepath.Join(bundlePath, "conf"+DirArchiveSuffix), 0750))
+
+		b, err := bundle.Load(ctx, bundlePath)
+		require.NoError(t, err)
+		b.Spec.Mounts = []specs.Mount{
+			{Destination: "/data", Type: "bind", Source: filepath.Join(bundlePath, "data"+DirArchiveSuffix)},
+		}
+		require.ErrorIs(t, TransformBindMounts(ctx, b), errdefs.ErrInvalidArgument)
+
+		// A directory source keeps its name and is archived as usual
+		b.Spec.Mounts = []specs.Mount{
+			{Destination: "/conf", Type: "bind", Source: filepath.Join(bundlePath, "conf"+DirArchiveSuffix)},
+		}
+		require.NoError(t, TransformBindMounts(ctx, b))
+		files, err := b.Files()
+		require.NoError(t, err)
+		assert.Contains(t, files, "conf"+DirArchiveSuffix+DirArchiveSuffix)
+	})
+
+	t.Run("rejects the bundle directory itself", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		bundlePath := filepath.Join(tmpDir, "test-container")
+		createTestBundle(t, bundlePath)
+
+		b, err := bundle.Load(ctx, bundlePath)
+		require.NoError(t, err)
+		b.Spec.Mounts = []specs.Mount{{Destination: "/bundle", Type: "bind", Source: bundlePath}}
+		require.ErrorIs(t, TransformBindMounts(ctx, b), errdefs.ErrInvalidArgument)
+	})
+
 	t.Run("ignores bind mount from different path", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		bundlePath := filepath.Join(tmpDir, "test-container")
@@ -98,6 +266,65 @@ func TestTransformBindMounts(t *testing.T) {
 	})
 }
 
+func TestTransformResolvConf(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("passes resolv.conf mount as bundle file", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		bundlePath := filepath.Join(tmpDir, "test-container")
+		createTestBundle(t, bundlePath)
+
+		// CRI keeps the pod resolv.conf outside the container bundle
+		resolvPath := filepath.Join(tmpDir, "sandbox", "resolv.conf")
+		require.NoError(t, os.MkdirAll(filepath.Dir(resolvPath), 0750))
+		content := []byte("nameserver 10.96.0.10\nsearch default.svc.cluster.local\n")
+		require.NoError(t, os.WriteFile(resolvPath, content, 0600))
+
+		b, err := bundle.Load(ctx, bundlePath)
+		require.NoError(t, err)
+		b.Spec.Mounts = []specs.Mount{
+			{Destination: "/proc", Type: "proc", Source: "proc"},
+			{Destination: "/etc/resolv.conf", Type: "bind", Source: resolvPath, Options: []string{"rbind", "ro"}},
+		}
+
+		require.NoError(t, TransformResolvConf(ctx, b))
+
+		assert.Equal(t, []specs.Mount{{Destination: "/proc", Type: "proc", Source: "proc"}}, b.Spec.Mounts)
+		files, err := b.Files()
+		require.NoError(t, err)
+		assert.Equal(t, content, files[ResolvConfFileName])
+	})
+
+	t.Run("no resolv.conf mount", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		bundlePath := filepath.Join(tmpDir, "test-container")
+		createTestBundle(t, bundlePath)
+
+		b, err := bundle.Load(ctx, bundlePath)
+		require.NoError(t, err)
+
+		require.NoError(t, TransformResolvConf(ctx, b))
+
+		files, err := b.Files()
+		require.NoError(t, err)
+		assert.NotContains(t, files, ResolvConfFileName)
+	})
+
+	t.Run("unreadable source fails", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		bundlePath := filepath.Join(tmpDir, "test-container")
+		createTestBundle(t, bundlePath)
+
+		b, err := bundle.Load(ctx, bundlePath)
+		require.NoError(t, err)
+		b.Spec.Mounts = []specs.Mount{
+			{Destination: "/etc/resolv.conf", Type: "bind", Source: filepath.Join(tmpDir, "missing")},
+		}
+
+		require.Error(t, TransformResolvConf(ctx, b))
+	})
+}
+
 func TestAdaptForVM(t *testing.T) {
 	ctx := context.Background()
 
@@ -198,6 +425,84 @@ func TestAdaptForVM(t *testing.T) {
 		assert.Contains(t, b.Spec.Process.Capabilities.Effective, "CAP_SYS_ADMIN")
 	})
 
+	t.Run("handles nil capabilities", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		bundlePath := filepath.Join(tmpDir, "test-container")
+		createTestBundle(t, bundlePath)
+
+		b, err := bundle.Load(ctx, bundlePath)
+		require.NoError(t, err)
+		require.Nil(t, b.Spec.Process.Capabilities)
+
+		err = AdaptForVM(ctx, b)
+		require.NoError(t, err)
+
+		require.NotNil(t, b.Spec.Process.Capabilities)
+		assert.NotEmpty(t, b.Spec.Process.Capabilities.Bounding)
+		assert.NotEmpty(t, b.Spec.Process.Capabilities.Ambient)
+	})
+
+	t.Run("security mode controls readonly and masked paths", func(t *testing.T) {
+		for _, tc := range []struct {
+			mode     string
+			wantKept bool
+			wantErr  bool
+		}{
+			{mode: "", wantKept: false},
+			{mode: "relaxed", wantKept: false},
+			{mode: "seccomp", wantKept: false},
+			{mode: "preserve", wantKept: true},
+			{mode: "bogus", wantErr: true},
+		} {
+			tmpDir := t.TempDir()
+			bundlePath := filepath.Join(tmpDir, "test-container")
+			createTestBundle(t, bundlePath)
+
+			b, err := bundle.Load(ctx, bundlePath)
+			require.NoError(t, err)
+			if tc.mode != "" {
+				b.Spec.Annotations = map[string]string{AnnotationSecurityMode: tc.mode}
+			}
+			b.Spec.Linux.ReadonlyPaths = []string{"/proc/bus"}
+			b.Spec.Linux.MaskedPaths = []string{"/proc/kcore"}
+
+			err = AdaptForVM(ctx, b)
+			if tc.wantErr {
+				require.Error(t, err, "mode %q", tc.mode)
+				continue
+			}
+			require.NoError(t, err, "mode %q", tc.mode)
+			if tc.wantKept {
+				assert.Equal(t, []string{"/proc/bus"}, b.Spec.Linux.ReadonlyPaths, "mode %q", tc.mode)
+				assert.Equal(t, []string{"/proc/kcore"}, b.Spec.Linux.MaskedPaths, "mode %q", tc.mode)
+			} else {
+				assert.Nil(t, b.Spec.Linux.ReadonlyPaths, "mode %q", tc.mode)
+				assert.Nil(t, b.Spec.Linux.MaskedPaths, "mode %q", tc.mode)
+			}
+		}
+	})
+
+	t.Run("handles nil process", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		bundlePath := filepath.Join(tmpDir, "test-container")
+
+		require.NoError(t, os.MkdirAll(bundlePath, 0750))
+		spec := specs.Spec{
+			Version: "1.0.0",
+			Root:    &specs.Root{Path: "rootfs"},
+		}
+		specBytes, _ := json.Marshal(spec)
+		require.NoError(t, os.WriteFile(filepath.Join(bundlePath, "config.json"), specBytes, 0600))
+		require.NoError(t, os.MkdirAll(filepath.Join(bundlePath, "rootfs"), 0750))
+
+		b, err := bundle.Load(ctx, bundlePath)
+		require.NoError(t, err)
+
+		err = AdaptForVM(ctx, b)
+		require.NoError(t, err)
+		assert.Nil(t, b.Spec.Process)
+	})
+
 	t.Run("handles nil Linux config", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		bundlePath := filepath.Join(tmpDir, "test-container")
@@ -242,7 +547,7 @@ func TestLoadForCreate(t *testing.T) {
 		specBytes, _ = json.Marshal(spec)
 		require.NoError(t, os.WriteFile(filepath.Join(bundlePath, "config.json"), specBytes, 0600))
 
-		b, err := LoadForCreate(ctx, bundlePath)
+		b, err := LoadForCreate(ctx, bundlePath, PrivilegePolicy{}, 0)
 		require.NoError(t, err)
 
 		// Check namespaces removed
@@ -272,7 +577,70 @@ func TestLoadForCreate(t *testing.T) {
 	})
 
 	t.Run("returns error for invalid path", func(t *testing.T) {
-		_, err := LoadForCreate(ctx, "/nonexistent")
+		_, err := LoadForCreate(ctx, "/nonexistent", PrivilegePolicy{}, 0)
 		require.Error(t, err)
 	})
 }
+
+func TestTransformAnnotations(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("keeps matching prefixes and spinbox annotations", func(t *testing.T) {
+		b := &bundle.Bundle{Spec: specs.Spec{Annotations: map[string]string{
+			"io.kubernetes.cri.sandbox-id":   "abc",
+			"io.kubernetes.cri.sandbox-name": "pod",
+			"io.kubernetes.cri.image-name":   "busybox",
+			"io.spin.network.names":          "default",
+			"io.containerd.host.path":        "/var/lib/secret",
+			"com.example.owner":              "team",
+		}}}
+
+		require.NoError(t, TransformAnnotations([]string{"io.kubernetes.cri.sandbox-"})(ctx, b))
+		assert.Equal(t, map[string]string{
+			"io.kubernetes.cri.sandbox-id":   "abc",
+			"io.kubernetes.cri.sandbox-name": "pod",
+			"io.spin.network.names":          "default",
+		}, b.Spec.Annotations)
+	})
+
+	t.Run("no prefixes keeps only spinbox annotations", func(t *testing.T) {
+		b := &bundle.Bundle{Spec: specs.Spec{Annotations: map[string]string{
+			"io.spin.security.mode":        "preserve",
+			"io.kubernetes.cri.sandbox-id": "abc",
+		}}}
+
+		require.NoError(t, TransformAnnotations(nil)(ctx, b))
+		assert.Equal(t, map[string]string{"io.spin.security.mode": "preserve"}, b.Spec.Annotations)
+	})
+
+	t.Run("nil annotations", func(t *testing.T) {
+		b := &bundle.Bundle{}
+		require.NoError(t, TransformAnnotations([]string{"io.kubernetes."})(ctx, b))
+		assert.Nil(t, b.Spec.Annotations)
+	})
+
+	t.Run("LoadForCreateFiltered", func(t *testing.T) {
+		bundlePath := filepath.Join(t.TempDir(), "test-container")
+		createTestBundle(t, bundlePath)
+
+		specBytes, err := os.ReadFile(filepath.Join(bundlePath, "config.json"))
+		require.NoError(t, err)
+		var spec specs.Spec
+		require.NoError(t, json.Unmarshal(specBytes, &spec))
+		spec.Annotations = map[string]string{
+			"io.kubernetes.cri.sandbox-id": "abc",
+			"io.kubernetes.cri.image-name": "busybox",
+		}
+		specBytes, err = json.Marshal(spec)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(bundlePath, "config.json"), specBytes, 0600))
+
+		b, err := LoadForCreateFiltered(ctx, bundlePath, PrivilegePolicy{}, 0, []string{"io.kubernetes.cri.sandbox-id"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"io.kubernetes.cri.sandbox-id": "abc"}, b.Spec.Annotations)
+
+		b, err = LoadForCreate(ctx, bundlePath, PrivilegePolicy{}, 0)
+		require.NoError(t, err)
+		assert.Len(t, b.Spec.Annotations, 2, "LoadForCreate must not filter annotations")
+	})
+}
@@ -0,0 +1,159 @@
+//go:build linux
+
+package transform
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+
+	"github.com/spin-stack/spinbox/internal/shim/bundle"
+)
+
+// vmManagedMounts are container mount destinations that spinbox replaces
+// with filesystems provided by the VM, mapped to the mount types expected
+// there. vminitd bind-mounts the VM's /dev over the container's /dev and
+// drops /dev/pts, /dev/shm and /dev/mqueue; AdaptForVM turns
+// /sys/fs/cgroup into a cgroup2 mount. Any other mount at these
+// destinations would be silently shadowed or replaced.
+var vmManagedMounts = map[string][]string{
+	"/proc":          {"proc"},
+	"/sys":           {"sysfs"},
+	"/sys/fs/cgroup": {"cgroup", "cgroup2"},
+	"/dev":           {"tmpfs", "devtmpfs"},
+	"/dev/pts":       {"devpts"},
+	"/dev/shm":       {"tmpfs"},
+	"/dev/mqueue":    {"mqueue"},
+}
+
+// CheckMountCollisions returns a transformer that rejects mounts whose
+// destination is a VM-managed filesystem (see vmManagedMounts) but whose
+// type is not the one the VM provides there, such as a bind mount to /proc
+// or /dev/shm. Destinations in allow are not checked.
+func CheckMountCollisions(allow ...string) bundle.Transformer {
+	return func(ctx context.Context, b *bundle.Bundle) error {
+		for _, m := range b.Spec.Mounts {
+			dest := path.Clean(m.Destination)
+			types, managed := vmManagedMounts[dest]
+			if !managed || slices.Contains(types, m.Type) || slices.Contains(allow, dest) {
+				continue
+			}
+			return fmt.Errorf("mount at %s (type %q, source %q) collides with a filesystem provided by the VM and would not take effect: %w",
+				dest, m.Type, m.Source, errdefs.ErrInvalidArgument)
+		}
+		return nil
+	}
+}
+
+// ResolveMountSources makes relative bind mount sources absolute by
+// resolving them against the bundle directory, where the runtime would look
+// for them on the host. The VM receives the bundle at a different path, so a
+// relative source would otherwise resolve differently there. Sources that
+// escape the bundle directory are rejected.
+func ResolveMountSources(ctx context.Context, b *bundle.Bundle) error {
+	for i := range b.Spec.Mounts {
+		m := &b.Spec.Mounts[i]
+		if !isBindMount(m.Type, m.Options) || m.Source == "" || filepath.IsAbs(m.Source) {
+			continue
+		}
+		src := filepath.Join(b.Path, m.Source)
+		if rel, err := filepath.Rel(b.Path, src); err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+			return fmt.Errorf("mount at %s: relative source %q escapes the bundle directory: %w",
+				m.Destination, m.Source, errdefs.ErrInvalidArgument)
+		}
+		log.G(ctx).WithFields(log.Fields{
+			"destination": m.Destination,
+			"source":      m.Source,
+			"resolved":    src,
+		}).Debug("resolved relative mount source")
+		m.Source = src
+	}
+	return nil
+}
+
+func isBindMount(typ string, options []string) bool {
+	return typ == "bind" || slices.Contains(options, "bind") || slices.Contains(options, "rbind")
+}
+
+// DefaultMaxMounts is the mount limit LoadForCreate applies when none is
+// configured.
+const DefaultMaxMounts = 256
+
+// LimitMounts returns a transformer that rejects specs with more than
+// maxMounts mounts, so a pathological bundle cannot stall mount setup in the guest.
+func LimitMounts(maxMounts int) bundle.Transformer {
+	return func(ctx context.Context, b *bundle.Bundle) error {
+		if n := len(b.Spec.Mounts); n > maxMounts {
+			return fmt.Errorf("spec has %d mounts, more than the limit of %d: %w", n, maxMounts, errdefs.ErrInvalidArgument)
+		}
+		return nil
+	}
+}
+
+// safeMountOptions are the flag options passed through to the VM unchanged.
+// Propagation flags (shared, rslave, ...) describe the host's mount tree and
+// have no counterpart in the VM, and idmapped mounts depend on host user
+// namespaces; both are dropped. Options of the form key=value are
+// filesystem data (mode=, size=, ...) and are kept unless they request an
+// ID mapping.
+var safeMountOptions = map[string]struct{}{
+	"defaults": {}, "bind": {}, "rbind": {},
+	"ro": {}, "rw": {}, "rro": {}, "rrw": {},
+	"suid": {}, "nosuid": {}, "rsuid": {}, "rnosuid": {},
+	"dev": {}, "nodev": {}, "rdev": {}, "rnodev": {},
+	"exec": {}, "noexec": {}, "rexec": {}, "rnoexec": {},
+	"sync": {}, "async": {}, "dirsync": {},
+	"atime": {}, "noatime": {}, "ratime": {}, "rnoatime": {},
+	"diratime": {}, "nodiratime": {}, "rdiratime": {}, "rnodiratime": {},
+	"relatime": {}, "norelatime": {}, "rrelatime": {}, "rnorelatime": {},
+	"strictatime": {}, "nostrictatime": {}, "rstrictatime": {}, "rnostrictatime": {},
+	"lazytime": {}, "nolazytime": {},
+	"symfollow": {}, "nosymfollow": {}, "rsymfollow": {}, "rnosymfollow": {},
+	"mand": {}, "nomand": {},
+	"newinstance": {}, "tmpcopyup": {},
+}
+
+// idmapDataOptions are key=value options that request an idmapped mount.
+var idmapDataOptions = []string{"X-mount.idmap", "idmap"}
+
+// NormalizeMountOptions strips mount options the VM cannot honor. Flag
+// options not in safeMountOptions, such as propagation and idmap flags, are
+// dropped, as are ID mappings on individual mounts. Every change is logged.
+func NormalizeMountOptions(ctx context.Context, b *bundle.Bundle) error {
+	for i := range b.Spec.Mounts {
+		m := &b.Spec.Mounts[i]
+		var dropped []string
+		m.Options = slices.DeleteFunc(m.Options, func(opt string) bool {
+			if safeMountOption(opt) {
+				return false
+			}
+			dropped = append(dropped, opt)
+			return true
+		})
+		if len(m.UIDMappings) > 0 || len(m.GIDMappings) > 0 {
+			m.UIDMappings, m.GIDMappings = nil, nil
+			dropped = append(dropped, "uidMappings/gidMappings")
+		}
+		if len(dropped) > 0 {
+			log.G(ctx).WithFields(log.Fields{
+				"destination": m.Destination,
+				"dropped":     dropped,
+			}).Info("dropping mount options unsupported in the VM")
+		}
+	}
+	return nil
+}
+
+func safeMountOption(opt string) bool {
+	if key, _, ok := strings.Cut(opt, "="); ok {
+		return !slices.Contains(idmapDataOptions, key)
+	}
+	_, ok := safeMountOptions[opt]
+	return ok
+}
@@ -0,0 +1,150 @@
+//go:build linux
+
+package transform
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spin-stack/spinbox/internal/shim/bundle"
+)
+
+func TestCheckMountCollisions(t *testing.T) {
+	ctx := context.Background()
+
+	// The mounts containerd generates for a default container
+	defaultMounts := []specs.Mount{
+		{Destination: "/proc", Type: "proc", Source: "proc"},
+		{Destination: "/dev", Type: "tmpfs", Source: "tmpfs"},
+		{Destination: "/dev/pts", Type: "devpts", Source: "devpts"},
+		{Destination: "/dev/shm", Type: "tmpfs", Source: "shm"},
+		{Destination: "/dev/mqueue", Type: "mqueue", Source: "mqueue"},
+		{Destination: "/sys", Type: "sysfs", Source: "sysfs"},
+		{Destination: "/sys/fs/cgroup", Type: "cgroup", Source: "cgroup"},
+		{Destination: "/data", Type: "bind", Source: "/srv/data"},
+	}
+
+	t.Run("default mounts", func(t *testing.T) {
+		b := &bundle.Bundle{Spec: specs.Spec{Mounts: defaultMounts}}
+		require.NoError(t, CheckMountCollisions()(ctx, b))
+	})
+
+	for _, tc := range []struct {
+		name     string
+		mount    specs.Mount
+		wantDest string
+	}{
+		{name: "bind to /proc", mount: specs.Mount{Destination: "/proc", Type: "bind", Source: "/host/proc"}, wantDest: "/proc"},
+		{name: "bind to /dev/shm", mount: specs.Mount{Destination: "/dev/shm", Type: "bind", Source: "/run/shm"}, wantDest: "/dev/shm"},
+		{name: "unclean destination", mount: specs.Mount{Destination: "/dev//shm/", Type: "bind", Source: "/run/shm"}, wantDest: "/dev/shm"},
+		{name: "tmpfs over /sys", mount: specs.Mount{Destination: "/sys", Type: "tmpfs", Source: "tmpfs"}, wantDest: "/sys"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &bundle.Bundle{Spec: specs.Spec{Mounts: append(append([]specs.Mount{}, defaultMounts...), tc.mount)}}
+			err := CheckMountCollisions()(ctx, b)
+			require.ErrorIs(t, err, errdefs.ErrInvalidArgument)
+			assert.Contains(t, err.Error(), "mount at "+tc.wantDest+" ")
+			assert.Contains(t, err.Error(), tc.mount.Source)
+		})
+	}
+
+	t.Run("allowed destination", func(t *testing.T) {
+		b := &bundle.Bundle{Spec: specs.Spec{Mounts: []specs.Mount{
+			{Destination: "/dev/shm", Type: "bind", Source: "/run/shm"},
+		}}}
+		require.NoError(t, CheckMountCollisions("/dev/shm")(ctx, b))
+
+		b.Spec.Mounts = append(b.Spec.Mounts, specs.Mount{Destination: "/proc", Type: "bind", Source: "/host/proc"})
+		require.Error(t, CheckMountCollisions("/dev/shm")(ctx, b))
+	})
+}
+
+func TestNormalizeMountOptions(t *testing.T) {
+	ctx := context.Background()
+	mapping := []specs.LinuxIDMapping{{ContainerID: 0, HostID: 100000, Size: 65536}}
+
+	b := &bundle.Bundle{Spec: specs.Spec{Mounts: []specs.Mount{
+		{Destination: "/data", Type: "bind", Source: "/srv/data", Options: []string{"rbind", "rshared", "ro", "nosuid"}},
+		{Destination: "/shared", Type: "bind", Source: "/srv/shared", Options: []string{"bind", "rslave", "idmap", "nodev", "noexec"},
+			UIDMappings: mapping, GIDMappings: mapping},
+		{Destination: "/cache", Type: "tmpfs", Source: "tmpfs", Options: []string{"rw", "mode=1777", "size=64m", "X-mount.idmap=0:1000:1", "private"}},
+		{Destination: "/proc", Type: "proc", Source: "proc"},
+	}}}
+	require.NoError(t, NormalizeMountOptions(ctx, b))
+
+	m := b.Spec.Mounts
+	assert.Equal(t, []string{"rbind", "ro", "nosuid"}, m[0].Options)
+	assert.Equal(t, []string{"bind", "nodev", "noexec"}, m[1].Options)
+	assert.Nil(t, m[1].UIDMappings)
+	assert.Nil(t, m[1].GIDMappings)
+	assert.Equal(t, []string{"rw", "mode=1777", "size=64m"}, m[2].Options)
+	assert.Empty(t, m[3].Options)
+}
+
+func TestLimitMounts(t *testing.T) {
+	ctx := context.Background()
+	mounts := func(n int) []specs.Mount {
+		m := make([]specs.Mount, n)
+		for i := range m {
+			m[i] = specs.Mount{Destination: fmt.Sprintf("/mnt/%d", i), Type: "tmpfs", Source: "tmpfs"}
+		}
+		return m
+	}
+
+	b := &bundle.Bundle{Spec: specs.Spec{Mounts: mounts(DefaultMaxMounts)}}
+	require.NoError(t, LimitMounts(DefaultMaxMounts)(ctx, b))
+
+	b.Spec.Mounts = mounts(DefaultMaxMounts + 1)
+	err := LimitMounts(DefaultMaxMounts)(ctx, b)
+	require.ErrorIs(t, err, errdefs.ErrInvalidArgument)
+	assert.Contains(t, err.Error(), "257 mounts")
+
+	t.Run("LoadForCreate applies the limit", func(t *testing.T) {
+		bundlePath := filepath.Join(t.TempDir(), "test-container")
+		createTestBundle(t, bundlePath)
+		writeSpec(t, bundlePath, &specs.Spec{Version: specs.Version, Root: &specs.Root{Path: "rootfs"}, Mounts: mounts(3)})
+
+		_, err := LoadForCreate(ctx, bundlePath, PrivilegePolicy{}, 2)
+		require.ErrorIs(t, err, errdefs.ErrInvalidArgument)
+
+		_, err = LoadForCreate(ctx, bundlePath, PrivilegePolicy{}, 3)
+		require.NoError(t, err)
+	})
+}
+
+func TestResolveMountSources(t *testing.T) {
+	ctx := context.Background()
+	bundlePath := "/run/containerd/io.containerd.runtime.v2.task/default/test"
+
+	b := &bundle.Bundle{Path: bundlePath, Spec: specs.Spec{Mounts: []specs.Mount{
+		{Destination: "/etc/hostname", Type: "bind", Source: "hostname"},
+		{Destination: "/data", Type: "none", Source: "./volumes/../data", Options: []string{"rbind", "ro"}},
+		{Destination: "/srv", Type: "bind", Source: "/srv/data"},
+		{Destination: "/tmp", Type: "tmpfs", Source: "tmpfs"},
+	}}}
+	require.NoError(t, ResolveMountSources(ctx, b))
+
+	m := b.Spec.Mounts
+	assert.Equal(t, bundlePath+"/hostname", m[0].Source)
+	assert.Equal(t, bundlePath+"/data", m[1].Source)
+	assert.Equal(t, "/srv/data", m[2].Source)
+	assert.Equal(t, "tmpfs", m[3].Source)
+
+	for _, src := range []string{"..", "../other/hostname", "data/../../../etc/passwd"} {
+		t.Run("escape "+src, func(t *testing.T) {
+			b := &bundle.Bundle{Path: bundlePath, Spec: specs.Spec{Mounts: []specs.Mount{
+				{Destination: "/etc/hostname", Type: "bind", Source: src},
+			}}}
+			err := ResolveMountSources(ctx, b)
+			require.ErrorIs(t, err, errdefs.ErrInvalidArgument)
+			assert.Contains(t, err.Error(), src)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+//go:build linux
+
+package transform
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/spin-stack/spinbox/internal/shim/bundle"
+)
+
+// PrivilegeMode selects how privileged containers are handled.
+type PrivilegeMode string
+
+const (
+	// PrivilegeAllow runs privileged containers unchanged (default).
+	PrivilegeAllow PrivilegeMode = "allow"
+	// PrivilegeReject fails creation of privileged containers.
+	PrivilegeReject PrivilegeMode = "reject"
+	// PrivilegeDowngrade runs privileged containers with a reduced
+	// capability set instead of the full set, and with the default device
+	// rules instead of access to all devices.
+	PrivilegeDowngrade PrivilegeMode = "downgrade"
+)
+
+// PrivilegePolicy is the operator policy for privileged containers.
+type PrivilegePolicy struct {
+	Mode PrivilegeMode
+	// Capabilities granted to downgraded containers.
+	// Defaults to DefaultDowngradeCapabilities.
+	Capabilities []string
+}
+
+// DefaultDowngradeCapabilities is the capability set of an unprivileged
+// container under the containerd default profile.
+var DefaultDowngradeCapabilities = []string{
+	"CAP_CHOWN",
+	"CAP_DAC_OVERRIDE",
+	"CAP_FSETID",
+	"CAP_FOWNER",
+	"CAP_MKNOD",
+	"CAP_NET_RAW",
+	"CAP_SETGID",
+	"CAP_SETUID",
+	"CAP_SETFCAP",
+	"CAP_SETPCAP",
+	"CAP_NET_BIND_SERVICE",
+	"CAP_SYS_CHROOT",
+	"CAP_KILL",
+	"CAP_AUDIT_WRITE",
+}
+
+// DefaultDowngradeDevices is the device cgroup of a downgraded container:
+// deny everything, as the containerd default profile does. runc adds its
+// allowed defaults (null, zero, full, tty, random, urandom, ptmx, pts, tun).
+var DefaultDowngradeDevices = []specs.LinuxDeviceCgroup{{Allow: false, Access: "rwm"}}
+
+// AnnotationRestrictDevices tells the guest to keep the spec's device cgroup
+// rules instead of allowing all devices (must match guest vminit/runc
+// package). The downgrade policy sets it; it is not meant for users.
+const AnnotationRestrictDevices = "io.spin.devices.restrict"
+
+// adminCapabilities are never granted by default profiles. A spec bounding
+// all of them was generated for a privileged container.
+var adminCapabilities = []string{
+	"CAP_SYS_ADMIN",
+	"CAP_SYS_MODULE",
+	"CAP_SYS_RAWIO",
+	"CAP_SYS_PTRACE",
+	"CAP_SYS_BOOT",
+	"CAP_NET_ADMIN",
+	"CAP_MAC_ADMIN",
+}
+
+// privilegeIndicators returns the signs of a privileged container in the
+// host-generated spec. Only all-caps and all-devices make a container
+// privileged; a disabled seccomp profile alone is common (e.g. Kubernetes
+// Unconfined) and is reported alongside them.
+func privilegeIndicators(spec *specs.Spec) (indicators []string, privileged bool) {
+	if spec.Process != nil && spec.Process.Capabilities != nil {
+		bounding := spec.Process.Capabilities.Bounding
+		if !slices.ContainsFunc(adminCapabilities, func(c string) bool { return !slices.Contains(bounding, c) }) {
+			indicators = append(indicators, "all-caps")
+			privileged = true
+		}
+	}
+
+	if spec.Linux != nil && spec.Linux.Resources != nil {
+		for _, d := range spec.Linux.Resources.Devices {
+			if d.Allow && (d.Type == "" || d.Type == "a") && d.Major == nil && d.Minor == nil &&
+				strings.Contains(d.Access, "r") && strings.Contains(d.Access, "w") && strings.Contains(d.Access, "m") {
+				indicators = append(indicators, "all-devices")
+				privileged = true
+				break
+			}
+		}
+	}
+
+	if spec.Linux == nil || spec.Linux.Seccomp == nil {
+		indicators = append(indicators, "seccomp-disabled")
+	}
+
+	return indicators, privileged
+}
+
+// transformers returns the bundle transformers enforcing the policy.
+// check must run on the host-generated spec, before AdaptForVM grants full
+// capabilities; apply must run after it.
+func (p PrivilegePolicy) transformers() (check, apply bundle.Transformer) {
+	var downgrade bool
+
+	check = func(ctx context.Context, b *bundle.Bundle) error {
+		if p.Mode == "" || p.Mode == PrivilegeAllow {
+			return nil
+		}
+		indicators, privileged := privilegeIndicators(&b.Spec)
+		if !privileged {
+			return nil
+		}
+
+		entry := log.G(ctx).WithFields(log.Fields{
+			"policy":     p.Mode,
+			"indicators": indicators,
+		})
+		switch p.Mode {
+		case PrivilegeReject:
+			entry.Warn("rejecting privileged container")
+			return fmt.Errorf("privileged container rejected by policy (%s): %w",
+				strings.Join(indicators, ", "), errdefs.ErrPermissionDenied)
+		case PrivilegeDowngrade:
+			entry.Info("downgrading privileged container capabilities")
+			downgrade = true
+			return nil
+		default:
+			return fmt.Errorf("unknown privilege policy %q: %w", p.Mode, errdefs.ErrInvalidArgument)
+		}
+	}
+
+	apply = func(ctx context.Context, b *bundle.Bundle) error {
+		if !downgrade || b.Spec.Process == nil {
+			return nil
+		}
+		caps := p.Capabilities
+		if len(caps) == 0 {
+			caps = DefaultDowngradeCapabilities
+		}
+		b.Spec.Process.Capabilities = &specs.LinuxCapabilities{
+			Bounding:  slices.Clone(caps),
+			Effective: slices.Clone(caps),
+			Permitted: slices.Clone(caps),
+		}
+
+		if b.Spec.Linux == nil {
+			b.Spec.Linux = &specs.Linux{}
+		}
+		if b.Spec.Linux.Resources == nil {
+			b.Spec.Linux.Resources = &specs.LinuxResources{}
+		}
+		b.Spec.Linux.Resources.Devices = slices.Clone(DefaultDowngradeDevices)
+		if b.Spec.Annotations == nil {
+			b.Spec.Annotations = map[string]string{}
+		}
+		b.Spec.Annotations[AnnotationRestrictDevices] = "true"
+		return nil
+	}
+
+	return check, apply
+}
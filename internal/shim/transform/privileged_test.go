@@ -0,0 +1,154 @@
+//go:build linux
+
+package transform
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	"github.com/opencontainers/runc/libcontainer/capabilities"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSpec writes spec as the bundle config.json.
+func writeSpec(t *testing.T, bundlePath string, spec *specs.Spec) {
+	t.Helper()
+	specBytes, err := json.Marshal(spec)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(bundlePath, "config.json"), specBytes, 0600))
+}
+
+// privilegedSpec returns a spec as generated for a --privileged container.
+func privilegedSpec() *specs.Spec {
+	allCaps := capabilities.KnownCapabilities()
+	return &specs.Spec{
+		Version: "1.0.0",
+		Root:    &specs.Root{Path: "rootfs"},
+		Process: &specs.Process{
+			Args: []string{"/bin/sh"},
+			Capabilities: &specs.LinuxCapabilities{
+				Bounding:  allCaps,
+				Effective: allCaps,
+				Permitted: allCaps,
+			},
+		},
+		Linux: &specs.Linux{
+			Resources: &specs.LinuxResources{
+				Devices: []specs.LinuxDeviceCgroup{{Allow: true, Access: "rwm"}},
+			},
+		},
+	}
+}
+
+func TestPrivilegeIndicators(t *testing.T) {
+	t.Run("privileged spec", func(t *testing.T) {
+		indicators, privileged := privilegeIndicators(privilegedSpec())
+		assert.True(t, privileged)
+		assert.Equal(t, []string{"all-caps", "all-devices", "seccomp-disabled"}, indicators)
+	})
+
+	t.Run("all devices only", func(t *testing.T) {
+		spec := privilegedSpec()
+		spec.Process.Capabilities = &specs.LinuxCapabilities{Bounding: DefaultDowngradeCapabilities}
+		spec.Linux.Seccomp = &specs.LinuxSeccomp{DefaultAction: specs.ActErrno}
+		indicators, privileged := privilegeIndicators(spec)
+		assert.True(t, privileged)
+		assert.Equal(t, []string{"all-devices"}, indicators)
+	})
+
+	t.Run("default container without seccomp", func(t *testing.T) {
+		spec := &specs.Spec{
+			Process: &specs.Process{
+				Capabilities: &specs.LinuxCapabilities{Bounding: DefaultDowngradeCapabilities},
+			},
+			Linux: &specs.Linux{
+				Resources: &specs.LinuxResources{
+					Devices: []specs.LinuxDeviceCgroup{
+						{Allow: false, Access: "rwm"},
+						{Allow: true, Type: "c", Major: ptrInt64(1), Minor: ptrInt64(3), Access: "rwm"},
+					},
+				},
+			},
+		}
+		indicators, privileged := privilegeIndicators(spec)
+		assert.False(t, privileged)
+		assert.Equal(t, []string{"seccomp-disabled"}, indicators)
+	})
+}
+
+func TestLoadForCreatePrivilegePolicy(t *testing.T) {
+	ctx := context.Background()
+
+	newBundle := func(t *testing.T, spec *specs.Spec) string {
+		t.Helper()
+		bundlePath := filepath.Join(t.TempDir(), "test-container")
+		createTestBundle(t, bundlePath)
+		writeSpec(t, bundlePath, spec)
+		return bundlePath
+	}
+
+	t.Run("allow keeps full capabilities", func(t *testing.T) {
+		b, err := LoadForCreate(ctx, newBundle(t, privilegedSpec()), PrivilegePolicy{Mode: PrivilegeAllow}, 0)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, capabilities.KnownCapabilities(), b.Spec.Process.Capabilities.Bounding)
+	})
+
+	t.Run("reject privileged", func(t *testing.T) {
+		_, err := LoadForCreate(ctx, newBundle(t, privilegedSpec()), PrivilegePolicy{Mode: PrivilegeReject}, 0)
+		require.ErrorIs(t, err, errdefs.ErrPermissionDenied)
+		assert.Contains(t, err.Error(), "all-caps")
+	})
+
+	t.Run("reject allows unprivileged", func(t *testing.T) {
+		spec := privilegedSpec()
+		spec.Process.Capabilities = &specs.LinuxCapabilities{Bounding: DefaultDowngradeCapabilities}
+		spec.Linux.Resources = nil
+		_, err := LoadForCreate(ctx, newBundle(t, spec), PrivilegePolicy{Mode: PrivilegeReject}, 0)
+		require.NoError(t, err)
+	})
+
+	t.Run("downgrade to default set", func(t *testing.T) {
+		b, err := LoadForCreate(ctx, newBundle(t, privilegedSpec()), PrivilegePolicy{Mode: PrivilegeDowngrade}, 0)
+		require.NoError(t, err)
+
+		caps := b.Spec.Process.Capabilities
+		assert.Equal(t, DefaultDowngradeCapabilities, caps.Bounding)
+		assert.Equal(t, DefaultDowngradeCapabilities, caps.Effective)
+		assert.Equal(t, DefaultDowngradeCapabilities, caps.Permitted)
+		assert.Empty(t, caps.Ambient)
+		assert.NotContains(t, caps.Bounding, "CAP_SYS_ADMIN")
+
+		assert.Equal(t, DefaultDowngradeDevices, b.Spec.Linux.Resources.Devices)
+		assert.Equal(t, "true", b.Spec.Annotations[AnnotationRestrictDevices])
+		_, privileged := privilegeIndicators(&b.Spec)
+		assert.False(t, privileged)
+	})
+
+	t.Run("downgrade to configured set", func(t *testing.T) {
+		policy := PrivilegePolicy{
+			Mode:         PrivilegeDowngrade,
+			Capabilities: []string{"CAP_NET_ADMIN", "CAP_NET_RAW"},
+		}
+		b, err := LoadForCreate(ctx, newBundle(t, privilegedSpec()), policy, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"CAP_NET_ADMIN", "CAP_NET_RAW"}, b.Spec.Process.Capabilities.Bounding)
+	})
+
+	t.Run("downgrade leaves unprivileged with VM defaults", func(t *testing.T) {
+		spec := privilegedSpec()
+		spec.Process.Capabilities = nil
+		spec.Linux.Resources = nil
+		b, err := LoadForCreate(ctx, newBundle(t, spec), PrivilegePolicy{Mode: PrivilegeDowngrade}, 0)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, capabilities.KnownCapabilities(), b.Spec.Process.Capabilities.Bounding)
+		assert.NotContains(t, b.Spec.Annotations, AnnotationRestrictDevices)
+	})
+}
+
+func ptrInt64(v int64) *int64 { return &v }
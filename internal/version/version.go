@@ -0,0 +1,17 @@
+// Package version holds build information for spinbox binaries.
+//
+// The values are set at link time, for example:
+//
+//	go build -ldflags "-X github.com/spin-stack/spinbox/internal/version.Version=v1.0.0"
+package version
+
+var (
+	// Version is the release version, or "dev" for unreleased builds.
+	Version = "dev"
+
+	// Revision is the git commit the binary was built from.
+	Revision = ""
+
+	// BuildDate is the UTC build time in RFC 3339 format.
+	BuildDate = ""
+)